@@ -0,0 +1,191 @@
+package fielder
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Arithmetic is implemented by the numeric fields so derived values can be computed without unwrapping to raw types
+// the result always has the type and key of the receiver, the operand is converted to match
+// operations that do not make sense (dividing by zero, adding a string) return FieldNil
+type Arithmetic interface {
+	Add(in2 Field) Field
+	Sub(in2 Field) Field
+	Mul(in2 Field) Field
+	Div(in2 Field) Field
+}
+
+// reads any numeric field as a decimal, other fields are parsed from their string form
+func fieldToDecimal(f Field) (decimal.Decimal, bool) {
+	switch v := f.(type) {
+	case nil:
+		return decimal.Decimal{}, false
+	case *IntegerField:
+		return decimal.NewFromInt(int64(v.ValueField)), true
+	case *DecimalField:
+		return v.ValueField, true
+	case *FloatField:
+		return decimal.NewFromFloat(v.ValueField), true
+	case *CounterField:
+		return decimal.NewFromInt(v.Load()), true
+	}
+	d, err := decimal.NewFromString(f.ToString())
+	if err != nil {
+		return decimal.Decimal{}, false
+	}
+	return d, true
+}
+
+func (s *IntegerField) Add(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &IntegerField{ValueField: s.ValueField + int(d.IntPart()), KeyField: s.KeyField}
+}
+
+func (s *IntegerField) Sub(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &IntegerField{ValueField: s.ValueField - int(d.IntPart()), KeyField: s.KeyField}
+}
+
+func (s *IntegerField) Mul(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &IntegerField{ValueField: int(decimal.NewFromInt(int64(s.ValueField)).Mul(d).IntPart()), KeyField: s.KeyField}
+}
+
+// integer division truncates toward zero
+func (s *IntegerField) Div(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok || d.IsZero() {
+		return FieldNil
+	}
+	return &IntegerField{ValueField: int(decimal.NewFromInt(int64(s.ValueField)).Div(d).IntPart()), KeyField: s.KeyField}
+}
+
+// results are rounded the same way the receiver rounds on set
+func (s *DecimalField) Add(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return s.derive(s.ValueField.Add(d))
+}
+
+func (s *DecimalField) Sub(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return s.derive(s.ValueField.Sub(d))
+}
+
+func (s *DecimalField) Mul(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return s.derive(s.ValueField.Mul(d))
+}
+
+func (s *DecimalField) Div(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok || d.IsZero() {
+		return FieldNil
+	}
+	return s.derive(s.ValueField.Div(d))
+}
+
+// a new field with the receiver's key and options holding d
+func (s *DecimalField) derive(d decimal.Decimal) *DecimalField {
+	out := *s
+	out.ValueField = s.round(d)
+	return &out
+}
+
+func (s *FloatField) Add(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &FloatField{ValueField: s.ValueField + d.InexactFloat64(), KeyField: s.KeyField}
+}
+
+func (s *FloatField) Sub(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &FloatField{ValueField: s.ValueField - d.InexactFloat64(), KeyField: s.KeyField}
+}
+
+func (s *FloatField) Mul(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &FloatField{ValueField: s.ValueField * d.InexactFloat64(), KeyField: s.KeyField}
+}
+
+func (s *FloatField) Div(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok || d.IsZero() {
+		return FieldNil
+	}
+	return &FloatField{ValueField: s.ValueField / d.InexactFloat64(), KeyField: s.KeyField}
+}
+
+// durations add and subtract other durations
+func (s *DurationField) Add(in2 Field) Field {
+	d, ok := fieldToDuration(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &DurationField{ValueField: s.ValueField + d, KeyField: s.KeyField}
+}
+
+func (s *DurationField) Sub(in2 Field) Field {
+	d, ok := fieldToDuration(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &DurationField{ValueField: s.ValueField - d, KeyField: s.KeyField}
+}
+
+// durations multiply and divide by plain numbers, ex: timeout * 2
+func (s *DurationField) Mul(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok {
+		return FieldNil
+	}
+	return &DurationField{ValueField: time.Duration(decimal.NewFromInt(int64(s.ValueField)).Mul(d).IntPart()), KeyField: s.KeyField}
+}
+
+func (s *DurationField) Div(in2 Field) Field {
+	d, ok := fieldToDecimal(in2)
+	if !ok || d.IsZero() {
+		return FieldNil
+	}
+	return &DurationField{ValueField: time.Duration(decimal.NewFromInt(int64(s.ValueField)).Div(d).IntPart()), KeyField: s.KeyField}
+}
+
+func fieldToDuration(f Field) (time.Duration, bool) {
+	if f == nil {
+		return 0, false
+	}
+	if v, ok := f.(*DurationField); ok {
+		return v.ValueField, true
+	}
+	d, err := time.ParseDuration(f.ToString())
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
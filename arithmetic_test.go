@@ -0,0 +1,91 @@
+package fielder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntegerFieldArithmetic(t *testing.T) {
+	key := NewDefaultFieldKey("Qty")
+	a := &IntegerField{ValueField: 7, KeyField: key}
+	for name, tc := range map[string]struct {
+		got  Field
+		want string
+	}{
+		"add":          {a.Add(&IntegerField{ValueField: 3}), "10"},
+		"sub decimal":  {a.Sub(testDecimal(t, "2.9")), "5"},
+		"mul float":    {a.Mul(&FloatField{ValueField: 1.5}), "10"},
+		"div":          {a.Div(&IntegerField{ValueField: -2}), "-3"},
+		"add counter":  {a.Add(NewCounterField(FieldKeyNil, 1)), "8"},
+		"add a string": {a.Add(&StringField{ValueField: "4"}), "11"},
+	} {
+		if tc.got.ToString() != tc.want {
+			t.Errorf("%s: got %s want %s", name, tc.got.ToString(), tc.want)
+		}
+		if tc.got.Key() != key {
+			t.Errorf("%s: the result has the receiver's key", name)
+		}
+	}
+	if a.Div(&IntegerField{}) != FieldNil || a.Add(&StringField{ValueField: "four"}) != FieldNil || a.Add(nil) != FieldNil {
+		t.Error("dividing by zero and non numbers are FieldNil")
+	}
+}
+
+func TestDecimalFieldArithmetic(t *testing.T) {
+	price := testDecimal(t, "10.00", WithScale(2))
+	third := price.Div(&IntegerField{ValueField: 3})
+	if third.ToString() != "3.33" {
+		t.Errorf("results are rounded like the receiver: got %s", third.ToString())
+	}
+	if got := price.Mul(testDecimal(t, "1.075")).ToString(); got != "10.75" {
+		t.Errorf("got %s", got)
+	}
+	if got := price.Sub(&FloatField{ValueField: 0.5}).(Arithmetic).Add(&IntegerField{ValueField: 1}).ToString(); got != "10.5" {
+		t.Errorf("got %s", got)
+	}
+	if price.ToString() != "10" {
+		t.Errorf("the receiver is not changed: got %s", price.ToString())
+	}
+}
+
+func TestFloatAndDurationArithmetic(t *testing.T) {
+	f := &FloatField{ValueField: 1.5}
+	if got := f.Mul(&IntegerField{ValueField: 2}).(Arithmetic).Add(testDecimal(t, "0.25")).ToString(); got != "3.25" {
+		t.Errorf("float: got %s", got)
+	}
+	if f.Div(&FloatField{}) != FieldNil {
+		t.Error("dividing by zero is FieldNil")
+	}
+	d := &DurationField{ValueField: time.Minute}
+	if got := d.Add(&DurationField{ValueField: time.Second}).Value(); got != time.Minute+time.Second {
+		t.Errorf("add: got %v", got)
+	}
+	if got := d.Sub(&StringField{ValueField: "30s"}).Value(); got != 30*time.Second {
+		t.Errorf("sub a duration string: got %v", got)
+	}
+	if got := d.Mul(&IntegerField{ValueField: 2}).Value(); got != 2*time.Minute {
+		t.Errorf("mul: got %v", got)
+	}
+	if got := d.Div(&FloatField{ValueField: 4}).Value(); got != 15*time.Second {
+		t.Errorf("div: got %v", got)
+	}
+	if d.Add(&IntegerField{ValueField: 1}) != FieldNil {
+		t.Error("durations only add durations")
+	}
+}
+
+func TestFloatField(t *testing.T) {
+	f := &FloatField{}
+	f.FromString("2.5")
+	f.FromString("two")
+	if f.ToString() != "2.5" || f.IsEmpty() {
+		t.Errorf("got %q", f.ToString())
+	}
+	if !f.LessThan(&FloatField{ValueField: 3}) || !f.GreaterThan(&FloatField{ValueField: 2}) || !f.Equal(&FloatField{ValueField: 2.5}) {
+		t.Error("floats compare by value")
+	}
+	f.SetValue(&StringField{ValueField: "0.125"})
+	if f.ValueField != 0.125 {
+		t.Errorf("SetValue from string: got %v", f.ValueField)
+	}
+}
@@ -0,0 +1,416 @@
+package fielder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/shopspring/decimal"
+)
+
+// decimals are written as the avro decimal logical type, which needs a fixed precision and scale
+// values with more places than AvroDecimalScale are rounded when they are encoded
+const (
+	AvroDecimalPrecision = 38
+	AvroDecimalScale     = 18
+)
+
+// parents map to avro records with one avro field per tagged member
+// the mapping follows the dynamo and json paths: numbers, bools and bytes are native, times are timestamp-millis,
+// dates are the date logical type, decimals are the decimal logical type, and every other field type is its string form
+// pointer members are a union with null, nested structs are nested records, and members that are fields hold their json form
+type AvroCodec[P any] struct {
+	codec *goavro.Codec
+}
+
+func NewAvroCodec[P any]() (*AvroCodec[P], error) {
+	schema, err := GenerateAvroSchema[P]()
+	if err != nil {
+		return nil, err
+	}
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &AvroCodec[P]{codec: codec}, nil
+}
+
+func (c *AvroCodec[P]) Schema() string {
+	return c.codec.Schema()
+}
+
+// Encode returns the avro binary encoding of the parent
+func (c *AvroCodec[P]) Encode(p P) ([]byte, error) {
+	native, err := avroNative(reflect.ValueOf(p), true)
+	if err != nil {
+		return nil, err
+	}
+	return c.codec.BinaryFromNative(nil, native)
+}
+
+func (c *AvroCodec[P]) Decode(b []byte) (P, error) {
+	out := new(P)
+	native, _, err := c.codec.NativeFromBinary(b)
+	if err != nil {
+		return *out, err
+	}
+	if err := avroFromNative(native, reflect.ValueOf(out).Elem(), true); err != nil {
+		return *out, err
+	}
+	return *out, nil
+}
+
+// GenerateAvroSchema returns the avro schema of a default parent as json
+func GenerateAvroSchema[P any]() (string, error) {
+	ty := reflect.TypeOf(*new(P))
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return "", ErrNotStruct
+	}
+	schema, err := avroSchemaOf(ty, map[reflect.Type]bool{}, true)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(schema)
+	return string(b), err
+}
+
+// the avro type each field type name is written as, names that are not here are written as strings
+var avroPrimitives = map[string]any{
+	"integer": "long",
+	"counter": "long",
+	"float":   "double",
+	"bool":    "boolean",
+	"bytes":   "bytes",
+	"time":    map[string]any{"type": "long", "logicalType": "timestamp-millis"},
+	"date":    map[string]any{"type": "int", "logicalType": "date"},
+	"decimal": map[string]any{"type": "bytes", "logicalType": "decimal", "precision": AvroDecimalPrecision, "scale": AvroDecimalScale},
+}
+
+// returns the field type name for types that have their own field, or "" for plain go types
+func avroFieldTypeName(ty reflect.Type) string {
+	return fieldTypeNames[ty]
+}
+
+// top is true for the members of the parent itself, only those are limited to tagged members
+// defined holds the records already written, later uses refer to them by name
+func avroSchemaOf(ty reflect.Type, defined map[reflect.Type]bool, top bool) (any, error) {
+	if ty.Implements(fieldInterfaceType) {
+		return "string", nil
+	}
+	if name := avroFieldTypeName(ty); name != "" {
+		if schema, ok := avroPrimitives[name]; ok {
+			return schema, nil
+		}
+		return "string", nil
+	}
+	switch ty.Kind() {
+	case reflect.Pointer:
+		inner, err := avroSchemaOf(ty.Elem(), defined, false)
+		if err != nil {
+			return nil, err
+		}
+		return []any{"null", inner}, nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "long", nil
+	case reflect.Float32, reflect.Float64:
+		return "double", nil
+	case reflect.Slice, reflect.Array:
+		items, err := avroSchemaOf(ty.Elem(), defined, false)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if ty.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("avro maps need string keys: %s", ty)
+		}
+		values, err := avroSchemaOf(ty.Elem(), defined, false)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "map", "values": values}, nil
+	case reflect.Struct:
+		if defined[ty] {
+			return ty.Name(), nil
+		}
+		defined[ty] = true
+		fields := []any{}
+		for i := 0; i < ty.NumField(); i++ {
+			sf := ty.Field(i)
+			if !avroMember(sf, top) {
+				continue
+			}
+			schema, err := avroSchemaOf(sf.Type, defined, false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", sf.Name, err)
+			}
+			fields = append(fields, map[string]any{"name": sf.Name, "type": schema})
+		}
+		return map[string]any{"type": "record", "name": ty.Name(), "fields": fields}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, ty)
+}
+
+func avroMember(sf reflect.StructField, top bool) bool {
+	if !sf.IsExported() {
+		return false
+	}
+	if !top {
+		return true
+	}
+	name, _ := parseFieldTag(sf.Tag.Get(FieldKeyTag))
+	return name != ""
+}
+
+// the branch name goavro uses for a union member, ex: "long.timestamp-millis" or the record name
+func avroBranchName(ty reflect.Type) string {
+	schema, err := avroSchemaOf(ty, map[reflect.Type]bool{}, false)
+	if err != nil {
+		return ""
+	}
+	switch s := schema.(type) {
+	case string:
+		return s
+	case map[string]any:
+		if lt, ok := s["logicalType"]; ok {
+			return fmt.Sprintf("%s.%s", s["type"], lt)
+		}
+		if s["type"] == "record" {
+			return s["name"].(string)
+		}
+		return s["type"].(string)
+	}
+	return ""
+}
+
+func avroNative(v reflect.Value, top bool) (any, error) {
+	ty := v.Type()
+	if ty.Implements(fieldInterfaceType) {
+		if v.IsNil() {
+			return "", nil
+		}
+		if f := v.Interface().(Field); IsSensitive(f) {
+			return nil, sensitiveFieldError(f)
+		}
+		b, err := json.Marshal(v.Interface())
+		return string(b), err
+	}
+	if name := avroFieldTypeName(ty); name != "" {
+		switch name {
+		case "integer", "counter":
+			return v.Int(), nil
+		case "float":
+			return v.Float(), nil
+		case "bool":
+			return v.Bool(), nil
+		case "bytes":
+			return v.Bytes(), nil
+		case "time":
+			return v.Interface().(time.Time), nil
+		case "date":
+			return v.Interface().(Date).Time(), nil
+		case "decimal":
+			return v.Interface().(decimal.Decimal).Round(AvroDecimalScale).Rat(), nil
+		}
+		return CreateFieldFromType(ty, v.Interface(), FieldKeyNil).ToString(), nil
+	}
+	switch ty.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		inner, err := avroNative(v.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		return goavro.Union(avroBranchName(ty.Elem()), inner), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Slice, reflect.Array:
+		out := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := avroNative(v.Index(i), false)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, item)
+		}
+		return out, nil
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item, err := avroNative(iter.Value(), false)
+			if err != nil {
+				return nil, err
+			}
+			out[iter.Key().String()] = item
+		}
+		return out, nil
+	case reflect.Struct:
+		out := map[string]any{}
+		for i := 0; i < ty.NumField(); i++ {
+			sf := ty.Field(i)
+			if !avroMember(sf, top) {
+				continue
+			}
+			item, err := avroNative(v.Field(i), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", sf.Name, err)
+			}
+			out[sf.Name] = item
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, ty)
+}
+
+// writes the decoded native value into the settable v
+func avroFromNative(native any, v reflect.Value, top bool) error {
+	ty := v.Type()
+	if ty.Implements(fieldInterfaceType) {
+		st, _ := native.(string)
+		if st == "" {
+			return nil
+		}
+		if ty.Kind() == reflect.Interface {
+			f, err := UnmarshalFieldJSON([]byte(st), nil)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(f))
+			return nil
+		}
+		f := reflect.New(ty.Elem())
+		if err := json.Unmarshal([]byte(st), f.Interface()); err != nil {
+			return err
+		}
+		v.Set(f)
+		return nil
+	}
+	if name := avroFieldTypeName(ty); name != "" {
+		switch n := native.(type) {
+		case int64:
+			v.SetInt(n)
+			return nil
+		case float64:
+			v.SetFloat(n)
+			return nil
+		case bool:
+			v.SetBool(n)
+			return nil
+		case []byte:
+			v.SetBytes(n)
+			return nil
+		case time.Time:
+			if name == "date" {
+				v.Set(reflect.ValueOf(DateOf(n)))
+			} else {
+				v.Set(reflect.ValueOf(n))
+			}
+			return nil
+		case *big.Rat:
+			v.Set(reflect.ValueOf(decimal.NewFromBigRat(n, AvroDecimalScale)))
+			return nil
+		case string:
+			if n == "" {
+				return nil
+			}
+			f := CreateFieldFromType(ty, nil, FieldKeyNil)
+			if err := ParseFieldString(f, n); err != nil {
+				return err
+			}
+			return assignField(v, f)
+		}
+		return fmt.Errorf("avro value %T does not fit %s", native, ty)
+	}
+	switch ty.Kind() {
+	case reflect.Pointer:
+		if native == nil {
+			v.Set(reflect.Zero(ty))
+			return nil
+		}
+		if union, ok := native.(map[string]any); ok && len(union) == 1 {
+			for _, inner := range union {
+				native = inner
+			}
+		}
+		elem := reflect.New(ty.Elem())
+		if err := avroFromNative(native, elem.Elem(), false); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.String:
+		v.SetString(native.(string))
+	case reflect.Bool:
+		v.SetBool(native.(bool))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(native.(int64))
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		v.SetUint(uint64(native.(int64)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(native.(float64))
+	case reflect.Slice:
+		items := native.([]any)
+		out := reflect.MakeSlice(ty, len(items), len(items))
+		for i, item := range items {
+			if err := avroFromNative(item, out.Index(i), false); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+	case reflect.Array:
+		for i, item := range native.([]any) {
+			if i >= v.Len() {
+				break
+			}
+			if err := avroFromNative(item, v.Index(i), false); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		items := native.(map[string]any)
+		out := reflect.MakeMapWithSize(ty, len(items))
+		for k, item := range items {
+			elem := reflect.New(ty.Elem()).Elem()
+			if err := avroFromNative(item, elem, false); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(ty.Key()), elem)
+		}
+		v.Set(out)
+	case reflect.Struct:
+		record := native.(map[string]any)
+		for i := 0; i < ty.NumField(); i++ {
+			sf := ty.Field(i)
+			if !avroMember(sf, top) {
+				continue
+			}
+			if item, ok := record[sf.Name]; ok {
+				if err := avroFromNative(item, v.Field(i), false); err != nil {
+					return fmt.Errorf("%s: %w", sf.Name, err)
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownFieldType, ty)
+	}
+	return nil
+}
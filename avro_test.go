@@ -0,0 +1,146 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type testAvroAddress struct {
+	City string
+}
+
+type testAvroRecord struct {
+	Name     string           `field:"Name"`
+	Age      int              `field:"Age"`
+	Score    float64          `field:"Score"`
+	Active   bool             `field:"Active"`
+	Joined   time.Time        `field:"Joined"`
+	Birthday Date             `field:"Birthday"`
+	Balance  decimal.Decimal  `field:"Balance"`
+	Timeout  time.Duration    `field:"Timeout"`
+	Nickname *string          `field:"Nickname"`
+	Tags     []string         `field:"Tags"`
+	Labels   map[string]int   `field:"Labels"`
+	Address  testAvroAddress  `field:"Address"`
+	Home     *testAvroAddress `field:"Home"`
+	Bio      *StringField     `field:"Bio"`
+	Note     Field            `field:"Note"`
+	Untagged string
+	internal string
+}
+
+func TestAvroCodecRoundTrip(t *testing.T) {
+	codec, err := NewAvroCodec[testAvroRecord]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nick := "sammy"
+	in := testAvroRecord{
+		Name:     "sam",
+		Age:      30,
+		Score:    1.5,
+		Active:   true,
+		Joined:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Birthday: Date{Year: 1990, Month: time.May, Day: 4},
+		Balance:  decimal.RequireFromString("12.34"),
+		Timeout:  time.Minute,
+		Nickname: &nick,
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]int{"x": 1},
+		Address:  testAvroAddress{City: "Oslo"},
+		Home:     &testAvroAddress{City: "Bergen"},
+		Bio:      testField("Bio", "hi"),
+		Note:     testField("Note", "there"),
+		Untagged: "dropped",
+	}
+	b, err := codec.Encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := codec.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != in.Name || out.Age != in.Age || out.Score != in.Score || !out.Active || out.Timeout != in.Timeout {
+		t.Errorf("scalars: got %+v", out)
+	}
+	if !out.Joined.Equal(in.Joined) || out.Birthday != in.Birthday || !out.Balance.Equal(in.Balance) {
+		t.Errorf("logical types: got %v %v %v", out.Joined, out.Birthday, out.Balance)
+	}
+	if out.Nickname == nil || *out.Nickname != nick || out.Home == nil || out.Home.City != "Bergen" || out.Address != in.Address {
+		t.Errorf("pointers and records: got %+v", out)
+	}
+	if !reflect.DeepEqual(out.Tags, in.Tags) || !reflect.DeepEqual(out.Labels, in.Labels) {
+		t.Errorf("collections: got %v %v", out.Tags, out.Labels)
+	}
+	if out.Bio == nil || out.Bio.ToString() != "hi" || out.Note == nil || out.Note.ToString() != "there" {
+		t.Errorf("field members: got %v %v", out.Bio, out.Note)
+	}
+	if out.Untagged != "" {
+		t.Error("untagged members of the parent are not encoded")
+	}
+}
+
+func TestAvroCodecNil(t *testing.T) {
+	codec, err := NewAvroCodec[testAvroRecord]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := codec.Encode(testAvroRecord{Name: "sam"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := codec.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Nickname != nil || out.Home != nil || out.Bio != nil || out.Note != nil {
+		t.Errorf("nil members stay nil: got %+v", out)
+	}
+}
+
+func TestGenerateAvroSchema(t *testing.T) {
+	schema, err := GenerateAvroSchema[testAvroRecord]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"name":"testAvroRecord"`,
+		`{"name":"Age","type":"long"}`,
+		`{"logicalType":"timestamp-millis","type":"long"}`,
+		`{"name":"Nickname","type":["null","string"]}`,
+		`{"name":"Bio","type":"string"}`,
+		`"name":"testAvroAddress"`,
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("schema is missing %s: %s", want, schema)
+		}
+	}
+	if strings.Contains(schema, "Untagged") || strings.Contains(schema, "internal") {
+		t.Errorf("untagged members are in the schema: %s", schema)
+	}
+	if _, err := GenerateAvroSchema[string](); err != ErrNotStruct {
+		t.Errorf("got %v", err)
+	}
+	type badMap struct {
+		M map[int]string `field:"M"`
+	}
+	if _, err := GenerateAvroSchema[badMap](); err == nil {
+		t.Error("maps without string keys are an error")
+	}
+}
+
+func TestAvroCodecSensitive(t *testing.T) {
+	c, err := NewAvroCodec[testAvroRecord]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Encode(testAvroRecord{Note: NewRedactedField(testField("Note", "abc"))}); !errors.Is(err, ErrSensitiveField) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package fielder
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// the binary form of a field is [type code][key][tag][payload], where key, tag and payload are a uvarint length followed by the bytes
+// the type code is the msgpack type code, with fieldBinaryNull set and no payload when the field has no value
+// integers, counters and durations are zigzag varints, times are varint unix seconds then uvarint nanoseconds in UTC,
+// dates are varint days since the unix epoch, floats are 8 bytes little endian, uuids are their 16 bytes,
+// and everything else is its string form
+// the tag is written empty when it is the default, so most fields have a two byte header around the key
+
+const fieldBinaryNull uint8 = 0x80
+
+var ErrShortBinary = errors.New("binary field is truncated")
+
+const secondsPerDay = int64(24 * time.Hour / time.Second)
+
+// AppendField appends the binary form of f to dst, a sensitive field is refused with ErrSensitiveField and dst is returned as it was
+// fields with a native payload only allocate when dst has to grow, the others allocate their string form
+func AppendField(dst []byte, f Field) ([]byte, error) {
+	if IsSensitive(f) {
+		return dst, sensitiveFieldError(f)
+	}
+	code := fieldTypeCode(f)
+	tag := f.Key().Tag
+	if tag == FieldKeyTag {
+		tag = ""
+	}
+	null := fieldBinaryIsNull(f)
+	if null {
+		code |= fieldBinaryNull
+	}
+	dst = append(dst, code)
+	dst = appendBinaryString(dst, f.Key().Name.String())
+	dst = appendBinaryString(dst, tag)
+	if null {
+		return dst, nil
+	}
+	// the payload is written in place and then moved up behind its length, so it needs no scratch buffer
+	start := len(dst)
+	dst = appendFieldPayload(dst, f)
+	n := len(dst) - start
+	var prefix [binary.MaxVarintLen64]byte
+	l := binary.PutUvarint(prefix[:], uint64(n))
+	dst = append(dst, prefix[:l]...)
+	copy(dst[start+l:], dst[start:start+n])
+	copy(dst[start:], prefix[:l])
+	return dst, nil
+}
+
+// the value types that can not be nil are checked without Value, which would box them
+func fieldBinaryIsNull(f Field) bool {
+	switch v := f.(type) {
+	case *BoolField:
+		return !v.Set
+	case *GeoPointField:
+		return !v.Set
+	case *IntegerField, *CounterField, *DurationField, *FloatField, *TimeField, *DateField, *UUIDField, *StringField:
+		return false
+	}
+	return f.Value() == nil
+}
+
+func appendBinaryString(dst []byte, st string) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(st)))
+	return append(dst, st...)
+}
+
+func appendFieldPayload(dst []byte, f Field) []byte {
+	switch v := f.(type) {
+	case *IntegerField:
+		return binary.AppendVarint(dst, int64(v.ValueField))
+	case *CounterField:
+		return binary.AppendVarint(dst, v.Load())
+	case *DurationField:
+		return binary.AppendVarint(dst, int64(v.ValueField))
+	case *BoolField:
+		if v.ValueField {
+			return append(dst, 1)
+		}
+		return append(dst, 0)
+	case *FloatField:
+		return binary.LittleEndian.AppendUint64(dst, math.Float64bits(v.ValueField))
+	case *TimeField:
+		dst = binary.AppendVarint(dst, v.ValueField.Unix())
+		return binary.AppendUvarint(dst, uint64(v.ValueField.Nanosecond()))
+	case *DateField:
+		return binary.AppendVarint(dst, v.ValueField.Time().Unix()/secondsPerDay)
+	case *UUIDField:
+		return append(dst, v.ValueField[:]...)
+	case *BytesField:
+		return append(dst, v.ValueField...)
+	case *StringField:
+		return append(dst, v.ValueField...)
+	}
+	return append(dst, f.ToString()...)
+}
+
+// ReadField reads one field from the front of b, and returns the number of bytes it took
+func ReadField(b []byte) (Field, int, error) {
+	if len(b) == 0 {
+		return nil, 0, ErrShortBinary
+	}
+	code := b[0]
+	n := 1
+	name, l, err := readBinaryBytes(b[n:])
+	if err != nil {
+		return nil, 0, err
+	}
+	n += l
+	tag, l, err := readBinaryBytes(b[n:])
+	if err != nil {
+		return nil, 0, err
+	}
+	n += l
+	typeName, err := fieldTypeNameFromCode(code &^ fieldBinaryNull)
+	if err != nil {
+		return nil, 0, err
+	}
+	f, err := NewFieldFromTypeName(typeName, NewFieldKey(string(name), string(tag)))
+	if err != nil {
+		return nil, 0, err
+	}
+	if code&fieldBinaryNull != 0 {
+		return f, n, nil
+	}
+	payload, l, err := readBinaryBytes(b[n:])
+	if err != nil {
+		return nil, 0, err
+	}
+	n += l
+	if err := readFieldPayload(payload, f); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return f, n, nil
+}
+
+func readBinaryBytes(b []byte) ([]byte, int, error) {
+	size, l := binary.Uvarint(b)
+	if l <= 0 || uint64(len(b)-l) < size {
+		return nil, 0, ErrShortBinary
+	}
+	return b[l : l+int(size)], l + int(size), nil
+}
+
+func readBinaryVarint(payload []byte) (int64, error) {
+	v, l := binary.Varint(payload)
+	if l <= 0 {
+		return 0, ErrShortBinary
+	}
+	return v, nil
+}
+
+func readFieldPayload(payload []byte, f Field) error {
+	switch v := f.(type) {
+	case *IntegerField, *CounterField:
+		i, err := readBinaryVarint(payload)
+		if err != nil {
+			return err
+		}
+		return scanField(f, i)
+	case *DurationField:
+		i, err := readBinaryVarint(payload)
+		v.ValueField = time.Duration(i)
+		return err
+	case *BoolField:
+		if len(payload) != 1 {
+			return ErrShortBinary
+		}
+		return scanField(f, payload[0] == 1)
+	case *FloatField:
+		if len(payload) != 8 {
+			return ErrShortBinary
+		}
+		v.ValueField = math.Float64frombits(binary.LittleEndian.Uint64(payload))
+		return nil
+	case *TimeField:
+		sec, l := binary.Varint(payload)
+		if l <= 0 {
+			return ErrShortBinary
+		}
+		nsec, l2 := binary.Uvarint(payload[l:])
+		if l2 <= 0 {
+			return ErrShortBinary
+		}
+		return scanField(f, time.Unix(sec, int64(nsec)).UTC())
+	case *DateField:
+		days, err := readBinaryVarint(payload)
+		if err != nil {
+			return err
+		}
+		return scanField(f, time.Unix(days*secondsPerDay, 0).UTC())
+	case *UUIDField:
+		id, err := uuid.FromBytes(payload)
+		v.ValueField = id
+		return err
+	}
+	return scanField(f, payload)
+}
+
+// AppendFieldSet appends the number of fields in the set followed by each field, in the set's order
+// dst is returned as it was when a field can not be appended
+func AppendFieldSet(dst []byte, s *FieldSet) ([]byte, error) {
+	start := len(dst)
+	dst = binary.AppendUvarint(dst, uint64(s.Len()))
+	for _, f := range s.Fields() {
+		var err error
+		if dst, err = AppendField(dst, f); err != nil {
+			return dst[:start], err
+		}
+	}
+	return dst, nil
+}
+
+// ReadFieldSet reads a field set from the front of b, and returns the number of bytes it took
+func ReadFieldSet(b []byte) (*FieldSet, int, error) {
+	count, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, 0, ErrShortBinary
+	}
+	out := NewFieldSet()
+	for i := uint64(0); i < count; i++ {
+		f, l, err := ReadField(b[n:])
+		if err != nil {
+			return nil, 0, err
+		}
+		out.Set(f)
+		n += l
+	}
+	return out, n, nil
+}
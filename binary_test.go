@@ -0,0 +1,111 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func testBinaryRoundTrip(t *testing.T, f Field) Field {
+	t.Helper()
+	b, err := AppendField(nil, f)
+	if err != nil {
+		t.Fatalf("%s: %v", f.Key().Name, err)
+	}
+	out, n, err := ReadField(b)
+	if err != nil {
+		t.Fatalf("%s: %v", f.Key().Name, err)
+	}
+	if n != len(b) {
+		t.Errorf("%s: read %d of %d bytes", f.Key().Name, n, len(b))
+	}
+	if out.Key() != f.Key() {
+		t.Errorf("key: got %v want %v", out.Key(), f.Key())
+	}
+	return out
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	fields := []Field{
+		&IntegerField{ValueField: -42, KeyField: NewDefaultFieldKey("Int")},
+		&DurationField{ValueField: time.Minute, KeyField: NewDefaultFieldKey("Duration")},
+		&BoolField{ValueField: true, Set: true, KeyField: NewDefaultFieldKey("Bool")},
+		&FloatField{ValueField: 1.25, KeyField: NewDefaultFieldKey("Float")},
+		&TimeField{ValueField: time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC), KeyField: NewDefaultFieldKey("Time")},
+		&DateField{ValueField: Date{Year: 1990, Month: time.May, Day: 4}, KeyField: NewDefaultFieldKey("Date")},
+		&UUIDField{ValueField: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"), KeyField: NewDefaultFieldKey("UUID")},
+		&BytesField{ValueField: []byte{1, 2, 3}, KeyField: NewDefaultFieldKey("Bytes")},
+		&StringField{ValueField: "hi", KeyField: NewFieldKey("Name", "json")},
+		&DecimalField{ValueField: decimal.RequireFromString("12.50"), KeyField: NewDefaultFieldKey("Decimal")},
+	}
+	for _, f := range fields {
+		out := testBinaryRoundTrip(t, f)
+		if !out.Equal(f) || out.ToString() != f.ToString() {
+			t.Errorf("%s: got %s want %s", f.Key().Name, out.ToString(), f.ToString())
+		}
+	}
+}
+
+func TestBinaryNull(t *testing.T) {
+	b, _ := AppendField(nil, &BoolField{KeyField: NewDefaultFieldKey("Bool")})
+	if b[0]&fieldBinaryNull == 0 {
+		t.Error("an unset bool is written null")
+	}
+	out := testBinaryRoundTrip(t, &BoolField{KeyField: NewDefaultFieldKey("Bool")})
+	if out.(*BoolField).Set {
+		t.Error("a null bool reads back unset")
+	}
+	// the default tag is written empty, so the header is a byte per length around the key
+	if b, _ := AppendField(nil, &IntegerField{KeyField: NewDefaultFieldKey("N")}); len(b) != 1+2+1+2 {
+		t.Errorf("got %v", b)
+	}
+}
+
+func TestBinaryFieldSet(t *testing.T) {
+	s := NewFieldSet()
+	s.Set(testField("Name", "sam"))
+	s.Set(&IntegerField{ValueField: 30, KeyField: NewDefaultFieldKey("Age")})
+	b, err := AppendFieldSet([]byte{0xff}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, n, err := ReadFieldSet(b[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(b)-1 || out.Len() != 2 {
+		t.Fatalf("got %d fields in %d bytes", out.Len(), n)
+	}
+	for i, f := range out.Fields() {
+		if !f.Equal(s.Fields()[i]) || f.Key() != s.Fields()[i].Key() {
+			t.Errorf("got %v want %v", f, s.Fields()[i])
+		}
+	}
+}
+
+func TestBinaryShort(t *testing.T) {
+	b, _ := AppendField(nil, testField("Name", "sam"))
+	for i := 0; i < len(b); i++ {
+		if _, _, err := ReadField(b[:i]); !errors.Is(err, ErrShortBinary) {
+			t.Errorf("%d bytes: got %v", i, err)
+		}
+	}
+	if _, _, err := ReadFieldSet(nil); !errors.Is(err, ErrShortBinary) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestBinarySensitive(t *testing.T) {
+	secret := NewRedactedField(testField("Token", "abc"))
+	b, err := AppendField([]byte{0xff}, secret)
+	if !errors.Is(err, ErrSensitiveField) || len(b) != 1 {
+		t.Errorf("got %v %v", b, err)
+	}
+	s := NewFieldSet(testField("Name", "sam"), secret)
+	if b, err := AppendFieldSet([]byte{0xff}, s); !errors.Is(err, ErrSensitiveField) || len(b) != 1 {
+		t.Errorf("a refused set leaves dst as it was: got %v %v", b, err)
+	}
+}
@@ -1,29 +1,37 @@
 package fielder
 
+// ConditionalFieldWDefault is a conditional field with a default, writes are checked like a FieldConditional's
+// and IsDefault works like a FieldWDefault's
 type ConditionalFieldWDefault interface {
-	Conditional
-	Field
+	ConditionalField
 	Default
+	IsDefault() bool
 }
 
 type conditionalFieldWDefault struct {
-	Conditional
+	*FieldConditional
 	Default
-	Field
 }
 
 func NewCFWD(f Field, prereqs []Prerequisite, defaultField Field) ConditionalFieldWDefault {
 	return &conditionalFieldWDefault{
-		Conditional: Conditions(prereqs...),
-		Default:     NewDefault(true, defaultField),
-		Field:       f,
+		FieldConditional: &FieldConditional{Field: f, Conditional: Conditions(prereqs...)},
+		Default:          NewDefault(true, defaultField),
 	}
 }
 
+// the field starts out as a copy of the default, so writing to it leaves the default alone
 func NewEmptyCFWD(prereqs []Prerequisite, defaultField Field) ConditionalFieldWDefault {
+	f := CreateFieldFromType(defaultField.Type(), defaultField.Value(), defaultField.Key())
+	if f == nil {
+		f = defaultField
+	}
 	return &conditionalFieldWDefault{
-		Conditional: Conditions(prereqs...),
-		Default:     NewDefault(false, defaultField),
-		Field:       defaultField,
+		FieldConditional: &FieldConditional{Field: f, Conditional: Conditions(prereqs...)},
+		Default:          NewDefault(false, defaultField),
 	}
 }
+
+func (s *conditionalFieldWDefault) IsDefault() bool {
+	return s.Default.MatchesDefault(s.Field) && !s.Default.ExplicitlySet()
+}
@@ -0,0 +1,67 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCFWDSetValue(t *testing.T) {
+	f := NewEmptyCFWD(testNotBad().Prerequisites(), testField("Region", "us"))
+	if !f.IsDefault() || f.ToString() != "us" {
+		t.Fatalf("an empty field starts out defaulted: got %s %v", f.ToString(), f.IsDefault())
+	}
+	f.SetValue(testField("Region", "bad"))
+	if f.ToString() != "us" || !f.IsDefault() {
+		t.Errorf("a rejected write keeps the default: got %s %v", f.ToString(), f.IsDefault())
+	}
+	if err := f.(TrySetter).TrySetValue(testField("Region", "bad")); !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("got %v", err)
+	}
+	f.SetValue(testField("Region", "eu"))
+	if f.ToString() != "eu" || f.IsDefault() {
+		t.Errorf("got %s %v", f.ToString(), f.IsDefault())
+	}
+	if f.DefaultField().ToString() != "us" {
+		t.Errorf("writing to the field leaves the default alone: got %s", f.DefaultField().ToString())
+	}
+	// writing the default again reads as defaulted, the default was never explicitly set
+	f.SetValue(testField("Region", "us"))
+	if !f.IsDefault() {
+		t.Error("expected the field to be defaulted")
+	}
+}
+
+func TestCFWDExplicitlySet(t *testing.T) {
+	f := NewCFWD(testField("Region", "us"), testNotBad().Prerequisites(), testField("Region", "us"))
+	if !f.ExplicitlySet() || !f.MatchesDefault(f) || f.IsDefault() {
+		t.Errorf("an explicitly set field is never defaulted: got %v %v", f.ExplicitlySet(), f.IsDefault())
+	}
+	f.SetValue(testField("Region", "eu"))
+	f.SetValue(testField("Region", "us"))
+	if f.IsDefault() {
+		t.Error("an explicitly set field is never defaulted")
+	}
+	f.SetValue(testField("Region", "bad"))
+	if f.ToString() != "us" {
+		t.Errorf("got %s", f.ToString())
+	}
+}
+
+func TestGobCFWD(t *testing.T) {
+	type snapshot struct {
+		Region Field
+	}
+	in := snapshot{Region: NewEmptyCFWD(testNotBad().Prerequisites(), testField("Region", "us"))}
+	in.Region.SetValue(testField("Region", "eu"))
+	var out snapshot
+	testGobRoundTrip(t, in, &out)
+	r, ok := out.Region.(ConditionalFieldWDefault)
+	if !ok || r.ToString() != "eu" || r.DefaultField().ToString() != "us" || r.IsDefault() {
+		t.Fatalf("got %#v", out.Region)
+	}
+	// a decoded field has no prerequisites, but can still be written
+	r.SetValue(testField("Region", "bad"))
+	if r.ToString() != "bad" {
+		t.Errorf("got %s", r.ToString())
+	}
+}
@@ -0,0 +1,75 @@
+package fielder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+)
+
+type BytesField struct {
+	ValueField []byte   `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey `dynamodbav:"key" json:"key"`
+}
+
+func (s *BytesField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *BytesField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *BytesField) Type() reflect.Type {
+	return reflect.TypeOf([]byte{})
+}
+
+// byte slices are compared lexicographically
+func (s *BytesField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return bytes.Compare(s.ValueField, in2.(*BytesField).ValueField) < 0
+}
+
+func (s *BytesField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return bytes.Compare(s.ValueField, in2.(*BytesField).ValueField) > 0
+}
+
+func (s *BytesField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return bytes.Equal(s.ValueField, in2.(*BytesField).ValueField)
+}
+
+// the string form is standard base64 so arbitrary binary survives text based storage
+func (s *BytesField) ToString() string {
+	return base64.StdEncoding.EncodeToString(s.ValueField)
+}
+
+func (s *BytesField) FromString(st string) {
+	b, err := base64.StdEncoding.DecodeString(st)
+	if err != nil {
+		return
+	}
+	s.ValueField = b
+}
+
+func (s *BytesField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	// copy so the field does not share a backing array with the caller
+	s.ValueField = bytes.Clone(in2.(*BytesField).ValueField)
+	return
+}
+
+func (s *BytesField) IsEmpty() bool {
+	return len(s.ValueField) == 0
+}
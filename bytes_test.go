@@ -0,0 +1,54 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBytesFieldCompare(t *testing.T) {
+	a := &BytesField{ValueField: []byte{1, 2}}
+	b := &BytesField{ValueField: []byte{1, 3}}
+	if !a.LessThan(b) || a.GreaterThan(b) || !b.GreaterThan(a) {
+		t.Error("bytes are compared lexicographically")
+	}
+	if !a.Equal(&BytesField{ValueField: []byte{1, 2}}) || a.Equal(b) {
+		t.Error("expected equal bytes to compare equal")
+	}
+}
+
+func TestBytesFieldString(t *testing.T) {
+	f := &BytesField{ValueField: []byte{0, 255, 'a'}}
+	st := f.ToString()
+	if st != "AP9h" {
+		t.Fatalf("ToString: got %q", st)
+	}
+	g := &BytesField{}
+	g.FromString(st)
+	if !g.Equal(f) {
+		t.Errorf("FromString: got %v", g.ValueField)
+	}
+	g.FromString("not base64!")
+	if !g.Equal(f) {
+		t.Error("invalid base64 changed the value")
+	}
+}
+
+func TestBytesFieldSetValueCopies(t *testing.T) {
+	src := &BytesField{ValueField: []byte("abc")}
+	f := &BytesField{}
+	f.SetValue(src)
+	src.ValueField[0] = 'x'
+	if string(f.ValueField) != "abc" {
+		t.Errorf("SetValue shares the caller's bytes: got %q", f.ValueField)
+	}
+	if f.IsEmpty() || !(&BytesField{}).IsEmpty() {
+		t.Error("only a field without bytes is empty")
+	}
+}
+
+func TestCreateBytesField(t *testing.T) {
+	f := CreateFieldFromType(reflect.TypeOf([]byte{}), []byte("a"), NewDefaultFieldKey("Blob"))
+	if b, ok := f.(*BytesField); !ok || string(b.ValueField) != "a" {
+		t.Fatalf("got %#v", f)
+	}
+}
@@ -0,0 +1,116 @@
+package fielder
+
+import "reflect"
+
+// Compute derives a field's value from the other fields of its parent, ex: Total = Price * Qty
+type Compute func(p Parent) FieldValue
+
+// ComputedField has no stored value, it runs Compute against its parent every time it is read
+// writes are rejected unless AllowOverride is set, in which case the written value replaces the computed one
+// until ClearOverride is called
+type ComputedField struct {
+	KeyField      FieldKey     `dynamodbav:"key" json:"key"`
+	ValueType     reflect.Type `dynamodbav:"-" json:"-"`
+	Parent        Parent       `dynamodbav:"-" json:"-"`
+	Compute       Compute      `dynamodbav:"-" json:"-"`
+	AllowOverride bool         `dynamodbav:"-" json:"-"`
+	override      Field
+}
+
+// valueType is the type Compute returns, ex: reflect.TypeOf(decimal.Decimal{})
+func NewComputedField(key FieldKey, valueType reflect.Type, parent Parent, compute Compute) *ComputedField {
+	return &ComputedField{
+		KeyField:  key,
+		ValueType: valueType,
+		Parent:    parent,
+		Compute:   compute,
+	}
+}
+
+// the field holding the current value, either the override or a freshly computed one
+func (s *ComputedField) current() Field {
+	if s.override != nil {
+		return s.override
+	}
+	var v FieldValue
+	if s.Compute != nil && s.Parent != nil {
+		v = s.Compute(s.Parent)
+	}
+	if f := CreateFieldFromType(s.ValueType, v, s.KeyField); f != nil {
+		return f
+	}
+	return FieldNil
+}
+
+func (s *ComputedField) IsOverridden() bool {
+	return s.override != nil
+}
+
+// goes back to computing the value from the parent
+func (s *ComputedField) ClearOverride() {
+	s.override = nil
+}
+
+func (s *ComputedField) Value() FieldValue {
+	return s.current().Value()
+}
+
+func (s *ComputedField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *ComputedField) Type() reflect.Type {
+	return s.ValueType
+}
+
+func (s *ComputedField) LessThan(in2 any) bool {
+	return s.current().LessThan(unwrapComputed(in2))
+}
+
+func (s *ComputedField) GreaterThan(in2 any) bool {
+	return s.current().GreaterThan(unwrapComputed(in2))
+}
+
+func (s *ComputedField) Equal(in2 any) bool {
+	return s.current().Equal(unwrapComputed(in2))
+}
+
+func (s *ComputedField) ToString() string {
+	return s.current().ToString()
+}
+
+func (s *ComputedField) FromString(st string) {
+	if !s.AllowOverride {
+		return
+	}
+	f := CreateFieldFromType(s.ValueType, nil, s.KeyField)
+	if f == nil {
+		return
+	}
+	f.FromString(st)
+	s.override = f
+}
+
+func (s *ComputedField) SetValue(in2 FieldValue) {
+	if !s.AllowOverride {
+		return
+	}
+	f := CreateFieldFromType(s.ValueType, nil, s.KeyField)
+	if f == nil {
+		return
+	}
+	f.SetValue(unwrapComputed(in2))
+	s.override = f
+}
+
+func (s *ComputedField) IsEmpty() bool {
+	return s.current().IsEmpty()
+}
+
+// compare and set against the current value of another computed field
+func unwrapComputed(in any) any {
+	if c, ok := in.(*ComputedField); ok {
+		return c.current()
+	}
+	return in
+}
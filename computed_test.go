@@ -0,0 +1,69 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testOrder struct {
+	Price int `field:"Price"`
+	Qty   int `field:"Qty"`
+}
+
+func testTotal(o *testOrder) *ComputedField {
+	return NewComputedField(NewDefaultFieldKey("Total"), reflect.TypeOf(0), DefaultParent(o), func(p Parent) FieldValue {
+		price := p.GetReflectValueOfKey(NewDefaultFieldKey("Price")).Int()
+		qty := p.GetReflectValueOfKey(NewDefaultFieldKey("Qty")).Int()
+		return int(price * qty)
+	})
+}
+
+func TestComputedFieldFollowsParent(t *testing.T) {
+	o := &testOrder{Price: 3, Qty: 2}
+	total := testTotal(o)
+	if total.ToString() != "6" {
+		t.Fatalf("got %q", total.ToString())
+	}
+	o.Qty = 5
+	if total.Value() != 15 {
+		t.Errorf("the value is computed when it is read: got %v", total.Value())
+	}
+	if total.Type() != reflect.TypeOf(0) || total.Key() != NewDefaultFieldKey("Total") {
+		t.Error("the type and key are the ones given")
+	}
+}
+
+func TestComputedFieldOverride(t *testing.T) {
+	o := &testOrder{Price: 3, Qty: 2}
+	total := testTotal(o)
+	total.SetValue(CreateFieldFromType(reflect.TypeOf(0), 100, FieldKeyNil))
+	total.FromString("100")
+	if total.IsOverridden() || total.ToString() != "6" {
+		t.Errorf("writes are rejected without AllowOverride: got %q", total.ToString())
+	}
+	total.AllowOverride = true
+	total.FromString("100")
+	o.Qty = 5
+	if !total.IsOverridden() || total.ToString() != "100" {
+		t.Errorf("the override replaces the computed value: got %q", total.ToString())
+	}
+	total.ClearOverride()
+	if total.ToString() != "15" {
+		t.Errorf("ClearOverride goes back to computing: got %q", total.ToString())
+	}
+}
+
+func TestComputedFieldCompare(t *testing.T) {
+	a := testTotal(&testOrder{Price: 3, Qty: 2})
+	b := testTotal(&testOrder{Price: 4, Qty: 2})
+	if !a.LessThan(b) || !b.GreaterThan(a) || a.Equal(b) {
+		t.Error("computed fields compare by their current values")
+	}
+	six := CreateFieldFromType(reflect.TypeOf(0), 6, FieldKeyNil)
+	if !a.Equal(six) || !six.Equal(a) {
+		t.Error("a computed field equals a plain field of its value, either way round")
+	}
+	if (&ComputedField{ValueType: reflect.TypeOf(0)}).Value() != 0 {
+		t.Error("a computed field without a parent is the zero value")
+	}
+}
@@ -1,15 +1,61 @@
 package fielder
 
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrConditionNotMet = errors.New("field does not meet its conditions")
+
 type ConditionalField interface {
 	Field
 	Conditional
+	// evaluates a write the way SetValue would, without making it
+	WouldSet(intended FieldValue) (bool, Result)
 }
 
 type Prerequisite struct {
+	// optional, used in evaluation results and documentation
+	Name        string
+	Description string
 	// function that determines if we should apply the checks
 	IsCandidate Enforceable
 	// set of required functions to run
 	Gauntlet []Question
+	// required functions with names, run after the gauntlet
+	Checks []NamedQuestion
+	// the questions do not depend on each other and are asked at the same time, see MeetsCtx
+	Concurrent bool
+	// a SeverityWarning prerequisite never rejects a value, its failed checks are warnings on the Result of Evaluate
+	Severity Severity
+	// the prerequisite passes when this many of its questions pass, ex: 2 of 3 risk checks, 0 means all of them
+	Quorum int
+}
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+// NamedQuestion is a Question with a name, ex: NamedQuestion{Name: "green", Description: "only green things have color", Question: isGreen}
+// a check that calls another service sets Ask instead of Question
+type NamedQuestion struct {
+	Name        string
+	Description string
+	Question    Question
+	Ask         ContextQuestion
+}
+
+// returns the gauntlet followed by the checks
+func (p Prerequisite) questions() []NamedQuestion {
+	out := make([]NamedQuestion, 0, len(p.Gauntlet)+len(p.Checks))
+	for _, v := range p.Gauntlet {
+		out = append(out, NamedQuestion{Question: v})
+	}
+	return append(out, p.Checks...)
 }
 
 type Enforceable func(f any) bool
@@ -43,25 +89,17 @@ func (c *conditional) Prerequisites() []Prerequisite {
 	return c.prereqs
 }
 
+// a check that fails with an error does not meet the conditional, use MeetsCtx to tell the two apart
 func (c *conditional) Meets(toSet any) bool {
-	for _, v := range c.prereqs {
-		// if its a candidate for this prerequisite, then we test
-		if v.IsCandidate(toSet) {
-			// run through all the tests
-			for _, w := range v.Gauntlet {
-				if !w()(toSet) {
-					// if one of the tests fails, we reject
-					return false
-				}
-			}
-		}
-	}
-	return true
+	ok, err := c.MeetsCtx(context.Background(), toSet)
+	return ok && err == nil
 }
 
 type FieldConditional struct {
 	Field
 	Conditional
+	// set by CheckOnDecode
+	decodeReport *DecodeReport
 }
 
 func NewConditionalField(field Field, cond Conditional) ConditionalField {
@@ -81,6 +119,44 @@ func (s *FieldConditional) SetValue(intendedToSet FieldValue) {
 	return
 }
 
+// TrySetter is a field whose writes can be rejected, unlike SetValue a rejected TrySetValue is never silent
+type TrySetter interface {
+	TrySetValue(FieldValue) error
+}
+
+// TrySetValue sets the value when it meets the conditional, otherwise it returns a FieldError holding a *ConditionError with the failed checks
+func (s *FieldConditional) TrySetValue(intendedToSet FieldValue) error {
+	fieldIntended, ok := intendedToSet.(Field)
+	if !ok {
+		return NewFieldError(s.Key(), fmt.Errorf("%w: %T is not a field", ErrConditionNotMet, intendedToSet))
+	}
+	if err := s.SetValueResult(fieldIntended).Err(); err != nil {
+		return NewFieldError(s.Key(), err)
+	}
+	return nil
+}
+
+// WouldSet reports whether SetValue would write the value, with the same evaluation TrySetValue makes, nothing is written
+// ex: pre-validating a form with the rules used on write
+func (s *FieldConditional) WouldSet(intended FieldValue) (bool, Result) {
+	fieldIntended, ok := intended.(Field)
+	if !ok {
+		return false, Result{Failures: []Failure{{Prerequisite: -1, Question: -1, Check: fmt.Sprintf("%T is not a field", intended)}}}
+	}
+	r := Evaluate(s.Conditional, fieldIntended)
+	return r.Met, r
+}
+
+// SetValueResult sets the value when it meets the conditional and returns the evaluation, with the warnings of the write, ex:
+// accept a row and flag it for review when r.Met && len(r.Warnings) > 0
+func (s *FieldConditional) SetValueResult(intendedToSet Field) Result {
+	r := Evaluate(s.Conditional, intendedToSet)
+	if r.Met {
+		s.Field.SetValue(intendedToSet)
+	}
+	return r
+}
+
 // example vars to illustrate the idea
 /*
 our type, ExampleParent, looks like this
@@ -0,0 +1,88 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// actor rules decide who may write a field next to the field's other rules, instead of in the handlers, ex:
+// Conditions(OnlyRoles("admin")) on a Status field
+// the actor is read from the context, so they are asked with MeetsCtx, EvaluateCtx or TrySetValueCtx,
+// asked without a context that holds an actor they fail with ErrNoActor
+
+var ErrNoActor = errors.New("no actor in context")
+
+// Actor is who is making a write, ex: the claims of the request's token
+type Actor interface {
+	HasRole(role string) bool
+}
+
+// Roles is an Actor that is nothing but its roles
+type Roles []string
+
+func (r Roles) HasRole(role string) bool {
+	for _, v := range r {
+		if v == role {
+			return true
+		}
+	}
+	return false
+}
+
+type actorKey struct{}
+
+// WithActor returns a context holding the actor, it can be any type, ex: the claims a middleware parsed
+func WithActor(ctx context.Context, actor any) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor the context holds, when it is an A
+func ActorFrom[A any](ctx context.Context) (A, bool) {
+	a, ok := ctx.Value(actorKey{}).(A)
+	return a, ok
+}
+
+// Authorize is a prerequisite met when allow returns true for the actor of the context and the value, ex:
+// Authorize("owner", func(c Claims, toSet any) bool { return c.UserID == owner })
+// a context without an actor of type A fails it with ErrNoActor
+func Authorize[A any](name string, allow func(actor A, toSet any) bool) Prerequisite {
+	return Prerequisite{
+		Name:        name,
+		IsCandidate: EnforceableTrue,
+		Checks: []NamedQuestion{{Name: name, Ask: func(ctx context.Context, toSet any) (bool, error) {
+			actor, ok := ActorFrom[A](ctx)
+			if !ok {
+				return false, fmt.Errorf("%w: want %s", ErrNoActor, reflect.TypeOf((*A)(nil)).Elem())
+			}
+			return allow(actor, toSet), nil
+		}}},
+	}
+}
+
+// OnlyRoles is a prerequisite met when the actor has one of the roles
+func OnlyRoles(roles ...string) Prerequisite {
+	return Authorize(fmt.Sprintf("only %s", strings.Join(roles, " or ")), func(actor Actor, _ any) bool {
+		for _, v := range roles {
+			if actor.HasRole(v) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// TrySetValueCtx is TrySetValue with the questions asked with the context, ex: one made by WithActor
+func (s *FieldConditional) TrySetValueCtx(ctx context.Context, intendedToSet FieldValue) error {
+	fieldIntended, ok := intendedToSet.(Field)
+	if !ok {
+		return NewFieldError(s.Key(), fmt.Errorf("%w: %T is not a field", ErrConditionNotMet, intendedToSet))
+	}
+	if err := EvaluateCtx(ctx, s.Conditional, fieldIntended).Err(); err != nil {
+		return NewFieldError(s.Key(), err)
+	}
+	s.Field.SetValue(fieldIntended)
+	return nil
+}
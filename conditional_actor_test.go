@@ -0,0 +1,77 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testClaims struct {
+	UserID string
+}
+
+func TestActorFrom(t *testing.T) {
+	ctx := WithActor(context.Background(), testClaims{UserID: "u1"})
+	if c, ok := ActorFrom[testClaims](ctx); !ok || c.UserID != "u1" {
+		t.Errorf("got %v %v", c, ok)
+	}
+	if _, ok := ActorFrom[Actor](ctx); ok {
+		t.Error("claims are not an Actor")
+	}
+	if _, ok := ActorFrom[testClaims](context.Background()); ok {
+		t.Error("expected no actor")
+	}
+	if !(Roles{"admin", "support"}).HasRole("support") || (Roles{"admin"}).HasRole("owner") {
+		t.Error("Roles has its roles and nothing else")
+	}
+}
+
+func TestOnlyRoles(t *testing.T) {
+	c := Conditions(OnlyRoles("admin", "support"))
+	if ok, err := MeetsCtx(WithActor(context.Background(), Roles{"support"}), c, "x"); !ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+	r := EvaluateCtx(WithActor(context.Background(), Roles{"viewer"}), c, "x")
+	if r.Met || len(r.Failures) != 1 || r.Failures[0].Check != "only admin or support" {
+		t.Errorf("got %+v", r)
+	}
+	// asked without an actor it fails with ErrNoActor
+	if ok, err := MeetsCtx(context.Background(), c, "x"); ok || !errors.Is(err, ErrNoActor) || !strings.Contains(err.Error(), "fielder.Actor") {
+		t.Errorf("got %v %v", ok, err)
+	}
+	if c.Meets("x") {
+		t.Error("Meets has no actor to ask about")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	owner := Authorize("owner", func(c testClaims, toSet any) bool {
+		return c.UserID == "u1" && toSet.(Field).ToString() != "bad"
+	})
+	f := NewConditionalField(testField("Status", "draft"), Conditions(owner)).(*FieldConditional)
+	ctx := WithActor(context.Background(), testClaims{UserID: "u1"})
+	if err := f.TrySetValueCtx(ctx, testField("Status", "live")); err != nil || f.ToString() != "live" {
+		t.Errorf("got %s %v", f.ToString(), err)
+	}
+	if err := f.TrySetValueCtx(ctx, testField("Status", "bad")); !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("the value is passed to allow: got %v", err)
+	}
+	other := WithActor(context.Background(), testClaims{UserID: "u2"})
+	var ferr FieldError
+	if err := f.TrySetValueCtx(other, testField("Status", "gone")); !errors.As(err, &ferr) || ferr.Key != f.Key() {
+		t.Errorf("got %v", err)
+	}
+	// an actor of another type is no actor, the failed check holds the error
+	err := f.TrySetValueCtx(WithActor(context.Background(), Roles{"admin"}), testField("Status", "gone"))
+	var cerr *ConditionError
+	if !errors.As(err, &cerr) || len(cerr.Failures) != 1 || !errors.Is(cerr.Failures[0].Err, ErrNoActor) {
+		t.Errorf("got %v", err)
+	}
+	if err := f.TrySetValueCtx(ctx, "gone"); !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("got %v", err)
+	}
+	if f.ToString() != "live" {
+		t.Errorf("rejected writes keep the value: got %s", f.ToString())
+	}
+}
@@ -0,0 +1,171 @@
+package fielder
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextQuestion is a check that can wait on, or fail to reach, something outside the process, ex: a feature flag service
+type ContextQuestion func(ctx context.Context, toSet any) (bool, error)
+
+// ContextConditional is a Conditional whose checks take a context and can fail
+type ContextConditional interface {
+	Conditional
+	MeetsCtx(ctx context.Context, toSet any) (bool, error)
+}
+
+// MeetsCtx asks the conditional about the value with the context, an error is a check that could not be made
+// conditionals that take no context are asked with Meets, or MeetsErr when they are a FallibleConditional
+func MeetsCtx(ctx context.Context, c Conditional, toSet any) (bool, error) {
+	return conditionalMeets(ctx, c, toSet)
+}
+
+// MeetsCtx asks the questions of each prerequisite in order and stops at the first that fails or errors
+// the questions of a Concurrent prerequisite are asked at the same time, the ones still running are cancelled when one fails
+func (c *conditional) MeetsCtx(ctx context.Context, toSet any) (bool, error) {
+	for _, v := range c.prereqs {
+		// if its a candidate for this prerequisite, then we test, warnings never reject
+		if v.Severity == SeverityWarning || !v.IsCandidate(toSet) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		ok, err := v.meets(ctx, toSet)
+		if err != nil || !ok {
+			// if one of the tests fails, we reject
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (c *conditional) MeetsErr(toSet any) (bool, error) {
+	return c.MeetsCtx(context.Background(), toSet)
+}
+
+// the number of questions of the prerequisite that have to pass, all of them unless it has a Quorum
+func (p Prerequisite) needed(questions int) int {
+	if p.Quorum > 0 {
+		return p.Quorum
+	}
+	return questions
+}
+
+// asks the questions until the prerequisite is decided, a question that errors counts as failed
+// the error is returned when the prerequisite fails
+func (p Prerequisite) meets(ctx context.Context, toSet any) (bool, error) {
+	questions := p.questions()
+	need := p.needed(len(questions))
+	passed, failed := 0, 0
+	var firstErr error
+	// reports whether the answer decided the prerequisite
+	count := func(ok bool, err error) bool {
+		if ok && err == nil {
+			passed++
+		} else {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		return passed >= need || len(questions)-failed < need
+	}
+	if need == 0 || len(questions)-failed < need {
+		return passed >= need, nil
+	}
+	if !p.Concurrent || len(questions) < 2 {
+		for _, w := range questions {
+			if count(w.ask(ctx, toSet)) {
+				break
+			}
+		}
+		if passed >= need {
+			return true, nil
+		}
+		return false, firstErr
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		decided bool
+		met     bool
+		err     error
+	)
+	for _, w := range questions {
+		wg.Add(1)
+		go func(w NamedQuestion) {
+			defer wg.Done()
+			ok, qerr := w.ask(ctx, toSet)
+			mu.Lock()
+			defer mu.Unlock()
+			// the answers of questions cancelled once the prerequisite was decided are not counted
+			if decided {
+				return
+			}
+			if count(ok, qerr) {
+				decided, met = true, passed >= need
+				if !met {
+					err = firstErr
+				}
+				cancel()
+			}
+		}(w)
+	}
+	wg.Wait()
+	return met, err
+}
+
+func (q NamedQuestion) ask(ctx context.Context, toSet any) (bool, error) {
+	if q.Ask != nil {
+		return q.Ask(ctx, toSet)
+	}
+	return q.Question()(toSet), nil
+}
+
+// a child that errors did not meet the value, like in Evaluate, so Or and Xor go on to the next child
+// the first error is returned when the combination is not met
+func (c *logicalConditional) MeetsCtx(ctx context.Context, toSet any) (bool, error) {
+	met := 0
+	var firstErr error
+	for _, v := range c.conds {
+		ok, err := conditionalMeets(ctx, v, toSet)
+		if err != nil {
+			ok = false
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if ok {
+			met++
+		}
+		switch {
+		case c.op == logicalAnd && !ok:
+			return false, err
+		case c.op == logicalOr && ok:
+			return true, nil
+		case c.op == logicalXor && met > 1:
+			return false, nil
+		}
+	}
+	switch c.op {
+	case logicalOr:
+		return false, firstErr
+	case logicalXor:
+		if met == 1 {
+			return true, nil
+		}
+		return false, firstErr
+	}
+	return true, nil
+}
+
+func (c *notConditional) MeetsCtx(ctx context.Context, toSet any) (bool, error) {
+	ok, err := conditionalMeets(ctx, c.cond, toSet)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
@@ -0,0 +1,116 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testCtxKey struct{}
+
+// a check that reads the context, and fails with errTestLookup when it holds nothing
+func testAskCtx(name string) NamedQuestion {
+	return NamedQuestion{Name: name, Ask: func(ctx context.Context, toSet any) (bool, error) {
+		want, ok := ctx.Value(testCtxKey{}).(string)
+		if !ok {
+			return false, errTestLookup
+		}
+		return toSet == want, nil
+	}}
+}
+
+func TestMeetsCtx(t *testing.T) {
+	c := Conditions(Prerequisite{Name: "flag", IsCandidate: EnforceableTrue, Checks: []NamedQuestion{testAskCtx("on")}})
+	ctx := context.WithValue(context.Background(), testCtxKey{}, "on")
+	if ok, err := MeetsCtx(ctx, c, "on"); !ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+	if ok, err := MeetsCtx(ctx, c, "off"); ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+	// a check that could not be made is an error, and does not meet the conditional
+	if ok, err := c.(FallibleConditional).MeetsErr("on"); ok || !errors.Is(err, errTestLookup) {
+		t.Errorf("got %v %v", ok, err)
+	}
+	if c.Meets("on") {
+		t.Error("got true")
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := MeetsCtx(cancelled, c, "on"); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v", err)
+	}
+	// conditionals that take no context are asked with Meets
+	var n int
+	if ok, err := MeetsCtx(ctx, testOpaque{testAnswer(true, &n)}, nil); !ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+}
+
+func TestLogicalMeetsCtxAgreesWithEvaluate(t *testing.T) {
+	var n int
+	yes, no := testAnswer(true, &n), testAnswer(false, &n)
+	failing := testFallible{Conditional: yes, err: errTestLookup}
+	for _, c := range []struct {
+		name string
+		c    Conditional
+		met  bool
+		err  error
+	}{
+		{"or goes on past an error", OrCond(failing, yes), true, nil},
+		{"or that fails keeps the error", OrCond(failing, no), false, errTestLookup},
+		{"and stops at an error", AndCond(yes, failing, yes), false, errTestLookup},
+		{"xor goes on past an error", XorCond(failing, yes), true, nil},
+		{"xor that fails keeps the error", XorCond(failing, no), false, errTestLookup},
+		{"xor of two", XorCond(failing, yes, yes), false, nil},
+	} {
+		ok, err := c.c.(ContextConditional).MeetsCtx(context.Background(), nil)
+		if ok != c.met || !errors.Is(err, c.err) || (c.err == nil && err != nil) {
+			t.Errorf("%s: got %v %v", c.name, ok, err)
+		}
+		if r := Evaluate(c.c, nil); r.Met != ok {
+			t.Errorf("%s: Evaluate got %v, MeetsCtx got %v", c.name, r.Met, ok)
+		}
+	}
+}
+
+func TestConcurrentPrerequisite(t *testing.T) {
+	var running, most atomic.Int32
+	slow := func(ok bool) NamedQuestion {
+		return NamedQuestion{Ask: func(ctx context.Context, toSet any) (bool, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+			}
+			if !ok {
+				time.Sleep(50 * time.Millisecond)
+				return false, nil
+			}
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case <-time.After(time.Second):
+				return true, nil
+			}
+		}}
+	}
+	c := Conditions(Prerequisite{IsCandidate: EnforceableTrue, Concurrent: true, Checks: []NamedQuestion{slow(true), slow(true), slow(false)}})
+	start := time.Now()
+	ok, err := MeetsCtx(context.Background(), c, nil)
+	if ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+	// the failed question cancels the ones still running
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("took %s", time.Since(start))
+	}
+	if most.Load() < 2 {
+		t.Errorf("the questions are asked at the same time: at most %d ran at once", most.Load())
+	}
+	all := Conditions(Prerequisite{IsCandidate: EnforceableTrue, Concurrent: true, Checks: []NamedQuestion{testAskCtx("a"), testAskCtx("b")}})
+	if ok, err := MeetsCtx(context.Background(), all, "a"); ok || !errors.Is(err, errTestLookup) {
+		t.Errorf("got %v %v", ok, err)
+	}
+}
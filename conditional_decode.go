@@ -0,0 +1,231 @@
+package fielder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// decoders write the fields they parse directly, so without these a conditional is never asked about what was read from csv, json or dynamo
+// there are three ways to have decoded values asked:
+// ReadCSV with WithDecodeConditions, CheckOnDecode on a conditional field before it is decoded into,
+// and CheckDecoded on a parent something else decoded
+// a value that does not meet its conditional is dropped and recorded in a DecodeReport instead of failing the whole decode
+
+// Rejection is a decoded value a conditional turned away
+type Rejection struct {
+	// the index of the record the value was read from, ex: the csv row not counting the header, -1 when it was not read as part of a list
+	Row   int
+	Key   FieldKey
+	Value string
+	Err   error
+}
+
+func (r Rejection) String() string {
+	if r.Row < 0 {
+		return fmt.Sprintf("%s %q: %s", r.Key.Name, r.Value, r.Err)
+	}
+	return fmt.Sprintf("row %d: %s %q: %s", r.Row, r.Key.Name, r.Value, r.Err)
+}
+
+// DecodeReport collects the rejections of one or more decodes, it can be shared between decodes running at the same time
+type DecodeReport struct {
+	mu         sync.Mutex
+	rejections []Rejection
+}
+
+// Rejections returns the rejections in the order they were made
+func (r *DecodeReport) Rejections() []Rejection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Rejection{}, r.rejections...)
+}
+
+// Err returns the rejections as FieldErrors, or nil when nothing was rejected
+func (r *DecodeReport) Err() error {
+	rejections := r.Rejections()
+	if len(rejections) == 0 {
+		return nil
+	}
+	errs := make(FieldErrors, 0, len(rejections))
+	for _, v := range rejections {
+		err := v.Err
+		if v.Row >= 0 {
+			err = fmt.Errorf("row %d: %w", v.Row, err)
+		}
+		errs = append(errs, NewFieldError(v.Key, err))
+	}
+	return errs
+}
+
+func (r *DecodeReport) reject(row int, key FieldKey, f Field, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rejections = append(r.rejections, Rejection{Row: row, Key: key, Value: f.ToString(), Err: err})
+}
+
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	conditions map[FieldKey]Conditional
+	report     *DecodeReport
+}
+
+// asks the conditional for their key about the decoded values, a rejected value is left out of the record and added to the report
+// with a nil report the first rejection fails the decode instead
+func WithDecodeConditions(conditions map[FieldKey]Conditional, report *DecodeReport) DecodeOption {
+	return func(o *decodeOptions) {
+		o.conditions = conditions
+		o.report = report
+	}
+}
+
+func newDecodeOptions(opts []DecodeOption) *decodeOptions {
+	o := &decodeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// returns false when the field was rejected and should be left out, the error is only set when there is no report to add it to
+func (o *decodeOptions) check(row int, f Field) (bool, error) {
+	c := o.conditions[f.Key()]
+	if c == nil {
+		return true, nil
+	}
+	err := Evaluate(c, f).Err()
+	if err == nil {
+		return true, nil
+	}
+	if o.report == nil {
+		return false, NewFieldError(f.Key(), fmt.Errorf("row %d: %w", row, err))
+	}
+	o.report.reject(row, f.Key(), f, err)
+	return false, nil
+}
+
+// CheckDecoded asks the conditions about the fields of a parent that something else decoded, ex: after json.Unmarshal or attributevalue.UnmarshalMap
+// a field that does not meet its conditional is reset to its zero value and added to the report with the row, use -1 when the parent is not one of a list
+// nil members were not decoded and are not asked, other members are asked about the value they hold, even a zero one
+func CheckDecoded[P any](p *P, row int, conditions map[FieldKey]Conditional, report *DecodeReport) {
+	pv := reflect.ValueOf(p).Elem()
+	keys := make([]FieldKey, 0, len(conditions))
+	for k := range conditions {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	for _, k := range keys {
+		member := reflectValueByPath(pv, k.Name)
+		if conditions[k] == nil || !member.IsValid() || isNilMember(member) {
+			continue
+		}
+		f := memberAsField(member, k)
+		if f == nil || f == FieldNil || isNilField(f) {
+			continue
+		}
+		if err := Evaluate(conditions[k], f).Err(); err != nil {
+			report.reject(row, k, f, err)
+			if member.CanSet() {
+				member.Set(reflect.Zero(member.Type()))
+			}
+		}
+	}
+}
+
+// true for members a decoder leaves nil when the value is missing, ex: a pointer or a Field
+func isNilMember(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// CheckOnDecode makes a conditional field ask its conditional about the values it decodes, from FromString, Scan, json or dynamo,
+// a value it does not meet is added to the report and the field keeps the value it had, a nil report stops the checks
+// it returns false when the field was not made by this package and can not be checked
+func CheckOnDecode(f ConditionalField, report *DecodeReport) bool {
+	switch v := f.(type) {
+	case *FieldConditional:
+		v.decodeReport = report
+	case *conditionalFieldWDefault:
+		v.FieldConditional.decodeReport = report
+	default:
+		return false
+	}
+	return true
+}
+
+// decodes into a copy of the field first when the field is checked on decode, and only decodes into the field when the copy meets the conditional
+// every decoded value is asked, even an empty one, ex: a json "" or a dynamo NULL
+func (s *FieldConditional) decode(decode func(Field) error) error {
+	if s.decodeReport == nil || s.Field == nil {
+		return decode(s.Field)
+	}
+	f := CreateFieldFromType(s.Field.Type(), nil, s.Field.Key())
+	if f == nil {
+		return decode(s.Field)
+	}
+	if err := decode(f); err != nil {
+		return err
+	}
+	if err := Evaluate(s.Conditional, f).Err(); err != nil {
+		s.decodeReport.reject(-1, s.Field.Key(), f, err)
+		return nil
+	}
+	return decode(s.Field)
+}
+
+func (s *FieldConditional) FromString(st string) {
+	_ = s.decode(func(f Field) error {
+		f.FromString(st)
+		return nil
+	})
+}
+
+func (s *FieldConditional) Scan(src any) error {
+	return s.decode(func(f Field) error {
+		return scanField(f, src)
+	})
+}
+
+func (s *FieldConditional) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s.Field)
+}
+
+// the type of the field is taken from the encoded type name when the field holds none
+func (s *FieldConditional) UnmarshalJSON(b []byte) error {
+	if s.Field == nil {
+		f, err := UnmarshalFieldJSON(b, nil)
+		if err != nil {
+			return err
+		}
+		s.Field = f
+		return nil
+	}
+	return s.decode(func(f Field) error {
+		return unmarshalFieldJSON(b, f)
+	})
+}
+
+func (s *FieldConditional) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s.Field)
+}
+
+func (s *FieldConditional) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	if s.Field == nil {
+		f, err := fieldFromAttributeValue(av, FieldKeyNil)
+		if err != nil {
+			return err
+		}
+		s.Field = f
+		return nil
+	}
+	return s.decode(func(f Field) error {
+		return attributeValueToField(av, f)
+	})
+}
@@ -0,0 +1,132 @@
+package fielder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// met when the field holds anything but its zero value
+func testNotZero() Conditional {
+	return Conditions(Prerequisite{
+		Name:        "set",
+		IsCandidate: EnforceableTrue,
+		Gauntlet: []Question{func() Enforceable {
+			return func(f any) bool { s := f.(Field).ToString(); return s != "" && s != "0" }
+		}},
+	})
+}
+
+func TestCheckDecoded(t *testing.T) {
+	p := testProfile{Name: "bad", Bio: testField("Bio", "")}
+	report := &DecodeReport{}
+	CheckDecoded(&p, 2, map[FieldKey]Conditional{
+		NewDefaultFieldKey("Name"):     testNotBad(),
+		NewDefaultFieldKey("Bio"):      testNotZero(),
+		NewDefaultFieldKey("Age"):      testNotZero(),
+		NewDefaultFieldKey("Nickname"): testNotZero(),
+		NewDefaultFieldKey("Note"):     testNotZero(),
+	}, report)
+	if p.Name != "" || p.Bio != nil || p.Age != 0 {
+		t.Errorf("rejected members are reset: got %#v", p)
+	}
+	// nil members were not decoded, an empty field and a zero value were
+	r := report.Rejections()
+	if len(r) != 3 || r[0].Key.Name != "Age" || r[1].Key.Name != "Bio" || r[2].Key.Name != "Name" {
+		t.Fatalf("got %v", r)
+	}
+	if r[2].Row != 2 || r[2].Value != "bad" || r[2].String() != `row 2: Name "bad": `+r[2].Err.Error() {
+		t.Errorf("got %s", r[2])
+	}
+
+	ok := testProfile{Name: "sam", Age: 3, Bio: testField("Bio", "hi")}
+	CheckDecoded(&ok, -1, map[FieldKey]Conditional{NewDefaultFieldKey("Name"): testNotBad(), NewDefaultFieldKey("Bio"): testNotZero()}, report)
+	if ok.Name != "sam" || ok.Bio.ValueField != "hi" || len(report.Rejections()) != 3 {
+		t.Errorf("got %#v %v", ok, report.Rejections())
+	}
+}
+
+func TestDecodeReportErr(t *testing.T) {
+	report := &DecodeReport{}
+	if report.Err() != nil {
+		t.Error("an empty report has no error")
+	}
+	report.reject(1, NewDefaultFieldKey("Name"), testField("Name", "bad"), ErrConditionNotMet)
+	report.reject(-1, NewDefaultFieldKey("Bio"), testField("Bio", ""), ErrConditionNotMet)
+	err := report.Err()
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || !errors.Is(err, ErrConditionNotMet) {
+		t.Fatalf("got %v", err)
+	}
+	if !strings.Contains(errs[0].Error(), "row 1") || strings.Contains(errs[1].Error(), "row") {
+		t.Errorf("got %v", errs)
+	}
+	if got := report.Rejections()[1].String(); got != `Bio "": `+ErrConditionNotMet.Error() {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCheckOnDecode(t *testing.T) {
+	report := &DecodeReport{}
+	f := NewConditionalField(testField("Bio", "old"), testNotBad()).(*FieldConditional)
+	if !CheckOnDecode(f, report) {
+		t.Fatal("a conditional field can be checked")
+	}
+	f.FromString("bad")
+	if err := f.Scan("bad"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := marshalFieldJSON(testField("Bio", "bad"))
+	if err := f.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if f.ToString() != "old" || len(report.Rejections()) != 3 {
+		t.Errorf("rejected values are not decoded: got %s %v", f.ToString(), report.Rejections())
+	}
+	if r := report.Rejections()[0]; r.Row != -1 || r.Value != "bad" || r.Key != f.Key() {
+		t.Errorf("got %+v", r)
+	}
+	f.FromString("new")
+	if f.ToString() != "new" {
+		t.Errorf("got %s", f.ToString())
+	}
+	// a nil report stops the checks
+	CheckOnDecode(f, nil)
+	f.FromString("bad")
+	if f.ToString() != "bad" || len(report.Rejections()) != 3 {
+		t.Errorf("got %s %v", f.ToString(), report.Rejections())
+	}
+}
+
+func TestCheckOnDecodeEmpty(t *testing.T) {
+	report := &DecodeReport{}
+	f := NewConditionalField(testField("Bio", "old"), testNotZero()).(*FieldConditional)
+	CheckOnDecode(f, report)
+	// empty values are asked like any other
+	f.FromString("")
+	if err := f.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if f.ToString() != "old" || len(report.Rejections()) != 2 {
+		t.Errorf("got %s %v", f.ToString(), report.Rejections())
+	}
+}
+
+func TestCheckOnDecodeCFWD(t *testing.T) {
+	report := &DecodeReport{}
+	f := NewEmptyCFWD(testNotBad().Prerequisites(), testField("Region", "us"))
+	if !CheckOnDecode(f, report) {
+		t.Fatal("a conditional field with a default can be checked")
+	}
+	if err := f.(interface{ Scan(any) error }).Scan("bad"); err != nil {
+		t.Fatal(err)
+	}
+	f.FromString("eu")
+	if f.ToString() != "eu" || len(report.Rejections()) != 1 {
+		t.Errorf("got %s %v", f.ToString(), report.Rejections())
+	}
+	// conditional fields made elsewhere can not be checked
+	if CheckOnDecode(struct{ *FieldConditional }{f.(*conditionalFieldWDefault).FieldConditional}, report) {
+		t.Error("expected a foreign conditional field to be refused")
+	}
+}
@@ -0,0 +1,127 @@
+package fielder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RuleDoc is one rule on a field, as written out for people who review the rules rather than the code
+type RuleDoc struct {
+	Field       FieldName `json:"field"`
+	Rule        string    `json:"rule"`
+	Severity    string    `json:"severity"`
+	Description string    `json:"description,omitempty"`
+}
+
+// RulesDoc is every rule on the fields of a parent type
+type RulesDoc struct {
+	Parent string    `json:"parent"`
+	Rules  []RuleDoc `json:"rules"`
+}
+
+// DescribeRules writes out the conditionals of a parent's fields, in field order, ex:
+// conds, _ := BuildConditionals[Order]()
+// b, _ := json.Marshal(DescribeRules[Order](conds))
+// each check of a conditional made with Conditions is a rule of its own, with the name, description and severity it was given
+// other conditionals are one rule each, described by the rules they combine, ex: Amount GT 100 || !(Status EQ held)
+func DescribeRules[P any](conditions map[FieldKey]Conditional) RulesDoc {
+	doc := RulesDoc{Parent: reflect.TypeOf((*P)(nil)).Elem().String(), Rules: []RuleDoc{}}
+	keys := make([]FieldKey, 0, len(conditions))
+	for k := range conditions {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	for _, k := range keys {
+		doc.Rules = append(doc.Rules, describeRules(k.Name, conditions[k])...)
+	}
+	return doc
+}
+
+func describeRules(field FieldName, c Conditional) []RuleDoc {
+	if m, ok := c.(*MemoConditional); ok {
+		return describeRules(field, m.cond)
+	}
+	cond, ok := c.(*conditional)
+	if !ok {
+		return []RuleDoc{{Field: field, Rule: describeConditional(c), Severity: SeverityError.String()}}
+	}
+	var out []RuleDoc
+	for i, p := range cond.prereqs {
+		for j, q := range p.questions() {
+			description := q.Description
+			if description == "" {
+				description = p.Description
+			}
+			out = append(out, RuleDoc{Field: field, Rule: checkName(i, p, j, q), Severity: p.Severity.String(), Description: description})
+		}
+	}
+	return out
+}
+
+// a one line description of a conditional from the names of its checks
+func describeConditional(c Conditional) string {
+	switch v := c.(type) {
+	case fmt.Stringer:
+		return v.String()
+	case *MemoConditional:
+		return describeConditional(v.cond)
+	case *notConditional:
+		return "!(" + describeConditional(v.cond) + ")"
+	case *logicalConditional:
+		op := [...]string{" && ", " || ", " xor "}[v.op]
+		parts := make([]string, len(v.conds))
+		for i, w := range v.conds {
+			parts[i] = describeOperand(w)
+		}
+		return strings.Join(parts, op)
+	case *parentConditional:
+		return "parent rule"
+	case *conditional:
+		var parts []string
+		for i, p := range v.prereqs {
+			for j, q := range p.questions() {
+				parts = append(parts, checkName(i, p, j, q))
+			}
+		}
+		return strings.Join(parts, " && ")
+	}
+	return "conditional"
+}
+
+// combined conditionals are put in parentheses when they are part of another
+func describeOperand(c Conditional) string {
+	switch v := c.(type) {
+	case *logicalConditional:
+		if len(v.conds) > 1 {
+			return "(" + describeConditional(c) + ")"
+		}
+	case *conditional:
+		if len(v.prereqs) > 1 || (len(v.prereqs) == 1 && len(v.prereqs[0].questions()) > 1) {
+			return "(" + describeConditional(c) + ")"
+		}
+	}
+	return describeConditional(c)
+}
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Markdown writes the rules out as a markdown table under a heading naming the parent
+func (d RulesDoc) Markdown() string {
+	cell := func(st string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(st, "|", `\|`), "\n", " ")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", d.Parent)
+	b.WriteString("| Field | Rule | Severity | Description |\n|---|---|---|---|\n")
+	for _, v := range d.Rules {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", cell(string(v.Field)), cell(v.Rule), v.Severity, cell(v.Description))
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package fielder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testDocumented struct {
+	Email  string `field:"Email" validate:"required,email"`
+	Amount int    `field:"Amount"`
+	Note   string `field:"Note"`
+}
+
+func TestDescribeRules(t *testing.T) {
+	conds, err := BuildConditionals[testDocumented]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, _ := ParseRule(`Amount > 100 || !(Status == held)`)
+	conds[NewDefaultFieldKey("Amount")] = Memoize(rule, testMemoKey)
+	conds[NewDefaultFieldKey("Note")] = Conditions(Prerequisite{
+		Name:        "short",
+		Description: "notes are kept short",
+		Severity:    SeverityWarning,
+		IsCandidate: EnforceableTrue,
+		Checks:      []NamedQuestion{{Name: "short", Question: testIs("")}, {Name: "plain", Description: "no markup", Question: testIs("")}},
+	})
+	doc := DescribeRules[testDocumented](conds)
+	b, _ := json.Marshal(doc)
+	want := `{"parent":"fielder.testDocumented","rules":[` +
+		`{"field":"Amount","rule":"Amount \u003e 100 || !(Status == held)","severity":"error"},` +
+		`{"field":"Email","rule":"Email: required","severity":"error"},` +
+		`{"field":"Email","rule":"Email: email","severity":"error"},` +
+		`{"field":"Note","rule":"short","severity":"warning","description":"notes are kept short"},` +
+		`{"field":"Note","rule":"short: plain","severity":"warning","description":"no markup"}]}`
+	if string(b) != want {
+		t.Errorf("got %s", b)
+	}
+	md := DescribeRules[testDocumented](map[FieldKey]Conditional{NewDefaultFieldKey("Amount"): rule}).Markdown()
+	wantMd := "## fielder.testDocumented\n\n| Field | Rule | Severity | Description |\n|---|---|---|---|\n" +
+		"| Amount | Amount > 100 \\|\\| !(Status == held) | error |  |\n"
+	if md != wantMd {
+		t.Errorf("got %q", md)
+	}
+	if doc := DescribeRules[testDocumented](nil); len(doc.Rules) != 0 {
+		t.Errorf("got %+v", doc)
+	}
+}
+
+func TestDescribeConditional(t *testing.T) {
+	a := Conditions(Prerequisite{Name: "is a", IsCandidate: EnforceableTrue, Gauntlet: []Question{testIs("a")}})
+	two := Conditions(Prerequisite{IsCandidate: EnforceableTrue, Gauntlet: []Question{testIs("a"), testIs("b")}})
+	for _, c := range []struct {
+		c    Conditional
+		want string
+	}{
+		{AndCond(a, OrCond(a, NotCond(a))), "is a: question 0 && (is a: question 0 || !(is a: question 0))"},
+		{XorCond(a, two), "is a: question 0 xor (prerequisite 0: question 0 && prerequisite 0: question 1)"},
+		{Require("Amount").GreaterThan(100), "Amount GT 100"},
+		{WithParent(testRequiresGreen, DefaultParent(&testColor{})), "parent rule"},
+		{testOpaque{a}, "conditional"},
+	} {
+		if got := describeConditional(c.c); got != c.want {
+			t.Errorf("got %q, want %q", got, c.want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidExpression = errors.New("invalid rule expression")
+
+// ParseRule builds a conditional from a rule expression, so rules can be kept in config instead of code, ex:
+// Green == true && (Amount > 100 || Status in ("held", open)) && !Archived == true
+// the left of a comparison is a field name, a dotted path reaches into nested structs, the right is a value,
+// quoted or bare, the comparisons are == != < <= > >= and in, && binds tighter than ||
+// fields are read from the value the conditional is asked about like WhenField does, so a Parent resolves its own fields
+// a value is parsed as the type of the field it is compared with, a missing or empty field, or a value that does not parse, meets nothing
+func ParseRule(expr string) (Conditional, error) {
+	toks, err := lexRule(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &ruleParser{toks: toks, expr: expr}
+	c, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != ruleEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return c, nil
+}
+
+type ruleTokenKind int
+
+const (
+	ruleEOF ruleTokenKind = iota
+	ruleWord
+	ruleString
+	ruleOp
+)
+
+type ruleToken struct {
+	kind ruleTokenKind
+	text string
+	pos  int
+}
+
+var ruleOps = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", ","}
+
+func lexRule(expr string) ([]ruleToken, error) {
+	var toks []ruleToken
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], expr[i])
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string at %d", ErrInvalidExpression, i)
+			}
+			toks = append(toks, ruleToken{kind: ruleString, text: expr[i+1 : i+1+end], pos: i})
+			i += end + 2
+		case isRuleWordChar(c):
+			start := i
+			for i < len(expr) && isRuleWordChar(rune(expr[i])) {
+				i++
+			}
+			toks = append(toks, ruleToken{kind: ruleWord, text: expr[start:i], pos: start})
+		default:
+			op := ""
+			for _, v := range ruleOps {
+				if strings.HasPrefix(expr[i:], v) {
+					op = v
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: unexpected %q at %d", ErrInvalidExpression, c, i)
+			}
+			toks = append(toks, ruleToken{kind: ruleOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(toks, ruleToken{kind: ruleEOF, pos: len(expr)}), nil
+}
+
+// words are field names and bare values, ex: Address.City, 100.5, -3, open
+func isRuleWordChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.' || c == '-' || c == ':'
+}
+
+type ruleParser struct {
+	toks []ruleToken
+	at   int
+	expr string
+}
+
+func (p *ruleParser) peek() ruleToken {
+	return p.toks[p.at]
+}
+
+func (p *ruleParser) next() ruleToken {
+	t := p.toks[p.at]
+	if t.kind != ruleEOF {
+		p.at++
+	}
+	return t
+}
+
+func (p *ruleParser) accept(op string) bool {
+	if t := p.peek(); t.kind == ruleOp && t.text == op {
+		p.at++
+		return true
+	}
+	return false
+}
+
+func (p *ruleParser) errorf(t ruleToken, format string, args ...any) error {
+	if t.kind == ruleEOF {
+		return fmt.Errorf("%w: unexpected end of %q", ErrInvalidExpression, p.expr)
+	}
+	return fmt.Errorf("%w: %s at %d", ErrInvalidExpression, fmt.Sprintf(format, args...), t.pos)
+}
+
+func (p *ruleParser) or() (Conditional, error) {
+	c, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	conds := []Conditional{c}
+	for p.accept("||") {
+		c, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, c)
+	}
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return OrCond(conds...), nil
+}
+
+func (p *ruleParser) and() (Conditional, error) {
+	c, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	conds := []Conditional{c}
+	for p.accept("&&") {
+		c, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, c)
+	}
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return AndCond(conds...), nil
+}
+
+func (p *ruleParser) unary() (Conditional, error) {
+	if p.accept("!") {
+		c, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return NotCond(c), nil
+	}
+	if p.accept("(") {
+		c, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf(p.peek(), "expected )")
+		}
+		return c, nil
+	}
+	return p.comparison()
+}
+
+func (p *ruleParser) comparison() (Conditional, error) {
+	start := p.peek()
+	field := p.next()
+	if field.kind != ruleWord {
+		return nil, p.errorf(field, "expected a field name")
+	}
+	opTok := p.next()
+	var op safeOp
+	switch {
+	case opTok.kind == ruleWord && opTok.text == "in":
+		op = EQ
+	case opTok.kind == ruleOp:
+		op = map[string]safeOp{"==": EQ, "!=": NE, "<": LT, "<=": LE, ">": GT, ">=": GE}[opTok.text]
+	}
+	if op == "" {
+		return nil, p.errorf(opTok, "expected a comparison")
+	}
+	var values []string
+	if opTok.text == "in" {
+		if !p.accept("(") {
+			return nil, p.errorf(p.peek(), "expected (")
+		}
+		for {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+			if p.accept(")") {
+				break
+			}
+			if !p.accept(",") {
+				return nil, p.errorf(p.peek(), "expected , or )")
+			}
+		}
+	} else {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = []string{v}
+	}
+	// the comparison is named by its text in the expression
+	end := p.toks[p.at-1]
+	endPos := end.pos + len(end.text)
+	if end.kind == ruleString {
+		endPos += 2
+	}
+	return &exprComparison{field: field.text, op: op, values: values, text: p.expr[start.pos:endPos]}, nil
+}
+
+func (p *ruleParser) value() (string, error) {
+	t := p.next()
+	if t.kind != ruleWord && t.kind != ruleString {
+		return "", p.errorf(t, "expected a value")
+	}
+	return t.text, nil
+}
+
+// a comparison of a rule expression, its values are parsed as the type of the field when it is asked
+type exprComparison struct {
+	field  string
+	op     safeOp
+	values []string
+	text   string
+}
+
+func (c *exprComparison) String() string {
+	return c.text
+}
+
+func (c *exprComparison) Prerequisites() []Prerequisite {
+	return []Prerequisite{conditionalPrerequisite(c.text, c)}
+}
+
+func (c *exprComparison) Meets(toSet any) bool {
+	f := fieldFromData(toSet, c.field)
+	if f == nil || f == FieldNil {
+		return false
+	}
+	for _, v := range c.values {
+		value := CreateFieldFromType(f.Type(), nil, f.Key())
+		if value == nil || ParseFieldString(value, v) != nil {
+			continue
+		}
+		if CompareFields(f, c.op, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *exprComparison) Evaluate(toSet any) Result {
+	if c.Meets(toSet) {
+		return Result{Met: true}
+	}
+	return Result{Failures: []Failure{{Prerequisite: 0, Question: 0, Check: c.text}}}
+}
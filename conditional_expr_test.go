@@ -0,0 +1,90 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+type testRuleAddress struct {
+	City string `field:"City"`
+}
+
+type testExprOrder struct {
+	Green    bool            `field:"Green"`
+	Amount   int             `field:"Amount"`
+	Status   string          `field:"Status"`
+	Archived bool            `field:"Archived"`
+	Total    *IntegerField   `field:"Total"`
+	Address  testRuleAddress `field:"Address"`
+}
+
+func TestParseRule(t *testing.T) {
+	o := &testExprOrder{Green: true, Amount: 150, Status: "held", Total: testAmount(10), Address: testRuleAddress{City: "Oslo"}}
+	for expr, want := range map[string]bool{
+		`Green == true`:                                  true,
+		`Amount > 100 && Amount <= 150`:                  true,
+		`Amount < 100 || Status == 'held'`:               true,
+		`Amount >= 200 || Status != held`:                false,
+		`Status in ("open", held)`:                       true,
+		`Status in (open)`:                               false,
+		`!Archived == true`:                              true,
+		`!(Green == true && Amount > 100)`:               false,
+		`Green == true || Amount > 1 && Amount > 1000`:   true,
+		`(Green == true || Amount > 1) && Amount > 1000`: false,
+		`Total < 20`:                                     true,
+		`Address.City == "Oslo"`:                         true,
+		`Missing == x`:                                   false,
+		// a value that does not parse as the type of the field meets nothing
+		`Amount == lots`: false,
+	} {
+		c, err := ParseRule(expr)
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+			continue
+		}
+		if got := c.Meets(o); got != want {
+			t.Errorf("%s: got %v", expr, got)
+		}
+	}
+	// a Parent resolves its own fields
+	c, _ := ParseRule(`Amount > 100`)
+	if !c.Meets(DefaultParent(o)) {
+		t.Error("got false")
+	}
+}
+
+func TestParseRuleFailures(t *testing.T) {
+	c, err := ParseRule(`Green == true && Status in ("open", "closed")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Evaluate(c, &testExprOrder{Green: true, Status: "held"})
+	if r.Met || len(r.Failures) != 1 || r.Failures[0].Check != `Status in ("open", "closed")` {
+		t.Errorf("the failures are named by their text: got %+v", r.Failures)
+	}
+	if p := c.Prerequisites(); len(p) != 1 || p[0].Name != "and" {
+		t.Errorf("got %+v", p)
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`Green`,
+		`Green ==`,
+		`Green = true`,
+		`== true`,
+		`Green == true &&`,
+		`(Green == true`,
+		`Green == true)`,
+		`Status in open`,
+		`Status in (open`,
+		`Status in (open held)`,
+		`Status == "open`,
+		`Status == open #`,
+	} {
+		if _, err := ParseRule(expr); !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("%q: got %v", expr, err)
+		}
+	}
+}
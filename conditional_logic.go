@@ -0,0 +1,81 @@
+package fielder
+
+import "context"
+
+// conditionals combine with AndCond, OrCond, NotCond and XorCond, ex:
+// AndCond(isPaid, OrCond(isShipped, NotCond(needsShipping)))
+// the combinators stop at the first child that decides the result, and are fallible when a child is, see FallibleConditional
+// the prerequisites of a combined conditional are a single prerequisite that asks the combination,
+// so Conditions(c.Prerequisites()...) meets the same values as c
+
+type logicalOp int
+
+const (
+	logicalAnd logicalOp = iota
+	logicalOr
+	logicalXor
+)
+
+type logicalConditional struct {
+	op    logicalOp
+	conds []Conditional
+}
+
+// AndCond meets a value when every conditional meets it, no conditionals always meets
+func AndCond(conds ...Conditional) Conditional {
+	return &logicalConditional{op: logicalAnd, conds: conds}
+}
+
+// OrCond meets a value when one of the conditionals meets it, no conditionals never meets
+func OrCond(conds ...Conditional) Conditional {
+	return &logicalConditional{op: logicalOr, conds: conds}
+}
+
+// XorCond meets a value when exactly one of the conditionals meets it, every conditional is asked
+func XorCond(conds ...Conditional) Conditional {
+	return &logicalConditional{op: logicalXor, conds: conds}
+}
+
+func (c *logicalConditional) Prerequisites() []Prerequisite {
+	return []Prerequisite{conditionalPrerequisite([...]string{"and", "or", "xor"}[c.op], c)}
+}
+
+func (c *logicalConditional) Meets(toSet any) bool {
+	ok, err := c.MeetsErr(toSet)
+	return ok && err == nil
+}
+
+func (c *logicalConditional) MeetsErr(toSet any) (bool, error) {
+	return c.MeetsCtx(context.Background(), toSet)
+}
+
+type notConditional struct {
+	cond Conditional
+}
+
+// NotCond meets a value when the conditional does not, an error from the conditional is not turned into a match
+func NotCond(cond Conditional) Conditional {
+	return &notConditional{cond: cond}
+}
+
+func (c *notConditional) Prerequisites() []Prerequisite {
+	return []Prerequisite{conditionalPrerequisite("not", c)}
+}
+
+func (c *notConditional) Meets(toSet any) bool {
+	ok, err := c.MeetsErr(toSet)
+	return ok && err == nil
+}
+
+func (c *notConditional) MeetsErr(toSet any) (bool, error) {
+	return c.MeetsCtx(context.Background(), toSet)
+}
+
+// a prerequisite that always applies and asks the conditional
+func conditionalPrerequisite(name string, c Conditional) Prerequisite {
+	return Prerequisite{
+		Name:        name,
+		IsCandidate: EnforceableTrue,
+		Gauntlet:    []Question{func() Enforceable { return c.Meets }},
+	}
+}
@@ -0,0 +1,86 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+// a conditional that always answers ok and counts how often it is asked
+func testAnswer(ok bool, asked *int) Conditional {
+	return Conditions(Prerequisite{
+		IsCandidate: EnforceableTrue,
+		Gauntlet: []Question{func() Enforceable {
+			return func(any) bool {
+				*asked++
+				return ok
+			}
+		}},
+	})
+}
+
+func TestLogicalConditionals(t *testing.T) {
+	var n int
+	yes, no := testAnswer(true, &n), testAnswer(false, &n)
+	for _, c := range []struct {
+		name string
+		c    Conditional
+		want bool
+	}{
+		{"and", AndCond(yes, yes), true},
+		{"and with a no", AndCond(yes, no), false},
+		{"empty and", AndCond(), true},
+		{"or", OrCond(no, yes), true},
+		{"or of noes", OrCond(no, no), false},
+		{"empty or", OrCond(), false},
+		{"xor", XorCond(no, yes, no), true},
+		{"xor of two", XorCond(yes, no, yes), false},
+		{"xor of none", XorCond(no, no), false},
+		{"not", NotCond(no), true},
+		{"nested", AndCond(yes, OrCond(no, NotCond(no))), true},
+	} {
+		if got := c.c.Meets(nil); got != c.want {
+			t.Errorf("%s: got %v", c.name, got)
+		}
+		// the prerequisites of a combined conditional ask the combination
+		if got := Conditions(c.c.Prerequisites()...).Meets(nil); got != c.want {
+			t.Errorf("%s prerequisites: got %v", c.name, got)
+		}
+	}
+}
+
+func TestLogicalShortCircuit(t *testing.T) {
+	var asked int
+	yes, no := testAnswer(true, &asked), testAnswer(false, &asked)
+	for _, c := range []struct {
+		name  string
+		c     Conditional
+		asked int
+	}{
+		{"and", AndCond(no, yes, yes), 1},
+		{"or", OrCond(yes, no, no), 1},
+		{"xor", XorCond(yes, yes, no), 2},
+	} {
+		asked = 0
+		c.c.Meets(nil)
+		if asked != c.asked {
+			t.Errorf("%s: asked %d conditionals, want %d", c.name, asked, c.asked)
+		}
+	}
+}
+
+func TestLogicalErrors(t *testing.T) {
+	var n int
+	yes := testAnswer(true, &n)
+	failing := testFallible{Conditional: yes, err: errTestLookup}
+	if ok, err := AndCond(yes, failing).(FallibleConditional).MeetsErr(nil); ok || !errors.Is(err, errTestLookup) {
+		t.Errorf("got %v %v", ok, err)
+	}
+	// an error is not turned into a match
+	not := NotCond(failing)
+	if ok, err := not.(FallibleConditional).MeetsErr(nil); ok || !errors.Is(err, errTestLookup) {
+		t.Errorf("got %v %v", ok, err)
+	}
+	if not.Meets(nil) {
+		t.Error("a conditional that errors is not met")
+	}
+}
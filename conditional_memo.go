@@ -0,0 +1,164 @@
+package fielder
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoKey returns the key a value is remembered under, values with the same key must get the same answer
+// a value that should not be remembered returns false, ex: one with no id
+type MemoKey func(toSet any) (string, bool)
+
+type MemoOption func(*MemoConditional)
+
+// answers are forgotten after the ttl, by default they are kept until they are evicted
+func WithMemoTTL(ttl time.Duration) MemoOption {
+	return func(c *MemoConditional) {
+		c.ttl = ttl
+	}
+}
+
+// at most size answers are kept, the least recently used is evicted first, by default there is no bound
+func WithMemoSize(size int) MemoOption {
+	return func(c *MemoConditional) {
+		c.size = size
+	}
+}
+
+// the clock used for the ttl, time.Now by default
+func WithMemoClock(now func() time.Time) MemoOption {
+	return func(c *MemoConditional) {
+		c.now = now
+	}
+}
+
+type memoEntry struct {
+	key     string
+	met     bool
+	result  *Result
+	expires time.Time
+}
+
+// MemoConditional is a conditional that remembers its answers, see Memoize
+type MemoConditional struct {
+	cond    Conditional
+	memoKey MemoKey
+	ttl     time.Duration
+	size    int
+	now     func() time.Time
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// Memoize remembers the answers of the conditional by the key of the value, for checks repeated on the same values, ex: a bulk import
+// answers that came with an error are not remembered, so a check that could not be made is asked again
+func Memoize(cond Conditional, key MemoKey, opts ...MemoOption) *MemoConditional {
+	c := &MemoConditional{
+		cond:    cond,
+		memoKey: key,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *MemoConditional) Prerequisites() []Prerequisite {
+	return c.cond.Prerequisites()
+}
+
+func (c *MemoConditional) Meets(toSet any) bool {
+	ok, err := c.MeetsCtx(context.Background(), toSet)
+	return ok && err == nil
+}
+
+func (c *MemoConditional) MeetsErr(toSet any) (bool, error) {
+	return c.MeetsCtx(context.Background(), toSet)
+}
+
+func (c *MemoConditional) MeetsCtx(ctx context.Context, toSet any) (bool, error) {
+	key, ok := c.memoKey(toSet)
+	if !ok {
+		return conditionalMeets(ctx, c.cond, toSet)
+	}
+	if e, found := c.get(key); found {
+		return e.met, nil
+	}
+	met, err := conditionalMeets(ctx, c.cond, toSet)
+	if err == nil {
+		c.put(&memoEntry{key: key, met: met})
+	}
+	return met, err
+}
+
+func (c *MemoConditional) Evaluate(toSet any) Result {
+	key, ok := c.memoKey(toSet)
+	if !ok {
+		return Evaluate(c.cond, toSet)
+	}
+	if e, found := c.get(key); found && e.result != nil {
+		return *e.result
+	}
+	r := Evaluate(c.cond, toSet)
+	for _, v := range r.Failures {
+		if v.Err != nil {
+			return r
+		}
+	}
+	c.put(&memoEntry{key: key, met: r.Met, result: &r})
+	return r
+}
+
+// Forget drops every remembered answer, ex: after the rules the checks read have changed
+func (c *MemoConditional) Forget() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+func (c *MemoConditional) get(key string) (memoEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return memoEntry{}, false
+	}
+	e := el.Value.(*memoEntry)
+	if c.ttl > 0 && !c.now().Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return memoEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return *e, true
+}
+
+func (c *MemoConditional) put(e *memoEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl > 0 {
+		e.expires = c.now().Add(c.ttl)
+	}
+	if el, ok := c.entries[e.key]; ok {
+		// an answer from Meets does not replace a result from Evaluate for the same key
+		if old := el.Value.(*memoEntry); e.result == nil && old.result != nil && old.met == e.met {
+			e.result = old.result
+		}
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[e.key] = c.order.PushFront(e)
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoEntry).key)
+	}
+}
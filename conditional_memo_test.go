@@ -0,0 +1,113 @@
+package fielder
+
+import (
+	"testing"
+	"time"
+)
+
+// a conditional met by "a" that counts how often it is asked
+func testCountedIsA(asked *int) Conditional {
+	return Conditions(Prerequisite{IsCandidate: EnforceableTrue, Gauntlet: []Question{func() Enforceable {
+		return func(f any) bool {
+			*asked++
+			return f == "a"
+		}
+	}}})
+}
+
+func testMemoKey(toSet any) (string, bool) {
+	s, ok := toSet.(string)
+	return s, ok && s != ""
+}
+
+func TestMemoize(t *testing.T) {
+	var asked int
+	c := Memoize(testCountedIsA(&asked), testMemoKey)
+	for i := 0; i < 3; i++ {
+		if !c.Meets("a") || c.Meets("b") {
+			t.Fatal("the answers are the conditional's")
+		}
+	}
+	if asked != 2 {
+		t.Errorf("asked %d times", asked)
+	}
+	// values without a key are always asked
+	c.Meets("")
+	c.Meets("")
+	if asked != 4 {
+		t.Errorf("asked %d times", asked)
+	}
+	c.Forget()
+	c.Meets("a")
+	if asked != 5 {
+		t.Errorf("asked %d times", asked)
+	}
+	if len(c.Prerequisites()) != 1 {
+		t.Error("the prerequisites are the conditional's")
+	}
+}
+
+func TestMemoizeEvaluate(t *testing.T) {
+	var asked int
+	c := Memoize(testCountedIsA(&asked), testMemoKey)
+	c.Meets("b")
+	// an answer from Meets has no failures to give, so Evaluate asks again
+	if r := Evaluate(c, "b"); r.Met || len(r.Failures) != 1 || asked != 2 {
+		t.Errorf("got %+v, asked %d times", r, asked)
+	}
+	if r := Evaluate(c, "b"); r.Met || len(r.Failures) != 1 || asked != 2 {
+		t.Errorf("got %+v, asked %d times", r, asked)
+	}
+	// and the result is kept when Meets is asked again
+	c.Meets("b")
+	if r := c.Evaluate("b"); len(r.Failures) != 1 || asked != 2 {
+		t.Errorf("got %+v, asked %d times", r, asked)
+	}
+}
+
+func TestMemoizeErrors(t *testing.T) {
+	var n int
+	failing := &testFallible{Conditional: testAnswer(true, &n), err: errTestLookup}
+	c := Memoize(failing, testMemoKey)
+	if _, err := c.MeetsErr("a"); err == nil {
+		t.Fatal("expected an error")
+	}
+	// answers with an error are not remembered
+	failing.err = nil
+	if ok, err := c.MeetsErr("a"); !ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+}
+
+func TestMemoizeBounds(t *testing.T) {
+	var asked int
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := Memoize(testCountedIsA(&asked), testMemoKey, WithMemoTTL(time.Minute), WithMemoClock(func() time.Time { return now }))
+	c.Meets("a")
+	now = now.Add(30 * time.Second)
+	c.Meets("a")
+	if asked != 1 {
+		t.Errorf("asked %d times", asked)
+	}
+	now = now.Add(time.Minute)
+	c.Meets("a")
+	if asked != 2 {
+		t.Errorf("an expired answer is asked again: asked %d times", asked)
+	}
+
+	asked = 0
+	c = Memoize(testCountedIsA(&asked), testMemoKey, WithMemoSize(2))
+	c.Meets("a")
+	c.Meets("b")
+	c.Meets("a")
+	// c evicts b, the least recently used
+	c.Meets("c")
+	c.Meets("a")
+	if asked != 3 {
+		t.Errorf("asked %d times", asked)
+	}
+	c.Meets("b")
+	if asked != 4 {
+		t.Errorf("asked %d times", asked)
+	}
+}
@@ -0,0 +1,104 @@
+package fielder
+
+import "reflect"
+
+// ParentAwareConditional is a rule that reads the other fields of the parent a field is set on, ex: HasColor requires Green to be true
+// bind it to a parent with WithParent to use it where a Conditional is wanted
+type ParentAwareConditional interface {
+	Meets(parent Parent, incoming Field) bool
+}
+
+// ParentConditionFunc is a ParentAwareConditional from a function, ex:
+//
+//	requiresGreen := ParentConditionFunc(func(p Parent, incoming Field) bool {
+//		return incoming.Value() != true || p.GetResultItemFieldFromKey(NewDefaultFieldKey("Green")).Value() == true
+//	})
+type ParentConditionFunc func(parent Parent, incoming Field) bool
+
+func (f ParentConditionFunc) Meets(parent Parent, incoming Field) bool {
+	return f(parent, incoming)
+}
+
+type parentConditional struct {
+	cond   ParentAwareConditional
+	parent Parent
+}
+
+// WithParent returns the conditional asked with the parent, a value that is not a Field never meets it
+// the parent is read each time the conditional is asked, so pass a parent that sees later changes, ex: DefaultParent(&order)
+func WithParent(cond ParentAwareConditional, parent Parent) Conditional {
+	return &parentConditional{cond: cond, parent: parent}
+}
+
+func (c *parentConditional) Prerequisites() []Prerequisite {
+	return []Prerequisite{conditionalPrerequisite("parent", c)}
+}
+
+func (c *parentConditional) Meets(toSet any) bool {
+	f, ok := toSet.(Field)
+	return ok && c.cond.Meets(c.parent, f)
+}
+
+func (c *parentConditional) Evaluate(toSet any) Result {
+	if c.Meets(toSet) {
+		return Result{Met: true}
+	}
+	return Result{Failures: []Failure{{Prerequisite: -1, Question: -1, Check: "parent"}}}
+}
+
+// AskParent returns a rule that asks the conditional about the parent instead of the incoming field,
+// for conditionals that read fields by name, ex: WithParent(AskParent(After(createdAt, 30*24*time.Hour)), DefaultParent(&order))
+func AskParent(c Conditional) ParentAwareConditional {
+	return ParentConditionFunc(func(parent Parent, _ Field) bool {
+		return c.Meets(parent)
+	})
+}
+
+// NewParentConditionalField is NewConditionalField with a rule that reads the parent
+func NewParentConditionalField(field Field, cond ParentAwareConditional, parent Parent) ConditionalField {
+	return NewConditionalField(field, WithParent(cond, parent))
+}
+
+type defaultParent[P any] struct {
+	in P
+}
+
+// DefaultParent is a Parent for a default parent, a struct with "field" tags, or a pointer to one
+func DefaultParent[P any](in P) Parent {
+	return defaultParent[P]{in: in}
+}
+
+// a pointer parent is read through, so the fields are current when they are asked for
+func (p defaultParent[P]) value() any {
+	v := reflect.ValueOf(p.in)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func (p defaultParent[P]) GetResultItemFieldFromKey(f FieldKey) Field {
+	return GetResultItemFieldFromKeyDefault(p.value(), f)
+}
+
+func (p defaultParent[P]) GetFieldTypeFromKey(f FieldKey) reflect.Type {
+	t := reflect.TypeOf(p.in)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return reflectTypeByPath(t, f.Name)
+}
+
+func (p defaultParent[P]) GetReflectValueOfKey(f FieldKey) reflect.Value {
+	return GetReflectValueOfKeyDefault(p.value(), f)
+}
+
+func (p defaultParent[P]) CheckKeyExists(f FieldKey) bool {
+	return p.GetFieldTypeFromKey(f) != nil
+}
@@ -0,0 +1,84 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testColor struct {
+	Green    bool         `field:"Green"`
+	HasColor bool         `field:"HasColor"`
+	Note     *StringField `field:"Note"`
+}
+
+var testRequiresGreen = ParentConditionFunc(func(p Parent, incoming Field) bool {
+	return incoming.Value() != true || p.GetResultItemFieldFromKey(NewDefaultFieldKey("Green")).Value() == true
+})
+
+func TestWithParent(t *testing.T) {
+	c := &testColor{}
+	hasColor := NewParentConditionalField(&BoolField{KeyField: NewDefaultFieldKey("HasColor")}, testRequiresGreen, DefaultParent(c))
+	yes := &BoolField{ValueField: true, Set: true, KeyField: NewDefaultFieldKey("HasColor")}
+	if err := hasColor.(TrySetter).TrySetValue(yes); err == nil {
+		t.Error("HasColor requires Green")
+	}
+	if r := Evaluate(WithParent(testRequiresGreen, DefaultParent(c)), yes); r.Met || r.Failures[0].Check != "parent" {
+		t.Errorf("got %+v", r)
+	}
+	// the parent is read when the conditional is asked, so it sees the change
+	c.Green = true
+	if err := hasColor.(TrySetter).TrySetValue(yes); err != nil || hasColor.Value() != true {
+		t.Errorf("got %v %v", hasColor.Value(), err)
+	}
+	if WithParent(testRequiresGreen, DefaultParent(c)).Meets(true) {
+		t.Error("a value that is not a field never meets it")
+	}
+	if name := WithParent(testRequiresGreen, DefaultParent(c)).Prerequisites()[0].Name; name != "parent" {
+		t.Errorf("got %q", name)
+	}
+}
+
+func TestAskParent(t *testing.T) {
+	c := &testColor{Note: testField("Note", "urgent")}
+	isUrgent := Conditions(Prerequisite{IsCandidate: EnforceableTrue, Gauntlet: []Question{func() Enforceable {
+		return func(p any) bool {
+			return p.(Parent).GetResultItemFieldFromKey(NewDefaultFieldKey("Note")).ToString() == "urgent"
+		}
+	}}})
+	cond := WithParent(AskParent(isUrgent), DefaultParent(c))
+	if !cond.Meets(testField("Green", "")) {
+		t.Error("the conditional is asked about the parent")
+	}
+	c.Note = testField("Note", "later")
+	if cond.Meets(testField("Green", "")) {
+		t.Error("the parent is read again")
+	}
+}
+
+func TestDefaultParent(t *testing.T) {
+	note := testField("Note", "a")
+	c := &testColor{Green: true, Note: note}
+	for _, p := range []Parent{DefaultParent(c), DefaultParent(*c), DefaultParent(&c)} {
+		// field members are returned as they are
+		if got := p.GetResultItemFieldFromKey(NewDefaultFieldKey("Note")); got != Field(note) {
+			t.Errorf("%T: got %v", p, got)
+		}
+		if got := p.GetResultItemFieldFromKey(NewDefaultFieldKey("Green")); got == nil || got.Value() != true {
+			t.Errorf("%T: got %v", p, got)
+		}
+		if p.GetFieldTypeFromKey(NewDefaultFieldKey("Note")) != reflect.TypeOf(note) || !p.CheckKeyExists(NewDefaultFieldKey("Green")) || p.CheckKeyExists(NewDefaultFieldKey("Blue")) {
+			t.Errorf("%T: got the wrong types", p)
+		}
+		if v := p.GetReflectValueOfKey(NewDefaultFieldKey("Green")); !v.IsValid() || !v.Bool() {
+			t.Errorf("%T: got %v", p, v)
+		}
+	}
+	var missing *testColor
+	p := DefaultParent(missing)
+	if f := p.GetResultItemFieldFromKey(NewDefaultFieldKey("Green")); f != nil && f != FieldNil {
+		t.Errorf("a nil parent has no fields: got %v", f)
+	}
+	if !p.CheckKeyExists(NewDefaultFieldKey("Green")) {
+		t.Error("the type of a nil parent is still known")
+	}
+}
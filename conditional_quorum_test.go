@@ -0,0 +1,75 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// a quorum prerequisite over three checks, one for each of the values listed
+func testQuorum(quorum int, concurrent bool, values ...string) Prerequisite {
+	p := Prerequisite{Name: "risk", IsCandidate: EnforceableTrue, Quorum: quorum, Concurrent: concurrent}
+	for _, v := range values {
+		p.Checks = append(p.Checks, NamedQuestion{Name: "is " + v, Question: testIs(v)})
+	}
+	return p
+}
+
+func TestQuorum(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		// "a" passes the two checks for "a" out of three
+		c := Conditions(testQuorum(2, concurrent, "a", "a", "b"))
+		if !c.Meets("a") {
+			t.Errorf("concurrent %v: expected 2 of 3 to pass", concurrent)
+		}
+		if c.Meets("b") {
+			t.Errorf("concurrent %v: expected 1 of 3 to fail", concurrent)
+		}
+		if ok, err := MeetsCtx(context.Background(), c, "b"); ok || err != nil {
+			t.Errorf("concurrent %v: got %v %v", concurrent, ok, err)
+		}
+	}
+	// without a quorum every question has to pass
+	if Conditions(testQuorum(0, false, "a", "a", "b")).Meets("a") {
+		t.Error("expected all of the questions to be needed")
+	}
+}
+
+func TestQuorumErrors(t *testing.T) {
+	errDown := errors.New("down")
+	p := testQuorum(2, false, "a", "a")
+	p.Checks = append([]NamedQuestion{{Name: "lookup", Ask: func(context.Context, any) (bool, error) { return false, errDown }}}, p.Checks...)
+	c := Conditions(p)
+	// an erroring question is one that failed, the rest still make the quorum
+	if ok, err := MeetsCtx(context.Background(), c, "a"); !ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+	if ok, err := MeetsCtx(context.Background(), c, "b"); ok || !errors.Is(err, errDown) {
+		t.Errorf("a failed quorum returns the error: got %v %v", ok, err)
+	}
+}
+
+func TestQuorumEvaluate(t *testing.T) {
+	c := Conditions(testQuorum(2, false, "a", "a", "b"))
+	if r := Evaluate(c, "a"); !r.Met || len(r.Failures) != 0 {
+		t.Errorf("the failed question of a met quorum is not reported: got %+v", r)
+	}
+	r := Evaluate(c, "b")
+	if r.Met || len(r.Failures) != 2 || r.Failures[0].Check != "risk: is a" || r.Failures[1].Question != 1 {
+		t.Errorf("a failed quorum reports its failed questions: got %+v", r)
+	}
+	// a quorum larger than the questions can never pass, even when every question does
+	r = Evaluate(Conditions(testQuorum(3, false, "a", "a")), "a")
+	if r.Met || len(r.Failures) != 1 || r.Failures[0].Question != -1 {
+		t.Fatalf("got %+v", r)
+	}
+	if got := r.Failures[0].Check; !strings.Contains(got, "quorum of 3 with 2 questions") || !strings.HasPrefix(got, "risk") {
+		t.Errorf("got %q", got)
+	}
+	warn := testQuorum(2, false, "a", "b", "c")
+	warn.Severity = SeverityWarning
+	if r := Evaluate(Conditions(warn), "a"); !r.Met || len(r.Warnings) != 2 {
+		t.Errorf("a warning quorum flags its failed questions: got %+v", r)
+	}
+}
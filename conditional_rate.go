@@ -0,0 +1,125 @@
+package fielder
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rate rules limit how often a field can change, ex: a payout account may change once a day, a username only 3 times ever
+// they read the times the field changed from a ChangeTracker, a ChangeLog filled by TrackChanges keeps them in memory,
+// a service that keeps them elsewhere, ex: next to the item in dynamo, implements ChangeTracker over its own store
+// the rules are asked before the write, so the write being asked about is not one of the changes yet
+
+// ChangeTracker is the change tracking metadata the rate rules read
+type ChangeTracker interface {
+	// the times the field changed, oldest first
+	Changes(key FieldKey) []time.Time
+}
+
+// ChangeLog is a ChangeTracker kept in memory, it is safe to share between goroutines
+type ChangeLog struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	changes map[FieldKey][]time.Time
+}
+
+// the clock is the time TrackChanges records changes at, see WithClock
+func NewChangeLog(opts ...TimeOption) *ChangeLog {
+	return &ChangeLog{now: clockFrom(opts), changes: map[FieldKey][]time.Time{}}
+}
+
+// Record adds a change to the field at the time given, ex: to load the changes kept with a stored item
+func (l *ChangeLog) Record(key FieldKey, at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	changes := l.changes[key]
+	i := len(changes)
+	for i > 0 && changes[i-1].After(at) {
+		i--
+	}
+	changes = append(changes, time.Time{})
+	copy(changes[i+1:], changes[i:])
+	changes[i] = at
+	l.changes[key] = changes
+}
+
+func (l *ChangeLog) Changes(key FieldKey) []time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]time.Time{}, l.changes[key]...)
+}
+
+// Forget drops the changes of the field, ex: support resetting a user's limit
+func (l *ChangeLog) Forget(key FieldKey) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.changes, key)
+}
+
+// TrackChanges records a change in the log after every write that changed the value of the field
+// writes that fail or set the same value again are not changes, ex:
+// Intercept(f, CheckConditions(Conditions(Cooldown(key, 24*time.Hour, log))), TrackChanges(log))
+func TrackChanges(log *ChangeLog) SetterMiddleware {
+	return func(next Setter) Setter {
+		return func(f Field, v FieldValue) error {
+			previous := cloneField(f)
+			if err := next(f, v); err != nil {
+				return err
+			}
+			if previous == nil || !f.Equal(previous) {
+				log.Record(f.Key(), log.now())
+			}
+			return nil
+		}
+	}
+}
+
+// Cooldown is met when the field has not changed within d
+func Cooldown(key FieldKey, d time.Duration, changes ChangeTracker, opts ...TimeOption) Prerequisite {
+	return rateLimit(fmt.Sprintf("%s not changed in %s", key.Name, d), key, 1, d, changes, opts)
+}
+
+// RateLimit is met while the field changed fewer than n times within the last per, so the write asked about is at most the nth
+func RateLimit(key FieldKey, n int, per time.Duration, changes ChangeTracker, opts ...TimeOption) Prerequisite {
+	return rateLimit(fmt.Sprintf("%s changed fewer than %d times in %s", key.Name, n, per), key, n, per, changes, opts)
+}
+
+// MaxChanges is met while the field changed fewer than n times in total
+func MaxChanges(key FieldKey, n int, changes ChangeTracker) Prerequisite {
+	return rateLimit(fmt.Sprintf("%s changed fewer than %d times", key.Name, n), key, n, 0, changes, nil)
+}
+
+// a per of zero counts every change
+func rateLimit(name string, key FieldKey, n int, per time.Duration, changes ChangeTracker, opts []TimeOption) Prerequisite {
+	now := clockFrom(opts)
+	return Prerequisite{
+		Name:        name,
+		IsCandidate: EnforceableTrue,
+		Checks: []NamedQuestion{{Name: name, Question: func() Enforceable {
+			return func(any) bool {
+				times := changes.Changes(key)
+				if per <= 0 {
+					return len(times) < n
+				}
+				since := now().Add(-per)
+				count := 0
+				for _, t := range times {
+					if t.After(since) {
+						count++
+					}
+				}
+				return count < n
+			}
+		}}},
+	}
+}
+
+// the clock the options give, time.Now when they give none
+func clockFrom(opts []TimeOption) func() time.Time {
+	c := &timeConditional{now: time.Now}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.now
+}
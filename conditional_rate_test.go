@@ -0,0 +1,104 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChangeLogRecord(t *testing.T) {
+	key := NewDefaultFieldKey("Payout")
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	log := NewChangeLog()
+	// changes loaded out of order are kept oldest first
+	log.Record(key, start.Add(2*time.Hour))
+	log.Record(key, start)
+	log.Record(key, start.Add(time.Hour))
+	got := log.Changes(key)
+	if len(got) != 3 || !got[0].Equal(start) || !got[1].Equal(start.Add(time.Hour)) || !got[2].Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("got %v", got)
+	}
+	got[0] = time.Time{}
+	if log.Changes(key)[0].IsZero() {
+		t.Error("Changes returns a copy")
+	}
+	log.Forget(key)
+	if len(log.Changes(key)) != 0 {
+		t.Error("expected the changes to be forgotten")
+	}
+}
+
+func TestCooldown(t *testing.T) {
+	key := NewDefaultFieldKey("Payout")
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	log := NewChangeLog(WithClock(clock.Now))
+	f := Intercept(testField("Payout", "a"), CheckConditions(Conditions(Cooldown(key, 24*time.Hour, log, WithClock(clock.Now)))), TrackChanges(log))
+	if err := f.TrySetValue(testField("Payout", "b")); err != nil {
+		t.Fatal(err)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	if err := f.TrySetValue(testField("Payout", "c")); !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("a second change within the cooldown is rejected: got %v", err)
+	}
+	clock.now = clock.now.Add(24 * time.Hour)
+	if err := f.TrySetValue(testField("Payout", "c")); err != nil || f.ToString() != "c" {
+		t.Errorf("got %s %v", f.ToString(), err)
+	}
+	if got := log.Changes(key); len(got) != 2 || !got[1].Equal(clock.now) {
+		t.Errorf("changes are recorded at the time of the clock: got %v", got)
+	}
+}
+
+func TestTrackChanges(t *testing.T) {
+	key := NewDefaultFieldKey("Name")
+	log := NewChangeLog()
+	f := Intercept(testField("Name", "a"), CheckConditions(testNotBad()), TrackChanges(log))
+	f.SetValue(testField("Name", "a"))
+	f.SetValue(testField("Name", "bad"))
+	if len(log.Changes(key)) != 0 {
+		t.Errorf("writes of the same value and failed writes are not changes: got %v", log.Changes(key))
+	}
+	f.SetValue(testField("Name", "b"))
+	if len(log.Changes(key)) != 1 {
+		t.Errorf("got %v", log.Changes(key))
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	key := NewDefaultFieldKey("Name")
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	log := NewChangeLog()
+	c := Conditions(RateLimit(key, 2, time.Hour, log, WithClock(clock.Now)))
+	log.Record(key, clock.now.Add(-2*time.Hour))
+	log.Record(key, clock.now.Add(-30*time.Minute))
+	if !c.Meets(nil) {
+		t.Error("a change older than the window is not counted")
+	}
+	log.Record(key, clock.now.Add(-time.Minute))
+	if c.Meets(nil) {
+		t.Error("expected 2 changes in the hour to reach the limit")
+	}
+	clock.now = clock.now.Add(40 * time.Minute)
+	if !c.Meets(nil) {
+		t.Error("expected the window to move with the clock")
+	}
+}
+
+func TestMaxChanges(t *testing.T) {
+	key := NewDefaultFieldKey("Username")
+	log := NewChangeLog()
+	c := Conditions(MaxChanges(key, 2, log))
+	log.Record(key, time.Now().Add(-365*24*time.Hour))
+	if !c.Meets(nil) {
+		t.Error("expected one change of two to be allowed")
+	}
+	log.Record(key, time.Now())
+	r := Evaluate(c, nil)
+	if r.Met || len(r.Failures) != 1 || r.Failures[0].Check != "Username changed fewer than 2 times" {
+		t.Errorf("every change counts, however old: got %+v", r)
+	}
+	// other fields are tracked apart
+	if !Conditions(MaxChanges(NewDefaultFieldKey("Email"), 1, log)).Meets(nil) {
+		t.Error("expected another field to have no changes")
+	}
+}
@@ -0,0 +1,127 @@
+package fielder
+
+import (
+	"reflect"
+	"strings"
+)
+
+// rules are conditionals built from a field name and a comparison instead of a closure, so they can be listed and named, ex:
+// Requirements(Require("Green").Equals(true), Require("Amount").GreaterThan(limit), Require("Status").In("open", "held"))
+// a rule reads its field from the value it is asked about like WhenField does, a Parent, a struct, a *FieldSet or a single Field
+// values that are not fields are made into fields of their own type, a missing or empty field meets no rule
+
+// Requirement is the field a rule is built on, see Require
+type Requirement struct {
+	name string
+}
+
+func Require(name string) Requirement {
+	return Requirement{name: name}
+}
+
+// Rule is met when its field compares with the op to one of its values
+type Rule struct {
+	Field  FieldName
+	Op     safeOp
+	Values []Field
+}
+
+func (r Requirement) rule(op safeOp, values ...any) Rule {
+	key := NewDefaultFieldKey(r.name)
+	fields := make([]Field, 0, len(values))
+	for _, v := range values {
+		fields = append(fields, ruleField(key, v))
+	}
+	return Rule{Field: key.Name, Op: op, Values: fields}
+}
+
+func ruleField(key FieldKey, v any) Field {
+	if f, ok := v.(Field); ok {
+		return f
+	}
+	if v == nil {
+		return FieldNil
+	}
+	if f := CreateFieldFromType(reflect.TypeOf(v), v, key); f != nil {
+		return f
+	}
+	return FieldNil
+}
+
+func (r Requirement) Equals(v any) Rule {
+	return r.rule(EQ, v)
+}
+
+func (r Requirement) NotEquals(v any) Rule {
+	return r.rule(NE, v)
+}
+
+func (r Requirement) LessThan(v any) Rule {
+	return r.rule(LT, v)
+}
+
+func (r Requirement) AtMost(v any) Rule {
+	return r.rule(LE, v)
+}
+
+func (r Requirement) GreaterThan(v any) Rule {
+	return r.rule(GT, v)
+}
+
+func (r Requirement) AtLeast(v any) Rule {
+	return r.rule(GE, v)
+}
+
+// In is met when the field equals one of the values
+func (r Requirement) In(values ...any) Rule {
+	return r.rule(EQ, values...)
+}
+
+// String names the rule, ex: Amount GT 100, Status EQ open or held
+func (r Rule) String() string {
+	values := make([]string, len(r.Values))
+	for i, v := range r.Values {
+		values[i] = v.ToString()
+	}
+	return string(r.Field) + " " + string(r.Op) + " " + strings.Join(values, " or ")
+}
+
+func (r Rule) Meets(toSet any) bool {
+	f := fieldFromData(toSet, string(r.Field))
+	for _, v := range r.Values {
+		if CompareFields(f, r.Op, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Prerequisite returns the rule as a prerequisite named after it
+func (r Rule) Prerequisite() Prerequisite {
+	name := r.String()
+	return Prerequisite{
+		Name:        name,
+		IsCandidate: EnforceableTrue,
+		Checks:      []NamedQuestion{{Name: name, Question: func() Enforceable { return r.Meets }}},
+	}
+}
+
+func (r Rule) Prerequisites() []Prerequisite {
+	return []Prerequisite{r.Prerequisite()}
+}
+
+func (r Rule) Evaluate(toSet any) Result {
+	if r.Meets(toSet) {
+		return Result{Met: true}
+	}
+	return Result{Failures: []Failure{{Prerequisite: 0, Question: 0, Check: r.String()}}}
+}
+
+// Requirements is a conditional met when every rule is met, its failures are named after the rules
+func Requirements(rules ...Rule) Conditional {
+	prereqs := make([]Prerequisite, len(rules))
+	for i, v := range rules {
+		prereqs[i] = v.Prerequisite()
+	}
+	return Conditions(prereqs...)
+}
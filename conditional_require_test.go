@@ -0,0 +1,64 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+type testRuleOrder struct {
+	Green  bool          `field:"Green"`
+	Amount int           `field:"Amount"`
+	Status string        `field:"Status"`
+	Total  *IntegerField `field:"Total"`
+}
+
+func TestRequire(t *testing.T) {
+	o := &testRuleOrder{Green: true, Amount: 150, Status: "held", Total: testAmount(10)}
+	for _, c := range []struct {
+		rule Rule
+		name string
+		want bool
+	}{
+		{Require("Green").Equals(true), "Green EQ true", true},
+		{Require("Green").NotEquals(true), "Green NE true", false},
+		{Require("Amount").GreaterThan(100), "Amount GT 100", true},
+		{Require("Amount").AtLeast(150), "Amount GE 150", true},
+		{Require("Amount").LessThan(150), "Amount LT 150", false},
+		{Require("Amount").AtMost(150), "Amount LE 150", true},
+		{Require("Status").In("open", "held"), "Status EQ open or held", true},
+		{Require("Status").In("open", "closed"), "Status EQ open or closed", false},
+		{Require("Total").LessThan(testAmount(20)), "Total LT 20", true},
+		{Require("Missing").Equals("x"), "Missing EQ x", false},
+	} {
+		if got := c.rule.String(); got != c.name {
+			t.Errorf("got %q, want %q", got, c.name)
+		}
+		if got := c.rule.Meets(o); got != c.want {
+			t.Errorf("%s: got %v", c.name, got)
+		}
+	}
+	if Require("Status").Equals(nil).Meets(o) || Require("Status").Equals(struct{}{}).Meets(o) {
+		t.Error("values that are not fields of a known type meet nothing")
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	c := Requirements(Require("Green").Equals(true), Require("Amount").GreaterThan(100))
+	if !c.Meets(&testRuleOrder{Green: true, Amount: 150}) {
+		t.Error("every rule is met")
+	}
+	r := Evaluate(c, &testRuleOrder{Amount: 50})
+	if r.Met || len(r.Failures) != 2 || r.Failures[0].Check != "Green EQ true" || r.Failures[1].Check != "Amount GT 100" {
+		t.Errorf("the failures are named after the rules: got %+v", r.Failures)
+	}
+	if !errors.Is(r.Err(), ErrConditionNotMet) {
+		t.Errorf("got %v", r.Err())
+	}
+	rule := Require("Amount").GreaterThan(100)
+	if r := Evaluate(rule, testAmount(50)); r.Met || r.Failures[0].Check != "Amount GT 100" {
+		t.Errorf("a rule is a conditional of its own: got %+v", r)
+	}
+	if p := rule.Prerequisites(); len(p) != 1 || p[0].Name != "Amount GT 100" || p[0].Checks[0].Name != "Amount GT 100" {
+		t.Errorf("got %+v", p)
+	}
+}
@@ -0,0 +1,200 @@
+package fielder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Failure is a check that rejected a value, the Prerequisite and Question are indexes into the conditional that failed
+// the Question counts the gauntlet of the prerequisite and then its checks
+// a Question of -1 is a conditional that was asked as a whole, ex: one that has no Evaluate
+type Failure struct {
+	Prerequisite int
+	Question     int
+	Check        string
+	// set when the check could not be made, see FallibleConditional
+	Err error
+}
+
+func (f Failure) String() string {
+	if f.Err != nil {
+		return fmt.Sprintf("%s: %v", f.Check, f.Err)
+	}
+	return f.Check
+}
+
+// Result is the outcome of evaluating a conditional, with every check that rejected the value
+// and every check of a SeverityWarning prerequisite that flagged it without rejecting it
+type Result struct {
+	Met      bool
+	Failures []Failure
+	Warnings []Failure
+}
+
+// Err is nil when the conditional was met, otherwise a *ConditionError
+func (r Result) Err() error {
+	if r.Met {
+		return nil
+	}
+	return &ConditionError{Failures: r.Failures}
+}
+
+// ConditionError is a value rejected by a conditional, it is ErrConditionNotMet with the checks that failed
+type ConditionError struct {
+	Failures []Failure
+}
+
+func (e *ConditionError) Error() string {
+	checks := make([]string, len(e.Failures))
+	for i, v := range e.Failures {
+		checks[i] = v.String()
+	}
+	return ErrConditionNotMet.Error() + ": " + strings.Join(checks, ", ")
+}
+
+func (e *ConditionError) Unwrap() error {
+	return ErrConditionNotMet
+}
+
+// EvaluatingConditional is a Conditional that can tell which of its checks rejected a value
+type EvaluatingConditional interface {
+	Conditional
+	Evaluate(any) Result
+}
+
+// Evaluate asks the conditional about the value, a conditional that is not an EvaluatingConditional
+// fails as a whole with a single failure
+func Evaluate(c Conditional, toSet any) Result {
+	return EvaluateCtx(context.Background(), c, toSet)
+}
+
+// EvaluateCtx is Evaluate with the context the questions are asked with, ex: one holding the actor, see WithActor
+// conditionals made by this package pass the context on to the conditionals they combine
+func EvaluateCtx(ctx context.Context, c Conditional, toSet any) Result {
+	switch v := c.(type) {
+	case *conditional:
+		return v.evaluate(ctx, toSet)
+	case *logicalConditional:
+		return v.evaluate(ctx, toSet)
+	case *notConditional:
+		return v.evaluate(ctx, toSet)
+	case EvaluatingConditional:
+		return v.Evaluate(toSet)
+	}
+	ok, err := conditionalMeets(ctx, c, toSet)
+	if ok && err == nil {
+		return Result{Met: true}
+	}
+	return Result{Failures: []Failure{{Prerequisite: -1, Question: -1, Check: "conditional", Err: err}}}
+}
+
+// Evaluate asks every question of every prerequisite the value is a candidate for, unlike Meets it does not stop at the first failure
+func (c *conditional) Evaluate(toSet any) Result {
+	return c.evaluate(context.Background(), toSet)
+}
+
+func (c *conditional) evaluate(ctx context.Context, toSet any) Result {
+	r := Result{Met: true}
+	for i, v := range c.prereqs {
+		if !v.IsCandidate(toSet) {
+			continue
+		}
+		questions := v.questions()
+		var failed []Failure
+		for j, w := range questions {
+			ok, err := w.ask(ctx, toSet)
+			if !ok || err != nil {
+				failed = append(failed, Failure{Prerequisite: i, Question: j, Check: checkName(i, v, j, w), Err: err})
+			}
+		}
+		// a prerequisite with a quorum only reports its failed questions when too few passed
+		if len(questions)-len(failed) >= v.needed(len(questions)) {
+			continue
+		}
+		if len(failed) == 0 {
+			name := v.Name
+			if name == "" {
+				name = fmt.Sprintf("prerequisite %d", i)
+			}
+			failed = append(failed, Failure{Prerequisite: i, Question: -1, Check: fmt.Sprintf("%s: quorum of %d with %d questions", name, v.Quorum, len(questions))})
+		}
+		if v.Severity == SeverityWarning {
+			r.Warnings = append(r.Warnings, failed...)
+			continue
+		}
+		r.Met = false
+		r.Failures = append(r.Failures, failed...)
+	}
+	return r
+}
+
+// names a question by its prerequisite and itself, ex: "has color: green", falling back to their indexes, ex: "prerequisite 0: question 1"
+// a named question of an unnamed prerequisite, or of one with the same name, goes by its own name
+func checkName(i int, p Prerequisite, j int, q NamedQuestion) string {
+	pn, qn := p.Name, q.Name
+	if qn != "" && (pn == "" || pn == qn) {
+		return qn
+	}
+	if pn == "" {
+		pn = fmt.Sprintf("prerequisite %d", i)
+	}
+	if qn == "" {
+		qn = fmt.Sprintf("question %d", j)
+	}
+	return pn + ": " + qn
+}
+
+// the combinators stop where Meets would, And reports the failures of the first child that failed, Or those of every child
+// the warnings of every child that was asked are kept
+func (c *logicalConditional) Evaluate(toSet any) Result {
+	return c.evaluate(context.Background(), toSet)
+}
+
+func (c *logicalConditional) evaluate(ctx context.Context, toSet any) Result {
+	var failures, warnings []Failure
+	met := 0
+	for _, v := range c.conds {
+		r := EvaluateCtx(ctx, v, toSet)
+		warnings = append(warnings, r.Warnings...)
+		if r.Met {
+			met++
+		} else {
+			failures = append(failures, r.Failures...)
+		}
+		switch {
+		case c.op == logicalAnd && !r.Met:
+			return Result{Failures: failures, Warnings: warnings}
+		case c.op == logicalOr && r.Met:
+			return Result{Met: true, Warnings: warnings}
+		case c.op == logicalXor && met > 1:
+			return Result{Failures: []Failure{{Prerequisite: -1, Question: -1, Check: "xor: more than one conditional met"}}, Warnings: warnings}
+		}
+	}
+	switch c.op {
+	case logicalOr:
+		return Result{Failures: failures, Warnings: warnings}
+	case logicalXor:
+		if met == 0 {
+			return Result{Failures: []Failure{{Prerequisite: -1, Question: -1, Check: "xor: no conditional met"}}, Warnings: warnings}
+		}
+	}
+	return Result{Met: true, Warnings: warnings}
+}
+
+func (c *notConditional) Evaluate(toSet any) Result {
+	return c.evaluate(context.Background(), toSet)
+}
+
+func (c *notConditional) evaluate(ctx context.Context, toSet any) Result {
+	r := EvaluateCtx(ctx, c.cond, toSet)
+	for _, v := range r.Failures {
+		if v.Err != nil {
+			return Result{Failures: []Failure{v}, Warnings: r.Warnings}
+		}
+	}
+	if r.Met {
+		return Result{Failures: []Failure{{Prerequisite: -1, Question: -1, Check: "not: conditional met"}}, Warnings: r.Warnings}
+	}
+	return Result{Met: true, Warnings: r.Warnings}
+}
@@ -0,0 +1,143 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testIs(want string) Question {
+	return func() Enforceable {
+		return func(f any) bool { return f == want }
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	c := Conditions(
+		Prerequisite{IsCandidate: EnforceableTrue, Gauntlet: []Question{testIs("a"), testIs("b"), testIs("c")}},
+		// never a candidate, so never asked
+		Prerequisite{IsCandidate: EnforceableFalse, Gauntlet: []Question{testIs("a")}},
+		Prerequisite{IsCandidate: EnforceableTrue, Gauntlet: []Question{testIs("c")}},
+	)
+	r := Evaluate(c, "b")
+	want := []Failure{
+		{Prerequisite: 0, Question: 0, Check: "prerequisite 0: question 0"},
+		{Prerequisite: 0, Question: 2, Check: "prerequisite 0: question 2"},
+		{Prerequisite: 2, Question: 0, Check: "prerequisite 2: question 0"},
+	}
+	if r.Met || !reflect.DeepEqual(r.Failures, want) {
+		t.Errorf("every failed check is reported: got %+v", r)
+	}
+	err := r.Err()
+	var cerr *ConditionError
+	if !errors.Is(err, ErrConditionNotMet) || !errors.As(err, &cerr) || len(cerr.Failures) != 3 {
+		t.Fatalf("got %v", err)
+	}
+	if err.Error() != "field does not meet its conditions: prerequisite 0: question 0, prerequisite 0: question 2, prerequisite 2: question 0" {
+		t.Errorf("got %q", err.Error())
+	}
+	if r := Evaluate(Conditions(), "b"); !r.Met || r.Err() != nil {
+		t.Errorf("got %+v", r)
+	}
+}
+
+// a Conditional that is only a Conditional
+type testOpaque struct {
+	Conditional
+}
+
+func TestEvaluateOpaque(t *testing.T) {
+	var n int
+	if r := Evaluate(testOpaque{testAnswer(true, &n)}, nil); !r.Met {
+		t.Errorf("got %+v", r)
+	}
+	r := Evaluate(testOpaque{testAnswer(false, &n)}, nil)
+	if r.Met || len(r.Failures) != 1 || r.Failures[0].Question != -1 || r.Failures[0].Check != "conditional" {
+		t.Errorf("it fails as a whole: got %+v", r)
+	}
+	r = Evaluate(testFallible{Conditional: testAnswer(true, &n), err: errTestLookup}, nil)
+	if r.Met || !errors.Is(r.Failures[0].Err, errTestLookup) || r.Failures[0].String() != "conditional: lookup failed" {
+		t.Errorf("got %+v", r)
+	}
+}
+
+func TestEvaluateLogical(t *testing.T) {
+	a := Conditions(Prerequisite{Name: "is a", IsCandidate: EnforceableTrue, Gauntlet: []Question{testIs("a")}})
+	b := Conditions(Prerequisite{Name: "is b", IsCandidate: EnforceableTrue, Gauntlet: []Question{testIs("b")}})
+	checks := func(r Result) []string {
+		var out []string
+		for _, v := range r.Failures {
+			out = append(out, v.Check)
+		}
+		return out
+	}
+	for _, c := range []struct {
+		name string
+		c    Conditional
+		in   string
+		met  bool
+		want []string
+	}{
+		{"and stops at the first failure", AndCond(a, b), "c", false, []string{"is a: question 0"}},
+		{"or reports every failure", OrCond(a, b), "c", false, []string{"is a: question 0", "is b: question 0"}},
+		{"or", OrCond(a, b), "b", true, nil},
+		{"xor of none", XorCond(a, b), "c", false, []string{"xor: no conditional met"}},
+		{"xor of two", XorCond(a, a), "a", false, []string{"xor: more than one conditional met"}},
+		{"not", NotCond(a), "a", false, []string{"not: conditional met"}},
+		{"not of a failure", NotCond(a), "b", true, nil},
+	} {
+		r := Evaluate(c.c, c.in)
+		if r.Met != c.met || !reflect.DeepEqual(checks(r), c.want) {
+			t.Errorf("%s: got %v %q", c.name, r.Met, checks(r))
+		}
+	}
+	// an error is passed on through not
+	r := Evaluate(NotCond(testFallible{Conditional: a, err: errTestLookup}), "a")
+	if r.Met || len(r.Failures) != 1 || !errors.Is(r.Failures[0].Err, errTestLookup) {
+		t.Errorf("got %+v", r)
+	}
+}
+
+func TestCheckName(t *testing.T) {
+	isA := NamedQuestion{Name: "is a", Question: testIs("a")}
+	c := Conditions(
+		Prerequisite{Name: "letters", IsCandidate: EnforceableTrue, Gauntlet: []Question{testIs("a")}, Checks: []NamedQuestion{isA}},
+		Prerequisite{IsCandidate: EnforceableTrue, Checks: []NamedQuestion{isA}},
+		Prerequisite{Name: "is a", IsCandidate: EnforceableTrue, Checks: []NamedQuestion{isA}},
+	)
+	r := Evaluate(c, "b")
+	var got []string
+	for _, v := range r.Failures {
+		got = append(got, v.Check)
+	}
+	// the checks are counted after the gauntlet
+	want := []string{"letters: question 0", "letters: is a", "is a", "is a"}
+	if !reflect.DeepEqual(got, want) || r.Failures[1].Question != 1 {
+		t.Errorf("got %q", got)
+	}
+	if !c.Meets("a") || c.Meets("b") {
+		t.Error("the checks are asked like the gauntlet")
+	}
+	for op, c := range map[string]Conditional{"and": AndCond(), "or": OrCond(), "xor": XorCond(), "not": NotCond(Conditions())} {
+		if name := c.Prerequisites()[0].Name; name != op {
+			t.Errorf("got %q, want %q", name, op)
+		}
+	}
+}
+
+func TestEvaluateWarnings(t *testing.T) {
+	c := Conditions(
+		Prerequisite{Name: "usual", Severity: SeverityWarning, IsCandidate: EnforceableTrue, Gauntlet: []Question{testIs("a")}},
+		Prerequisite{Name: "not empty", IsCandidate: EnforceableTrue, Gauntlet: []Question{func() Enforceable { return func(f any) bool { return f != "" } }}},
+	)
+	r := Evaluate(c, "b")
+	if !r.Met || len(r.Warnings) != 1 || r.Warnings[0].Check != "usual: question 0" {
+		t.Errorf("a warning does not reject: got %+v", r)
+	}
+	if !c.Meets("b") {
+		t.Error("a warning does not reject")
+	}
+	if r := Evaluate(AndCond(c, c), "b"); !r.Met || len(r.Warnings) != 2 {
+		t.Errorf("the warnings of every child are kept: got %+v", r)
+	}
+}
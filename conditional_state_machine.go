@@ -2,8 +2,9 @@ package fielder
 
 import (
 	"container/ring"
-	"errors"
+	"context"
 	"sync"
+	"time"
 )
 
 type ConditionalStateMachine struct {
@@ -12,7 +13,7 @@ type ConditionalStateMachine struct {
 
 func NewConditionalStateMachine(states ...ConditionalState) *ConditionalStateMachine {
 	if len(states) == 0 {
-		return &ConditionalStateMachine{StateMachine: &StateMachine{}}
+		return &ConditionalStateMachine{StateMachine: NewStateMachine()}
 	}
 	sm := &ConditionalStateMachine{StateMachine: &StateMachine{
 		Ring:               ring.New(len(states)),
@@ -28,47 +29,26 @@ func NewConditionalStateMachine(states ...ConditionalState) *ConditionalStateMac
 func (sm *ConditionalStateMachine) PopulateConditionalRing(in ...ConditionalState) {
 	// we already created the ring and make it length "len(in)" so we know we can iterate safely through the items in the ring
 	// Initialize the ring with the states. create the id -> ring address cache and the id -> value cache
+	defer sm.lock()()
+	addr := sm.Ring
+	sm.startAddr = addr
 	for _, v := range in {
-		sm.Ring.Value = v
-		sm.IdRingAddressCache[v.Id] = sm.Ring
+		addr.Value = v
+		sm.IdRingAddressCache[v.Id] = addr
 		sm.ValueCache[v.Id] = v.StateValue
-		sm.Ring = sm.Ring.Next()
+		addr = addr.Next()
 	}
 }
 
 // "in" is the current state value, "testData" is the data that will be tested by the conditional questions to determine next state
 // "equals" is a function that allows us to compare values without knowing the exact type ahead of time
 func (sm *ConditionalStateMachine) ProcessInMachine(in StateValue, testData any, equals func(i, j StateValue) bool) (StateValue, error) {
-	stateId := sm.lookupValueCacheId(in, equals)
-	// evaluate state with id stateId
-	currentAddr, ok := sm.IdRingAddressCache[stateId]
-	if !ok {
-		return nil, errors.New("id does not exist in machine")
-	}
-	if currentAddr == nil {
-		return nil, errors.New("address does not exist in machine")
-	}
-	currentState, ok := currentAddr.Value.(ConditionalState)
-	if !ok {
-		return nil, errors.New("error retrieving state")
-	}
-	//
-	nextId, err := currentState.EvaluateTransition(testData)
-	if err != nil {
-		return nil, err
-	}
-	if nextId == "" {
-		return nil, errors.New("next id is empty")
-	}
+	return sm.ProcessInMachineCtx(context.Background(), in, testData, equals)
+}
 
-	value, ok := sm.ValueCache[nextId]
-	if !ok {
-		return nil, errors.New("next id does not exist in machine")
-	}
-	if value == nil {
-		return nil, errors.New("value is nil for next id")
-	}
-	return value, nil
+// conditional states are evaluated by the shared StateMachine path, which moves to the state the outcome names even when it is the same state
+func (sm *ConditionalStateMachine) ProcessInMachineCtx(ctx context.Context, in StateValue, testData any, equals func(i, j StateValue) bool) (StateValue, error) {
+	return sm.StateMachine.ProcessInMachineCtx(ctx, in, testData, equals)
 }
 
 type ConditionalState struct {
@@ -76,22 +56,68 @@ type ConditionalState struct {
 	Outcomes   []ConditionalTransition // the different transitions from this current state
 	StateValue                         // what is the value at this state?
 	Start      bool                    // is this the start state for the machine?
+	Terminal   bool                    // is this an end state for the machine? (no more transitions are needed)
+	OnEnter    TransitionHook          // called after the machine moves into this state
+	OnExit     TransitionHook          // called after the machine moves out of this state
+	Else       StateId                 // the state to move to when no outcome is met, optional
+	Retry      *RetryPolicy            // how a MachineInstance retries the state when no outcome is met, optional
 }
 
 func (s *ConditionalState) EvaluateTransition(dataToTest any) (StateId, error) {
-	for _, v := range s.Outcomes {
-		if v.Conditional.Meets(dataToTest) {
-			return v.NextState, nil
+	return s.EvaluateTransitionCtx(context.Background(), dataToTest)
+}
+
+func (s *ConditionalState) EvaluateTransitionCtx(ctx context.Context, dataToTest any) (StateId, error) {
+	return s.evaluateTransitions(ctx, dataToTest, false)
+}
+
+func (s *ConditionalState) evaluateTransitions(ctx context.Context, dataToTest any, strict bool) (StateId, error) {
+	next, _, err := s.selectTransition(ctx, dataToTest, strict)
+	return next, err
+}
+
+func (s *ConditionalState) selectTransition(ctx context.Context, dataToTest any, strict bool) (StateId, int, error) {
+	if s.Terminal {
+		return s.Id, -1, nil
+	}
+	var matched []int
+	for _, i := range transitionOrder(len(s.Outcomes), func(i int) int { return s.Outcomes[i].Priority }) {
+		v := s.Outcomes[i]
+		if v.Event != "" || v.After > 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return "", -1, err
+		}
+		ok, err := conditionalMeets(ctx, v.Conditional, dataToTest)
+		if err != nil {
+			return "", -1, &GuardError{State: s.Id, NextState: v.NextState, Err: err}
+		}
+		if ok {
+			if !strict {
+				return v.NextState, i, nil
+			}
+			matched = append(matched, i)
 		}
 	}
-	return "", errors.New("no valid transitions available")
+	return chooseTransition(s.Id, s.Else, matched, func(i int) StateId { return s.Outcomes[i].NextState })
 }
 
-// note, each transition conditional must be mutually exclusive
-// only one transition of a state should evaluate to "true" at a time, there should be no overlap between transitions
+// note, each transition conditional should be mutually exclusive
+// when they overlap the transition with the highest priority wins, then the first one in the slice, see SetStrict to report the overlap instead
 type ConditionalTransition struct {
 	NextState   StateId
 	Conditional // we use the conditional interface to support whether we are eligible to move to this next state
+	// the name the conditional is registered under, needed to save the machine, see RegisterConditional
+	ConditionalName string
+	// a transition with an event is only taken when the event is fired, the conditional is then an optional guard on the payload
+	Event string
+	// a timed transition is taken once the machine has been in the state for the duration, see MachineInstance.Expire
+	After time.Duration
+	// transitions with a higher priority are tested first, transitions with the same priority are tested in slice order
+	Priority int
+	// the relative chance of the transition being taken by a weighted step, see MachineInstance.StepWeighted
+	Weight float64
 }
 
 func example() {
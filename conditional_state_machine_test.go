@@ -0,0 +1,56 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConditionalStateTerminal(t *testing.T) {
+	entered := 0
+	csm := NewConditionalStateMachine(
+		ConditionalState{Id: "retry", StateValue: "Retry", Outcomes: []ConditionalTransition{
+			{NextState: "retry", Conditional: testNotBad()},
+			{NextState: "done", Conditional: Conditions()},
+		}, OnEnter: func(TransitionEvent) { entered++ }},
+		ConditionalState{Id: "done", StateValue: "Done", Terminal: true, Outcomes: []ConditionalTransition{
+			{NextState: "retry", Conditional: Conditions()},
+		}},
+	)
+	// a conditional state moves to the state its outcome names even when it is itself
+	if v, err := csm.ProcessInMachine("Retry", testField("Name", "good"), BasicEquals); err != nil || v != "Retry" || entered != 1 {
+		t.Errorf("got %v %v, entered %d times", v, err, entered)
+	}
+	if v, err := csm.ProcessInMachine("Retry", testField("Name", "bad"), BasicEquals); err != nil || v != "Done" {
+		t.Errorf("got %v %v", v, err)
+	}
+	// a terminal conditional state stays where it is like a terminal State
+	if _, err := csm.ProcessInMachine("Done", testField("Name", "good"), BasicEquals); !errors.Is(err, SameStateNoUpdate) {
+		t.Errorf("got %v", err)
+	}
+	if a := csm.Analyze(); len(a.NoTerminalPath) != 0 {
+		t.Errorf("got %+v", a)
+	}
+}
+
+func TestMachineState(t *testing.T) {
+	retry := &RetryPolicy{MaxAttempts: 2, Fallback: "failed"}
+	for _, v := range []any{
+		State{Id: "a", StateValue: "A", Start: true, Matches: []Transition{{NextState: "b"}}, Else: "c", Retry: retry},
+		ConditionalState{Id: "a", StateValue: "A", Start: true, Outcomes: []ConditionalTransition{{NextState: "b"}}, Else: "c", Retry: retry},
+	} {
+		s, ok := asMachineState(v)
+		if !ok {
+			t.Fatalf("%T is a MachineState", v)
+		}
+		if s.StateId() != "a" || s.Value() != "A" || !s.IsStart() || s.IsTerminal() || s.Retries() != retry {
+			t.Errorf("%T: got %s %v", v, s.StateId(), s.Value())
+		}
+		if got := s.NextStates(); !reflect.DeepEqual(got, []StateId{"b", "c", "failed"}) {
+			t.Errorf("%T: got %v", v, got)
+		}
+	}
+	if _, ok := asMachineState("a"); ok {
+		t.Error("a string is not a state")
+	}
+}
@@ -0,0 +1,82 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTrySetValue(t *testing.T) {
+	f := NewConditionalField(testField("Name", "old"), testNotBad())
+	setter, ok := f.(TrySetter)
+	if !ok {
+		t.Fatal("a conditional field is a TrySetter")
+	}
+	if err := setter.TrySetValue(testField("Name", "new")); err != nil || f.ToString() != "new" {
+		t.Errorf("got %s %v", f.ToString(), err)
+	}
+	err := setter.TrySetValue(testField("Name", "bad"))
+	var ferr FieldError
+	var cerr *ConditionError
+	if !errors.As(err, &ferr) || ferr.Key != f.Key() || !errors.As(err, &cerr) || !errors.Is(err, ErrConditionNotMet) {
+		t.Fatalf("got %v", err)
+	}
+	if len(cerr.Failures) != 1 || cerr.Failures[0].Check != "not bad: question 0" {
+		t.Errorf("the rejection names the failed check: got %+v", cerr.Failures)
+	}
+	if f.ToString() != "new" {
+		t.Errorf("a rejected write keeps the value: got %s", f.ToString())
+	}
+	if err := setter.TrySetValue("bad"); !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("got %v", err)
+	}
+	// SetValue still drops rejected writes silently
+	f.SetValue(testField("Name", "bad"))
+	if f.ToString() != "new" {
+		t.Errorf("got %s", f.ToString())
+	}
+}
+
+func TestSetValueResult(t *testing.T) {
+	usual := Prerequisite{Name: "usual", Severity: SeverityWarning, IsCandidate: EnforceableTrue, Gauntlet: []Question{func() Enforceable {
+		return func(f any) bool { return f.(Field).ToString() == "usual" }
+	}}}
+	f := NewConditionalField(testField("Name", "old"), Conditions(usual, testNotBad().Prerequisites()[0])).(*FieldConditional)
+	r := f.SetValueResult(testField("Name", "odd"))
+	if !r.Met || len(r.Warnings) != 1 || f.ToString() != "odd" {
+		t.Errorf("a write with a warning is made and flagged: got %+v %s", r, f.ToString())
+	}
+	r = f.SetValueResult(testField("Name", "bad"))
+	if r.Met || len(r.Failures) != 1 || len(r.Warnings) != 1 || f.ToString() != "odd" {
+		t.Errorf("got %+v %s", r, f.ToString())
+	}
+	if err := f.TrySetValue(testField("Name", "other")); err != nil {
+		t.Errorf("a warning does not reject: got %v", err)
+	}
+}
+
+func TestWouldSet(t *testing.T) {
+	var f ConditionalField = NewConditionalField(testField("Name", "old"), testNotBad()).(*FieldConditional)
+	ok, r := f.WouldSet(testField("Name", "new"))
+	if !ok || !r.Met || f.ToString() != "old" {
+		t.Errorf("a value that would be set is not written: got %v %+v %s", ok, r, f.ToString())
+	}
+	ok, r = f.WouldSet(testField("Name", "bad"))
+	if ok || r.Met || len(r.Failures) != 1 || r.Failures[0].Check != "not bad: question 0" {
+		t.Errorf("got %v %+v", ok, r)
+	}
+	ok, r = f.WouldSet("new")
+	if ok || len(r.Failures) != 1 || r.Failures[0].Check != "string is not a field" || r.Failures[0].Prerequisite != -1 {
+		t.Errorf("got %v %+v", ok, r)
+	}
+	if f.ToString() != "old" {
+		t.Errorf("got %s", f.ToString())
+	}
+	// a field with a default asks the same conditional
+	var d ConditionalField = NewCFWD(testField("Name", "old"), testNotBad().Prerequisites(), testField("Name", "default"))
+	if ok, _ := d.WouldSet(testField("Name", "bad")); ok {
+		t.Error("expected the default field to refuse bad")
+	}
+	if ok, _ := d.WouldSet(testField("Name", "new")); !ok || d.ToString() != "old" {
+		t.Errorf("got %v %s", ok, d.ToString())
+	}
+}
@@ -0,0 +1,150 @@
+package fielder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// time rules are conditionals that depend on when they are asked, ex:
+// After(NewDefaultFieldKey("CreatedAt"), 30*24*time.Hour) is met once 30 days have passed since the CreatedAt field
+// Window(9*time.Hour, 17*time.Hour, Weekdays) is met during business hours
+// rules on a field read it from the value they are asked about like WhenField does, use AskParent to ask them with a parent
+// every rule reads time.Now unless it is given a clock with WithClock
+
+type TimeOption func(*timeConditional)
+
+// the clock the rule reads, ex: a fixed time in tests
+func WithClock(now func() time.Time) TimeOption {
+	return func(c *timeConditional) {
+		c.now = now
+	}
+}
+
+type timeConditional struct {
+	name  string
+	now   func() time.Time
+	meets func(now time.Time, toSet any) bool
+}
+
+func newTimeConditional(name string, meets func(now time.Time, toSet any) bool, opts []TimeOption) Conditional {
+	c := &timeConditional{name: name, now: time.Now, meets: meets}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *timeConditional) String() string {
+	return c.name
+}
+
+func (c *timeConditional) Prerequisites() []Prerequisite {
+	return []Prerequisite{conditionalPrerequisite(c.name, c)}
+}
+
+func (c *timeConditional) Meets(toSet any) bool {
+	return c.meets(c.now(), toSet)
+}
+
+func (c *timeConditional) Evaluate(toSet any) Result {
+	if c.Meets(toSet) {
+		return Result{Met: true}
+	}
+	return Result{Failures: []Failure{{Prerequisite: 0, Question: 0, Check: c.name}}}
+}
+
+// After is met once d has passed since the time in the field, a missing or empty field never meets it
+func After(key FieldKey, d time.Duration, opts ...TimeOption) Conditional {
+	return newTimeConditional(fmt.Sprintf("%s + %s has passed", key.Name, d), func(now time.Time, toSet any) bool {
+		t, ok := timeFromData(toSet, key)
+		return ok && !now.Before(t.Add(d))
+	}, opts)
+}
+
+// Before is met until d has passed since the time in the field, a missing or empty field never meets it
+func Before(key FieldKey, d time.Duration, opts ...TimeOption) Conditional {
+	return newTimeConditional(fmt.Sprintf("%s + %s has not passed", key.Name, d), func(now time.Time, toSet any) bool {
+		t, ok := timeFromData(toSet, key)
+		return ok && now.Before(t.Add(d))
+	}, opts)
+}
+
+// Effective is met from the from time until the until time, a zero bound is open
+func Effective(from, until time.Time, opts ...TimeOption) Conditional {
+	name := "always"
+	switch {
+	case !from.IsZero() && !until.IsZero():
+		name = fmt.Sprintf("from %s until %s", from.Format(time.RFC3339), until.Format(time.RFC3339))
+	case !from.IsZero():
+		name = fmt.Sprintf("from %s", from.Format(time.RFC3339))
+	case !until.IsZero():
+		name = fmt.Sprintf("until %s", until.Format(time.RFC3339))
+	}
+	return newTimeConditional(name, func(now time.Time, _ any) bool {
+		return (from.IsZero() || !now.Before(from)) && (until.IsZero() || now.Before(until))
+	}, opts)
+}
+
+// Weekdays are Monday to Friday
+var Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// Window is met between the start and end times of day of the clock, on the days given, or every day when there are none
+// an end before the start wraps past midnight, the hours after midnight count as the day the window opened
+func Window(start, end time.Duration, days []time.Weekday, opts ...TimeOption) Conditional {
+	name := fmt.Sprintf("between %s and %s", clockTime(start), clockTime(end))
+	if len(days) > 0 {
+		names := make([]string, len(days))
+		for i, v := range days {
+			names[i] = v.String()[:3]
+		}
+		name += " on " + strings.Join(names, ", ")
+	}
+	return newTimeConditional(name, func(now time.Time, _ any) bool {
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		of := now.Sub(midnight)
+		day := now.Weekday()
+		in := of >= start && of < end
+		if end <= start {
+			in = of >= start || of < end
+			if of < end {
+				day = (day + 6) % 7
+			}
+		}
+		if !in {
+			return false
+		}
+		if len(days) == 0 {
+			return true
+		}
+		for _, v := range days {
+			if v == day {
+				return true
+			}
+		}
+		return false
+	}, opts)
+}
+
+func clockTime(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// reads a time.Time or Date field from the data
+func timeFromData(data any, key FieldKey) (time.Time, bool) {
+	f := fieldFromData(data, string(key.Name))
+	if f == nil || f == FieldNil {
+		return time.Time{}, false
+	}
+	switch v := f.Value().(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case *time.Time:
+		if v != nil {
+			return *v, !v.IsZero()
+		}
+	case Date:
+		return v.Time(), !v.IsZero()
+	}
+	return time.Time{}, false
+}
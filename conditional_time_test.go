@@ -0,0 +1,97 @@
+package fielder
+
+import (
+	"testing"
+	"time"
+)
+
+type testCreated struct {
+	CreatedAt time.Time `field:"CreatedAt"`
+}
+
+func TestAfterBefore(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(10 * 24 * time.Hour)
+	clock := WithClock(func() time.Time { return now })
+	key := NewDefaultFieldKey("CreatedAt")
+	after, before := After(key, 30*24*time.Hour, clock), Before(key, 30*24*time.Hour, clock)
+	a := &testCreated{CreatedAt: created}
+	if after.Meets(a) || !before.Meets(a) {
+		t.Error("30 days have not passed")
+	}
+	now = created.Add(30 * 24 * time.Hour)
+	if !after.Meets(a) || before.Meets(a) {
+		t.Error("30 days have passed")
+	}
+	if after.Meets(&testCreated{}) || before.Meets(&testCreated{}) || after.Meets(nil) {
+		t.Error("an empty field never meets a time rule")
+	}
+	if r := Evaluate(before, a); r.Met || r.Failures[0].Check != "CreatedAt + 720h0m0s has not passed" {
+		t.Errorf("got %+v", r)
+	}
+	// asked with a parent
+	if !WithParent(AskParent(after), DefaultParent(a)).Meets(testField("Name", "x")) {
+		t.Error("the rule reads the parent")
+	}
+}
+
+func TestEffective(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := from.Add(24 * time.Hour)
+	var now time.Time
+	clock := WithClock(func() time.Time { return now })
+	for _, c := range []struct {
+		c    Conditional
+		name string
+	}{
+		{Effective(from, until, clock), "from 2025-01-01T00:00:00Z until 2025-01-02T00:00:00Z"},
+		{Effective(from, time.Time{}, clock), "from 2025-01-01T00:00:00Z"},
+		{Effective(time.Time{}, until, clock), "until 2025-01-02T00:00:00Z"},
+		{Effective(time.Time{}, time.Time{}, clock), "always"},
+	} {
+		if got := c.c.(interface{ String() string }).String(); got != c.name {
+			t.Errorf("got %q", got)
+		}
+	}
+	c := Effective(from, until, clock)
+	for at, want := range map[time.Time]bool{from.Add(-time.Second): false, from: true, until.Add(-time.Second): true, until: false} {
+		now = at
+		if c.Meets(nil) != want {
+			t.Errorf("at %s: got %v", at, !want)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	var now time.Time
+	clock := WithClock(func() time.Time { return now })
+	business := Window(9*time.Hour, 17*time.Hour, Weekdays, clock)
+	night := Window(22*time.Hour, 6*time.Hour, []time.Weekday{time.Friday}, clock)
+	if got := business.(interface{ String() string }).String(); got != "between 09:00 and 17:00 on Mon, Tue, Wed, Thu, Fri" {
+		t.Errorf("got %q", got)
+	}
+	// 2025-01-03 is a Friday
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC) }
+	for _, c := range []struct {
+		c    Conditional
+		at   time.Time
+		want bool
+	}{
+		{business, at(3, 9, 0), true},
+		{business, at(3, 16, 59), true},
+		{business, at(3, 17, 0), false},
+		{business, at(3, 8, 59), false},
+		{business, at(4, 12, 0), false},
+		{night, at(3, 23, 0), true},
+		// the hours after midnight count as friday, the day the window opened
+		{night, at(4, 5, 0), true},
+		{night, at(4, 23, 0), false},
+		{night, at(3, 5, 0), false},
+		{Window(0, 24*time.Hour, nil, clock), at(4, 12, 0), true},
+	} {
+		now = c.at
+		if got := c.c.Meets(nil); got != c.want {
+			t.Errorf("%s at %s: got %v", c.c, c.at, got)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package fielder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FieldTransaction stages writes to several fields of a parent and applies all of them or none
+// the conditionals are asked once every write is staged, so rules between fields do not depend on the order of the writes,
+// a conditional made with WithParent reads the staged parent instead of the one it was bound to, ex:
+//
+//	tx := NewFieldTransaction(&item, conds)
+//	tx.SetValue(hasColor).SetValue(green)
+//	err := tx.Commit()
+type FieldTransaction[P any] struct {
+	parent     *P
+	conditions map[FieldKey]Conditional
+	staged     *FieldSet
+}
+
+// the conditions are keyed by field like the ones BuildConditionals returns, they are all asked on commit,
+// so the parent has to meet every rule once the staged writes are applied, not just the rules of the fields written
+func NewFieldTransaction[P any](parent *P, conditions map[FieldKey]Conditional) *FieldTransaction[P] {
+	return &FieldTransaction[P]{parent: parent, conditions: conditions, staged: NewFieldSet()}
+}
+
+// SetValue stages a write, a later write to the same field replaces it
+func (t *FieldTransaction[P]) SetValue(f Field) *FieldTransaction[P] {
+	t.staged.Set(f)
+	return t
+}
+
+// Staged returns the staged writes, in the order they were first made
+func (t *FieldTransaction[P]) Staged() []Field {
+	return t.staged.Fields()
+}
+
+// Rollback drops every staged write
+func (t *FieldTransaction[P]) Rollback() {
+	t.staged = NewFieldSet()
+}
+
+// Parent returns the parent as it would be read after the staged writes
+func (t *FieldTransaction[P]) Parent() Parent {
+	return &stagedParent{Parent: DefaultParent(t.parent), staged: t.staged}
+}
+
+// Evaluate asks every conditional against the staged parent without writing anything, it returns a FieldErrors or nil
+// a staged field is asked by the conditions for its key, its own conditional and the conditional of the member it replaces
+// every other key of the conditions is asked with the field the parent already has
+func (t *FieldTransaction[P]) Evaluate() error {
+	if t.parent == nil {
+		return ErrNotStruct
+	}
+	sp := t.Parent()
+	var errs FieldErrors
+	check := func(key FieldKey, c Conditional, f Field) bool {
+		if c == nil {
+			return true
+		}
+		if err := Evaluate(bindParent(c, sp), f).Err(); err != nil {
+			errs = append(errs, NewFieldError(key, err))
+			return false
+		}
+		return true
+	}
+	pv := reflect.ValueOf(t.parent).Elem()
+	for _, f := range t.staged.Fields() {
+		key := f.Key()
+		if !check(key, t.conditions[key], f) {
+			continue
+		}
+		if c, ok := f.(Conditional); ok && !check(key, c, f) {
+			continue
+		}
+		if existing, ok := memberField(pv, key); ok {
+			if c, ok := existing.(Conditional); ok {
+				check(key, c, f)
+			}
+		}
+	}
+	keys := make([]FieldKey, 0, len(t.conditions))
+	for k := range t.conditions {
+		if !t.staged.Has(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	for _, k := range keys {
+		check(k, t.conditions[k], sp.GetResultItemFieldFromKey(k))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Commit applies every staged write when the staged parent meets every conditional, otherwise nothing is written
+// a member that can not hold its staged field is found before any member is written
+// the staged writes are cleared once they are applied
+func (t *FieldTransaction[P]) Commit() error {
+	if err := t.Evaluate(); err != nil {
+		return err
+	}
+	pv := reflect.ValueOf(t.parent).Elem()
+	var errs FieldErrors
+	for _, f := range t.staged.Fields() {
+		member := reflectValueByPath(pv, f.Key().Name)
+		if !member.IsValid() {
+			errs = append(errs, NewFieldError(f.Key(), fmt.Errorf("%s: no member named %s", pv.Type(), f.Key().Name)))
+			continue
+		}
+		if _, ok := memberField(pv, f.Key()); ok {
+			continue
+		}
+		if err := assignField(reflect.New(member.Type()).Elem(), f); err != nil {
+			errs = append(errs, NewFieldError(f.Key(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, f := range t.staged.Fields() {
+		if existing, ok := memberField(pv, f.Key()); ok {
+			// the conditional of the member was asked with the staged parent, asking it again with the field alone could drop the write
+			switch fc := existing.(type) {
+			case *FieldConditional:
+				existing = fc.Field
+			case *conditionalFieldWDefault:
+				existing = fc.Field
+			}
+			existing.SetValue(f)
+			continue
+		}
+		_ = assignField(reflectValueByPath(pv, f.Key().Name), f)
+	}
+	t.Rollback()
+	return nil
+}
+
+// the member of the parent that is itself a field and is updated in place, as ApplyField does
+func memberField(pv reflect.Value, key FieldKey) (Field, bool) {
+	member := reflectValueByPath(pv, key.Name)
+	if !member.IsValid() || member.IsZero() || !member.CanInterface() {
+		return nil, false
+	}
+	f, ok := member.Interface().(Field)
+	return f, ok && f != nil
+}
+
+// a parent read through the writes staged over it
+type stagedParent struct {
+	Parent
+	staged *FieldSet
+}
+
+func (p *stagedParent) GetResultItemFieldFromKey(f FieldKey) Field {
+	if p.staged.Has(f) {
+		return p.staged.Get(f)
+	}
+	return p.Parent.GetResultItemFieldFromKey(f)
+}
+
+// returns the conditional with every conditional made by WithParent inside it reading the parent instead
+func bindParent(c Conditional, parent Parent) Conditional {
+	switch v := c.(type) {
+	case *parentConditional:
+		return WithParent(v.cond, parent)
+	case *notConditional:
+		return NotCond(bindParent(v.cond, parent))
+	case *logicalConditional:
+		conds := make([]Conditional, len(v.conds))
+		for i, w := range v.conds {
+			conds[i] = bindParent(w, parent)
+		}
+		return &logicalConditional{op: v.op, conds: conds}
+	}
+	return c
+}
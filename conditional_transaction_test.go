@@ -0,0 +1,92 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+func testBool(name string, v bool) *BoolField {
+	return &BoolField{ValueField: v, Set: true, KeyField: NewDefaultFieldKey(name)}
+}
+
+func testColorConditions() map[FieldKey]Conditional {
+	// bound to a parent of its own, the transaction asks it with the staged parent
+	return map[FieldKey]Conditional{NewDefaultFieldKey("HasColor"): WithParent(testRequiresGreen, DefaultParent(&testColor{}))}
+}
+
+func TestFieldTransaction(t *testing.T) {
+	// the rule between the fields does not depend on the order of the writes
+	for _, writes := range [][]Field{
+		{testBool("HasColor", true), testBool("Green", true), testField("Note", "green now")},
+		{testField("Note", "green now"), testBool("Green", true), testBool("HasColor", true)},
+	} {
+		note := testField("Note", "")
+		c := &testColor{Note: note}
+		tx := NewFieldTransaction(c, testColorConditions())
+		for _, f := range writes {
+			tx.SetValue(f)
+		}
+		if len(tx.Staged()) != 3 {
+			t.Errorf("got %d staged writes", len(tx.Staged()))
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if !c.Green || !c.HasColor || c.Note != note || note.ToString() != "green now" {
+			t.Errorf("got %+v, a field member is updated in place", c)
+		}
+		if len(tx.Staged()) != 0 {
+			t.Error("the writes are cleared once applied")
+		}
+	}
+}
+
+func TestFieldTransactionRejected(t *testing.T) {
+	c := &testColor{Note: testField("Note", "old")}
+	tx := NewFieldTransaction(c, testColorConditions())
+	tx.SetValue(testField("Note", "new")).SetValue(testBool("HasColor", true))
+	if tx.Parent().GetResultItemFieldFromKey(NewDefaultFieldKey("Note")).ToString() != "new" {
+		t.Error("the staged parent reads the staged writes")
+	}
+	err := tx.Commit()
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Key.Name != "HasColor" || !errors.Is(err, ErrConditionNotMet) {
+		t.Fatalf("got %v", err)
+	}
+	if c.HasColor || c.Note.ToString() != "old" {
+		t.Errorf("nothing is written: got %+v", c)
+	}
+	// the rules of fields that were not written are asked too
+	c.HasColor = true
+	if err := NewFieldTransaction(c, testColorConditions()).SetValue(testField("Note", "x")).Evaluate(); err == nil {
+		t.Error("the parent has to meet every rule")
+	}
+	tx.Rollback()
+	if len(tx.Staged()) != 0 {
+		t.Error("got staged writes")
+	}
+}
+
+func TestFieldTransactionMembers(t *testing.T) {
+	c := &testColor{Green: true}
+	// a member that can not hold its write is found before anything is written
+	err := NewFieldTransaction(c, nil).SetValue(testBool("Green", false)).SetValue(testField("Missing", "x")).Commit()
+	if err == nil || !c.Green {
+		t.Errorf("got %v %+v", err, c)
+	}
+	// the member's own conditional is asked
+	type guardedParent struct {
+		Bio ConditionalField `field:"Bio"`
+	}
+	g := &guardedParent{Bio: NewConditionalField(testField("Bio", "old"), testNotBad())}
+	if err := NewFieldTransaction(g, nil).SetValue(testField("Bio", "bad")).Commit(); err == nil || g.Bio.ToString() != "old" {
+		t.Errorf("got %v %s", err, g.Bio.ToString())
+	}
+	if err := NewFieldTransaction(g, nil).SetValue(testField("Bio", "good")).Commit(); err != nil || g.Bio.ToString() != "good" {
+		t.Errorf("got %v %s", err, g.Bio.ToString())
+	}
+	var missing *testColor
+	if err := NewFieldTransaction(missing, nil).Commit(); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// struct tag holding the rules a member's value has to meet, ex: field:"Email" validate:"required,email"
+const ValidateTag = "validate"
+
+var ErrUnknownValidation = errors.New("unknown validation rule")
+
+// the rules of the validate tag, an empty value is only checked by required
+// required   the value is not empty
+// email, url the string parses as an EmailField or URLField does
+// min=n      a number is at least n, a string, slice or map has at least n items
+// max=n      a number is at most n, a string, slice or map has at most n items
+// len=n      a string, slice or map has exactly n items
+// oneof=a b  the value is one of the space separated values
+
+// BuildConditionals compiles the validate tags of the parent into a conditional for each member that has one,
+// keyed like the conditions BindForm and WithEnvConditions take, each rule is a check named after itself, ex: Email: email
+func BuildConditionals[P any]() (map[FieldKey]Conditional, error) {
+	ty := reflect.TypeOf(*new(P))
+	for ty != nil && ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	out := make(map[FieldKey]Conditional)
+	var errs []error
+	for i := 0; i < ty.NumField(); i++ {
+		sf := ty.Field(i)
+		name, _ := parseFieldTag(sf.Tag.Get(FieldKeyTag))
+		tag := sf.Tag.Get(ValidateTag)
+		if name == "" || tag == "" || !sf.IsExported() {
+			continue
+		}
+		key := NewDefaultFieldKey(name)
+		checks := []NamedQuestion{}
+		for _, rule := range strings.Split(tag, ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				continue
+			}
+			check, err := validationCheck(rule)
+			if err != nil {
+				errs = append(errs, NewFieldError(key, err))
+				continue
+			}
+			checks = append(checks, NamedQuestion{Name: rule, Question: func() Enforceable { return check }})
+		}
+		out[key] = Conditions(Prerequisite{Name: string(key.Name), IsCandidate: EnforceableTrue, Checks: checks})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return out, nil
+}
+
+func validationCheck(rule string) (Enforceable, error) {
+	name, arg, _ := strings.Cut(rule, "=")
+	number := func() (float64, error) {
+		n, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: %v", ErrUnknownValidation, rule, err)
+		}
+		return n, nil
+	}
+	switch name {
+	case "required":
+		return func(toSet any) bool { return !validationEmpty(validationValue(toSet)) }, nil
+	case "email":
+		return validationString(func(st string) bool { return (&EmailField{}).ParseString(st) == nil }), nil
+	case "url":
+		return validationString(func(st string) bool { return (&URLField{}).ParseString(st) == nil }), nil
+	case "min", "max", "len":
+		n, err := number()
+		if err != nil {
+			return nil, err
+		}
+		return func(toSet any) bool {
+			v := validationValue(toSet)
+			if validationEmpty(v) {
+				return true
+			}
+			size, isLen, ok := validationSize(v)
+			if !ok || (name == "len" && !isLen) {
+				return false
+			}
+			switch name {
+			case "min":
+				return size >= n
+			case "max":
+				return size <= n
+			}
+			return size == n
+		}, nil
+	case "oneof":
+		allowed := strings.Fields(arg)
+		return func(toSet any) bool {
+			v := validationValue(toSet)
+			if validationEmpty(v) {
+				return true
+			}
+			st := fmt.Sprint(v.Interface())
+			return SliceContains(allowed, st, func(s1, s2 string) bool { return s1 == s2 })
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownValidation, rule)
+}
+
+// the value being set, with fields unwrapped and pointers followed
+func validationValue(toSet any) reflect.Value {
+	if f, ok := toSet.(Field); ok {
+		if f == FieldNil {
+			return reflect.Value{}
+		}
+		toSet = f.Value()
+	}
+	v := reflect.ValueOf(toSet)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func validationEmpty(v reflect.Value) bool {
+	return !v.IsValid() || v.IsZero()
+}
+
+// the number a value is measured by, a length for strings, slices and maps
+func validationSize(v reflect.Value) (float64, bool, bool) {
+	if d, ok := v.Interface().(decimal.Decimal); ok {
+		return d.InexactFloat64(), false, true
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	}
+	return 0, false, false
+}
+
+func validationString(ok func(st string) bool) Enforceable {
+	return func(toSet any) bool {
+		v := validationValue(toSet)
+		if validationEmpty(v) {
+			return true
+		}
+		if v.Kind() != reflect.String {
+			return false
+		}
+		return ok(v.String())
+	}
+}
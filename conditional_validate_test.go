@@ -0,0 +1,85 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+type testValidated struct {
+	Email   string          `field:"Email" validate:"required,email"`
+	Site    string          `field:"Site" validate:"url"`
+	Age     int             `field:"Age" validate:"min=18,max=130"`
+	Code    string          `field:"Code" validate:"len=4"`
+	Plan    string          `field:"Plan" validate:"oneof=free pro"`
+	Tags    []string        `field:"Tags" validate:"max=2"`
+	Balance decimal.Decimal `field:"Balance" validate:"min=0"`
+	Note    string          `field:"Note"`
+	hidden  string          `field:"hidden" validate:"required"`
+}
+
+func TestBuildConditionals(t *testing.T) {
+	conds, err := BuildConditionals[testValidated]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conds) != 7 {
+		t.Errorf("only exported members with a validate tag get a conditional: got %d", len(conds))
+	}
+	meets := func(name string, v any) bool {
+		return conds[NewDefaultFieldKey(name)].Meets(v)
+	}
+	for _, c := range []struct {
+		name string
+		v    any
+		want bool
+	}{
+		{"Email", "ann@example.com", true},
+		{"Email", "", false},
+		{"Email", "not an email", false},
+		{"Email", testField("Email", "ann@example.com"), true},
+		{"Site", "", true},
+		{"Site", "https://example.com", true},
+		{"Site", 10, false},
+		{"Age", 17, false},
+		{"Age", 18, true},
+		{"Age", 131, false},
+		{"Age", 0, true},
+		{"Code", "abcd", true},
+		{"Code", "abc", false},
+		{"Code", 1234, false},
+		{"Plan", "pro", true},
+		{"Plan", "gold", false},
+		{"Tags", []string{"a", "b"}, true},
+		{"Tags", []string{"a", "b", "c"}, false},
+		{"Balance", decimal.NewFromInt(-1), false},
+		{"Balance", decimal.NewFromInt(5), true},
+	} {
+		if got := meets(c.name, c.v); got != c.want {
+			t.Errorf("%s %v: got %v", c.name, c.v, got)
+		}
+	}
+	r := Evaluate(conds[NewDefaultFieldKey("Age")], 200)
+	if r.Met || len(r.Failures) != 1 || r.Failures[0].Check != "Age: max=130" {
+		t.Errorf("each rule is a check named after itself: got %+v", r.Failures)
+	}
+}
+
+func TestBuildConditionalsErrors(t *testing.T) {
+	type bad struct {
+		Age  int    `field:"Age" validate:"min=ten"`
+		Name string `field:"Name" validate:"shout"`
+	}
+	_, err := BuildConditionals[bad]()
+	var ferr FieldError
+	if !errors.Is(err, ErrUnknownValidation) || !errors.As(err, &ferr) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := BuildConditionals[string](); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("got %v", err)
+	}
+	if conds, err := BuildConditionals[*testValidated](); err != nil || len(conds) != 7 {
+		t.Errorf("a pointer parent is read through: got %v", err)
+	}
+}
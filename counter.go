@@ -0,0 +1,97 @@
+package fielder
+
+import (
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+// CounterField is an integer field that is safe to change from many goroutines at once
+// use Add, Increment and Decrement rather than reading, changing and calling SetValue
+type CounterField struct {
+	value    atomic.Int64
+	KeyField FieldKey `dynamodbav:"key" json:"key"`
+}
+
+func NewCounterField(key FieldKey, start int64) *CounterField {
+	c := &CounterField{KeyField: key}
+	c.value.Store(start)
+	return c
+}
+
+// adds n (which may be negative) and returns the new value
+func (s *CounterField) Add(n int64) int64 {
+	return s.value.Add(n)
+}
+
+func (s *CounterField) Increment() int64 {
+	return s.value.Add(1)
+}
+
+func (s *CounterField) Decrement() int64 {
+	return s.value.Add(-1)
+}
+
+func (s *CounterField) Load() int64 {
+	return s.value.Load()
+}
+
+func (s *CounterField) Value() FieldValue {
+	return s.value.Load()
+}
+
+func (s *CounterField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *CounterField) Type() reflect.Type {
+	return reflect.TypeOf(int64(0))
+}
+
+func (s *CounterField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.Load() < in2.(*CounterField).Load()
+}
+
+func (s *CounterField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.Load() > in2.(*CounterField).Load()
+}
+
+func (s *CounterField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.Load() == in2.(*CounterField).Load()
+}
+
+func (s *CounterField) ToString() string {
+	return strconv.FormatInt(s.Load(), 10)
+}
+
+func (s *CounterField) FromString(st string) {
+	it, err := strconv.ParseInt(st, 10, 64)
+	if err != nil {
+		return
+	}
+	s.value.Store(it)
+}
+
+func (s *CounterField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.value.Store(in2.(*CounterField).Load())
+	return
+}
+
+func (s *CounterField) IsEmpty() bool {
+	return s.Load() == 0
+}
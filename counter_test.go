@@ -0,0 +1,53 @@
+package fielder
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCounterFieldConcurrent(t *testing.T) {
+	c := NewCounterField(NewDefaultFieldKey("Views"), 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); c.Increment() }()
+		go func() { defer wg.Done(); c.Add(2) }()
+	}
+	wg.Wait()
+	if c.Load() != 160 {
+		t.Errorf("got %d", c.Load())
+	}
+	if c.Decrement() != 159 || c.Value() != int64(159) {
+		t.Errorf("Decrement: got %d", c.Load())
+	}
+}
+
+func TestCounterFieldString(t *testing.T) {
+	c := &CounterField{}
+	if !c.IsEmpty() {
+		t.Error("a zero counter is empty")
+	}
+	c.FromString("-7")
+	if c.ToString() != "-7" {
+		t.Errorf("got %q", c.ToString())
+	}
+	c.FromString("seven")
+	if c.Load() != -7 {
+		t.Errorf("bad input is ignored: got %d", c.Load())
+	}
+	c.SetValue(NewCounterField(FieldKeyNil, 3))
+	if c.Load() != 3 {
+		t.Errorf("SetValue: got %d", c.Load())
+	}
+	c.SetValue(&StringField{ValueField: "4"})
+	if c.Load() != 4 {
+		t.Errorf("SetValue from string: got %d", c.Load())
+	}
+}
+
+func TestCounterFieldCompare(t *testing.T) {
+	a, b := NewCounterField(FieldKeyNil, 9), NewCounterField(FieldKeyNil, 10)
+	if !a.LessThan(b) || !b.GreaterThan(a) || a.Equal(b) || !a.Equal(NewCounterField(FieldKeyNil, 9)) {
+		t.Error("counters compare by value")
+	}
+}
@@ -0,0 +1,110 @@
+package fielder
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// default parents as csv, one row per parent and one column per tagged member
+// the header row is the key set of the parent, and each cell is the string form of the field
+// a nil pointer member is written as an empty cell, and an empty cell leaves the member at its zero value
+
+func csvKeySet[P any]() []FieldKey {
+	keys := []FieldKey{}
+	for _, k := range FullKeySet[P](FieldKeyTag) {
+		if k.Name != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func WriteCSV[P any](w io.Writer, parents []P) error {
+	keys := csvKeySet[P]()
+	cw := csv.NewWriter(w)
+	header := make([]string, 0, len(keys))
+	for _, k := range keys {
+		header = append(header, k.Name.String())
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, p := range parents {
+		fields := ToFieldMap(p)
+		row := make([]string, 0, len(keys))
+		for _, k := range keys {
+			if f, ok := fields[k]; ok {
+				if IsSensitive(f) {
+					return sensitiveFieldError(f)
+				}
+				row = append(row, f.ToString())
+			} else {
+				row = append(row, "")
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// the columns can be in any order, but every column has to be a key of the parent
+// cells are only asked about conditionals when WithDecodeConditions is given
+func ReadCSV[P any](r io.Reader, opts ...DecodeOption) ([]P, error) {
+	o := newDecodeOptions(opts)
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return []P{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	keySet := csvKeySet[P]()
+	keys := make([]FieldKey, 0, len(header))
+	for _, name := range header {
+		if !IsFieldKey(FieldName(name), keySet) {
+			return nil, fmt.Errorf("csv column %s is not a key of %s", name, reflect.TypeOf(*new(P)))
+		}
+		keys = append(keys, NewDefaultFieldKey(name))
+	}
+	out := []P{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := map[FieldKey]Field{}
+		for i, cell := range row {
+			if cell == "" {
+				continue
+			}
+			f := newMemberField(GetFieldTypeFromKey[P](keys[i]), keys[i])
+			if f == nil {
+				return nil, fmt.Errorf("csv column %s: %w", keys[i].Name, ErrUnknownFieldType)
+			}
+			if err := ParseFieldString(f, cell); err != nil {
+				return nil, fmt.Errorf("csv column %s: %w", keys[i].Name, err)
+			}
+			ok, err := o.check(len(out), f)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				fields[keys[i]] = f
+			}
+		}
+		p, err := FromFieldMap[P](fields)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+}
@@ -0,0 +1,68 @@
+package fielder
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	nick := "s"
+	in := []testProfile{
+		{Name: "sam", Age: 30, Nickname: &nick, Bio: testField("Bio", "likes, commas")},
+		{Name: "alex"},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	want := "Name,Age,Nickname,Bio,Note\nsam,30,s,\"likes, commas\",\nalex,0,,,\n"
+	if buf.String() != want {
+		t.Errorf("got %q", buf.String())
+	}
+	out, err := ReadCSV[testProfile](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0].Name != "sam" || out[0].Age != 30 || *out[0].Nickname != "s" || out[1].Nickname != nil {
+		t.Fatalf("got %#v", out)
+	}
+	if out[0].Bio == nil || out[0].Bio.ValueField != "likes, commas" || out[0].Bio.Key() != NewDefaultFieldKey("Bio") || out[1].Bio != nil {
+		t.Errorf("members that are fields are read into a new field: got %#v", out[0].Bio)
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	if out, err := ReadCSV[testProfile](strings.NewReader("")); err != nil || len(out) != 0 {
+		t.Errorf("an empty input has no rows: got %v %v", out, err)
+	}
+	if _, err := ReadCSV[testProfile](strings.NewReader("Name,Shoe\nsam,9\n")); err == nil {
+		t.Error("columns that are not keys are an error")
+	}
+	if _, err := ReadCSV[testProfile](strings.NewReader("Age\nold\n")); err == nil {
+		t.Error("cells that do not parse are an error")
+	}
+	out, err := ReadCSV[testProfile](strings.NewReader("Age,Name\n7,kim\n"))
+	if err != nil || out[0].Name != "kim" || out[0].Age != 7 {
+		t.Errorf("the columns can be in any order: got %#v %v", out, err)
+	}
+}
+
+func TestReadCSVConditions(t *testing.T) {
+	input := "Name,Bio\nsam,bad\nalex,fine\n"
+	report := &DecodeReport{}
+	conds := map[FieldKey]Conditional{NewDefaultFieldKey("Bio"): testNotBad()}
+	out, err := ReadCSV[testProfile](strings.NewReader(input), WithDecodeConditions(conds, report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].Bio != nil || out[1].Bio.ValueField != "fine" {
+		t.Errorf("rejected cells are left out: got %#v", out)
+	}
+	if r := report.Rejections(); len(r) != 1 || r[0].Row != 0 || r[0].Value != "bad" {
+		t.Errorf("got %v", r)
+	}
+	if _, err := ReadCSV[testProfile](strings.NewReader(input), WithDecodeConditions(conds, nil)); err == nil {
+		t.Error("without a report the first rejection fails the read")
+	}
+}
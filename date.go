@@ -0,0 +1,124 @@
+package fielder
+
+import (
+	"reflect"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+// layouts DateField.FromString will try, in order. anything with a time component is truncated to its date
+var DateLayouts = []string{
+	DateLayout,
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006/01/02",
+	"01/02/2006",
+	"20060102",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+}
+
+// Date is a calendar date with no time or location
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// the date of t in t's own location
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// midnight UTC at the start of the date
+func (d Date) Time() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+func (d Date) Before(d2 Date) bool {
+	return d.Time().Before(d2.Time())
+}
+
+func (d Date) After(d2 Date) bool {
+	return d.Time().After(d2.Time())
+}
+
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+func (d Date) String() string {
+	return d.Time().Format(DateLayout)
+}
+
+type DateField struct {
+	ValueField Date     `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey `dynamodbav:"key" json:"key"`
+}
+
+func (s *DateField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *DateField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *DateField) Type() reflect.Type {
+	return reflect.TypeOf(Date{})
+}
+
+func (s *DateField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.ValueField.Before(in2.(*DateField).ValueField)
+}
+
+func (s *DateField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.ValueField.After(in2.(*DateField).ValueField)
+}
+
+func (s *DateField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField == in2.(*DateField).ValueField
+}
+
+func (s *DateField) ToString() string {
+	if s.ValueField.IsZero() {
+		return ""
+	}
+	return s.ValueField.String()
+}
+
+// tries every layout in DateLayouts, the date is taken in the location of the parsed input
+func (s *DateField) FromString(st string) {
+	for _, layout := range DateLayouts {
+		if t, err := time.Parse(layout, st); err == nil {
+			s.ValueField = DateOf(t)
+			return
+		}
+	}
+}
+
+func (s *DateField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = in2.(*DateField).ValueField
+	return
+}
+
+func (s *DateField) IsEmpty() bool {
+	return s.ValueField.IsZero()
+}
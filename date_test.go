@@ -0,0 +1,54 @@
+package fielder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateFieldLayouts(t *testing.T) {
+	want := Date{Year: 2024, Month: time.March, Day: 5}
+	for _, in := range []string{"2024-03-05", "2024-03-05T23:30:00-05:00", "2024/03/05", "03/05/2024", "20240305", "Mar 5, 2024", "5 Mar 2024"} {
+		f := &DateField{}
+		f.FromString(in)
+		if f.ValueField != want {
+			t.Errorf("FromString(%q): got %v", in, f.ValueField)
+		}
+	}
+	f := &DateField{ValueField: want}
+	f.FromString("tomorrow")
+	if f.ValueField != want {
+		t.Error("input matching no layout is ignored")
+	}
+	if f.ToString() != "2024-03-05" || (&DateField{}).ToString() != "" {
+		t.Errorf("ToString: got %q", f.ToString())
+	}
+}
+
+func TestDateFieldCompare(t *testing.T) {
+	early := &DateField{ValueField: Date{2024, time.January, 31}}
+	late := &DateField{ValueField: Date{2024, time.February, 1}}
+	if !early.LessThan(late) || !late.GreaterThan(early) || early.GreaterThan(late) {
+		t.Error("dates are ordered by the calendar")
+	}
+	if !early.Equal(&DateField{ValueField: early.ValueField}) || early.Equal(late) {
+		t.Error("dates are equal when their days are")
+	}
+	f := &DateField{}
+	f.SetValue(&TimeField{ValueField: time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)})
+	if !f.Equal(late) {
+		t.Errorf("SetValue from a time keeps the date: got %v", f.ValueField)
+	}
+}
+
+func TestDate(t *testing.T) {
+	d := DateOf(time.Date(2024, 3, 5, 23, 59, 0, 0, time.FixedZone("x", -5*3600)))
+	if d != (Date{2024, time.March, 5}) {
+		t.Errorf("DateOf uses the time's own location: got %v", d)
+	}
+	if !d.Time().Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) || d.String() != "2024-03-05" {
+		t.Errorf("Time: got %v", d.Time())
+	}
+	if d.IsZero() || !(Date{}).IsZero() {
+		t.Error("only the zero date is zero")
+	}
+}
@@ -0,0 +1,142 @@
+package fielder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+type SQLDialect int
+
+const (
+	DialectPostgres SQLDialect = iota
+	DialectMySQL
+	DialectSQLite
+)
+
+// the tag options that shape a column, ex: field:"Id,primarykey" or field:"Nickname,nullable"
+// members are NOT NULL unless they are pointers or tagged nullable, notnull overrides a pointer
+const (
+	TagOptionPrimaryKey = "primarykey"
+	TagOptionNullable   = "nullable"
+	TagOptionNotNull    = "notnull"
+)
+
+// column types for each field type name, in dialect order
+// the types line up with what SQLValue writes, ex: a duration is written in its string form so its column is text
+var sqlColumnTypes = map[string][3]string{
+	"string":   {"TEXT", "VARCHAR(255)", "TEXT"},
+	"time":     {"TIMESTAMPTZ", "DATETIME(6)", "DATETIME"},
+	"decimal":  {"NUMERIC", "DECIMAL(65,30)", "NUMERIC"},
+	"integer":  {"BIGINT", "BIGINT", "INTEGER"},
+	"bool":     {"BOOLEAN", "BOOLEAN", "INTEGER"},
+	"float":    {"DOUBLE PRECISION", "DOUBLE", "REAL"},
+	"duration": {"TEXT", "VARCHAR(64)", "TEXT"},
+	"uuid":     {"UUID", "CHAR(36)", "TEXT"},
+	"bytes":    {"BYTEA", "BLOB", "BLOB"},
+	"date":     {"DATE", "DATE", "DATE"},
+	"money":    {"TEXT", "VARCHAR(64)", "TEXT"},
+	"quantity": {"TEXT", "VARCHAR(64)", "TEXT"},
+	"url":      {"TEXT", "TEXT", "TEXT"},
+	"email":    {"TEXT", "VARCHAR(320)", "TEXT"},
+	"ip":       {"INET", "VARCHAR(45)", "TEXT"},
+	"cidr":     {"CIDR", "VARCHAR(49)", "TEXT"},
+	"geo":      {"TEXT", "VARCHAR(64)", "TEXT"},
+	"enum":     {"TEXT", "VARCHAR(255)", "TEXT"},
+	"counter":  {"BIGINT", "BIGINT", "INTEGER"},
+	"slice":    {"JSONB", "JSON", "TEXT"},
+	"map":      {"JSONB", "JSON", "TEXT"},
+	"struct":   {"JSONB", "JSON", "TEXT"},
+}
+
+func (d SQLDialect) quote(name string) string {
+	if d == DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// GenerateCreateTable returns the CREATE TABLE statement for a default parent, one column per tagged member
+// the table is named after the parent type in snake case, ex: UserAccount becomes user_account, and columns keep the field names
+func GenerateCreateTable[P any](dialect SQLDialect) (string, error) {
+	ty := reflect.TypeOf(*new(P))
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return "", ErrNotStruct
+	}
+	if dialect < DialectPostgres || dialect > DialectSQLite {
+		return "", fmt.Errorf("unknown sql dialect %d", dialect)
+	}
+	columns := []string{}
+	primary := []string{}
+	for i := 0; i < ty.NumField(); i++ {
+		sf := ty.Field(i)
+		name, opts := parseFieldTag(sf.Tag.Get(FieldKeyTag))
+		if name == "" || !sf.IsExported() {
+			continue
+		}
+		colType, err := sqlColumnType(sf.Type, dialect)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		hasOpt := func(opt string) bool {
+			return SliceContains(opts, opt, func(s1, s2 string) bool { return s1 == s2 })
+		}
+		column := dialect.quote(name) + " " + colType
+		if !hasOpt(TagOptionNotNull) && (sf.Type.Kind() == reflect.Pointer || hasOpt(TagOptionNullable)) {
+			column += " NULL"
+		} else {
+			column += " NOT NULL"
+		}
+		columns = append(columns, column)
+		if hasOpt(TagOptionPrimaryKey) {
+			primary = append(primary, dialect.quote(name))
+		}
+	}
+	if len(primary) > 0 {
+		columns = append(columns, "PRIMARY KEY ("+strings.Join(primary, ", ")+")")
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);", dialect.quote(snakeCase(ty.Name())), strings.Join(columns, ",\n\t")), nil
+}
+
+func sqlColumnType(ty reflect.Type, dialect SQLDialect) (string, error) {
+	if ty.Kind() == reflect.Pointer && ty.Implements(fieldInterfaceType) && ty.Elem().Kind() == reflect.Struct {
+		// members that are concrete fields use the type of value they hold,
+		// wrappers, ex: *FieldNullable, only know the field they hold once it is set
+		member := newMemberField(ty, FieldKeyNil)
+		if member == nil {
+			return "", fmt.Errorf("%w: %s wraps a field that is not known until it is set", ErrUnknownFieldType, ty)
+		}
+		ty = member.Type()
+	} else if ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+	if ty == nil {
+		return "", ErrUnknownFieldType
+	}
+	f := CreateFieldFromType(ty, nil, FieldKeyNil)
+	if f == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownFieldType, ty)
+	}
+	types, ok := sqlColumnTypes[FieldTypeName(f)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownFieldType, ty)
+	}
+	return types[dialect], nil
+}
+
+// ex: UserAccount becomes user_account, and HTTPServer becomes http_server
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
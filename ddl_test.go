@@ -0,0 +1,87 @@
+package fielder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testUserAccount struct {
+	Id       string       `field:"Id,primarykey"`
+	Age      int          `field:"Age"`
+	Joined   time.Time    `field:"Joined"`
+	Nickname *string      `field:"Nickname"`
+	Bio      *StringField `field:"Bio,notnull"`
+	Note     string       `field:"Note,nullable"`
+	Tags     []string     `field:"Tags"`
+	internal string
+	Skipped  string
+}
+
+func TestGenerateCreateTable(t *testing.T) {
+	got, err := GenerateCreateTable[testUserAccount](DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `CREATE TABLE "test_user_account" (
+	"Id" TEXT NOT NULL,
+	"Age" BIGINT NOT NULL,
+	"Joined" TIMESTAMPTZ NOT NULL,
+	"Nickname" TEXT NULL,
+	"Bio" TEXT NOT NULL,
+	"Note" TEXT NULL,
+	"Tags" JSONB NOT NULL,
+	PRIMARY KEY ("Id")
+);`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateCreateTableDialects(t *testing.T) {
+	mysql, err := GenerateCreateTable[testUserAccount](DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mysql, "`Id` VARCHAR(255) NOT NULL") || !strings.Contains(mysql, "`Joined` DATETIME(6)") {
+		t.Errorf("mysql: %s", mysql)
+	}
+	sqlite, err := GenerateCreateTable[testUserAccount](DialectSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sqlite, `"Age" INTEGER NOT NULL`) {
+		t.Errorf("sqlite: %s", sqlite)
+	}
+	if _, err := GenerateCreateTable[testUserAccount](SQLDialect(9)); err == nil {
+		t.Error("an unknown dialect is an error")
+	}
+	if _, err := GenerateCreateTable[int](DialectPostgres); err != ErrNotStruct {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestGenerateCreateTableWrappers(t *testing.T) {
+	type nullable struct {
+		Id  string         `field:"Id"`
+		Bio *FieldNullable `field:"Bio"`
+	}
+	type conditional struct {
+		Status *FieldConditional `field:"Status"`
+	}
+	if _, err := GenerateCreateTable[nullable](DialectPostgres); !errors.Is(err, ErrUnknownFieldType) || !strings.HasPrefix(err.Error(), "Bio: ") {
+		t.Errorf("nullable member: got %v", err)
+	}
+	if _, err := GenerateCreateTable[conditional](DialectPostgres); !errors.Is(err, ErrUnknownFieldType) {
+		t.Errorf("conditional member: got %v", err)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{"UserAccount": "user_account", "HTTPServer": "http_server", "user": "user", "ID": "id"} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("%s: got %s want %s", in, got, want)
+		}
+	}
+}
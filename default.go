@@ -1,5 +1,18 @@
 package fielder
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// struct tag holding the string form of a member's default value, ex: field:"Port" default:"8080"
+// read by LoadFromEnv and BuildDefaults
+const DefaultTag = "default"
+
 type FieldWDefault interface {
 	Field
 	Default
@@ -36,6 +49,41 @@ func (d *defaulter) DefaultField() Field {
 	return d.Value
 }
 
+// NewDefaultFunc is a Default made when it is read instead of when it is declared, ex: the current time or a new uuid
+// every DefaultField calls fn again, MatchesDefault compares with the last default handed out, so a field
+// filled from DefaultField still reads as defaulted after fn would return something else
+func NewDefaultFunc(fn func() Field) Default {
+	return &defaultFunc{fn: fn}
+}
+
+type defaultFunc struct {
+	fn   func() Field
+	mu   sync.Mutex
+	last Field
+}
+
+func (d *defaultFunc) ExplicitlySet() bool {
+	return false
+}
+
+func (d *defaultFunc) MatchesDefault(f Field) bool {
+	d.mu.Lock()
+	last := d.last
+	d.mu.Unlock()
+	if last == nil {
+		last = d.DefaultField()
+	}
+	return last != nil && last.Equal(f)
+}
+
+func (d *defaultFunc) DefaultField() Field {
+	f := d.fn()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.last = f
+	return f
+}
+
 type FieldWDefaultImpl struct {
 	Field
 	Default
@@ -51,3 +99,112 @@ func NewFieldWDefault(f Field, d Default) FieldWDefault {
 		Default: d,
 	}
 }
+
+// BuildDefaults parses the default tags of the parent into a Default for each member that has one,
+// keyed like the conditions BuildConditionals returns, a slice member's default is comma separated like LoadFromEnv reads it
+// the defaults are not explicitly set, so a field holding one still reads as defaulted
+func BuildDefaults[P any]() (map[FieldKey]Default, error) {
+	ty := reflect.TypeOf(*new(P))
+	for ty != nil && ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	out := make(map[FieldKey]Default)
+	var errs []error
+	for i := 0; i < ty.NumField(); i++ {
+		sf := ty.Field(i)
+		name, _ := parseFieldTag(sf.Tag.Get(FieldKeyTag))
+		tag, ok := sf.Tag.Lookup(DefaultTag)
+		if name == "" || !ok || !sf.IsExported() {
+			continue
+		}
+		key := NewDefaultFieldKey(name)
+		raw := []string{tag}
+		if sf.Type.Kind() == reflect.Slice && sf.Type != reflect.TypeOf([]byte{}) {
+			raw = strings.Split(tag, ",")
+		}
+		f, err := memberFromValues(sf.Type, key, raw)
+		if err != nil {
+			errs = append(errs, NewFieldError(key, err))
+			continue
+		}
+		if f == nil {
+			// an empty tag defaults to the zero value
+			if f = newMemberField(sf.Type, key); f == nil {
+				errs = append(errs, NewFieldError(key, ErrUnknownFieldType))
+				continue
+			}
+		}
+		out[key] = NewDefault(false, f)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return out, nil
+}
+
+// ApplyDefaults writes the default of every key whose member of the parent is empty, ex: after decoding a partial request
+// members that are fields are written in place like ApplyField does, a conditional member can reject its default,
+// it returns the keys that were defaulted in key order, and a FieldErrors for the ones that could not be
+func ApplyDefaults[P any](parent *P, defaults map[FieldKey]Default) ([]FieldKey, error) {
+	if parent == nil {
+		return nil, ErrNotStruct
+	}
+	pv := reflect.ValueOf(parent).Elem()
+	if pv.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	keys := make([]FieldKey, 0, len(defaults))
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	defaulted := []FieldKey{}
+	var errs FieldErrors
+	for _, key := range keys {
+		if defaults[key] == nil {
+			continue
+		}
+		member := reflectValueByPath(pv, key.Name)
+		if !member.IsValid() {
+			errs = append(errs, NewFieldError(key, fmt.Errorf("%s: no member named %s", pv.Type(), key.Name)))
+			continue
+		}
+		existing, isField := memberField(pv, key)
+		if (isField && !existing.IsEmpty()) || (!isField && !member.IsZero()) {
+			continue
+		}
+		d := defaults[key].DefaultField()
+		if d == nil {
+			continue
+		}
+		// the member gets a copy, so writing to it later leaves the default alone
+		f := cloneField(d)
+		if f == nil {
+			f = d
+		}
+		var err error
+		if !isField {
+			err = assignField(member, f)
+		} else if ts, ok := existing.(TrySetter); ok {
+			err = ts.TrySetValue(f)
+		} else {
+			existing.SetValue(f)
+		}
+		if err != nil {
+			var fe FieldError
+			if !errors.As(err, &fe) {
+				fe = NewFieldError(key, err)
+			}
+			errs = append(errs, fe)
+			continue
+		}
+		defaulted = append(defaulted, key)
+	}
+	if len(errs) > 0 {
+		return defaulted, errs
+	}
+	return defaulted, nil
+}
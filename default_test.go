@@ -0,0 +1,177 @@
+package fielder
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testServer struct {
+	Host    string        `field:"Host" default:"localhost"`
+	Timeout time.Duration `field:"Timeout" default:"30s"`
+	Tags    []string      `field:"Tags" default:"a,b"`
+	Port    *IntegerField `field:"Port" default:"8080"`
+	Name    *StringField  `field:"Name" default:""`
+	Retries int           `field:"Retries" default:""`
+	Debug   bool          `field:"Debug"`
+}
+
+func TestBuildDefaults(t *testing.T) {
+	defaults, err := BuildDefaults[testServer]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defaults) != 6 {
+		t.Errorf("members without a default tag have no default: got %v", defaults)
+	}
+	want := map[string]string{"Host": "localhost", "Timeout": "30s", "Port": "8080", "Name": "", "Retries": "0"}
+	for name, v := range want {
+		d := defaults[NewDefaultFieldKey(name)]
+		if d == nil || d.DefaultField().ToString() != v || d.ExplicitlySet() {
+			t.Errorf("%s: got %#v", name, d)
+		}
+	}
+	if tags, ok := defaults[NewDefaultFieldKey("Tags")].DefaultField().Value().([]string); !ok || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("a slice default is comma separated: got %#v", defaults[NewDefaultFieldKey("Tags")].DefaultField().Value())
+	}
+	// members that are fields get a default of their own type
+	port, ok := defaults[NewDefaultFieldKey("Port")].DefaultField().(*IntegerField)
+	if !ok || port.ValueField != 8080 || port.Key() != NewDefaultFieldKey("Port") {
+		t.Errorf("got %#v", defaults[NewDefaultFieldKey("Port")].DefaultField())
+	}
+	if _, ok := defaults[NewDefaultFieldKey("Name")].DefaultField().(*StringField); !ok {
+		t.Errorf("an empty tag on a field member is its zero value: got %#v", defaults[NewDefaultFieldKey("Name")].DefaultField())
+	}
+	if !defaults[NewDefaultFieldKey("Port")].MatchesDefault(&IntegerField{ValueField: 8080}) {
+		t.Error("expected the default to match its value")
+	}
+}
+
+func TestBuildDefaultsErrors(t *testing.T) {
+	type bad struct {
+		Port    int           `field:"Port" default:"http"`
+		Timeout time.Duration `field:"Timeout" default:"soon"`
+		Host    string        `field:"Host" default:"localhost"`
+	}
+	_, err := BuildDefaults[bad]()
+	var ferr FieldError
+	if !errors.As(err, &ferr) || ferr.Key.Name != "Port" {
+		t.Errorf("got %v", err)
+	}
+	if _, err := BuildDefaults[string](); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("got %v", err)
+	}
+	if d, err := BuildDefaults[*testServer](); err != nil || len(d) != 6 {
+		t.Errorf("a pointer to a parent is the parent: got %v %v", d, err)
+	}
+}
+
+func TestDefaultFunc(t *testing.T) {
+	n := 0
+	d := NewDefaultFunc(func() Field {
+		n++
+		return &IntegerField{ValueField: n, KeyField: NewDefaultFieldKey("Seq")}
+	})
+	if d.ExplicitlySet() {
+		t.Error("a default func is never explicitly set")
+	}
+	// before any default is handed out the match asks fn for one
+	if !d.MatchesDefault(&IntegerField{ValueField: 1}) || n != 1 {
+		t.Errorf("got %d", n)
+	}
+	f := NewFieldWDefault(d.DefaultField(), d)
+	if f.ToString() != "2" || !f.IsDefault() {
+		t.Errorf("got %s %v", f.ToString(), f.IsDefault())
+	}
+	// a later default does not change what the field matches against, the last one handed out does
+	if d.DefaultField().ToString() != "3" || f.IsDefault() || !d.MatchesDefault(&IntegerField{ValueField: 3}) {
+		t.Errorf("got %s %v", f.ToString(), f.IsDefault())
+	}
+}
+
+func TestDefaultFuncConcurrent(t *testing.T) {
+	d := NewDefaultFunc(func() Field { return testField("Id", "x") })
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.DefaultField()
+			d.MatchesDefault(testField("Id", "x"))
+		}()
+	}
+	wg.Wait()
+	if !d.MatchesDefault(testField("Id", "x")) {
+		t.Error("expected the default to match")
+	}
+}
+
+type testRequest struct {
+	Host   string        `field:"Host"`
+	Port   *IntegerField `field:"Port"`
+	Views  *CounterField `field:"Views"`
+	Active *BoolField    `field:"Active"`
+	Note   Field         `field:"Note"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	defaults := map[FieldKey]Default{
+		NewDefaultFieldKey("Host"):   NewDefault(false, testField("Host", "localhost")),
+		NewDefaultFieldKey("Port"):   NewDefault(false, &IntegerField{ValueField: 8080, KeyField: NewDefaultFieldKey("Port")}),
+		NewDefaultFieldKey("Views"):  NewDefault(false, NewCounterField(NewDefaultFieldKey("Views"), 3)),
+		NewDefaultFieldKey("Active"): NewDefault(false, NewBool(NewDefaultFieldKey("Active"), true)),
+		NewDefaultFieldKey("Note"):   NewDefault(false, testField("Note", "none")),
+	}
+	r := testRequest{Host: "example.com", Active: NewBool(NewDefaultFieldKey("Active"), false).(*BoolField)}
+	keys, err := ApplyDefaults(&r, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 || keys[0].Name != "Note" || keys[1].Name != "Port" || keys[2].Name != "Views" {
+		t.Errorf("the defaulted keys are returned in order: got %v", keys)
+	}
+	if r.Host != "example.com" || !r.Active.Set || r.Active.ValueField {
+		t.Errorf("members that are set keep their value, false included: got %#v", r)
+	}
+	if r.Port == nil || r.Port.ValueField != 8080 || r.Views == nil || r.Views.ToString() != "3" || r.Note == nil || r.Note.ToString() != "none" {
+		t.Fatalf("got %#v", r)
+	}
+	// the members are copies of the defaults
+	r.Port.SetValue(&IntegerField{ValueField: 1})
+	r.Views.SetValue(NewCounterField(NewDefaultFieldKey("Views"), 9))
+	r.Note.SetValue(testField("Note", "changed"))
+	for name, want := range map[string]string{"Port": "8080", "Views": "3", "Note": "none"} {
+		if got := defaults[NewDefaultFieldKey(name)].DefaultField().ToString(); got != want {
+			t.Errorf("%s: writing the member changed the default to %s", name, got)
+		}
+	}
+
+	empty := testRequest{Active: &BoolField{KeyField: NewDefaultFieldKey("Active")}}
+	if keys, err := ApplyDefaults(&empty, defaults); err != nil || len(keys) != 5 || !empty.Active.ValueField || empty.Host != "localhost" {
+		t.Errorf("an unset bool is empty: got %v %v %#v", keys, err, empty)
+	}
+}
+
+func TestApplyDefaultsErrors(t *testing.T) {
+	type guarded struct {
+		Name *FieldConditional `field:"Name"`
+		Host string            `field:"Host"`
+	}
+	g := guarded{Name: NewConditionalField(testField("Name", ""), testNotBad()).(*FieldConditional)}
+	keys, err := ApplyDefaults(&g, map[FieldKey]Default{
+		NewDefaultFieldKey("Name"):    NewDefault(false, testField("Name", "bad")),
+		NewDefaultFieldKey("Host"):    NewDefault(false, testField("Host", "localhost")),
+		NewDefaultFieldKey("Missing"): NewDefault(false, testField("Missing", "x")),
+	})
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Key.Name != "Missing" || errs[1].Key.Name != "Name" || !errors.Is(errs[1], ErrConditionNotMet) {
+		t.Errorf("got %v", err)
+	}
+	if len(keys) != 1 || g.Host != "localhost" || g.Name.ToString() != "" {
+		t.Errorf("a conditional member can reject its default: got %v %#v", keys, g)
+	}
+	if _, err := ApplyDefaults[testRequest](nil, nil); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package fielder
+
+import (
+	"reflect"
+	"time"
+)
+
+type DurationField struct {
+	ValueField time.Duration `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey      `dynamodbav:"key" json:"key"`
+}
+
+func (s *DurationField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *DurationField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *DurationField) Type() reflect.Type {
+	return reflect.TypeOf(time.Duration(0))
+}
+
+func (s *DurationField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.ValueField < in2.(*DurationField).ValueField
+}
+
+func (s *DurationField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.ValueField > in2.(*DurationField).ValueField
+}
+
+func (s *DurationField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField == in2.(*DurationField).ValueField
+}
+
+// uses the time.Duration string form ("1h30m0s") so it round trips through time.ParseDuration
+func (s *DurationField) ToString() string {
+	return s.ValueField.String()
+}
+
+func (s *DurationField) FromString(st string) {
+	d, err := time.ParseDuration(st)
+	if err != nil {
+		return
+	}
+	s.ValueField = d
+}
+
+func (s *DurationField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = in2.(*DurationField).ValueField
+	return
+}
+
+func (s *DurationField) IsEmpty() bool {
+	return s.ValueField == 0
+}
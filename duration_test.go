@@ -0,0 +1,75 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDurationFieldCompare(t *testing.T) {
+	key := NewDefaultFieldKey("Timeout")
+	short := &DurationField{ValueField: time.Second, KeyField: key}
+	long := &DurationField{ValueField: time.Minute, KeyField: key}
+	if !short.LessThan(long) || short.GreaterThan(long) {
+		t.Errorf("expected %s < %s", short.ToString(), long.ToString())
+	}
+	if !long.GreaterThan(short) || long.LessThan(short) {
+		t.Errorf("expected %s > %s", long.ToString(), short.ToString())
+	}
+	if !short.Equal(&DurationField{ValueField: time.Second}) || short.Equal(long) {
+		t.Error("expected equal durations to compare equal")
+	}
+	if short.Equal(nil) {
+		t.Error("a duration is never equal to nil")
+	}
+}
+
+func TestDurationFieldString(t *testing.T) {
+	f := &DurationField{KeyField: NewDefaultFieldKey("Timeout")}
+	f.FromString("1h30m")
+	if f.ValueField != 90*time.Minute {
+		t.Fatalf("FromString: got %s", f.ValueField)
+	}
+	if got := f.ToString(); got != "1h30m0s" {
+		t.Errorf("ToString: got %q", got)
+	}
+	// input that does not parse is ignored
+	f.FromString("soon")
+	if f.ValueField != 90*time.Minute {
+		t.Errorf("FromString of bad input changed the value to %s", f.ValueField)
+	}
+}
+
+func TestDurationFieldSetValue(t *testing.T) {
+	f := &DurationField{}
+	f.SetValue(&DurationField{ValueField: time.Hour})
+	if f.ValueField != time.Hour {
+		t.Errorf("SetValue: got %s", f.ValueField)
+	}
+	// other field types are set through their string form
+	f.SetValue(&StringField{ValueField: "2m"})
+	if f.ValueField != 2*time.Minute {
+		t.Errorf("SetValue from string: got %s", f.ValueField)
+	}
+	if f.IsEmpty() {
+		t.Error("a non zero duration is not empty")
+	}
+	if !(&DurationField{}).IsEmpty() {
+		t.Error("a zero duration is empty")
+	}
+}
+
+func TestCreateDurationField(t *testing.T) {
+	key := NewDefaultFieldKey("Timeout")
+	f := CreateFieldFromType(reflect.TypeOf(time.Duration(0)), time.Second, key)
+	d, ok := f.(*DurationField)
+	if !ok {
+		t.Fatalf("got %T", f)
+	}
+	if d.ValueField != time.Second || d.Key() != key {
+		t.Errorf("got %s %v", d.ValueField, d.Key())
+	}
+	if f := CreateFieldFromType(reflect.TypeOf(time.Duration(0)), nil, key); !f.IsEmpty() {
+		t.Error("a field created without a value is empty")
+	}
+}
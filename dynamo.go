@@ -1,28 +1,433 @@
 package fielder
 
-// import (
-// 	"errors"
-// 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
-// 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-// 	"reflect"
-// )
-
-// func (s *FieldWDefaultImpl) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
-// 	if s.IsDefault() {
-// 		return nil, nil
-// 	}
-
-// }
-
-// func (s *FieldWDefaultImpl) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
-// 	convertedValS, ok := av.(*types.AttributeValueMemberS) // all types should be s
-// 	if ok {
-// 		return nil
-// 	}
-
-// 	return &attributevalue.UnmarshalTypeError{
-// 		Value: "string field",
-// 		Type:  reflect.TypeOf(av),
-// 		Err:   errors.New("attribute value is not string type"),
-// 	}
-// }
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fields are written to dynamo as a single scalar attribute, the attribute name in the parent already identifies the key
+// numbers are written as N so dynamo sorts them numerically, bools as BOOL, bytes as B, and everything else as S using ToString
+// a field with no value is written as NULL
+
+// a defaulted field is written as NULL and reads back as a copy of the default,
+// but the decoder sets a pointer member to nil on NULL without asking it, so to keep a *FieldWDefaultImpl member
+// tag it dynamodbav:",omitempty" and set EncoderOptions.OmitNullAttributeValues, the attribute is then left out and the member is not touched
+func (s *FieldWDefaultImpl) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	if s.IsDefault() {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+	return fieldToAttributeValue(s.Field)
+}
+
+// the concrete type of the field is restored from the field already held, or from the default if there is none
+// NULL reads back as a copy of the default
+func (s *FieldWDefaultImpl) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	if _, ok := av.(*types.AttributeValueMemberNULL); ok && s.Default != nil {
+		if d := cloneField(s.Default.DefaultField()); d != nil {
+			s.Field = d
+			return nil
+		}
+	}
+	if s.Field == nil && s.Default != nil && s.Default.DefaultField() != nil {
+		d := s.Default.DefaultField()
+		s.Field = CreateFieldFromType(d.Type(), nil, d.Key())
+	}
+	if s.Field == nil {
+		f, err := fieldFromAttributeValue(av, FieldKeyNil)
+		if err != nil {
+			return err
+		}
+		s.Field = f
+		return nil
+	}
+	return attributeValueToField(av, s.Field)
+}
+
+// a redacted field is refused rather than stored as "****"
+func (s *RedactedField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+// an encrypted field is written as its ciphertext in S, the plaintext never reaches the item
+func (s *EncryptedField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	if s.Field == nil || s.Field.Value() == nil {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+	out, err := s.encrypt()
+	if err != nil {
+		return nil, err
+	}
+	return &types.AttributeValueMemberS{Value: out}, nil
+}
+
+// the inner field and cipher have to be set before unmarshaling, like UnmarshalJSON
+func (s *EncryptedField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return s.decrypt(v.Value)
+	case *types.AttributeValueMemberNULL:
+		resetField(s.Field)
+		return nil
+	default:
+		return &attributevalue.UnmarshalTypeError{
+			Value: "encrypted field",
+			Type:  reflect.TypeOf(av),
+			Err:   errors.New("attribute value is not S or NULL type"),
+		}
+	}
+}
+
+func (s *StringField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *StringField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *TimeField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *TimeField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *DecimalField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *DecimalField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *IntegerField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *IntegerField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *BoolField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *BoolField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *FloatField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *FloatField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *DurationField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *DurationField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *UUIDField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *UUIDField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *BytesField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *BytesField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *DateField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *DateField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *MoneyField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *MoneyField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *QuantityField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *QuantityField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *URLField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *URLField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *EmailField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *EmailField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *IPField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *IPField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *CIDRField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *CIDRField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *GeoPointField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *GeoPointField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *EnumField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *EnumField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *CounterField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *CounterField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *SliceField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *SliceField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *MapField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *MapField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func (s *StructField) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return fieldToAttributeValue(s)
+}
+
+func (s *StructField) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	return attributeValueToField(av, s)
+}
+
+func fieldToAttributeValue(f Field) (types.AttributeValue, error) {
+	if f == nil || f.Value() == nil {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+	if IsSensitive(f) {
+		return nil, sensitiveFieldError(f)
+	}
+	switch v := f.(type) {
+	case *BoolField:
+		if !v.Set {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		}
+		return &types.AttributeValueMemberBOOL{Value: v.ValueField}, nil
+	case *IntegerField, *DecimalField, *FloatField, *CounterField:
+		return &types.AttributeValueMemberN{Value: f.ToString()}, nil
+	case *BytesField:
+		return &types.AttributeValueMemberB{Value: v.ValueField}, nil
+	default:
+		return &types.AttributeValueMemberS{Value: f.ToString()}, nil
+	}
+}
+
+// sets f from the attribute value, NULL resets f to its zero value
+func attributeValueToField(av types.AttributeValue, f Field) error {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return ParseFieldString(f, v.Value)
+	case *types.AttributeValueMemberN:
+		return ParseFieldString(f, v.Value)
+	case *types.AttributeValueMemberBOOL:
+		return ParseFieldString(f, strconv.FormatBool(v.Value))
+	case *types.AttributeValueMemberB:
+		return ParseFieldString(f, base64.StdEncoding.EncodeToString(v.Value))
+	case *types.AttributeValueMemberNULL:
+		resetField(f)
+		return nil
+	default:
+		return &attributevalue.UnmarshalTypeError{
+			Value: "scalar field",
+			Type:  reflect.TypeOf(av),
+			Err:   errors.New("attribute value is not S, N, B, BOOL or NULL type"),
+		}
+	}
+}
+
+// creates a field from the attribute value when there is no field to restore the type from
+// S becomes a StringField, N a DecimalField, B a BytesField and BOOL a BoolField
+func fieldFromAttributeValue(av types.AttributeValue, key FieldKey) (Field, error) {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return &StringField{ValueField: v.Value, KeyField: key}, nil
+	case *types.AttributeValueMemberN:
+		f := &DecimalField{KeyField: key}
+		return f, ParseFieldString(f, v.Value)
+	case *types.AttributeValueMemberBOOL:
+		return NewBool(key, v.Value), nil
+	case *types.AttributeValueMemberB:
+		return &BytesField{ValueField: v.Value, KeyField: key}, nil
+	case *types.AttributeValueMemberNULL:
+		return FieldNil, nil
+	default:
+		return nil, &attributevalue.UnmarshalTypeError{
+			Value: "scalar field",
+			Type:  reflect.TypeOf(av),
+			Err:   errors.New("attribute value is not S, N, B, BOOL or NULL type"),
+		}
+	}
+}
+
+// the tag options that mark the members making up the table key, ex: field:"UserId,pk" and field:"CreatedAt,sk"
+const (
+	TagOptionPartitionKey = "pk"
+	TagOptionSortKey      = "sk"
+)
+
+var (
+	ErrNoPartitionKey = errors.New("parent has no partition key field")
+	ErrEmptyKeyField  = errors.New("key field is empty")
+)
+
+// a member of a parent that is part of a key, with the attribute name it is stored under
+type dynamoKeyMember struct {
+	Key       FieldKey
+	Attribute string
+}
+
+// returns the members whose field tag has the option, in declaration order
+// the attribute name comes from the dynamodbav tag if there is one, and is the field name otherwise
+func dynamoKeyMembers[P any](option string) []dynamoKeyMember {
+	out := []dynamoKeyMember{}
+	ty := reflect.TypeOf(*new(P))
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return out
+	}
+	for i := 0; i < ty.NumField(); i++ {
+		sf := ty.Field(i)
+		name, opts := parseFieldTag(sf.Tag.Get(FieldKeyTag))
+		if name == "" || !SliceContains(opts, option, func(s1, s2 string) bool { return s1 == s2 }) {
+			continue
+		}
+		attr := strings.Split(sf.Tag.Get("dynamodbav"), ",")[0]
+		if attr == "" || attr == "-" {
+			attr = name
+		}
+		out = append(out, dynamoKeyMember{Key: NewDefaultFieldKey(name), Attribute: attr})
+	}
+	return out
+}
+
+// returns the partition and sort key members of the parent, the sort key is optional
+func dynamoKeySchema[P any]() (dynamoKeyMember, *dynamoKeyMember, error) {
+	pks := dynamoKeyMembers[P](TagOptionPartitionKey)
+	sks := dynamoKeyMembers[P](TagOptionSortKey)
+	if len(pks) == 0 {
+		return dynamoKeyMember{}, nil, ErrNoPartitionKey
+	}
+	if len(pks) > 1 || len(sks) > 1 {
+		return dynamoKeyMember{}, nil, fmt.Errorf("%s has more than one partition or sort key field", reflect.TypeOf(*new(P)))
+	}
+	if len(sks) == 0 {
+		return pks[0], nil, nil
+	}
+	return pks[0], &sks[0], nil
+}
+
+// BuildKey returns the key of the parent, ready to use in a GetItem or DeleteItem
+func BuildKey[P any](p P) (map[string]types.AttributeValue, error) {
+	pk, sk, err := dynamoKeySchema[P]()
+	if err != nil {
+		return nil, err
+	}
+	members := []dynamoKeyMember{pk}
+	if sk != nil {
+		members = append(members, *sk)
+	}
+	out := make(map[string]types.AttributeValue, len(members))
+	for _, m := range members {
+		av, err := dynamoKeyAttribute(p, m.Key)
+		if err != nil {
+			return nil, err
+		}
+		out[m.Attribute] = av
+	}
+	return out, nil
+}
+
+// ValidateKey checks that the key fields of the parent are set, it should be called before the parent is written
+func ValidateKey[P any](p P) error {
+	_, err := BuildKey(p)
+	return err
+}
+
+// key attributes have to be S, N or B, and can not be empty
+func dynamoKeyAttribute[P any](p P, key FieldKey) (types.AttributeValue, error) {
+	f := GetResultItemFieldFromKeyDefault(p, key)
+	if f == nil || f.Key() == FieldKeyNil || f.IsEmpty() {
+		return nil, fmt.Errorf("%s: %w", key.Name, ErrEmptyKeyField)
+	}
+	av, err := fieldToAttributeValue(f)
+	if err != nil {
+		return nil, err
+	}
+	switch av.(type) {
+	case *types.AttributeValueMemberS, *types.AttributeValueMemberN, *types.AttributeValueMemberB:
+		return av, nil
+	case *types.AttributeValueMemberNULL:
+		return nil, fmt.Errorf("%s: %w", key.Name, ErrEmptyKeyField)
+	default:
+		return nil, fmt.Errorf("%s: key attributes have to be strings, numbers or bytes", key.Name)
+	}
+}
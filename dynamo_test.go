@@ -0,0 +1,242 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestFieldWDefaultDynamo(t *testing.T) {
+	key := NewDefaultFieldKey("Region")
+	d := NewDefault(false, &StringField{ValueField: "us-east-1", KeyField: key})
+	f := NewFieldWDefault(&StringField{ValueField: "us-east-1", KeyField: key}, d).(*FieldWDefaultImpl)
+	av, err := f.MarshalDynamoDBAttributeValue()
+	if _, ok := av.(*types.AttributeValueMemberNULL); err != nil || !ok {
+		t.Errorf("a defaulted field is NULL: got %#v %v", av, err)
+	}
+	f.Field.FromString("eu-west-1")
+	av, err = f.MarshalDynamoDBAttributeValue()
+	if s, ok := av.(*types.AttributeValueMemberS); err != nil || !ok || s.Value != "eu-west-1" {
+		t.Errorf("got %#v %v", av, err)
+	}
+
+	empty := &FieldWDefaultImpl{Default: d}
+	if err := empty.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberS{Value: "ap-south-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := empty.Field.(*StringField); !ok || empty.ToString() != "ap-south-1" || empty.Key() != key {
+		t.Errorf("the field is made from the default: got %#v", empty.Field)
+	}
+	bare := &FieldWDefaultImpl{}
+	if err := bare.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberN{Value: "1.5"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bare.Field.(*DecimalField); !ok {
+		t.Errorf("without a default the field is made from the attribute: got %T", bare.Field)
+	}
+}
+
+func TestAttributeValueNull(t *testing.T) {
+	b := NewBool(NewDefaultFieldKey("Active"), true).(*BoolField)
+	if err := attributeValueToField(&types.AttributeValueMemberNULL{Value: true}, b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Set || b.ValueField || !b.IsEmpty() {
+		t.Errorf("NULL leaves a bool unset: got %#v", b)
+	}
+	s := &StringField{ValueField: "x"}
+	if err := attributeValueToField(&types.AttributeValueMemberNULL{Value: true}, s); err != nil || s.ValueField != "" {
+		t.Errorf("NULL resets the field: got %q %v", s.ValueField, err)
+	}
+	if err := attributeValueToField(&types.AttributeValueMemberL{}, s); err == nil {
+		t.Error("lists are not scalar fields")
+	}
+}
+
+type testRegionItem struct {
+	Name   *StringField      `dynamodbav:"name" field:"Name"`
+	Region FieldWDefaultImpl `dynamodbav:"region" field:"Region"`
+}
+
+func testRegionDefault(v string) *FieldWDefaultImpl {
+	key := NewDefaultFieldKey("Region")
+	return NewFieldWDefault(&StringField{ValueField: v, KeyField: key}, NewDefault(false, &StringField{ValueField: "us-east-1", KeyField: key})).(*FieldWDefaultImpl)
+}
+
+func TestFieldWDefaultMarshalMap(t *testing.T) {
+	in := testRegionItem{Name: testField("Name", "sam"), Region: *testRegionDefault("us-east-1")}
+	// through a pointer, so the encoder finds the methods of the member held by value
+	av, err := attributevalue.MarshalMap(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := av["region"].(*types.AttributeValueMemberNULL); !ok {
+		t.Fatalf("a defaulted member is NULL: got %#v", av["region"])
+	}
+	out := testRegionItem{Region: FieldWDefaultImpl{Default: in.Region.Default}}
+	if err := attributevalue.UnmarshalMap(av, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Region.ToString() != "us-east-1" || !out.Region.IsDefault() || out.Region.Field == in.Region.DefaultField() {
+		t.Errorf("NULL reads back as a copy of the default: got %#v", out.Region)
+	}
+
+	in.Region.Field.FromString("eu-west-1")
+	if av, err = attributevalue.MarshalMap(&in); err != nil {
+		t.Fatal(err)
+	}
+	out = testRegionItem{Region: *testRegionDefault("")}
+	if err := attributevalue.UnmarshalMap(av, &out); err != nil || out.Region.ToString() != "eu-west-1" {
+		t.Errorf("got %q %v", out.Region.ToString(), err)
+	}
+}
+
+func TestFieldWDefaultOmitEmpty(t *testing.T) {
+	type item struct {
+		Region *FieldWDefaultImpl `dynamodbav:"region,omitempty" field:"Region"`
+	}
+	av, err := attributevalue.MarshalMapWithOptions(item{Region: testRegionDefault("us-east-1")}, func(o *attributevalue.EncoderOptions) {
+		o.OmitNullAttributeValues = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := av["region"]; ok {
+		t.Errorf("a defaulted member tagged omitempty is left out: got %#v", av)
+	}
+	out := item{Region: testRegionDefault("us-east-1")}
+	if err := attributevalue.UnmarshalMap(av, &out); err != nil || out.Region == nil || out.Region.ToString() != "us-east-1" {
+		t.Errorf("the member keeps the field it was made with: got %#v %v", out.Region, err)
+	}
+	// without omitempty the decoder sets the member to nil on NULL
+	if av, err = attributevalue.MarshalMap(item{Region: testRegionDefault("us-east-1")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := attributevalue.UnmarshalMap(av, &out); err != nil || out.Region != nil {
+		t.Errorf("got %#v %v", out.Region, err)
+	}
+}
+
+type testDynamoItem struct {
+	Name    *StringField   `dynamodbav:"name" field:"Name"`
+	Count   *IntegerField  `dynamodbav:"count" field:"Count"`
+	Price   *DecimalField  `dynamodbav:"price" field:"Price"`
+	Active  *BoolField     `dynamodbav:"active" field:"Active"`
+	Ratio   *FloatField    `dynamodbav:"ratio" field:"Ratio"`
+	Data    *BytesField    `dynamodbav:"data" field:"Data"`
+	Created *TimeField     `dynamodbav:"created" field:"Created"`
+	Timeout *DurationField `dynamodbav:"timeout" field:"Timeout"`
+	Views   *CounterField  `dynamodbav:"views" field:"Views"`
+}
+
+func TestDynamoRoundTrip(t *testing.T) {
+	in := testDynamoItem{
+		Name:    &StringField{ValueField: "widget"},
+		Count:   &IntegerField{ValueField: 3},
+		Price:   testDecimal(t, "9.99"),
+		Active:  NewBool(FieldKeyNil, true).(*BoolField),
+		Ratio:   &FloatField{ValueField: 0.5},
+		Data:    &BytesField{ValueField: []byte{1, 2, 3}},
+		Created: NewTimeField(FieldKeyNil, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Timeout: &DurationField{ValueField: time.Minute},
+		Views:   NewCounterField(FieldKeyNil, 42),
+	}
+	av, err := attributevalue.MarshalMap(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for attr, want := range map[string]string{"name": "S", "count": "N", "price": "N", "active": "BOOL", "ratio": "N", "data": "B", "created": "S", "timeout": "S", "views": "N"} {
+		var got string
+		switch av[attr].(type) {
+		case *types.AttributeValueMemberS:
+			got = "S"
+		case *types.AttributeValueMemberN:
+			got = "N"
+		case *types.AttributeValueMemberB:
+			got = "B"
+		case *types.AttributeValueMemberBOOL:
+			got = "BOOL"
+		}
+		if got != want {
+			t.Errorf("%s: got %T want %s", attr, av[attr], want)
+		}
+	}
+	out := testDynamoItem{}
+	if err := attributevalue.UnmarshalMap(av, &out); err != nil {
+		t.Fatal(err)
+	}
+	for name, pair := range map[string][2]Field{
+		"name": {in.Name, out.Name}, "count": {in.Count, out.Count}, "price": {in.Price, out.Price},
+		"active": {in.Active, out.Active}, "ratio": {in.Ratio, out.Ratio}, "data": {in.Data, out.Data},
+		"created": {in.Created, out.Created}, "timeout": {in.Timeout, out.Timeout}, "views": {in.Views, out.Views},
+	} {
+		if !pair[0].Equal(pair[1]) {
+			t.Errorf("%s: got %q want %q", name, pair[1].ToString(), pair[0].ToString())
+		}
+	}
+}
+
+func TestDynamoUnsetBool(t *testing.T) {
+	av, err := attributevalue.Marshal(&BoolField{})
+	if _, ok := av.(*types.AttributeValueMemberNULL); err != nil || !ok {
+		t.Errorf("an unset bool is NULL: got %#v %v", av, err)
+	}
+}
+
+type testDynamoKeyed struct {
+	UserID    string       `dynamodbav:"user_id" field:"UserID,pk"`
+	CreatedAt *StringField `field:"CreatedAt,sk"`
+	Note      string       `field:"Note"`
+}
+
+func TestBuildKey(t *testing.T) {
+	p := testDynamoKeyed{UserID: "u1", CreatedAt: testField("CreatedAt", "2025-01-01")}
+	key, err := BuildKey(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 2 || key["user_id"].(*types.AttributeValueMemberS).Value != "u1" || key["CreatedAt"].(*types.AttributeValueMemberS).Value != "2025-01-01" {
+		t.Errorf("the attribute name comes from the dynamodbav tag: got %#v", key)
+	}
+	for name, p := range map[string]testDynamoKeyed{
+		"empty pk": {CreatedAt: testField("CreatedAt", "x")},
+		"nil sk":   {UserID: "u1"},
+		"empty sk": {UserID: "u1", CreatedAt: testField("CreatedAt", "")},
+	} {
+		if err := ValidateKey(p); !errors.Is(err, ErrEmptyKeyField) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+}
+
+func TestBuildKeySchema(t *testing.T) {
+	type noKey struct {
+		Name string `field:"Name"`
+	}
+	if _, err := BuildKey(noKey{Name: "x"}); !errors.Is(err, ErrNoPartitionKey) {
+		t.Errorf("got %v", err)
+	}
+	type twoKeys struct {
+		A string `field:"A,pk"`
+		B string `field:"B,pk"`
+	}
+	if _, err := BuildKey(twoKeys{A: "a", B: "b"}); err == nil {
+		t.Error("more than one partition key is an error")
+	}
+	type boolKey struct {
+		Active *BoolField `field:"Active,pk"`
+	}
+	if _, err := BuildKey(boolKey{Active: NewBool(NewDefaultFieldKey("Active"), true).(*BoolField)}); err == nil {
+		t.Error("key attributes have to be strings, numbers or bytes")
+	}
+	type numberKey struct {
+		ID int `field:"ID,pk"`
+	}
+	key, err := BuildKey(numberKey{ID: 7})
+	if n, ok := key["ID"].(*types.AttributeValueMemberN); err != nil || !ok || n.Value != "7" {
+		t.Errorf("got %#v %v", key, err)
+	}
+}
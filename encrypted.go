@@ -0,0 +1,152 @@
+package fielder
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+var ErrCiphertextTooShort = errors.New("ciphertext too short")
+
+// Cipher is anything that can encrypt and decrypt a field's string form, a local key or a KMS client
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// EncryptedField keeps the plaintext in memory so comparisons work as normal,
+// but ToString, json, dynamo and gob only ever produce the encrypted form (base64), and FromString expects it back
+type EncryptedField struct {
+	Field
+	Cipher Cipher `dynamodbav:"-" json:"-"`
+}
+
+func NewEncryptedField(f Field, c Cipher) *EncryptedField {
+	return &EncryptedField{
+		Field:  f,
+		Cipher: c,
+	}
+}
+
+// the plaintext string form of the inner field
+func (s *EncryptedField) Plaintext() string {
+	return s.Field.ToString()
+}
+
+func (s *EncryptedField) encrypt() (string, error) {
+	out, err := s.Cipher.Encrypt([]byte(s.Field.ToString()))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+func (s *EncryptedField) decrypt(st string) error {
+	b, err := base64.StdEncoding.DecodeString(st)
+	if err != nil {
+		return err
+	}
+	plain, err := s.Cipher.Decrypt(b)
+	if err != nil {
+		return err
+	}
+	s.Field.FromString(string(plain))
+	return nil
+}
+
+func (s *EncryptedField) LessThan(in2 any) bool {
+	return s.Field.LessThan(unwrapEncrypted(in2))
+}
+
+func (s *EncryptedField) GreaterThan(in2 any) bool {
+	return s.Field.GreaterThan(unwrapEncrypted(in2))
+}
+
+func (s *EncryptedField) Equal(in2 any) bool {
+	return s.Field.Equal(unwrapEncrypted(in2))
+}
+
+// returns "" if encryption fails, the plaintext is never returned
+func (s *EncryptedField) ToString() string {
+	out, err := s.encrypt()
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// expects the encrypted form produced by ToString, input that fails to decrypt is ignored
+func (s *EncryptedField) FromString(st string) {
+	_ = s.decrypt(st)
+}
+
+func (s *EncryptedField) ParseString(st string) error {
+	return s.decrypt(st)
+}
+
+func (s *EncryptedField) SetValue(in2 FieldValue) {
+	s.Field.SetValue(unwrapEncrypted(in2))
+}
+
+func (s *EncryptedField) MarshalJSON() ([]byte, error) {
+	out, err := s.encrypt()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(newFieldJSON(s.Field, out))
+}
+
+// the inner field and cipher have to be set before unmarshaling, the key comes from the inner field
+func (s *EncryptedField) UnmarshalJSON(b []byte) error {
+	var in fieldJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	return s.decrypt(in.Value)
+}
+
+// compare and set against the plaintext of another encrypted field, not its ciphertext
+func unwrapEncrypted(in any) any {
+	if e, ok := in.(*EncryptedField); ok {
+		return e.Field
+	}
+	return in
+}
+
+// AESGCMCipher is a Cipher using a local AES key, the nonce is prepended to the ciphertext
+type AESGCMCipher struct {
+	aead cipher.AEAD
+}
+
+// key must be 16, 24 or 32 bytes
+func NewAESGCMCipher(key []byte) (*AESGCMCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCMCipher{aead: aead}, nil
+}
+
+func (c *AESGCMCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *AESGCMCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < c.aead.NonceSize() {
+		return nil, ErrCiphertextTooShort
+	}
+	nonce, sealed := ciphertext[:c.aead.NonceSize()], ciphertext[c.aead.NonceSize():]
+	return c.aead.Open(nil, nonce, sealed, nil)
+}
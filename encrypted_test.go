@@ -0,0 +1,149 @@
+package fielder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func testCipher(t *testing.T, b byte) *AESGCMCipher {
+	t.Helper()
+	c, err := NewAESGCMCipher(bytes.Repeat([]byte{b}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestEncryptedFieldRoundTrip(t *testing.T) {
+	c := testCipher(t, 1)
+	f := NewEncryptedField(&StringField{ValueField: "123-45-6789", KeyField: NewDefaultFieldKey("SSN")}, c)
+	out := f.ToString()
+	if out == "" || strings.Contains(out, "123-45") {
+		t.Fatalf("ToString is the encrypted form: got %q", out)
+	}
+	if f.Plaintext() != "123-45-6789" {
+		t.Errorf("Plaintext: got %q", f.Plaintext())
+	}
+	g := NewEncryptedField(&StringField{}, c)
+	if err := g.ParseString(out); err != nil {
+		t.Fatal(err)
+	}
+	if g.Plaintext() != "123-45-6789" || !g.Equal(f) {
+		t.Errorf("FromString decrypts: got %q", g.Plaintext())
+	}
+}
+
+func TestEncryptedFieldBadInput(t *testing.T) {
+	f := NewEncryptedField(&StringField{ValueField: "secret"}, testCipher(t, 1))
+	other := NewEncryptedField(&StringField{}, testCipher(t, 2))
+	if err := other.ParseString(f.ToString()); err == nil {
+		t.Error("another key does not decrypt")
+	}
+	if err := other.ParseString("AA=="); !errors.Is(err, ErrCiphertextTooShort) {
+		t.Errorf("got %v", err)
+	}
+	other.FromString("not base64!")
+	if other.Plaintext() != "" {
+		t.Errorf("bad input is ignored: got %q", other.Plaintext())
+	}
+}
+
+func TestEncryptedFieldJSON(t *testing.T) {
+	c := testCipher(t, 1)
+	f := NewEncryptedField(&StringField{ValueField: "secret", KeyField: NewDefaultFieldKey("Token")}, c)
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte("secret")) {
+		t.Errorf("the plaintext is not encoded: %s", b)
+	}
+	g := NewEncryptedField(&StringField{}, c)
+	if err := json.Unmarshal(b, g); err != nil {
+		t.Fatal(err)
+	}
+	if g.Plaintext() != "secret" {
+		t.Errorf("got %q", g.Plaintext())
+	}
+}
+
+func TestEncryptedFieldCompare(t *testing.T) {
+	c := testCipher(t, 1)
+	a := NewEncryptedField(&StringField{ValueField: "a"}, c)
+	b := NewEncryptedField(&StringField{ValueField: "b"}, c)
+	if !a.LessThan(b) || !b.GreaterThan(a) || a.Equal(b) {
+		t.Error("encrypted fields compare by plaintext")
+	}
+	if !a.Equal(&StringField{ValueField: "a"}) {
+		t.Error("an encrypted field equals a plain field with the same value")
+	}
+	b.SetValue(a)
+	if b.Plaintext() != "a" {
+		t.Errorf("SetValue copies the plaintext: got %q", b.Plaintext())
+	}
+}
+
+type testPatient struct {
+	Name *StringField    `dynamodbav:"name" field:"Name"`
+	SSN  *EncryptedField `dynamodbav:"ssn" field:"SSN"`
+}
+
+func TestEncryptedFieldDynamo(t *testing.T) {
+	c := testCipher(t, 1)
+	in := testPatient{
+		Name: testField("Name", "sam"),
+		SSN:  NewEncryptedField(testField("SSN", "123-45-6789"), c),
+	}
+	item, err := attributevalue.MarshalMap(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ssn, ok := item["ssn"].(*types.AttributeValueMemberS)
+	if !ok || strings.Contains(ssn.Value, "123-45") {
+		t.Fatalf("the ssn is stored as its ciphertext: got %#v", item["ssn"])
+	}
+	for k, v := range item {
+		if s, ok := v.(*types.AttributeValueMemberS); ok && strings.Contains(s.Value, "123-45") {
+			t.Errorf("%s holds the plaintext", k)
+		}
+	}
+	out := testPatient{SSN: NewEncryptedField(&StringField{}, c)}
+	if err := attributevalue.UnmarshalMap(item, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.SSN.Plaintext() != "123-45-6789" || out.Name.ToString() != "sam" {
+		t.Errorf("got %q %q", out.SSN.Plaintext(), out.Name.ToString())
+	}
+	if err := out.SSN.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberNULL{Value: true}); err != nil || out.SSN.Plaintext() != "" {
+		t.Errorf("NULL resets the field: got %q %v", out.SSN.Plaintext(), err)
+	}
+	if err := out.SSN.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberN{Value: "1"}); err == nil {
+		t.Error("the ciphertext is only read from S")
+	}
+}
+
+func TestEncryptedFieldGob(t *testing.T) {
+	c := testCipher(t, 1)
+	in := testPatient{Name: testField("Name", "sam"), SSN: NewEncryptedField(testField("SSN", "123-45-6789"), c)}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("123-45")) {
+		t.Error("the plaintext is not encoded")
+	}
+	out := testPatient{SSN: NewEncryptedField(&StringField{}, c)}
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.SSN.Plaintext() != "123-45-6789" {
+		t.Errorf("got %q", out.SSN.Plaintext())
+	}
+}
@@ -0,0 +1,109 @@
+package fielder
+
+import (
+	"reflect"
+)
+
+// EnumValue is the value type of an EnumField
+// it is a separate type from string so enums and plain StringFields are not mistaken for each other when compared
+type EnumValue string
+
+// EnumField holds one of a declared set of values, anything outside the set is rejected by SetValue and FromString
+// enums are ordered by their position in the allowed set, so declaring ["draft", "review", "done"] makes draft < review < done
+type EnumField struct {
+	ValueField EnumValue   `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey    `dynamodbav:"key" json:"key"`
+	Allowed    []EnumValue `dynamodbav:"-" json:"-"`
+}
+
+func NewEnumField(key FieldKey, allowed ...string) *EnumField {
+	e := &EnumField{
+		KeyField: key,
+	}
+	for _, v := range allowed {
+		e.Allowed = append(e.Allowed, EnumValue(v))
+	}
+	return e
+}
+
+// the allowed values in declaration order
+func (s *EnumField) AllowedValues() []string {
+	out := make([]string, 0, len(s.Allowed))
+	for _, v := range s.Allowed {
+		out = append(out, string(v))
+	}
+	return out
+}
+
+func (s *EnumField) IsAllowed(st string) bool {
+	return s.index(EnumValue(st)) >= 0
+}
+
+func (s *EnumField) index(v EnumValue) int {
+	for i, a := range s.Allowed {
+		if a == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *EnumField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *EnumField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *EnumField) Type() reflect.Type {
+	return reflect.TypeOf(EnumValue(""))
+}
+
+func (s *EnumField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.index(s.ValueField) < s.index(in2.(*EnumField).ValueField)
+}
+
+func (s *EnumField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.index(s.ValueField) > s.index(in2.(*EnumField).ValueField)
+}
+
+func (s *EnumField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField == in2.(*EnumField).ValueField
+}
+
+func (s *EnumField) ToString() string {
+	return string(s.ValueField)
+}
+
+// values outside the allowed set are rejected and leave the current value untouched
+func (s *EnumField) FromString(st string) {
+	if !s.IsAllowed(st) {
+		return
+	}
+	s.ValueField = EnumValue(st)
+}
+
+func (s *EnumField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.FromString(in2.(*EnumField).ToString())
+	return
+}
+
+func (s *EnumField) IsEmpty() bool {
+	return s.ValueField == ""
+}
@@ -0,0 +1,43 @@
+package fielder
+
+import "testing"
+
+func TestEnumFieldAllowed(t *testing.T) {
+	f := NewEnumField(NewDefaultFieldKey("Status"), "draft", "review", "done")
+	if got := f.AllowedValues(); len(got) != 3 || got[2] != "done" {
+		t.Errorf("AllowedValues: got %v", got)
+	}
+	f.FromString("review")
+	if f.ToString() != "review" {
+		t.Fatalf("FromString: got %q", f.ToString())
+	}
+	f.FromString("shipped")
+	if f.ToString() != "review" || f.IsAllowed("shipped") {
+		t.Error("a value outside the set is rejected")
+	}
+	f.SetValue(&StringField{ValueField: "lost"})
+	if f.ToString() != "review" {
+		t.Error("SetValue rejects values outside the set too")
+	}
+	f.SetValue(&StringField{ValueField: "done"})
+	if f.ToString() != "done" {
+		t.Errorf("SetValue from a string field: got %q", f.ToString())
+	}
+}
+
+func TestEnumFieldOrder(t *testing.T) {
+	key := NewDefaultFieldKey("Status")
+	draft, done := NewEnumField(key, "draft", "review", "done"), NewEnumField(key, "draft", "review", "done")
+	draft.FromString("draft")
+	done.FromString("done")
+	if !draft.LessThan(done) || !done.GreaterThan(draft) || draft.GreaterThan(done) {
+		t.Error("enums are ordered by their position in the allowed set")
+	}
+	if draft.Equal(done) || !draft.Equal(draft) {
+		t.Error("enums are equal when their values are")
+	}
+	empty := NewEnumField(key, "draft")
+	if !empty.IsEmpty() || draft.IsEmpty() {
+		t.Error("only an enum without a value is empty")
+	}
+}
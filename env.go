@@ -0,0 +1,90 @@
+package fielder
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+type envLoader struct {
+	lookup     func(string) (string, bool)
+	conditions map[FieldKey]Conditional
+}
+
+type EnvOption func(*envLoader)
+
+// checks the loaded values against the conditional for their key, defaults are not checked
+func WithEnvConditions(conditions map[FieldKey]Conditional) EnvOption {
+	return func(l *envLoader) {
+		l.conditions = conditions
+	}
+}
+
+// reads variables from lookup instead of the process environment
+func WithEnvLookup(lookup func(string) (string, bool)) EnvOption {
+	return func(l *envLoader) {
+		l.lookup = lookup
+	}
+}
+
+// the variable a key is read from, ex: prefix "APP" and key MaxConns read APP_MAX_CONNS
+func EnvVarName(prefix string, key FieldKey) string {
+	name := strings.ToUpper(snakeCase(strings.ReplaceAll(key.Name.String(), FieldPathSeparator, "_")))
+	if prefix == "" {
+		return name
+	}
+	return strings.TrimSuffix(prefix, "_") + "_" + name
+}
+
+// LoadFromEnv builds a parent from environment variables, one per tagged member, named with EnvVarName
+// a variable that is unset or empty falls back to the default tag of the member, ex: default:"8080"
+// slice members take a comma separated list, ex: APP_TAGS=a,b
+// invalid values and failed conditions are returned together as a FieldErrors
+func LoadFromEnv[P any](prefix string, opts ...EnvOption) (P, error) {
+	l := &envLoader{lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(l)
+	}
+	ty := reflect.TypeOf(*new(P))
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return *new(P), ErrNotStruct
+	}
+	fields := map[FieldKey]Field{}
+	errs := FieldErrors{}
+	for i := 0; i < ty.NumField(); i++ {
+		sf := ty.Field(i)
+		name, _ := parseFieldTag(sf.Tag.Get(FieldKeyTag))
+		if name == "" || !sf.IsExported() {
+			continue
+		}
+		key := NewDefaultFieldKey(name)
+		st, loaded := l.lookup(EnvVarName(prefix, key))
+		if !loaded || st == "" {
+			st, loaded = sf.Tag.Get(DefaultTag), false
+		}
+		if st == "" {
+			continue
+		}
+		raw := []string{st}
+		if sf.Type.Kind() == reflect.Slice && sf.Type != reflect.TypeOf([]byte{}) {
+			raw = strings.Split(st, ",")
+		}
+		f, err := fieldFromValues[P](key, raw)
+		if err != nil {
+			errs = append(errs, NewFieldError(key, err))
+			continue
+		}
+		if f == nil {
+			continue
+		}
+		if c, ok := l.conditions[key]; loaded && ok && c != nil && !c.Meets(f) {
+			errs = append(errs, NewFieldError(key, ErrConditionNotMet))
+			continue
+		}
+		fields[key] = f
+	}
+	if len(errs) > 0 {
+		return *new(P), errs
+	}
+	return FromFieldMap[P](fields)
+}
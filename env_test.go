@@ -0,0 +1,86 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testEnvConfig struct {
+	Host     string        `field:"Host" default:"localhost"`
+	Port     int           `field:"Port" default:"8080"`
+	MaxConns int           `field:"MaxConns"`
+	Timeout  time.Duration `field:"Timeout"`
+	Tags     []string      `field:"Tags"`
+	Name     *StringField  `field:"Name"`
+	Region   *StringField  `field:"Region" default:"eu"`
+}
+
+func testEnv(vars map[string]string) EnvOption {
+	return WithEnvLookup(func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+}
+
+func TestEnvVarName(t *testing.T) {
+	if got := EnvVarName("APP_", NewDefaultFieldKey("MaxConns")); got != "APP_MAX_CONNS" {
+		t.Errorf("got %s", got)
+	}
+	if got := EnvVarName("", NewDefaultFieldKey("Host")); got != "HOST" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	c, err := LoadFromEnv[testEnvConfig]("APP", testEnv(map[string]string{
+		"APP_PORT":      "9090",
+		"APP_MAX_CONNS": "5",
+		"APP_TIMEOUT":   "2s",
+		"APP_TAGS":      "a,b",
+		"APP_NAME":      "api",
+		"APP_HOST":      "",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Host != "localhost" || c.Port != 9090 || c.MaxConns != 5 || c.Timeout != 2*time.Second {
+		t.Errorf("got %+v", c)
+	}
+	if !reflect.DeepEqual(c.Tags, []string{"a", "b"}) {
+		t.Errorf("slice member: got %v", c.Tags)
+	}
+	if c.Name == nil || c.Name.ValueField != "api" {
+		t.Errorf("field member: got %v", c.Name)
+	}
+	if c.Region == nil || c.Region.ValueField != "eu" {
+		t.Errorf("field member default: got %v", c.Region)
+	}
+}
+
+func TestLoadFromEnvErrors(t *testing.T) {
+	_, err := LoadFromEnv[testEnvConfig]("APP", testEnv(map[string]string{"APP_PORT": "http", "APP_TIMEOUT": "soon"}))
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("got %v", err)
+	}
+	if _, err := LoadFromEnv[int]("APP"); err != ErrNotStruct {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestLoadFromEnvConditions(t *testing.T) {
+	conditions := WithEnvConditions(map[FieldKey]Conditional{NewDefaultFieldKey("Name"): testNotBad(), NewDefaultFieldKey("Region"): testNotBad()})
+	if _, err := LoadFromEnv[testEnvConfig]("APP", testEnv(map[string]string{"APP_NAME": "bad"}), conditions); !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("got %v", err)
+	}
+	// defaults are not checked
+	type withBadDefault struct {
+		Name string `field:"Name" default:"bad"`
+	}
+	c, err := LoadFromEnv[withBadDefault]("APP", testEnv(nil), conditions)
+	if err != nil || c.Name != "bad" {
+		t.Errorf("got %+v %v", c, err)
+	}
+}
@@ -0,0 +1,88 @@
+package fielder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrEnvelopeType = errors.New("envelope holds a different type")
+
+// Envelope is the stream form of a parent, ex: {"type":"order","version":3,"fields":{"Total":{"type":"decimal","key":"Total","value":"12.50"}}}
+// every field is in its type tagged json form, so a consumer that does not know a field can still carry it
+type Envelope struct {
+	Type    string                        `json:"type"`
+	Version int                           `json:"version"`
+	Fields  map[FieldName]json.RawMessage `json:"fields"`
+	// the fields the decoding parent type has no member for, kept so they survive being published again
+	Unknown map[FieldName]json.RawMessage `json:"-"`
+}
+
+// EnvelopeCodec writes parents of one type under a type name and schema version
+// a consumer decodes envelopes of any version: members missing from the envelope keep their zero value,
+// and fields it has no member for are kept in the envelope's Unknown
+type EnvelopeCodec[P any] struct {
+	Type    string
+	Version int
+}
+
+func NewEnvelopeCodec[P any](typeName string, version int) *EnvelopeCodec[P] {
+	return &EnvelopeCodec[P]{Type: typeName, Version: version}
+}
+
+func (c *EnvelopeCodec[P]) Encode(p P) ([]byte, error) {
+	return c.EncodeWithUnknown(p, nil)
+}
+
+// EncodeWithUnknown writes p along with the unknown fields of an envelope it was decoded from
+// fields of p win over unknown fields with the same name
+func (c *EnvelopeCodec[P]) EncodeWithUnknown(p P, from *Envelope) ([]byte, error) {
+	env := Envelope{Type: c.Type, Version: c.Version, Fields: map[FieldName]json.RawMessage{}}
+	if from != nil {
+		for k, v := range from.Unknown {
+			env.Fields[k] = v
+		}
+	}
+	for k, f := range ToFieldMap(p) {
+		b, err := marshalFieldJSON(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k.Name, err)
+		}
+		env.Fields[k.Name] = b
+	}
+	return json.Marshal(env)
+}
+
+func (c *EnvelopeCodec[P]) Decode(b []byte) (P, *Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return *new(P), nil, err
+	}
+	if env.Type != c.Type {
+		return *new(P), &env, fmt.Errorf("%w: %s, expected %s", ErrEnvelopeType, env.Type, c.Type)
+	}
+	env.Unknown = map[FieldName]json.RawMessage{}
+	keySet := FullKeySet[P](FieldKeyTag)
+	fields := map[FieldKey]Field{}
+	for name, raw := range env.Fields {
+		if !IsFieldKey(name, keySet) {
+			env.Unknown[name] = raw
+			continue
+		}
+		key := NewDefaultFieldKey(name.String())
+		f := CreateFieldFromType(GetFieldTypeFromKey[P](key), nil, key)
+		var err error
+		if f == nil {
+			// members that are fields come back as the type named in the payload
+			f, err = UnmarshalFieldJSON(raw, nil)
+		} else {
+			err = unmarshalFieldJSON(raw, f)
+		}
+		if err != nil {
+			return *new(P), &env, fmt.Errorf("%s: %w", name, err)
+		}
+		fields[key] = f
+	}
+	p, err := FromFieldMap[P](fields)
+	return p, &env, err
+}
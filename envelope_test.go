@@ -0,0 +1,89 @@
+package fielder
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+type testOrderV1 struct {
+	Id    string          `field:"Id"`
+	Total decimal.Decimal `field:"Total"`
+}
+
+type testOrderV2 struct {
+	Id    string          `field:"Id"`
+	Total decimal.Decimal `field:"Total"`
+	Note  *StringField    `field:"Note"`
+	Count int             `field:"Count"`
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	codec := NewEnvelopeCodec[testOrderV2]("order", 2)
+	in := testOrderV2{Id: "o1", Total: decimal.RequireFromString("12.50"), Note: testField("Note", "gift"), Count: 3}
+	b, err := codec.Encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw["type"] != "order" || raw["version"] != float64(2) {
+		t.Errorf("got %s", b)
+	}
+	out, env, err := codec.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Id != "o1" || !out.Total.Equal(in.Total) || out.Count != 3 || out.Note == nil || out.Note.ValueField != "gift" {
+		t.Errorf("got %+v", out)
+	}
+	if env.Version != 2 || len(env.Unknown) != 0 {
+		t.Errorf("envelope: got %+v", env)
+	}
+}
+
+func TestEnvelopeVersions(t *testing.T) {
+	v2 := NewEnvelopeCodec[testOrderV2]("order", 2)
+	v1 := NewEnvelopeCodec[testOrderV1]("order", 1)
+	b, err := v2.Encode(testOrderV2{Id: "o1", Note: testField("Note", "gift"), Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an older consumer keeps the fields it does not know
+	old, env, err := v1.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.Id != "o1" || len(env.Unknown) != 2 {
+		t.Fatalf("got %+v %v", old, env.Unknown)
+	}
+	old.Id = "o2"
+	b, err = v1.EncodeWithUnknown(old, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, _, err := v2.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Id != "o2" || back.Count != 3 || back.Note == nil || back.Note.ValueField != "gift" {
+		t.Errorf("unknown fields survive being published again: got %+v", back)
+	}
+	// a newer consumer leaves members missing from the envelope zero
+	b, _ = v1.Encode(testOrderV1{Id: "o3"})
+	if newer, _, err := v2.Decode(b); err != nil || newer.Id != "o3" || newer.Note != nil || newer.Count != 0 {
+		t.Errorf("got %+v %v", newer, err)
+	}
+}
+
+func TestEnvelopeType(t *testing.T) {
+	b, _ := NewEnvelopeCodec[testOrderV1]("invoice", 1).Encode(testOrderV1{Id: "i1"})
+	_, env, err := NewEnvelopeCodec[testOrderV1]("order", 1).Decode(b)
+	if !errors.Is(err, ErrEnvelopeType) || env == nil || env.Type != "invoice" {
+		t.Errorf("got %v %v", env, err)
+	}
+}
@@ -0,0 +1,61 @@
+package fielder
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// FieldError is a problem with the input for one field, it encodes as {"field":"Age","message":"..."}
+type FieldError struct {
+	Key FieldKey
+	Err error
+}
+
+func NewFieldError(key FieldKey, err error) FieldError {
+	return FieldError{Key: key, Err: err}
+}
+
+func (e FieldError) Error() string {
+	return e.Key.Name.String() + ": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+func (e FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Field   FieldName `json:"field"`
+		Message string    `json:"message"`
+	}{Field: e.Key.Name, Message: e.Err.Error()})
+}
+
+// FieldErrors is every field that failed binding, in key order, it encodes as a json list ready for a 400 response body
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	out := make([]string, 0, len(e))
+	for _, v := range e {
+		out = append(out, v.Error())
+	}
+	return strings.Join(out, "; ")
+}
+
+// errors.Is and errors.As look through every field error
+func (e FieldErrors) Unwrap() []error {
+	out := make([]error, 0, len(e))
+	for _, v := range e {
+		out = append(out, v)
+	}
+	return out
+}
+
+// returns the error for the key, or nil if that field was fine
+func (e FieldErrors) For(key FieldKey) error {
+	for _, v := range e {
+		if v.Key == key {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrNotStruct = errors.New("parent is not a struct")
+
+// explodes a default parent into its fields, keyed by the tag on each member
+// members that are nil pointers come back as null fields, so they can still be set and written back,
+// except members that are fields themselves, a nil one has no field to hand out and is left out
+func ToFieldMap[P any](p P) map[FieldKey]Field {
+	out := map[FieldKey]Field{}
+	for _, k := range FullKeySet[P](FieldKeyTag) {
+		if k.Name == "" {
+			continue
+		}
+		v := GetReflectValueOfKeyDefault(p, k)
+		if !v.IsValid() {
+			continue
+		}
+		if v.Type().Implements(fieldInterfaceType) {
+			// members that are already fields are handed out as is, nil ones are left out
+			if (v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface) || !v.IsNil() {
+				out[k] = v.Interface().(Field)
+			}
+			continue
+		}
+		if f := CreateFieldFromType(v.Type(), v.Interface(), k); f != nil {
+			out[k] = f
+		}
+	}
+	return out
+}
+
+// rebuilds a parent from fields, the reverse of ToFieldMap
+// keys that are not members of the parent are an error, members without a field keep their zero value
+func FromFieldMap[P any](m map[FieldKey]Field) (P, error) {
+	p := new(P)
+	pv := reflect.ValueOf(p).Elem()
+	if pv.Kind() != reflect.Struct {
+		return *p, ErrNotStruct
+	}
+	for k, f := range m {
+		member := reflectValueByPath(pv, k.Name)
+		if !member.IsValid() {
+			return *p, fmt.Errorf("%s: no member named %s", pv.Type(), k.Name)
+		}
+		if err := assignField(member, f); err != nil {
+			return *p, fmt.Errorf("%s: %w", k.Name, err)
+		}
+	}
+	return *p, nil
+}
+
+// writes the value of f into the settable member v
+// values are assigned directly when the types line up, otherwise they go through the string form of the member type
+func assignField(v reflect.Value, f Field) error {
+	if !v.CanSet() {
+		return fmt.Errorf("member of type %s can not be set", v.Type())
+	}
+	if f == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Type().Implements(fieldInterfaceType) {
+		// members that are fields take the field itself
+		fv := reflect.ValueOf(f)
+		if fv.Type().AssignableTo(v.Type()) {
+			v.Set(fv)
+			return nil
+		}
+	}
+	val := f.Value()
+	if val == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	rv := reflect.ValueOf(val)
+	if v.Kind() == reflect.Pointer && !rv.Type().AssignableTo(v.Type()) {
+		// optional members are allocated, then the value is written through the pointer
+		elem := reflect.New(v.Type().Elem())
+		if err := assignField(elem.Elem(), f); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if rv.Type().AssignableTo(v.Type()) {
+		v.Set(rv)
+		return nil
+	}
+	if rv.Type().ConvertibleTo(v.Type()) && rv.Kind() != reflect.String {
+		v.Set(rv.Convert(v.Type()))
+		return nil
+	}
+	target := CreateFieldFromType(v.Type(), nil, f.Key())
+	if target == nil {
+		return fmt.Errorf("can not assign %s to member of type %s", rv.Type(), v.Type())
+	}
+	if err := ParseFieldString(target, f.ToString()); err != nil {
+		return err
+	}
+	tv := reflect.ValueOf(target.Value())
+	if !tv.IsValid() || !tv.Type().AssignableTo(v.Type()) {
+		return fmt.Errorf("can not assign %s to member of type %s", rv.Type(), v.Type())
+	}
+	v.Set(tv)
+	return nil
+}
+
+// writes f into the matching member of the parent, the reverse of GetReflectValueOfKeyDefault
+// parent has to be a pointer so the member is addressable
+// a conditional field, or a member that is itself a conditional field, is only written when its conditions are met
+func ApplyField[P any](parent *P, f Field) error {
+	if parent == nil {
+		return ErrNotStruct
+	}
+	pv := reflect.ValueOf(parent).Elem()
+	if pv.Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	member := reflectValueByPath(pv, f.Key().Name)
+	if !member.IsValid() {
+		return fmt.Errorf("%s: no member named %s", pv.Type(), f.Key().Name)
+	}
+	if c, ok := f.(Conditional); ok && !c.Meets(f) {
+		return fmt.Errorf("%s: %w", f.Key().Name, ErrConditionNotMet)
+	}
+	if existing, isField := member.Interface().(Field); isField && existing != nil && !member.IsZero() {
+		// members that are fields are updated in place, so their own wrappers apply
+		if c, ok := existing.(Conditional); ok && !c.Meets(f) {
+			return fmt.Errorf("%s: %w", f.Key().Name, ErrConditionNotMet)
+		}
+		existing.SetValue(unwrapFieldTo(f, existing))
+		return nil
+	}
+	if err := assignField(member, f); err != nil {
+		return fmt.Errorf("%s: %w", f.Key().Name, err)
+	}
+	return nil
+}
+
+// unwraps f until it has the go type of target, ex: a conditional field around a StringField written to a *StringField member,
+// so target's SetValue gets a field it can take, f is returned as is when it does not wrap one
+func unwrapFieldTo(f Field, target Field) Field {
+	for in := f; in != nil; in = unwrapField(in) {
+		if reflect.TypeOf(in) == reflect.TypeOf(target) {
+			return in
+		}
+	}
+	return f
+}
+
+// applies every field to the parent, the conditions of all of them are checked before any member is written
+func ApplyFields[P any](parent *P, fields ...Field) error {
+	for _, f := range fields {
+		if c, ok := f.(Conditional); ok && !c.Meets(f) {
+			return fmt.Errorf("%s: %w", f.Key().Name, ErrConditionNotMet)
+		}
+	}
+	for _, f := range fields {
+		if err := ApplyField(parent, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
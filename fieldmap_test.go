@@ -0,0 +1,134 @@
+package fielder
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type testProfile struct {
+	Name     string       `field:"Name"`
+	Age      int          `field:"Age"`
+	Nickname *string      `field:"Nickname"`
+	Bio      *StringField `field:"Bio"`
+	Note     Field        `field:"Note"`
+	internal string
+}
+
+func TestToFieldMap(t *testing.T) {
+	m := ToFieldMap(testProfile{Name: "sam", Age: 30})
+	if m[NewDefaultFieldKey("Name")].ToString() != "sam" || m[NewDefaultFieldKey("Age")].Value() != 30 {
+		t.Errorf("got %v", m)
+	}
+	if n, ok := m[NewDefaultFieldKey("Nickname")].(*FieldNullable); !ok || !n.IsNull() {
+		t.Errorf("a nil pointer is a null field: got %#v", m[NewDefaultFieldKey("Nickname")])
+	}
+	for _, k := range []string{"Bio", "Note"} {
+		if _, ok := m[NewDefaultFieldKey(k)]; ok {
+			t.Errorf("%s: nil field members are left out", k)
+		}
+	}
+	bio := &StringField{ValueField: "hi", KeyField: NewDefaultFieldKey("Bio")}
+	m = ToFieldMap(testProfile{Bio: bio, Note: bio})
+	if m[NewDefaultFieldKey("Bio")] != bio || m[NewDefaultFieldKey("Note")] != bio {
+		t.Error("field members are handed out as is")
+	}
+}
+
+func TestToFieldMapNilFieldMembers(t *testing.T) {
+	// the encoders built on ToFieldMap skip nil field members instead of calling them
+	p := testProfile{Name: "sam"}
+	if q := ToQuery(p); q.Get("Name") != "sam" || q.Has("Bio") {
+		t.Errorf("ToQuery: got %v", q)
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []testProfile{p}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "Name,Age,Nickname,Bio,Note\nsam,0,,,\n" {
+		t.Errorf("WriteCSV: got %q", buf.String())
+	}
+}
+
+func TestFromFieldMap(t *testing.T) {
+	nick := "s"
+	in := testProfile{Name: "sam", Age: 30, Nickname: &nick, Bio: &StringField{ValueField: "hi", KeyField: NewDefaultFieldKey("Bio")}}
+	out, err := FromFieldMap[testProfile](ToFieldMap(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "sam" || out.Age != 30 || out.Nickname == nil || *out.Nickname != "s" || out.Bio != in.Bio {
+		t.Errorf("got %#v", out)
+	}
+	if _, err := FromFieldMap[testProfile](map[FieldKey]Field{NewDefaultFieldKey("Missing"): &StringField{}}); err == nil {
+		t.Error("keys that are not members are an error")
+	}
+	if _, err := FromFieldMap[string](nil); err != ErrNotStruct {
+		t.Errorf("got %v", err)
+	}
+	out, err = FromFieldMap[testProfile](map[FieldKey]Field{NewDefaultFieldKey("Age"): &StringField{ValueField: "41"}})
+	if err != nil || out.Age != 41 {
+		t.Errorf("values are converted through their string form: got %d %v", out.Age, err)
+	}
+}
+
+// a conditional that rejects the value "bad"
+func testNotBad() Conditional {
+	return Conditions(Prerequisite{
+		Name:        "not bad",
+		IsCandidate: EnforceableTrue,
+		Gauntlet: []Question{func() Enforceable {
+			return func(f any) bool { return f.(Field).ToString() != "bad" }
+		}},
+	})
+}
+
+func TestApplyField(t *testing.T) {
+	p := &testProfile{Bio: testField("Bio", "old")}
+	bio := p.Bio
+	if err := ApplyField(p, testField("Name", "sam")); err != nil || p.Name != "sam" {
+		t.Errorf("plain members are assigned: got %q %v", p.Name, err)
+	}
+	if err := ApplyField(p, &IntegerField{ValueField: 4, KeyField: NewDefaultFieldKey("Age")}); err != nil || p.Age != 4 {
+		t.Errorf("got %d %v", p.Age, err)
+	}
+	if err := ApplyField(p, NewConditionalField(testField("Bio", "new"), testNotBad())); err != nil {
+		t.Fatal(err)
+	}
+	if p.Bio != bio || p.Bio.ValueField != "new" {
+		t.Errorf("field members are set in place: got %q", p.Bio.ValueField)
+	}
+	if err := ApplyField(p, NewNullableField(testField("Bio", "newer"))); err != nil || p.Bio.ValueField != "newer" {
+		t.Errorf("a wrapped field is unwrapped for the member: got %q %v", p.Bio.ValueField, err)
+	}
+	if err := ApplyField(p, NewConditionalField(testField("Bio", "bad"), testNotBad())); !errors.Is(err, ErrConditionNotMet) || p.Bio.ValueField != "newer" {
+		t.Errorf("a field that does not meet its conditions is not applied: got %q %v", p.Bio.ValueField, err)
+	}
+	if err := ApplyField(p, testField("Missing", "x")); err == nil {
+		t.Error("keys that are not members are an error")
+	}
+}
+
+func TestApplyFieldMemberConditions(t *testing.T) {
+	type guarded struct {
+		Status ConditionalField `field:"Status"`
+	}
+	p := &guarded{Status: NewConditionalField(testField("Status", "draft"), testNotBad())}
+	if err := ApplyField(p, testField("Status", "bad")); !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("the member's conditions apply: got %v", err)
+	}
+	if err := ApplyField(p, testField("Status", "live")); err != nil || p.Status.ToString() != "live" {
+		t.Errorf("got %q %v", p.Status.ToString(), err)
+	}
+}
+
+func TestApplyFields(t *testing.T) {
+	p := &testProfile{}
+	err := ApplyFields(p, testField("Name", "sam"), NewConditionalField(testField("Bio", "bad"), testNotBad()))
+	if !errors.Is(err, ErrConditionNotMet) || p.Name != "" {
+		t.Errorf("nothing is applied when one field fails: got %q %v", p.Name, err)
+	}
+	if err := ApplyFields(p, testField("Name", "sam"), testField("Age", "5")); err != nil || p.Name != "sam" || p.Age != 5 {
+		t.Errorf("got %#v %v", p, err)
+	}
+}
@@ -0,0 +1,141 @@
+package fielder
+
+// FieldSet holds the fields of one parent in declaration order
+// it sits between single fields and whole parents, ex: the fields of a request that are about to be applied
+type FieldSet struct {
+	fields []Field
+	index  map[FieldKey]int
+}
+
+type MergeStrategy int
+
+const (
+	MergeOverwrite    MergeStrategy = iota // the other set wins
+	MergeKeepExisting                      // fields already in the set are left alone, only new keys are added
+	MergeFillEmpty                         // fields already in the set are only set when they are empty
+)
+
+func NewFieldSet(fields ...Field) *FieldSet {
+	s := &FieldSet{index: map[FieldKey]int{}}
+	for _, f := range fields {
+		s.Set(f)
+	}
+	return s
+}
+
+// builds a set from every tagged member of a default parent, in declaration order
+func FieldSetOf[P any](p P) *FieldSet {
+	m := ToFieldMap(p)
+	s := NewFieldSet()
+	for _, k := range FullKeySet[P](FieldKeyTag) {
+		if f, ok := m[k]; ok {
+			s.Set(f)
+		}
+	}
+	return s
+}
+
+// returns FieldNil if the key is not in the set
+func (s *FieldSet) Get(key FieldKey) Field {
+	if i, ok := s.index[key]; ok {
+		return s.fields[i]
+	}
+	return FieldNil
+}
+
+func (s *FieldSet) Has(key FieldKey) bool {
+	_, ok := s.index[key]
+	return ok
+}
+
+// replaces the field with the same key in place, or appends it to the end
+func (s *FieldSet) Set(f Field) {
+	if f == nil {
+		return
+	}
+	if s.index == nil {
+		s.index = map[FieldKey]int{}
+	}
+	if i, ok := s.index[f.Key()]; ok {
+		s.fields[i] = f
+		return
+	}
+	s.index[f.Key()] = len(s.fields)
+	s.fields = append(s.fields, f)
+}
+
+func (s *FieldSet) Delete(key FieldKey) {
+	i, ok := s.index[key]
+	if !ok {
+		return
+	}
+	s.fields = append(s.fields[:i], s.fields[i+1:]...)
+	delete(s.index, key)
+	for j := i; j < len(s.fields); j++ {
+		s.index[s.fields[j].Key()] = j
+	}
+}
+
+func (s *FieldSet) Len() int {
+	return len(s.fields)
+}
+
+func (s *FieldSet) Keys() []FieldKey {
+	out := make([]FieldKey, 0, len(s.fields))
+	for _, f := range s.fields {
+		out = append(out, f.Key())
+	}
+	return out
+}
+
+// returns the fields in order, the slice is a copy but the fields are shared
+func (s *FieldSet) Fields() []Field {
+	return append([]Field{}, s.fields...)
+}
+
+// returns the keys that are only in one of the sets or whose fields are not equal
+// keys from this set come first in order, followed by the keys only in other
+func (s *FieldSet) Diff(other *FieldSet) []FieldKey {
+	out := []FieldKey{}
+	for _, f := range s.fields {
+		if !other.Has(f.Key()) || !f.Equal(other.Get(f.Key())) {
+			out = append(out, f.Key())
+		}
+	}
+	for _, f := range other.fields {
+		if !s.Has(f.Key()) {
+			out = append(out, f.Key())
+		}
+	}
+	return out
+}
+
+// merges other into this set, keys only in other are appended in their order
+// existing fields are updated with SetValue, so any Conditional on them still applies
+func (s *FieldSet) Merge(other *FieldSet, strategy MergeStrategy) {
+	for _, f := range other.fields {
+		if !s.Has(f.Key()) {
+			s.Set(f)
+			continue
+		}
+		existing := s.Get(f.Key())
+		switch strategy {
+		case MergeOverwrite:
+			existing.SetValue(f)
+		case MergeFillEmpty:
+			if existing.IsEmpty() {
+				existing.SetValue(f)
+			}
+		case MergeKeepExisting:
+		}
+	}
+}
+
+// returns the set as a map, ready for FromFieldMap to rebuild the parent
+func (s *FieldSet) ToFieldMap() map[FieldKey]Field {
+	out := make(map[FieldKey]Field, len(s.fields))
+	for _, f := range s.fields {
+		out[f.Key()] = f
+	}
+	return out
+}
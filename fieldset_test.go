@@ -0,0 +1,86 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testField(name, value string) *StringField {
+	return &StringField{ValueField: value, KeyField: NewDefaultFieldKey(name)}
+}
+
+func TestFieldSetOrder(t *testing.T) {
+	s := NewFieldSet(testField("A", "1"), testField("B", "2"), testField("C", "3"))
+	s.Set(testField("B", "22"))
+	s.Set(nil)
+	s.Delete(NewDefaultFieldKey("A"))
+	s.Set(testField("D", "4"))
+	want := []FieldKey{NewDefaultFieldKey("B"), NewDefaultFieldKey("C"), NewDefaultFieldKey("D")}
+	if !reflect.DeepEqual(s.Keys(), want) || s.Len() != 3 {
+		t.Errorf("got %v", s.Keys())
+	}
+	if s.Get(NewDefaultFieldKey("B")).ToString() != "22" || s.Get(NewDefaultFieldKey("A")) != FieldNil || s.Has(NewDefaultFieldKey("A")) {
+		t.Error("Set replaces in place and Delete removes")
+	}
+	if s.Get(NewDefaultFieldKey("C")) != s.Fields()[1] {
+		t.Error("the index is kept up to date after a delete")
+	}
+	var zero FieldSet
+	zero.Set(testField("A", "1"))
+	if zero.Len() != 1 {
+		t.Error("the zero set is ready to use")
+	}
+}
+
+func TestFieldSetOf(t *testing.T) {
+	s := FieldSetOf(testProfile{Name: "sam", Age: 3})
+	want := []FieldKey{NewDefaultFieldKey("Name"), NewDefaultFieldKey("Age"), NewDefaultFieldKey("Nickname")}
+	if !reflect.DeepEqual(s.Keys(), want) {
+		t.Errorf("members in declaration order, without nil field members: got %v", s.Keys())
+	}
+	p, err := FromFieldMap[testProfile](s.ToFieldMap())
+	if err != nil || p.Name != "sam" || p.Age != 3 {
+		t.Errorf("got %#v %v", p, err)
+	}
+}
+
+func TestFieldSetDiff(t *testing.T) {
+	a := NewFieldSet(testField("A", "1"), testField("B", "2"), testField("C", "3"))
+	b := NewFieldSet(testField("B", "2"), testField("C", "x"), testField("D", "4"))
+	want := []FieldKey{NewDefaultFieldKey("A"), NewDefaultFieldKey("C"), NewDefaultFieldKey("D")}
+	if got := a.Diff(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v", got)
+	}
+	if got := a.Diff(a); len(got) != 0 {
+		t.Errorf("a set does not differ from itself: got %v", got)
+	}
+}
+
+func TestFieldSetMerge(t *testing.T) {
+	other := func() *FieldSet {
+		return NewFieldSet(testField("A", "new"), testField("B", "new"), testField("C", "new"))
+	}
+	for strategy, want := range map[MergeStrategy][]string{
+		MergeOverwrite:    {"new", "new", "new"},
+		MergeKeepExisting: {"old", "", "new"},
+		MergeFillEmpty:    {"old", "new", "new"},
+	} {
+		s := NewFieldSet(testField("A", "old"), testField("B", ""))
+		s.Merge(other(), strategy)
+		got := []string{}
+		for _, f := range s.Fields() {
+			got = append(got, f.ToString())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("strategy %d: got %q want %q", strategy, got, want)
+		}
+	}
+}
+
+func TestMergeFillEmptyBool(t *testing.T) {
+	s := NewFieldSet(NewBool(NewDefaultFieldKey("Active"), false), NewBoolEmpty(NewDefaultFieldKey("Admin")))
+	s.Merge(NewFieldSet(NewBool(NewDefaultFieldKey("Active"), true), NewBool(NewDefaultFieldKey("Admin"), true)), MergeFillEmpty)
+	if s.Get(NewDefaultFieldKey("Active")).ToString() != "false" || s.Get(NewDefaultFieldKey("Admin")).ToString() != "true" {
+		t.Errorf("a bool set to false is kept, an unset one is filled: got %v", s.Fields())
+	}
+}
@@ -0,0 +1,100 @@
+package fielder
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// fieldFlag is a flag.Value that writes into one member of a parent
+type fieldFlag[P any] struct {
+	parent *P
+	key    FieldKey
+}
+
+// the current value of the member is what -help shows as the default
+func (v *fieldFlag[P]) String() string {
+	if v == nil || v.parent == nil {
+		return ""
+	}
+	if f := flagMemberField(reflect.ValueOf(v.parent).Elem(), v.key); f != nil {
+		return f.ToString()
+	}
+	return ""
+}
+
+func (v *fieldFlag[P]) Set(st string) error {
+	member := reflectValueByPath(reflect.ValueOf(v.parent).Elem(), v.key.Name)
+	var f Field
+	if existing, isField := member.Interface().(Field); isField && existing != nil && !member.IsZero() {
+		f = CreateFieldFromType(existing.Type(), nil, v.key)
+	} else {
+		f = newMemberField(member.Type(), v.key)
+	}
+	if f == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownFieldType, member.Type())
+	}
+	if err := ParseFieldString(f, st); err != nil {
+		return err
+	}
+	return ApplyField(v.parent, f)
+}
+
+// bool members can be given as -Name without a value, members that are fields are bool by the type they hold, even before they are set
+func (v *fieldFlag[P]) IsBoolFlag() bool {
+	if v == nil || v.parent == nil {
+		return false
+	}
+	f := flagMemberField(reflect.ValueOf(v.parent).Elem(), v.key)
+	if f == nil {
+		f = newMemberField(GetFieldTypeFromKey[P](v.key), v.key)
+	}
+	return f != nil && f.Type() != nil && f.Type().Kind() == reflect.Bool
+}
+
+// returns the field currently in the member, a member that is a field with a default shows the default until it is set
+func flagMemberField(pv reflect.Value, key FieldKey) Field {
+	member := reflectValueByPath(pv, key.Name)
+	if !member.IsValid() {
+		return nil
+	}
+	if existing, isField := member.Interface().(Field); isField {
+		if existing == nil || member.IsZero() {
+			return nil
+		}
+		if d, ok := existing.(Default); ok && existing.IsEmpty() && d.DefaultField() != nil {
+			return d.DefaultField()
+		}
+		return existing
+	}
+	return CreateFieldFromType(member.Type(), member.Interface(), key)
+}
+
+// RegisterFlags adds a flag for every tagged member of the parent, named after the field key, ex: -Port 8080
+// flags are parsed with the field's own parsing and written with ApplyField, so conditionals on members still apply
+// the value already in the parent is the flag default, members that are fields with a Default show that default
+func RegisterFlags[P any](fs *flag.FlagSet, parent *P) error {
+	if parent == nil {
+		return ErrNotStruct
+	}
+	pv := reflect.ValueOf(parent).Elem()
+	if pv.Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	for _, key := range FullKeySet[P](FieldKeyTag) {
+		if key.Name == "" {
+			continue
+		}
+		typeName := "value"
+		if f := flagMemberField(pv, key); f != nil {
+			typeName = FieldTypeName(f)
+		} else if ty := GetFieldTypeFromKey[P](key); ty != nil {
+			if f := newMemberField(ty, key); f != nil {
+				typeName = FieldTypeName(f)
+			}
+		}
+		// the back quoted type name is what -help shows as the placeholder, ex: -Port integer
+		fs.Var(&fieldFlag[P]{parent: parent, key: key}, key.Name.String(), fmt.Sprintf("the `%s` for %s", typeName, key.Name))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package fielder
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Host    string        `field:"Host"`
+	Port    int           `field:"Port"`
+	Debug   bool          `field:"Debug"`
+	Timeout time.Duration `field:"Timeout"`
+	Name    *StringField  `field:"Name"`
+	Verbose *BoolField    `field:"Verbose"`
+}
+
+func testFlags(t *testing.T, c *testConfig, args ...string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	if err := RegisterFlags(fs, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+func TestRegisterFlags(t *testing.T) {
+	c := &testConfig{Host: "localhost", Port: 80}
+	testFlags(t, c, "-Port", "8080", "-Debug", "-Timeout", "5s", "-Name", "api")
+	if c.Host != "localhost" || c.Port != 8080 || !c.Debug || c.Timeout != 5*time.Second {
+		t.Errorf("got %+v", c)
+	}
+	if c.Name == nil || c.Name.ValueField != "api" || c.Name.Key().Name != "Name" {
+		t.Errorf("nil field member: got %v", c.Name)
+	}
+}
+
+func TestRegisterFlagsFieldMembers(t *testing.T) {
+	// a nil bool field is still a bool flag, so it can be given without a value
+	c := &testConfig{}
+	testFlags(t, c, "-Verbose", "-Port", "1")
+	if c.Verbose == nil || c.Verbose.ValueField != true || c.Port != 1 {
+		t.Errorf("got %v %d", c.Verbose, c.Port)
+	}
+	existing := testField("Name", "old")
+	c = &testConfig{Name: existing}
+	testFlags(t, c, "-Name", "new")
+	if c.Name != existing || existing.ValueField != "new" {
+		t.Errorf("a set field member is updated in place: got %v", c.Name)
+	}
+}
+
+func TestRegisterFlagsErrors(t *testing.T) {
+	c := &testConfig{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	if err := RegisterFlags(fs, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-Port", "eighty"}); err == nil {
+		t.Error("a value that does not parse is an error")
+	}
+	if err := RegisterFlags[testConfig](fs, nil); err != ErrNotStruct {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestRegisterFlagsUsage(t *testing.T) {
+	c := &testConfig{Port: 80}
+	fs := testFlags(t, c)
+	var out bytes.Buffer
+	fs.SetOutput(&out)
+	fs.PrintDefaults()
+	usage := out.String()
+	for _, want := range []string{"-Port integer", "(default 80)", "-Name string", "-Debug"} {
+		if !strings.Contains(usage, want) {
+			t.Errorf("usage is missing %q:\n%s", want, usage)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package fielder
+
+import (
+	"reflect"
+	"strconv"
+)
+
+type FloatField struct {
+	ValueField float64  `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey `dynamodbav:"key" json:"key"`
+}
+
+func (s *FloatField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *FloatField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *FloatField) Type() reflect.Type {
+	return reflect.TypeOf(float64(0))
+}
+
+func (s *FloatField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.ValueField < in2.(*FloatField).ValueField
+}
+
+func (s *FloatField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.ValueField > in2.(*FloatField).ValueField
+}
+
+func (s *FloatField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField == in2.(*FloatField).ValueField
+}
+
+func (s *FloatField) ToString() string {
+	return strconv.FormatFloat(s.ValueField, 'f', -1, 64)
+}
+
+func (s *FloatField) FromString(st string) {
+	fl, err := strconv.ParseFloat(st, 64)
+	if err != nil {
+		return
+	}
+	s.ValueField = fl
+}
+
+func (s *FloatField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = in2.(*FloatField).ValueField
+	return
+}
+
+func (s *FloatField) IsEmpty() bool {
+	return s.ValueField == 0
+}
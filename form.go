@@ -0,0 +1,26 @@
+package fielder
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// the most memory a multipart form is parsed into before its files go to disk, the same default net/http uses
+const FormMaxMemory = 32 << 20
+
+// BindForm builds a parent from the form values of a request, url encoded or multipart, the same way BindQuery does
+// each field that parses is checked against the conditional for its key, if there is one
+// a request that can not be parsed returns that error, and invalid fields return a FieldErrors
+func BindForm[P any](r *http.Request, conditions map[FieldKey]Conditional) (P, error) {
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err = r.ParseMultipartForm(FormMaxMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return *new(P), err
+	}
+	return bindValues[P](r.Form, conditions)
+}
@@ -0,0 +1,68 @@
+package fielder
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type testSignup struct {
+	Name string       `field:"Name"`
+	Age  int          `field:"Age"`
+	Bio  *StringField `field:"Bio"`
+}
+
+func TestBindForm(t *testing.T) {
+	body := url.Values{"Name": {"sam"}, "Age": {"30"}, "Bio": {"hi"}}.Encode()
+	r := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s, err := BindForm[testSignup](r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "sam" || s.Age != 30 {
+		t.Errorf("got %+v", s)
+	}
+	if s.Bio == nil || s.Bio.ValueField != "hi" {
+		t.Errorf("field member: got %v", s.Bio)
+	}
+}
+
+func TestBindFormMultipart(t *testing.T) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	w.WriteField("Name", "sam")
+	w.WriteField("Bio", "hi")
+	w.Close()
+	r := httptest.NewRequest(http.MethodPost, "/signup?Age=30", &b)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	s, err := BindForm[testSignup](r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "sam" || s.Age != 30 || s.Bio == nil || s.Bio.ValueField != "hi" {
+		t.Errorf("got %+v", s)
+	}
+}
+
+func TestBindFormConditions(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/signup?Name=bad&Bio=bad&Age=x", nil)
+	conditions := map[FieldKey]Conditional{NewDefaultFieldKey("Name"): testNotBad(), NewDefaultFieldKey("Bio"): testNotBad()}
+	_, err := BindForm[testSignup](r, conditions)
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("got %v", err)
+	}
+	if !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("got %v", err)
+	}
+	r = httptest.NewRequest(http.MethodGet, "/signup?Name=good&Bio=fine", nil)
+	if s, err := BindForm[testSignup](r, conditions); err != nil || s.Name != "good" || s.Bio.ValueField != "fine" {
+		t.Errorf("got %+v %v", s, err)
+	}
+}
@@ -0,0 +1,150 @@
+package fielder
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// mean earth radius used for distances, in meters
+const EarthRadiusMeters = 6371008.8
+
+// GeoPoint is a latitude and longitude in degrees
+type GeoPoint struct {
+	Lat float64 `dynamodbav:"lat" json:"lat"`
+	Lng float64 `dynamodbav:"lng" json:"lng"`
+}
+
+func (g GeoPoint) Valid() bool {
+	return g.Lat >= -90 && g.Lat <= 90 && g.Lng >= -180 && g.Lng <= 180
+}
+
+// great circle distance in meters, using the haversine formula
+func (g GeoPoint) DistanceTo(g2 GeoPoint) float64 {
+	toRad := func(deg float64) float64 {
+		return deg * math.Pi / 180
+	}
+	dLat := toRad(g2.Lat - g.Lat)
+	dLng := toRad(g2.Lng - g.Lng)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(g.Lat))*math.Cos(toRad(g2.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ex: "40.7128,-74.006"
+func (g GeoPoint) String() string {
+	return strconv.FormatFloat(g.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(g.Lng, 'f', -1, 64)
+}
+
+type GeoPointField struct {
+	ValueField GeoPoint `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey `dynamodbav:"key" json:"key"`
+	Set        bool     `dynamodbav:"geo_set" json:"geo_set"`
+}
+
+func NewGeoPointField(key FieldKey, lat, lng float64) *GeoPointField {
+	return &GeoPointField{
+		ValueField: GeoPoint{Lat: lat, Lng: lng},
+		KeyField:   key,
+		Set:        true,
+	}
+}
+
+func (s *GeoPointField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *GeoPointField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *GeoPointField) Type() reflect.Type {
+	return reflect.TypeOf(GeoPoint{})
+}
+
+// less than and greater than are not relevant for points, use WithinRadius
+func (s *GeoPointField) LessThan(in2 any) bool {
+	return false
+}
+
+func (s *GeoPointField) GreaterThan(in2 any) bool {
+	return false
+}
+
+func (s *GeoPointField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField == in2.(*GeoPointField).ValueField
+}
+
+// reports whether the other point is within meters of this one
+func (s *GeoPointField) WithinRadius(other GeoPoint, meters float64) bool {
+	return s.ValueField.DistanceTo(other) <= meters
+}
+
+func (s *GeoPointField) ToString() string {
+	if !s.Set {
+		return ""
+	}
+	return s.ValueField.String()
+}
+
+func (s *GeoPointField) FromString(st string) {
+	_ = s.ParseString(st)
+}
+
+// expects "lat,lng" in degrees, out of range values are rejected
+func (s *GeoPointField) ParseString(st string) error {
+	parts := strings.Split(st, ",")
+	if len(parts) != 2 {
+		return fmt.Errorf("%w: %s: expected lat,lng", ErrInvalidGeoPoint, st)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidGeoPoint, st, err)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidGeoPoint, st, err)
+	}
+	g := GeoPoint{Lat: lat, Lng: lng}
+	if !g.Valid() {
+		return fmt.Errorf("%w: %s: out of range", ErrInvalidGeoPoint, st)
+	}
+	s.ValueField = g
+	s.Set = true
+	return nil
+}
+
+func (s *GeoPointField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = in2.(*GeoPointField).ValueField
+	s.Set = in2.(*GeoPointField).Set
+	return
+}
+
+// 0,0 is a real place, so emptiness is tracked separately like BoolField
+func (s *GeoPointField) IsEmpty() bool {
+	return !s.Set
+}
+
+// an Enforceable for Prerequisites, passes when the incoming value is a GeoPointField or GeoPoint within meters of center
+func WithinRadius(center GeoPoint, meters float64) Enforceable {
+	return func(f any) bool {
+		switch v := f.(type) {
+		case *GeoPointField:
+			return v.Set && v.ValueField.DistanceTo(center) <= meters
+		case GeoPoint:
+			return v.DistanceTo(center) <= meters
+		default:
+			return false
+		}
+	}
+}
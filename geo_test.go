@@ -0,0 +1,51 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGeoPointDistance(t *testing.T) {
+	nyc := GeoPoint{Lat: 40.7128, Lng: -74.006}
+	london := GeoPoint{Lat: 51.5074, Lng: -0.1278}
+	if d := nyc.DistanceTo(london); d < 5_560_000 || d > 5_580_000 {
+		t.Errorf("new york to london: got %.0fm", d)
+	}
+	if nyc.DistanceTo(nyc) != 0 {
+		t.Error("a point is no distance from itself")
+	}
+	f := NewGeoPointField(NewDefaultFieldKey("Location"), nyc.Lat, nyc.Lng)
+	if !f.WithinRadius(GeoPoint{Lat: 40.7138, Lng: -74.006}, 200) || f.WithinRadius(london, 1000) {
+		t.Error("WithinRadius")
+	}
+	within := WithinRadius(nyc, 1000)
+	if !within(f) || !within(nyc) || within(london) || within(&GeoPointField{}) || within("nyc") {
+		t.Error("the WithinRadius enforceable takes set points only")
+	}
+}
+
+func TestGeoPointFieldString(t *testing.T) {
+	f := &GeoPointField{}
+	if f.ToString() != "" || !f.IsEmpty() {
+		t.Error("an unset point has no string form")
+	}
+	if err := f.ParseString("0, 0"); err != nil {
+		t.Fatal(err)
+	}
+	if f.IsEmpty() || f.ToString() != "0,0" {
+		t.Errorf("0,0 is a set point: got %q", f.ToString())
+	}
+	for _, bad := range []string{"1", "a,b", "91,0", "0,181"} {
+		if err := f.ParseString(bad); !errors.Is(err, ErrInvalidGeoPoint) {
+			t.Errorf("ParseString(%q): got %v", bad, err)
+		}
+	}
+	g := &GeoPointField{}
+	g.SetValue(NewGeoPointField(FieldKeyNil, 1.5, 2.5))
+	if !g.Equal(NewGeoPointField(FieldKeyNil, 1.5, 2.5)) || g.IsEmpty() {
+		t.Errorf("SetValue: got %q", g.ToString())
+	}
+	if g.LessThan(f) || g.GreaterThan(f) {
+		t.Error("points are not ordered")
+	}
+}
@@ -2,4 +2,26 @@ module github.com/habruzzo/go-fielder
 
 go 1.22
 
-require github.com/shopspring/decimal v1.4.0
+require (
+	github.com/aws/aws-sdk-go-v2 v1.39.2
+	github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue v1.20.14
+	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.51.0
+	github.com/google/uuid v1.6.0
+	github.com/graphql-go/graphql v0.8.1
+	github.com/linkedin/goavro/v2 v2.12.0
+	github.com/shopspring/decimal v1.4.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	golang.org/x/text v0.21.0
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	github.com/aws/aws-sdk-go-v2/internal/configsources v1.4.9 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.7.9 // indirect
+	github.com/aws/aws-sdk-go-v2/service/dynamodbstreams v1.31.0 // indirect
+	github.com/aws/aws-sdk-go-v2/service/internal/accept-encoding v1.13.1 // indirect
+	github.com/aws/aws-sdk-go-v2/service/internal/endpoint-discovery v1.11.9 // indirect
+	github.com/aws/smithy-go v1.23.0 // indirect
+	github.com/golang/snappy v0.0.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+)
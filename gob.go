@@ -0,0 +1,346 @@
+package fielder
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// fields are gob encoded as their msgpack form, so a field held behind the Field interface keeps its type code
+// the concrete types are registered so parents with interface members can be snapshotted with gob
+// conditions are functions and can not be encoded, a wrapper decoded into a nil member comes back without prerequisites
+// and has to have its conditions attached again, decoding into a wrapper that already exists keeps them
+
+func init() {
+	gob.Register(&StringField{})
+	gob.Register(&TimeField{})
+	gob.Register(&DecimalField{})
+	gob.Register(&IntegerField{})
+	gob.Register(&BoolField{})
+	gob.Register(&FloatField{})
+	gob.Register(&DurationField{})
+	gob.Register(&UUIDField{})
+	gob.Register(&BytesField{})
+	gob.Register(&DateField{})
+	gob.Register(&MoneyField{})
+	gob.Register(&QuantityField{})
+	gob.Register(&URLField{})
+	gob.Register(&EmailField{})
+	gob.Register(&IPField{})
+	gob.Register(&CIDRField{})
+	gob.Register(&GeoPointField{})
+	gob.Register(&EnumField{})
+	gob.Register(&CounterField{})
+	gob.Register(&SliceField{})
+	gob.Register(&MapField{})
+	gob.Register(&StructField{})
+	gob.Register(&FieldWDefaultImpl{})
+	gob.Register(&FieldConditional{})
+	gob.Register(&conditionalFieldWDefault{})
+	gob.Register(&FieldNullable{})
+}
+
+// wrapperGob is the gob form of the wrappers, the inner and default fields are in their msgpack form
+type wrapperGob struct {
+	Field         []byte
+	Default       []byte
+	ExplicitlySet bool
+	Null          bool
+}
+
+func encodeWrapperGob(w wrapperGob, f Field, d Default) ([]byte, error) {
+	var err error
+	if f != nil {
+		if w.Field, err = MarshalFieldMsgpack(f); err != nil {
+			return nil, err
+		}
+	}
+	if d != nil && d.DefaultField() != nil {
+		if w.Default, err = MarshalFieldMsgpack(d.DefaultField()); err != nil {
+			return nil, err
+		}
+		w.ExplicitlySet = d.ExplicitlySet()
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(w); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodes into the fields the wrapper already holds, or creates them from the encoded type codes
+func decodeWrapperGob(b []byte, f *Field, d *Default) (wrapperGob, error) {
+	var w wrapperGob
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&w); err != nil {
+		return w, err
+	}
+	if w.Field != nil {
+		if err := decodeWrapperField(w.Field, f); err != nil {
+			return w, err
+		}
+	}
+	if d != nil && w.Default != nil {
+		var df Field
+		if *d != nil {
+			df = (*d).DefaultField()
+		}
+		if err := decodeWrapperField(w.Default, &df); err != nil {
+			return w, err
+		}
+		*d = NewDefault(w.ExplicitlySet, df)
+	}
+	return w, nil
+}
+
+func decodeWrapperField(b []byte, f *Field) error {
+	if *f != nil {
+		return unmarshalFieldMsgpack(b, *f)
+	}
+	decoded, err := UnmarshalFieldMsgpack(b, nil)
+	if err != nil {
+		return err
+	}
+	*f = decoded
+	return nil
+}
+
+func (s *FieldWDefaultImpl) GobEncode() ([]byte, error) {
+	return encodeWrapperGob(wrapperGob{}, s.Field, s.Default)
+}
+
+func (s *FieldWDefaultImpl) GobDecode(b []byte) error {
+	_, err := decodeWrapperGob(b, &s.Field, &s.Default)
+	return err
+}
+
+func (s *FieldConditional) GobEncode() ([]byte, error) {
+	return encodeWrapperGob(wrapperGob{}, s.Field, nil)
+}
+
+func (s *FieldConditional) GobDecode(b []byte) error {
+	if _, err := decodeWrapperGob(b, &s.Field, nil); err != nil {
+		return err
+	}
+	if s.Conditional == nil {
+		s.Conditional = Conditions()
+	}
+	return nil
+}
+
+func (s *conditionalFieldWDefault) GobEncode() ([]byte, error) {
+	return encodeWrapperGob(wrapperGob{}, s.Field, s.Default)
+}
+
+func (s *conditionalFieldWDefault) GobDecode(b []byte) error {
+	if s.FieldConditional == nil {
+		s.FieldConditional = &FieldConditional{}
+	}
+	if _, err := decodeWrapperGob(b, &s.Field, &s.Default); err != nil {
+		return err
+	}
+	if s.Conditional == nil {
+		s.Conditional = Conditions()
+	}
+	return nil
+}
+
+func (s *FieldNullable) GobEncode() ([]byte, error) {
+	return encodeWrapperGob(wrapperGob{Null: s.Null}, s.Field, nil)
+}
+
+func (s *FieldNullable) GobDecode(b []byte) error {
+	w, err := decodeWrapperGob(b, &s.Field, nil)
+	if err != nil {
+		return err
+	}
+	s.Null = w.Null
+	return nil
+}
+
+// an encrypted field is gob encoded as its ciphertext, it is not registered since decoding needs the inner field and cipher set
+func (s *EncryptedField) GobEncode() ([]byte, error) {
+	out, err := s.encrypt()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+func (s *EncryptedField) GobDecode(b []byte) error {
+	return s.decrypt(string(b))
+}
+
+func (s *StringField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *StringField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *TimeField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *TimeField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *DecimalField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *DecimalField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *IntegerField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *IntegerField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *BoolField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *BoolField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *FloatField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *FloatField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *DurationField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *DurationField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *UUIDField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *UUIDField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *BytesField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *BytesField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *DateField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *DateField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *MoneyField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *MoneyField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *QuantityField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *QuantityField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *URLField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *URLField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *EmailField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *EmailField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *IPField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *IPField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *CIDRField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *CIDRField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *GeoPointField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *GeoPointField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *EnumField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *EnumField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *CounterField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *CounterField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *SliceField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *SliceField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *MapField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *MapField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
+
+func (s *StructField) GobEncode() ([]byte, error) {
+	return MarshalFieldMsgpack(s)
+}
+
+func (s *StructField) GobDecode(b []byte) error {
+	return unmarshalFieldMsgpack(b, s)
+}
@@ -0,0 +1,76 @@
+package fielder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func testGobRoundTrip[T any](t *testing.T, in T, out *T) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	if err := gob.NewDecoder(&buf).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGobInterfaceMembers(t *testing.T) {
+	type snapshot struct {
+		Fields []Field
+	}
+	in := snapshot{Fields: []Field{
+		&StringField{ValueField: "a", KeyField: NewDefaultFieldKey("Name")},
+		testDecimal(t, "1.5"),
+		NewCounterField(NewDefaultFieldKey("Views"), 3),
+	}}
+	var out snapshot
+	testGobRoundTrip(t, in, &out)
+	if len(out.Fields) != 3 {
+		t.Fatalf("got %v", out.Fields)
+	}
+	for i, f := range in.Fields {
+		if !out.Fields[i].Equal(f) || out.Fields[i].Key() != f.Key() {
+			t.Errorf("%T: got %T %q", f, out.Fields[i], out.Fields[i].ToString())
+		}
+	}
+}
+
+func TestGobWrappers(t *testing.T) {
+	type snapshot struct {
+		Nick   Field
+		Region Field
+		Status Field
+	}
+	key := NewDefaultFieldKey("Region")
+	in := snapshot{
+		Nick:   NewNullField(&StringField{KeyField: NewDefaultFieldKey("Nick")}),
+		Region: NewFieldWDefault(&StringField{ValueField: "eu", KeyField: key}, NewDefault(true, &StringField{ValueField: "us", KeyField: key})),
+		Status: NewConditionalField(testField("Status", "live"), testNotBad()),
+	}
+	var out snapshot
+	testGobRoundTrip(t, in, &out)
+	if n, ok := out.Nick.(*FieldNullable); !ok || !n.IsNull() {
+		t.Errorf("null is kept: got %#v", out.Nick)
+	}
+	r, ok := out.Region.(*FieldWDefaultImpl)
+	if !ok || r.ToString() != "eu" || r.DefaultField().ToString() != "us" || !r.ExplicitlySet() {
+		t.Errorf("the default is kept: got %#v", out.Region)
+	}
+	c, ok := out.Status.(*FieldConditional)
+	if !ok || c.ToString() != "live" || len(c.Prerequisites()) != 0 {
+		t.Errorf("a new conditional has no prerequisites: got %#v", out.Status)
+	}
+
+	// decoding into a wrapper that already exists keeps its conditions
+	type guarded struct {
+		Status *FieldConditional
+	}
+	g := guarded{Status: NewConditionalField(testField("Status", ""), testNotBad()).(*FieldConditional)}
+	testGobRoundTrip(t, guarded{Status: out.Status.(*FieldConditional)}, &g)
+	if g.Status.ToString() != "live" || len(g.Status.Prerequisites()) != 1 {
+		t.Errorf("got %#v", g.Status)
+	}
+}
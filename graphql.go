@@ -0,0 +1,130 @@
+package fielder
+
+import (
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// GraphQLObject builds a graphql object type for a default parent, with one graphql field per tagged member
+// the resolvers read each value through the parent's fields, so a source that implements Parent is asked with
+// GetResultItemFieldFromKey, and any other struct goes through GetResultItemFieldFromKeyDefault
+// nested structs become their own object types named after the parent and the member, ex: UserAddress
+// decimals, money and anything else without a graphql scalar are exposed in their string form
+// members holding their zero value resolve to null, the same way they read as FieldNil
+func GraphQLObject[P any](name string) (*graphql.Object, error) {
+	ty := reflect.TypeOf(*new(P))
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	return graphQLObjectOf(name, ty, map[reflect.Type]*graphql.Object{}), nil
+}
+
+// objects holds the types already built, so a struct used twice, or inside itself, is one graphql type
+func graphQLObjectOf(name string, ty reflect.Type, objects map[reflect.Type]*graphql.Object) *graphql.Object {
+	if obj, ok := objects[ty]; ok {
+		return obj
+	}
+	obj := graphql.NewObject(graphql.ObjectConfig{Name: name, Fields: graphql.Fields{}})
+	objects[ty] = obj
+	for i := 0; i < ty.NumField(); i++ {
+		sf := ty.Field(i)
+		key, _ := parseFieldTag(sf.Tag.Get(FieldKeyTag))
+		if key == "" || !sf.IsExported() {
+			continue
+		}
+		obj.AddFieldConfig(key, &graphql.Field{
+			Name:    key,
+			Type:    graphQLOutputOf(name+sf.Name, sf.Type, objects),
+			Resolve: graphQLResolver(NewDefaultFieldKey(key)),
+		})
+	}
+	return obj
+}
+
+func graphQLOutputOf(name string, ty reflect.Type, objects map[reflect.Type]*graphql.Object) graphql.Output {
+	if ty.Implements(fieldInterfaceType) {
+		return graphql.String
+	}
+	switch ty {
+	case reflect.TypeOf([]byte{}):
+		return graphql.String
+	}
+	switch ty.Kind() {
+	case reflect.Pointer:
+		return graphQLOutputOf(name, ty.Elem(), objects)
+	case reflect.Bool:
+		return graphql.Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return graphql.Int
+	case reflect.Float32, reflect.Float64:
+		return graphql.Float
+	case reflect.Slice, reflect.Array:
+		return graphql.NewList(graphQLOutputOf(name, ty.Elem(), objects))
+	case reflect.Struct:
+		if f := CreateFieldFromType(ty, nil, FieldKeyNil); f != nil {
+			if _, isStruct := f.(*StructField); !isStruct {
+				// structs that have their own field type, like time.Time or Money, are scalars
+				if _, isTime := f.(*TimeField); isTime {
+					return graphql.DateTime
+				}
+				return graphql.String
+			}
+		}
+		return graphQLObjectOf(name, ty, objects)
+	}
+	// int64 does not fit in a graphql Int, and maps have no graphql type, so both are strings
+	return graphql.String
+}
+
+func graphQLResolver(key FieldKey) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return graphQLValue(graphQLSourceField(p.Source, key)), nil
+	}
+}
+
+func graphQLSourceField(source any, key FieldKey) Field {
+	if parent, ok := source.(Parent); ok {
+		return parent.GetResultItemFieldFromKey(key)
+	}
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return FieldNil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return FieldNil
+	}
+	if member := reflectValueByPath(v, key.Name); member.IsValid() && member.CanInterface() {
+		if f, isField := member.Interface().(Field); isField {
+			// members that are fields are used as they are
+			return f
+		}
+	}
+	return GetResultItemFieldFromKeyDefault(v.Interface(), key)
+}
+
+// converts a field into the value its graphql type serializes
+func graphQLValue(f Field) any {
+	if f == nil || reflect.ValueOf(f).IsNil() || f.Key() == FieldKeyNil || f.Value() == nil {
+		return nil
+	}
+	switch v := f.(type) {
+	case *FieldNullable:
+		return graphQLValue(v.Field)
+	case *IntegerField:
+		return v.ValueField
+	case *FloatField:
+		return v.ValueField
+	case *BoolField:
+		return v.ValueField
+	case *TimeField:
+		return v.ValueField
+	case *StructField, *SliceField:
+		// nested objects and lists resolve from the raw value
+		return f.Value()
+	}
+	return f.ToString()
+}
@@ -0,0 +1,80 @@
+package fielder
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/shopspring/decimal"
+)
+
+type testGraphQLAddress struct {
+	City string `field:"City"`
+}
+
+type testGraphQLUser struct {
+	Name    string             `field:"Name"`
+	Age     int                `field:"Age"`
+	Joined  time.Time          `field:"Joined"`
+	Balance decimal.Decimal    `field:"Balance"`
+	Bio     *StringField       `field:"Bio"`
+	Nick    *string            `field:"Nick"`
+	Address testGraphQLAddress `field:"Address"`
+	secret  string
+}
+
+func testGraphQL(t *testing.T, root any, query string) string {
+	t.Helper()
+	user, err := GraphQLObject[testGraphQLUser]("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{"user": &graphql.Field{Type: user, Resolve: func(graphql.ResolveParams) (any, error) {
+			return root, nil
+		}}},
+	})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+	if len(res.Errors) > 0 {
+		t.Fatal(res.Errors)
+	}
+	b, _ := json.Marshal(res.Data)
+	return string(b)
+}
+
+func TestGraphQLObject(t *testing.T) {
+	u := testGraphQLUser{
+		Name:    "sam",
+		Age:     30,
+		Joined:  time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Balance: decimal.RequireFromString("1.50"),
+		Bio:     testField("Bio", "hi"),
+		Address: testGraphQLAddress{City: "Oslo"},
+	}
+	got := testGraphQL(t, u, "{ user { Name Age Joined Balance Bio Nick Address { City } } }")
+	want := `{"user":{"Address":{"City":"Oslo"},"Age":30,"Balance":"1.5","Bio":"hi","Joined":"2025-01-02T00:00:00Z","Name":"sam","Nick":null}}`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if got := testGraphQL(t, &testGraphQLUser{Name: "ptr"}, "{ user { Name Age Bio } }"); got != `{"user":{"Age":null,"Bio":null,"Name":"ptr"}}` {
+		t.Errorf("zero values and nil fields are null: got %s", got)
+	}
+}
+
+func TestGraphQLObjectNotStruct(t *testing.T) {
+	if _, err := GraphQLObject[int]("Int"); err != ErrNotStruct {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestGraphQLValueSensitive(t *testing.T) {
+	// graphql output is only displayed, so a sensitive field shows redacted instead of failing the query
+	if got := graphQLValue(NewRedactedField(testField("Token", "abc"))); got != RedactedString {
+		t.Errorf("got %v", got)
+	}
+}
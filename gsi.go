@@ -0,0 +1,225 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// global secondary indexes are declared with tag options named after the index, ex: field:"Email,gsi1pk" and field:"CreatedAt,gsi1sk"
+// a member tagged with the bare index name is projected into it, ex: field:"Status,gsi1"
+// several entities share a table, so every item carries its entity type and the type is projected into every index
+
+// the prefix of the tag options naming an index
+const TagOptionIndexPrefix = "gsi"
+
+// the attribute holding the entity type of an item
+const EntityTypeAttribute = "EntityType"
+
+var ErrUnknownIndex = errors.New("parent has no such index")
+
+// parents that share a table with other entities can name their type, otherwise the go type name is used
+type EntityTyped interface {
+	EntityType() string
+}
+
+// GSI is a global secondary index declared on a parent, the keys and projected members are attribute names
+type GSI struct {
+	Name         string
+	PartitionKey string
+	// empty when the index has no sort key
+	SortKey   string
+	Projected []string
+
+	partitionKey dynamoKeyMember
+	sortKey      *dynamoKeyMember
+}
+
+// the projection always includes the entity type, along with the members tagged with the index name
+func (g GSI) Projection() *types.Projection {
+	return &types.Projection{
+		ProjectionType:   types.ProjectionTypeInclude,
+		NonKeyAttributes: append([]string{EntityTypeAttribute}, g.Projected...),
+	}
+}
+
+// the index definition for a CreateTable or UpdateTable, the caller adds the throughput
+func (g GSI) GlobalSecondaryIndex() types.GlobalSecondaryIndex {
+	schema := []types.KeySchemaElement{{AttributeName: aws.String(g.PartitionKey), KeyType: types.KeyTypeHash}}
+	if g.SortKey != "" {
+		schema = append(schema, types.KeySchemaElement{AttributeName: aws.String(g.SortKey), KeyType: types.KeyTypeRange})
+	}
+	return types.GlobalSecondaryIndex{
+		IndexName:  aws.String(g.Name),
+		KeySchema:  schema,
+		Projection: g.Projection(),
+	}
+}
+
+func EntityTypeOf[P any](p P) string {
+	if e, ok := any(p).(EntityTyped); ok {
+		return e.EntityType()
+	}
+	if e, ok := any(&p).(EntityTyped); ok {
+		return e.EntityType()
+	}
+	ty := reflect.TypeOf(p)
+	if ty == nil {
+		return ""
+	}
+	if ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+	return ty.Name()
+}
+
+// returns the index names used in the field tags of the parent, in declaration order
+func dynamoIndexNames[P any]() []string {
+	out := []string{}
+	ty := reflect.TypeOf(*new(P))
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return out
+	}
+	for i := 0; i < ty.NumField(); i++ {
+		_, opts := parseFieldTag(ty.Field(i).Tag.Get(FieldKeyTag))
+		for _, opt := range opts {
+			if !strings.HasPrefix(opt, TagOptionIndexPrefix) {
+				continue
+			}
+			name := strings.TrimSuffix(strings.TrimSuffix(opt, TagOptionPartitionKey), TagOptionSortKey)
+			if !SliceContains(out, name, func(s1, s2 string) bool { return s1 == s2 }) {
+				out = append(out, name)
+			}
+		}
+	}
+	return out
+}
+
+// GSIs returns the indexes declared on the parent, every index needs exactly one partition key member
+func GSIs[P any]() ([]GSI, error) {
+	out := []GSI{}
+	for _, name := range dynamoIndexNames[P]() {
+		pks := dynamoKeyMembers[P](name + TagOptionPartitionKey)
+		sks := dynamoKeyMembers[P](name + TagOptionSortKey)
+		if len(pks) != 1 || len(sks) > 1 {
+			return nil, fmt.Errorf("%s: index %s needs one partition key field and at most one sort key field", reflect.TypeOf(*new(P)), name)
+		}
+		g := GSI{Name: name, PartitionKey: pks[0].Attribute, partitionKey: pks[0]}
+		if len(sks) == 1 {
+			g.SortKey = sks[0].Attribute
+			g.sortKey = &sks[0]
+		}
+		for _, m := range dynamoKeyMembers[P](name) {
+			g.Projected = append(g.Projected, m.Attribute)
+		}
+		out = append(out, g)
+	}
+	return out, nil
+}
+
+func gsiByName[P any](index string) (GSI, error) {
+	indexes, err := GSIs[P]()
+	if err != nil {
+		return GSI{}, err
+	}
+	for _, g := range indexes {
+		if g.Name == index {
+			return g, nil
+		}
+	}
+	return GSI{}, fmt.Errorf("%w: %s", ErrUnknownIndex, index)
+}
+
+// IndexKey returns the key of the parent in the index, ready to use as the ExclusiveStartKey of an index query
+func IndexKey[P any](p P, index string) (map[string]types.AttributeValue, error) {
+	g, err := gsiByName[P](index)
+	if err != nil {
+		return nil, err
+	}
+	return gsiKey(p, g)
+}
+
+func gsiKey[P any](p P, g GSI) (map[string]types.AttributeValue, error) {
+	members := []dynamoKeyMember{g.partitionKey}
+	if g.sortKey != nil {
+		members = append(members, *g.sortKey)
+	}
+	out := make(map[string]types.AttributeValue, len(members))
+	for _, m := range members {
+		av, err := dynamoKeyAttribute(p, m.Key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", g.Name, err)
+		}
+		out[m.Attribute] = av
+	}
+	return out, nil
+}
+
+// IndexKeys returns the key of the parent in every index it appears in, keyed by index name
+// indexes are sparse, so an index with an empty key field is left out rather than being an error
+func IndexKeys[P any](p P) (map[string]map[string]types.AttributeValue, error) {
+	indexes, err := GSIs[P]()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]map[string]types.AttributeValue, len(indexes))
+	for _, g := range indexes {
+		key, err := gsiKey(p, g)
+		if errors.Is(err, ErrEmptyKeyField) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out[g.Name] = key
+	}
+	return out, nil
+}
+
+// ProjectedItem returns the attributes of the parent as they appear in the index:
+// the table key, the index key, the entity type and the projected members
+func ProjectedItem[P any](p P, index string) (map[string]types.AttributeValue, error) {
+	g, err := gsiByName[P](index)
+	if err != nil {
+		return nil, err
+	}
+	out, err := BuildKey(p)
+	if err != nil {
+		return nil, err
+	}
+	key, err := gsiKey(p, g)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range key {
+		out[k] = v
+	}
+	out[EntityTypeAttribute] = &types.AttributeValueMemberS{Value: EntityTypeOf(p)}
+	for _, m := range dynamoKeyMembers[P](g.Name) {
+		f := GetResultItemFieldFromKeyDefault(p, m.Key)
+		if f == nil || f.Key() == FieldKeyNil {
+			continue
+		}
+		av, err := fieldToAttributeValue(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Key.Name, err)
+		}
+		out[m.Attribute] = av
+	}
+	return out, nil
+}
+
+// MarshalEntity marshals the parent as an item of a shared table, tagged with its entity type
+func MarshalEntity[P any](p P) (map[string]types.AttributeValue, error) {
+	out, err := attributevalue.MarshalMap(p)
+	if err != nil {
+		return nil, err
+	}
+	out[EntityTypeAttribute] = &types.AttributeValueMemberS{Value: EntityTypeOf(p)}
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type testGSIUser struct {
+	ID        string       `dynamodbav:"id" field:"ID,pk"`
+	Email     string       `field:"Email,gsi1pk"`
+	CreatedAt string       `field:"CreatedAt,gsi1sk"`
+	Org       string       `field:"Org,gsi2pk"`
+	Status    string       `field:"Status,gsi1,gsi2"`
+	Nickname  *StringField `field:"Nickname,gsi1"`
+	Secret    string       `field:"Secret"`
+}
+
+type testGSIOrder struct {
+	ID string `field:"ID,pk"`
+}
+
+func (testGSIOrder) EntityType() string {
+	return "order"
+}
+
+func TestGSIs(t *testing.T) {
+	indexes, err := GSIs[testGSIUser]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indexes) != 2 {
+		t.Fatalf("got %+v", indexes)
+	}
+	g1, g2 := indexes[0], indexes[1]
+	if g1.Name != "gsi1" || g1.PartitionKey != "Email" || g1.SortKey != "CreatedAt" || !reflect.DeepEqual(g1.Projected, []string{"Status", "Nickname"}) {
+		t.Errorf("gsi1: got %+v", g1)
+	}
+	if g2.Name != "gsi2" || g2.PartitionKey != "Org" || g2.SortKey != "" || !reflect.DeepEqual(g2.Projected, []string{"Status"}) {
+		t.Errorf("gsi2: got %+v", g2)
+	}
+	def := g1.GlobalSecondaryIndex()
+	if aws.ToString(def.IndexName) != "gsi1" || len(def.KeySchema) != 2 || def.KeySchema[1].KeyType != types.KeyTypeRange {
+		t.Errorf("definition: got %+v", def)
+	}
+	if !reflect.DeepEqual(def.Projection.NonKeyAttributes, []string{EntityTypeAttribute, "Status", "Nickname"}) {
+		t.Errorf("projection: got %v", def.Projection.NonKeyAttributes)
+	}
+	type twoKeys struct {
+		A string `field:"A,gsi1pk"`
+		B string `field:"B,gsi1pk"`
+	}
+	if _, err := GSIs[twoKeys](); err == nil {
+		t.Error("an index with two partition keys is an error")
+	}
+}
+
+func TestIndexKeys(t *testing.T) {
+	u := testGSIUser{ID: "u1", Email: "a@b.c", CreatedAt: "2025"}
+	key, err := IndexKey(u, "gsi1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 2 || key["Email"].(*types.AttributeValueMemberS).Value != "a@b.c" {
+		t.Errorf("got %v", key)
+	}
+	if _, err := IndexKey(u, "gsi9"); !errors.Is(err, ErrUnknownIndex) {
+		t.Errorf("got %v", err)
+	}
+	// indexes are sparse, gsi2 has no Org so the user is not in it
+	keys, err := IndexKeys(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := keys["gsi2"]; ok || len(keys) != 1 {
+		t.Errorf("got %v", keys)
+	}
+}
+
+func TestProjectedItem(t *testing.T) {
+	u := testGSIUser{ID: "u1", Email: "a@b.c", CreatedAt: "2025", Status: "active", Nickname: testField("Nickname", "sam"), Secret: "x"}
+	item, err := ProjectedItem(u, "gsi1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"id": "u1", "Email": "a@b.c", "CreatedAt": "2025", EntityTypeAttribute: "testGSIUser", "Status": "active", "Nickname": "sam"}
+	if len(item) != len(want) {
+		t.Errorf("got %v", item)
+	}
+	for k, v := range want {
+		if s, ok := item[k].(*types.AttributeValueMemberS); !ok || s.Value != v {
+			t.Errorf("%s: got %v want %s", k, item[k], v)
+		}
+	}
+	// nil members are left out of the projection
+	u.Nickname = nil
+	item, err = ProjectedItem(u, "gsi1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := item["Nickname"]; ok {
+		t.Errorf("got %v", item["Nickname"])
+	}
+}
+
+func TestEntityType(t *testing.T) {
+	if got := EntityTypeOf(testGSIUser{}); got != "testGSIUser" {
+		t.Errorf("got %s", got)
+	}
+	if got := EntityTypeOf(testGSIOrder{}); got != "order" {
+		t.Errorf("got %s", got)
+	}
+	item, err := MarshalEntity(testGSIOrder{ID: "o1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := item[EntityTypeAttribute].(*types.AttributeValueMemberS); !ok || s.Value != "order" {
+		t.Errorf("got %v", item)
+	}
+}
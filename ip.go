@@ -0,0 +1,186 @@
+package fielder
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"reflect"
+)
+
+// IPField orders addresses by their 16 byte form, so an ipv4 address and its ipv4-in-ipv6 form are equal
+type IPField struct {
+	ValueField net.IP   `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey `dynamodbav:"key" json:"key"`
+}
+
+func (s *IPField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *IPField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *IPField) Type() reflect.Type {
+	return reflect.TypeOf(net.IP{})
+}
+
+func (s *IPField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return bytes.Compare(s.ValueField.To16(), in2.(*IPField).ValueField.To16()) < 0
+}
+
+func (s *IPField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return bytes.Compare(s.ValueField.To16(), in2.(*IPField).ValueField.To16()) > 0
+}
+
+func (s *IPField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField.Equal(in2.(*IPField).ValueField)
+}
+
+func (s *IPField) ToString() string {
+	if len(s.ValueField) == 0 {
+		return ""
+	}
+	return s.ValueField.String()
+}
+
+func (s *IPField) FromString(st string) {
+	_ = s.ParseString(st)
+}
+
+func (s *IPField) ParseString(st string) error {
+	ip := net.ParseIP(st)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrInvalidIP, st)
+	}
+	s.ValueField = ip
+	return nil
+}
+
+func (s *IPField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = bytes.Clone(in2.(*IPField).ValueField)
+	return
+}
+
+func (s *IPField) IsEmpty() bool {
+	return len(s.ValueField) == 0
+}
+
+// CIDRField orders networks by their base address, then by prefix length
+type CIDRField struct {
+	ValueField net.IPNet `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey  `dynamodbav:"key" json:"key"`
+}
+
+func (s *CIDRField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *CIDRField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *CIDRField) Type() reflect.Type {
+	return reflect.TypeOf(net.IPNet{})
+}
+
+func (s *CIDRField) compare(s2 *CIDRField) int {
+	if c := bytes.Compare(s.ValueField.IP.To16(), s2.ValueField.IP.To16()); c != 0 {
+		return c
+	}
+	ones, _ := s.ValueField.Mask.Size()
+	ones2, _ := s2.ValueField.Mask.Size()
+	return ones - ones2
+}
+
+func (s *CIDRField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.compare(in2.(*CIDRField)) < 0
+}
+
+func (s *CIDRField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.compare(in2.(*CIDRField)) > 0
+}
+
+func (s *CIDRField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.compare(in2.(*CIDRField)) == 0
+}
+
+// reports whether the network contains the ip
+func (s *CIDRField) Contains(ip net.IP) bool {
+	return s.ValueField.IP != nil && s.ValueField.Contains(ip)
+}
+
+// reports whether the network contains the ip held by an IPField
+func (s *CIDRField) ContainsField(f *IPField) bool {
+	return s.Contains(f.ValueField)
+}
+
+func (s *CIDRField) ToString() string {
+	if s.ValueField.IP == nil {
+		return ""
+	}
+	return s.ValueField.String()
+}
+
+func (s *CIDRField) FromString(st string) {
+	_ = s.ParseString(st)
+}
+
+// the stored network is always the masked base address, so "10.0.0.7/8" is stored as 10.0.0.0/8
+func (s *CIDRField) ParseString(st string) error {
+	_, ipNet, err := net.ParseCIDR(st)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidCIDR, st, err)
+	}
+	s.ValueField = *ipNet
+	return nil
+}
+
+// SetValue drops the parse error, use TrySetValue to see it
+func (s *CIDRField) SetValue(in2 FieldValue) {
+	_ = s.TrySetValue(in2)
+}
+
+// TrySetValue copies the value of a CIDRField as is, so an empty one clears the field
+// any other field is parsed from its string form, where "" clears the field and anything invalid is returned as an error
+func (s *CIDRField) TrySetValue(in2 FieldValue) error {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		st := in2.(Field).ToString()
+		if st == "" {
+			resetField(s)
+			return nil
+		}
+		return s.ParseString(st)
+	}
+	s.ValueField = in2.(*CIDRField).ValueField
+	return nil
+}
+
+func (s *CIDRField) IsEmpty() bool {
+	return s.ValueField.IP == nil
+}
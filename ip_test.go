@@ -0,0 +1,80 @@
+package fielder
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestIPFieldCompare(t *testing.T) {
+	v4 := &IPField{ValueField: net.ParseIP("10.0.0.1").To4()}
+	mapped := &IPField{ValueField: net.ParseIP("::ffff:10.0.0.1")}
+	if !v4.Equal(mapped) {
+		t.Error("an ipv4 address equals its ipv4-in-ipv6 form")
+	}
+	next := &IPField{ValueField: net.ParseIP("10.0.0.2")}
+	if !v4.LessThan(next) || !next.GreaterThan(v4) || v4.Equal(next) {
+		t.Error("addresses order by their bytes")
+	}
+}
+
+func TestIPFieldString(t *testing.T) {
+	f := &IPField{}
+	if f.ToString() != "" || !f.IsEmpty() {
+		t.Error("an ip field without a value is empty")
+	}
+	if err := f.ParseString("2001:db8::1"); err != nil {
+		t.Fatal(err)
+	}
+	if f.ToString() != "2001:db8::1" {
+		t.Errorf("got %q", f.ToString())
+	}
+	if err := f.ParseString("10.0.0.256"); !errors.Is(err, ErrInvalidIP) {
+		t.Errorf("got %v", err)
+	}
+	src := &IPField{ValueField: net.ParseIP("10.0.0.1")}
+	f.SetValue(src)
+	src.ValueField[len(src.ValueField)-1] = 9
+	if f.ToString() != "10.0.0.1" {
+		t.Errorf("SetValue copies the address: got %q", f.ToString())
+	}
+}
+
+func TestCIDRField(t *testing.T) {
+	f := &CIDRField{}
+	if err := f.ParseString("10.0.0.7/8"); err != nil {
+		t.Fatal(err)
+	}
+	if f.ToString() != "10.0.0.0/8" {
+		t.Errorf("the network is stored masked: got %q", f.ToString())
+	}
+	if !f.Contains(net.ParseIP("10.200.0.1")) || f.ContainsField(&IPField{ValueField: net.ParseIP("11.0.0.1")}) {
+		t.Error("Contains checks the network")
+	}
+	if (&CIDRField{}).Contains(net.ParseIP("10.0.0.1")) {
+		t.Error("an empty network contains nothing")
+	}
+	narrow := &CIDRField{}
+	narrow.FromString("10.0.0.0/16")
+	if !f.LessThan(narrow) || !narrow.GreaterThan(f) || f.Equal(narrow) {
+		t.Error("networks with the same base order by prefix length")
+	}
+	if err := f.ParseString("10.0.0.0"); !errors.Is(err, ErrInvalidCIDR) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestCIDRFieldSetValue(t *testing.T) {
+	f := &CIDRField{}
+	f.FromString("10.0.0.0/8")
+	f.SetValue(&CIDRField{})
+	if !f.IsEmpty() {
+		t.Errorf("an empty cidr clears the field, got %q", f.ToString())
+	}
+	if err := f.TrySetValue(testField("Net", "10.0.0.0")); !errors.Is(err, ErrInvalidCIDR) {
+		t.Errorf("a bare address is returned, got %v", err)
+	}
+	if err := f.TrySetValue(testField("Net", "10.0.0.7/8")); err != nil || f.ToString() != "10.0.0.0/8" {
+		t.Errorf("got %q, %v", f.ToString(), err)
+	}
+}
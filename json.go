@@ -0,0 +1,341 @@
+package fielder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var ErrUnknownFieldType = errors.New("unknown field type")
+
+// fieldJSON is the json form of every field, ex: {"type":"decimal","key":"Amount","value":"12.50"}
+// the value is always the field's string form so no precision is lost, and the tag is left out when it is the default
+type fieldJSON struct {
+	Type  string    `json:"type"`
+	Key   FieldName `json:"key"`
+	Tag   string    `json:"tag,omitempty"`
+	Value string    `json:"value"`
+}
+
+// the type names used to tag encoded fields, keyed by the field's value type
+var fieldTypeNames = map[reflect.Type]string{
+	reflect.TypeOf(""):                "string",
+	reflect.TypeOf(time.Time{}):       "time",
+	reflect.TypeOf(decimal.Decimal{}): "decimal",
+	reflect.TypeOf(int(0)):            "integer",
+	reflect.TypeOf(true):              "bool",
+	reflect.TypeOf(float64(0)):        "float",
+	reflect.TypeOf(time.Duration(0)):  "duration",
+	reflect.TypeOf(uuid.UUID{}):       "uuid",
+	reflect.TypeOf([]byte{}):          "bytes",
+	reflect.TypeOf(Date{}):            "date",
+	reflect.TypeOf(Money{}):           "money",
+	reflect.TypeOf(Quantity{}):        "quantity",
+	reflect.TypeOf(URL("")):           "url",
+	reflect.TypeOf(Email("")):         "email",
+	reflect.TypeOf(net.IP{}):          "ip",
+	reflect.TypeOf(net.IPNet{}):       "cidr",
+	reflect.TypeOf(GeoPoint{}):        "geo",
+	reflect.TypeOf(EnumValue("")):     "enum",
+	reflect.TypeOf(int64(0)):          "counter",
+}
+
+// the value types for each type name, the reverse of fieldTypeNames
+var fieldTypesByName = func() map[string]reflect.Type {
+	out := make(map[string]reflect.Type, len(fieldTypeNames))
+	for k, v := range fieldTypeNames {
+		out[v] = k
+	}
+	return out
+}()
+
+// the name a field's type is tagged with when encoded, ex: "decimal"
+// slices, maps and structs are tagged by their kind, anything else unknown by its go type
+func FieldTypeName(f Field) string {
+	ty := f.Type()
+	if ty == nil {
+		return "nil"
+	}
+	if name, ok := fieldTypeNames[ty]; ok {
+		return name
+	}
+	switch ty.Kind() {
+	case reflect.Slice:
+		return "slice"
+	case reflect.Map:
+		return "map"
+	case reflect.Struct:
+		return "struct"
+	}
+	return ty.String()
+}
+
+// creates an empty field for a type name, fields that need more than a key to be built (enums, slices) are not supported
+func NewFieldFromTypeName(name string, key FieldKey) (Field, error) {
+	if name == "counter" {
+		return NewCounterField(key, 0), nil
+	}
+	ty, ok := fieldTypesByName[name]
+	if !ok || name == "enum" {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, name)
+	}
+	return CreateFieldFromType(ty, nil, key), nil
+}
+
+func newFieldJSON(f Field, value string) fieldJSON {
+	out := fieldJSON{
+		Type:  FieldTypeName(f),
+		Key:   f.Key().Name,
+		Value: value,
+	}
+	if f.Key().Tag != FieldKeyTag {
+		out.Tag = f.Key().Tag
+	}
+	return out
+}
+
+func (j fieldJSON) key() FieldKey {
+	return NewFieldKey(j.Key.String(), j.Tag)
+}
+
+func marshalFieldJSON(f Field) ([]byte, error) {
+	return json.Marshal(newFieldJSON(f, f.ToString()))
+}
+
+// sets the key and value of f from its json form, f keeps its own type and options
+func unmarshalFieldJSON(b []byte, f Field) error {
+	var in fieldJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	if keyField := reflect.ValueOf(f).Elem().FieldByName("KeyField"); keyField.IsValid() && keyField.CanSet() {
+		keyField.Set(reflect.ValueOf(in.key()))
+	}
+	if in.Value == "" {
+		return nil
+	}
+	return ParseFieldString(f, in.Value)
+}
+
+// UnmarshalFieldJSON decodes a field of the expected value type, ex: reflect.TypeOf(decimal.Decimal{})
+// if expected is nil the type is taken from the encoded type name
+func UnmarshalFieldJSON(b []byte, expected reflect.Type) (Field, error) {
+	var in fieldJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return nil, err
+	}
+	var f Field
+	if expected != nil {
+		f = CreateFieldFromType(expected, nil, in.key())
+		if f == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, expected)
+		}
+	} else {
+		var err error
+		if f, err = NewFieldFromTypeName(in.Type, in.key()); err != nil {
+			return nil, err
+		}
+	}
+	if in.Value == "" {
+		return f, nil
+	}
+	return f, ParseFieldString(f, in.Value)
+}
+
+func (s *StringField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *StringField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *TimeField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *TimeField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *DecimalField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *DecimalField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *IntegerField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *IntegerField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+// an unset bool is written with an empty value so it comes back unset
+func (s *BoolField) MarshalJSON() ([]byte, error) {
+	if !s.Set {
+		return json.Marshal(newFieldJSON(s, ""))
+	}
+	return marshalFieldJSON(s)
+}
+
+func (s *BoolField) UnmarshalJSON(b []byte) error {
+	s.Set = false
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *FloatField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *FloatField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *DurationField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *DurationField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *UUIDField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *UUIDField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *BytesField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *BytesField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *DateField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *DateField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *MoneyField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *MoneyField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *QuantityField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *QuantityField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *URLField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *URLField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *EmailField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *EmailField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *IPField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *IPField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *CIDRField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *CIDRField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+// an unset point is written with an empty value so it comes back unset
+func (s *GeoPointField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *GeoPointField) UnmarshalJSON(b []byte) error {
+	s.Set = false
+	return unmarshalFieldJSON(b, s)
+}
+
+// the allowed set is not encoded, it has to come from the field being unmarshaled into
+func (s *EnumField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *EnumField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *CounterField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *CounterField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *SliceField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *SliceField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *MapField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *MapField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *StructField) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *StructField) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
+
+func (s *TypedField[T]) MarshalJSON() ([]byte, error) {
+	return marshalFieldJSON(s)
+}
+
+func (s *TypedField[T]) UnmarshalJSON(b []byte) error {
+	return unmarshalFieldJSON(b, s)
+}
@@ -0,0 +1,87 @@
+package fielder
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFieldJSONShape(t *testing.T) {
+	b, err := json.Marshal(testDecimal(t, "12.50"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"type":"decimal","key":"Amount","value":"12.5"}` {
+		t.Errorf("got %s", b)
+	}
+	b, _ = json.Marshal(&StringField{ValueField: "x", KeyField: NewFieldKey("name", "json")})
+	if string(b) != `{"type":"string","key":"name","tag":"json","value":"x"}` {
+		t.Errorf("a tag other than the default is kept: got %s", b)
+	}
+}
+
+func TestFieldJSONRoundTrip(t *testing.T) {
+	key := NewDefaultFieldKey("Value")
+	for _, f := range []Field{
+		&StringField{ValueField: "hi", KeyField: key},
+		&IntegerField{ValueField: -4, KeyField: key},
+		NewBool(key, false),
+		&FloatField{ValueField: 2.25, KeyField: key},
+		NewTimeField(key, time.Date(2025, 6, 7, 8, 9, 10, 0, time.UTC)),
+		&DurationField{ValueField: 90 * time.Second, KeyField: key},
+		&BytesField{ValueField: []byte("raw"), KeyField: key},
+		&IPField{ValueField: net.ParseIP("10.1.2.3"), KeyField: key},
+		NewGeoPointField(key, 0, 0),
+		NewCounterField(key, 5),
+	} {
+		b, err := json.Marshal(f)
+		if err != nil {
+			t.Fatalf("%T: %v", f, err)
+		}
+		got, err := UnmarshalFieldJSON(b, nil)
+		if err != nil {
+			t.Fatalf("%T: %v", f, err)
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(f) || !got.Equal(f) || got.Key() != key {
+			t.Errorf("%s: got %T %q", b, got, got.ToString())
+		}
+		into := CreateFieldFromType(f.Type(), nil, FieldKeyNil)
+		if f.Type() == reflect.TypeOf(int64(0)) {
+			into = NewCounterField(FieldKeyNil, 0)
+		}
+		if err := json.Unmarshal(b, into); err != nil || !into.Equal(f) || into.Key() != key {
+			t.Errorf("%s: unmarshal into a field: got %q %v", b, into.ToString(), err)
+		}
+	}
+}
+
+func TestFieldJSONUnset(t *testing.T) {
+	b, _ := json.Marshal(&BoolField{})
+	f := NewBool(FieldKeyNil, true).(*BoolField)
+	if err := json.Unmarshal(b, f); err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalFieldJSON(b, nil)
+	if err != nil || got.(*BoolField).Set {
+		t.Errorf("an unset bool comes back unset: got %#v %v", got, err)
+	}
+}
+
+func TestUnmarshalFieldJSONErrors(t *testing.T) {
+	if _, err := UnmarshalFieldJSON([]byte(`{"type":"widget","key":"X","value":"1"}`), nil); !errors.Is(err, ErrUnknownFieldType) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := UnmarshalFieldJSON([]byte(`{"type":"enum","key":"X","value":"a"}`), nil); !errors.Is(err, ErrUnknownFieldType) {
+		t.Errorf("enums need their allowed set: got %v", err)
+	}
+	if _, err := UnmarshalFieldJSON([]byte(`{"type":"integer","key":"X","value":"one"}`), nil); err == nil {
+		t.Error("a value that does not parse is an error")
+	}
+	f, err := UnmarshalFieldJSON([]byte(`{"type":"string","key":"X","value":"7"}`), reflect.TypeOf(0))
+	if err != nil || f.Value() != 7 {
+		t.Errorf("the expected type wins over the encoded one: got %#v %v", f, err)
+	}
+}
@@ -0,0 +1,187 @@
+package fielder
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// MapField is a keyed bag of sub fields that behaves as a single field
+// sub fields do not need to share a type, but if ElemType is set it is used when parsing new entries
+type MapField struct {
+	ValueField map[string]Field `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey         `dynamodbav:"key" json:"key"`
+	ElemType   reflect.Type     `dynamodbav:"-" json:"-"`
+}
+
+func NewMapField(key FieldKey, elemType reflect.Type) *MapField {
+	return &MapField{
+		ValueField: make(map[string]Field),
+		KeyField:   key,
+		ElemType:   elemType,
+	}
+}
+
+// nested fields are keyed off the parent key, ex: Attributes.color or Address.City
+func nestedFieldKey(key FieldKey, name string) FieldKey {
+	return NewFieldKey(key.Name.String()+FieldPathSeparator+name, key.Tag)
+}
+
+// creates a map field from a map with string keys and any value type supported by CreateFieldFromType
+func createMapField(ty reflect.Type, va any, fk FieldKey) Field {
+	if ty.Key().Kind() != reflect.String || CreateFieldFromType(ty.Elem(), nil, fk) == nil {
+		return nil
+	}
+	m := NewMapField(fk, ty.Elem())
+	if va == nil {
+		return m
+	}
+	iter := reflect.ValueOf(va).MapRange()
+	for iter.Next() {
+		name := iter.Key().String()
+		m.ValueField[name] = CreateFieldFromType(ty.Elem(), iter.Value().Interface(), nestedFieldKey(fk, name))
+	}
+	return m
+}
+
+func (s *MapField) Value() FieldValue {
+	out := make(map[string]FieldValue, len(s.ValueField))
+	for k, v := range s.ValueField {
+		if v == nil {
+			out[k] = nil
+			continue
+		}
+		out[k] = v.Value()
+	}
+	return out
+}
+
+func (s *MapField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *MapField) Type() reflect.Type {
+	return reflect.TypeOf(map[string]Field{})
+}
+
+// less than and greater than are not relevant for maps
+func (s *MapField) LessThan(in2 any) bool {
+	return false
+}
+
+func (s *MapField) GreaterThan(in2 any) bool {
+	return false
+}
+
+// maps are equal when they have the same set of names and every sub field is equal
+func (s *MapField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	m2 := in2.(*MapField)
+	if len(s.ValueField) != len(m2.ValueField) {
+		return false
+	}
+	for k, v := range s.ValueField {
+		v2, ok := m2.ValueField[k]
+		if !ok || compareElems(v, v2) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// the string form is a json object of the ToString of each entry, ex: {"color":"green"}
+func (s *MapField) ToString() string {
+	out := make(map[string]string, len(s.ValueField))
+	for k, v := range s.ValueField {
+		if v == nil {
+			out[k] = ""
+			continue
+		}
+		out[k] = v.ToString()
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// entries keep the type of the existing entry with the same name if there is one,
+// otherwise they use ElemType, and fall back to StringField
+func (s *MapField) FromString(st string) {
+	var in map[string]string
+	if err := json.Unmarshal([]byte(st), &in); err != nil {
+		return
+	}
+	out := make(map[string]Field, len(in))
+	for k, v := range in {
+		elemType := reflect.TypeOf("")
+		if existing, ok := s.ValueField[k]; ok && existing != nil {
+			elemType = existing.Type()
+		} else if s.ElemType != nil {
+			elemType = s.ElemType
+		}
+		f := CreateFieldFromType(elemType, nil, nestedFieldKey(s.KeyField, k))
+		if f == nil {
+			return
+		}
+		f.FromString(v)
+		out[k] = f
+	}
+	s.ValueField = out
+}
+
+func (s *MapField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	out := make(map[string]Field, len(in2.(*MapField).ValueField))
+	for k, v := range in2.(*MapField).ValueField {
+		out[k] = v
+	}
+	s.ValueField = out
+	return
+}
+
+func (s *MapField) IsEmpty() bool {
+	return len(s.ValueField) == 0
+}
+
+// returns FieldNil if there is no entry for the name
+func (s *MapField) Get(name string) Field {
+	if f, ok := s.ValueField[name]; ok {
+		return f
+	}
+	return FieldNil
+}
+
+func (s *MapField) Set(name string, f Field) {
+	if s.ValueField == nil {
+		s.ValueField = make(map[string]Field)
+	}
+	s.ValueField[name] = f
+}
+
+func (s *MapField) Delete(name string) {
+	delete(s.ValueField, name)
+}
+
+func (s *MapField) Has(name string) bool {
+	_, ok := s.ValueField[name]
+	return ok
+}
+
+// names of every entry, sorted
+func (s *MapField) Names() []string {
+	out := make([]string, 0, len(s.ValueField))
+	for k := range s.ValueField {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,81 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapFieldFromType(t *testing.T) {
+	m := CreateFieldFromType(reflect.TypeOf(map[string]int{}), map[string]int{"a": 1, "b": 2}, NewDefaultFieldKey("Counts")).(*MapField)
+	if !reflect.DeepEqual(m.Names(), []string{"a", "b"}) {
+		t.Fatalf("Names: got %v", m.Names())
+	}
+	if m.Get("a").Key().Name != "Counts.a" || m.Get("a").Value() != 1 {
+		t.Errorf("Get: got %v %v", m.Get("a").Key().Name, m.Get("a").Value())
+	}
+	if m.Get("z") != FieldNil {
+		t.Error("a missing name is FieldNil")
+	}
+	if CreateFieldFromType(reflect.TypeOf(map[int]string{}), nil, NewDefaultFieldKey("M")) != nil {
+		t.Error("maps without string keys make no field")
+	}
+}
+
+func TestMapFieldEqual(t *testing.T) {
+	key := NewDefaultFieldKey("Attrs")
+	a, b := NewMapField(key, nil), NewMapField(key, nil)
+	a.Set("color", &StringField{ValueField: "green"})
+	b.Set("color", &StringField{ValueField: "green"})
+	if !a.Equal(b) {
+		t.Error("maps with equal entries are equal")
+	}
+	b.Set("size", &IntegerField{ValueField: 2})
+	if a.Equal(b) || b.Equal(a) {
+		t.Error("maps with different names are not equal")
+	}
+	b.Delete("size")
+	b.Set("color", &StringField{ValueField: "red"})
+	if a.Equal(b) {
+		t.Error("maps with different entries are not equal")
+	}
+	if a.LessThan(b) || a.GreaterThan(b) {
+		t.Error("maps are not ordered")
+	}
+}
+
+func TestMapFieldNilEntries(t *testing.T) {
+	m := NewMapField(NewDefaultFieldKey("Attrs"), nil)
+	m.Set("gone", nil)
+	if got := m.Value(); !reflect.DeepEqual(got, map[string]FieldValue{"gone": nil}) {
+		t.Errorf("Value: got %#v", got)
+	}
+	if got := m.ToString(); got != `{"gone":""}` {
+		t.Errorf("ToString: got %s", got)
+	}
+	other := NewMapField(NewDefaultFieldKey("Attrs"), nil)
+	other.Set("gone", nil)
+	if !m.Equal(other) {
+		t.Error("nil entries are equal to each other")
+	}
+}
+
+func TestMapFieldString(t *testing.T) {
+	m := NewMapField(NewDefaultFieldKey("Attrs"), reflect.TypeOf(0))
+	m.Set("name", &StringField{ValueField: "x"})
+	m.FromString(`{"name":"y","count":"3"}`)
+	if m.Get("name").Value() != "y" || m.Get("count").Value() != 3 {
+		t.Fatalf("existing entries keep their type, new ones use ElemType: got %#v", m.Value())
+	}
+	if got := m.ToString(); got != `{"count":"3","name":"y"}` {
+		t.Errorf("ToString: got %s", got)
+	}
+	other := NewMapField(NewDefaultFieldKey("Attrs"), nil)
+	other.SetValue(m)
+	m.Delete("name")
+	if !other.Has("name") {
+		t.Error("SetValue shares the entries map")
+	}
+	if other.IsEmpty() || !NewMapField(NewDefaultFieldKey("A"), nil).IsEmpty() {
+		t.Error("only a map without entries is empty")
+	}
+}
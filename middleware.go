@@ -0,0 +1,71 @@
+package fielder
+
+// Setter writes a value to a field, returning why it did not
+type Setter func(f Field, v FieldValue) error
+
+// SetterMiddleware wraps a Setter, it can look at the write, change it, stop it or pass it on to next
+type SetterMiddleware func(next Setter) Setter
+
+// InterceptedField is a field whose writes go through a chain of middleware, see Intercept
+type InterceptedField struct {
+	Field
+	setter Setter
+}
+
+// Intercept runs every write to the field through the middleware, the first one given is the first to see the write, ex:
+// Intercept(f, ObserveSets(logSet), CheckConditions(rules), DryRun())
+// the field itself is written last, with TrySetValue when it has one
+func Intercept(f Field, mw ...SetterMiddleware) *InterceptedField {
+	setter := Setter(func(f Field, v FieldValue) error {
+		if ts, ok := f.(TrySetter); ok {
+			return ts.TrySetValue(v)
+		}
+		f.SetValue(v)
+		return nil
+	})
+	for i := len(mw) - 1; i >= 0; i-- {
+		setter = mw[i](setter)
+	}
+	return &InterceptedField{Field: f, setter: setter}
+}
+
+// SetValue drops the error of the chain, use TrySetValue to see it
+func (s *InterceptedField) SetValue(in2 FieldValue) {
+	_ = s.TrySetValue(in2)
+}
+
+func (s *InterceptedField) TrySetValue(in2 FieldValue) error {
+	return s.setter(s.Field, in2)
+}
+
+// ObserveSets calls fn after every write with its outcome, ex: to log writes or count rejections
+func ObserveSets(fn func(f Field, v FieldValue, err error)) SetterMiddleware {
+	return func(next Setter) Setter {
+		return func(f Field, v FieldValue) error {
+			err := next(f, v)
+			fn(f, v, err)
+			return err
+		}
+	}
+}
+
+// CheckConditions rejects a write that does not meet the conditional with a FieldError holding a *ConditionError
+func CheckConditions(c Conditional) SetterMiddleware {
+	return func(next Setter) Setter {
+		return func(f Field, v FieldValue) error {
+			if err := Evaluate(c, v).Err(); err != nil {
+				return NewFieldError(f.Key(), err)
+			}
+			return next(f, v)
+		}
+	}
+}
+
+// DryRun stops every write before it reaches the field, the middleware before it still run, so it goes last in the chain
+func DryRun() SetterMiddleware {
+	return func(next Setter) Setter {
+		return func(f Field, v FieldValue) error {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIntercept(t *testing.T) {
+	var order []string
+	tag := func(name string) SetterMiddleware {
+		return func(next Setter) Setter {
+			return func(f Field, v FieldValue) error {
+				order = append(order, name)
+				return next(f, v)
+			}
+		}
+	}
+	var observed []error
+	f := Intercept(testField("Name", "old"), tag("first"), ObserveSets(func(f Field, v FieldValue, err error) {
+		observed = append(observed, err)
+	}), CheckConditions(testNotBad()), tag("last"))
+	if err := f.TrySetValue(testField("Name", "new")); err != nil || f.ToString() != "new" {
+		t.Errorf("got %s %v", f.ToString(), err)
+	}
+	if !reflect.DeepEqual(order, []string{"first", "last"}) {
+		t.Errorf("the first middleware sees the write first: got %v", order)
+	}
+	err := f.TrySetValue(testField("Name", "bad"))
+	var ferr FieldError
+	if !errors.As(err, &ferr) || !errors.Is(err, ErrConditionNotMet) || f.ToString() != "new" {
+		t.Errorf("got %s %v", f.ToString(), err)
+	}
+	if len(observed) != 2 || observed[0] != nil || observed[1] == nil {
+		t.Errorf("every write is observed with its outcome: got %v", observed)
+	}
+	// SetValue drops the error
+	f.SetValue(testField("Name", "bad"))
+	if f.ToString() != "new" || len(observed) != 3 {
+		t.Errorf("got %s", f.ToString())
+	}
+}
+
+func TestInterceptTrySetter(t *testing.T) {
+	// the field is written with TrySetValue when it has one, so its own rejection is returned
+	f := Intercept(NewConditionalField(testField("Name", "old"), testNotBad()))
+	if err := f.TrySetValue(testField("Name", "bad")); !errors.Is(err, ErrConditionNotMet) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	checked := 0
+	f := Intercept(testField("Name", "old"), ObserveSets(func(Field, FieldValue, error) { checked++ }), DryRun())
+	if err := f.TrySetValue(testField("Name", "new")); err != nil || f.ToString() != "old" || checked != 1 {
+		t.Errorf("got %s %v", f.ToString(), err)
+	}
+}
@@ -0,0 +1,179 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidMoney    = errors.New("invalid money")
+	ErrUnknownCurrency = errors.New("unknown currency")
+)
+
+// Money is an amount in a single ISO-4217 currency
+type Money struct {
+	Amount   decimal.Decimal `dynamodbav:"amount" json:"amount"`
+	Currency string          `dynamodbav:"currency" json:"currency"`
+}
+
+// ex: "12.34 USD"
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// RateProvider converts between currencies so MoneyFields in different currencies can be compared
+// the returned rate is the number of "to" units per one "from" unit
+type RateProvider interface {
+	Rate(from, to string) (decimal.Decimal, error)
+}
+
+// MoneyField refuses to order or equate amounts in different currencies unless it has a RateProvider,
+// in which case the other amount is converted into this field's currency before comparing
+type MoneyField struct {
+	ValueField Money        `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey     `dynamodbav:"key" json:"key"`
+	Rates      RateProvider `dynamodbav:"-" json:"-"`
+}
+
+func NewMoneyField(key FieldKey, amount decimal.Decimal, currency string, rates RateProvider) *MoneyField {
+	return &MoneyField{
+		ValueField: Money{Amount: amount, Currency: strings.ToUpper(currency)},
+		KeyField:   key,
+		Rates:      rates,
+	}
+}
+
+func (s *MoneyField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *MoneyField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *MoneyField) Type() reflect.Type {
+	return reflect.TypeOf(Money{})
+}
+
+// returns the other amount in this field's currency, false if that is not possible
+func (s *MoneyField) convert(in2 *MoneyField) (decimal.Decimal, bool) {
+	other := in2.ValueField
+	if other.Currency == s.ValueField.Currency {
+		return other.Amount, true
+	}
+	if s.Rates == nil {
+		return decimal.Decimal{}, false
+	}
+	rate, err := s.Rates.Rate(other.Currency, s.ValueField.Currency)
+	if err != nil {
+		return decimal.Decimal{}, false
+	}
+	return other.Amount.Mul(rate), true
+}
+
+func (s *MoneyField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	other, ok := s.convert(in2.(*MoneyField))
+	return ok && s.ValueField.Amount.LessThan(other)
+}
+
+func (s *MoneyField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	other, ok := s.convert(in2.(*MoneyField))
+	return ok && s.ValueField.Amount.GreaterThan(other)
+}
+
+func (s *MoneyField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	other, ok := s.convert(in2.(*MoneyField))
+	return ok && s.ValueField.Amount.Equal(other)
+}
+
+func (s *MoneyField) ToString() string {
+	if s.IsEmpty() {
+		return ""
+	}
+	return s.ValueField.String()
+}
+
+func (s *MoneyField) FromString(st string) {
+	_ = s.ParseString(st)
+}
+
+// expects "<amount> <currency>", ex: "12.34 USD". unknown currencies and bad amounts are rejected
+func (s *MoneyField) ParseString(st string) error {
+	parts := strings.Fields(st)
+	if len(parts) != 2 {
+		return fmt.Errorf("%w: %s: expected an amount and a currency", ErrInvalidMoney, st)
+	}
+	currency := strings.ToUpper(parts[1])
+	if !IsCurrencyCode(currency) {
+		return fmt.Errorf("%w: %s", ErrUnknownCurrency, parts[1])
+	}
+	d, err := decimal.NewFromString(parts[0])
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidMoney, st, err)
+	}
+	s.ValueField = Money{Amount: d, Currency: currency}
+	return nil
+}
+
+// SetValue drops the parse error, use TrySetValue to see it
+func (s *MoneyField) SetValue(in2 FieldValue) {
+	_ = s.TrySetValue(in2)
+}
+
+// TrySetValue copies the value of a MoneyField as is, so an empty one clears the field
+// any other field is parsed from its string form, where "" clears the field and anything invalid is returned as an error
+func (s *MoneyField) TrySetValue(in2 FieldValue) error {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		st := in2.(Field).ToString()
+		if st == "" {
+			resetField(s)
+			return nil
+		}
+		return s.ParseString(st)
+	}
+	s.ValueField = in2.(*MoneyField).ValueField
+	return nil
+}
+
+func (s *MoneyField) IsEmpty() bool {
+	return s.ValueField.Currency == "" && s.ValueField.Amount.IsZero()
+}
+
+func IsCurrencyCode(code string) bool {
+	_, ok := currencyCodes[code]
+	return ok
+}
+
+// active ISO-4217 currency codes
+var currencyCodes = map[string]struct{}{
+	"AED": {}, "AFN": {}, "ALL": {}, "AMD": {}, "ANG": {}, "AOA": {}, "ARS": {}, "AUD": {}, "AWG": {}, "AZN": {},
+	"BAM": {}, "BBD": {}, "BDT": {}, "BGN": {}, "BHD": {}, "BIF": {}, "BMD": {}, "BND": {}, "BOB": {}, "BRL": {},
+	"BSD": {}, "BTN": {}, "BWP": {}, "BYN": {}, "BZD": {}, "CAD": {}, "CDF": {}, "CHF": {}, "CLP": {}, "CNY": {},
+	"COP": {}, "CRC": {}, "CUP": {}, "CVE": {}, "CZK": {}, "DJF": {}, "DKK": {}, "DOP": {}, "DZD": {}, "EGP": {},
+	"ERN": {}, "ETB": {}, "EUR": {}, "FJD": {}, "FKP": {}, "GBP": {}, "GEL": {}, "GHS": {}, "GIP": {}, "GMD": {},
+	"GNF": {}, "GTQ": {}, "GYD": {}, "HKD": {}, "HNL": {}, "HTG": {}, "HUF": {}, "IDR": {}, "ILS": {}, "INR": {},
+	"IQD": {}, "IRR": {}, "ISK": {}, "JMD": {}, "JOD": {}, "JPY": {}, "KES": {}, "KGS": {}, "KHR": {}, "KMF": {},
+	"KPW": {}, "KRW": {}, "KWD": {}, "KYD": {}, "KZT": {}, "LAK": {}, "LBP": {}, "LKR": {}, "LRD": {}, "LSL": {},
+	"LYD": {}, "MAD": {}, "MDL": {}, "MGA": {}, "MKD": {}, "MMK": {}, "MNT": {}, "MOP": {}, "MRU": {}, "MUR": {},
+	"MVR": {}, "MWK": {}, "MXN": {}, "MYR": {}, "MZN": {}, "NAD": {}, "NGN": {}, "NIO": {}, "NOK": {}, "NPR": {},
+	"NZD": {}, "OMR": {}, "PAB": {}, "PEN": {}, "PGK": {}, "PHP": {}, "PKR": {}, "PLN": {}, "PYG": {}, "QAR": {},
+	"RON": {}, "RSD": {}, "RUB": {}, "RWF": {}, "SAR": {}, "SBD": {}, "SCR": {}, "SDG": {}, "SEK": {}, "SGD": {},
+	"SHP": {}, "SLE": {}, "SOS": {}, "SRD": {}, "SSP": {}, "STN": {}, "SVC": {}, "SYP": {}, "SZL": {}, "THB": {},
+	"TJS": {}, "TMT": {}, "TND": {}, "TOP": {}, "TRY": {}, "TTD": {}, "TWD": {}, "TZS": {}, "UAH": {}, "UGX": {},
+	"USD": {}, "UYU": {}, "UZS": {}, "VES": {}, "VND": {}, "VUV": {}, "WST": {}, "XAF": {}, "XCD": {}, "XCG": {},
+	"XOF": {}, "XPF": {}, "YER": {}, "ZAR": {}, "ZMW": {}, "ZWG": {},
+}
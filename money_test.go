@@ -0,0 +1,93 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+type testRates map[string]decimal.Decimal
+
+func (r testRates) Rate(from, to string) (decimal.Decimal, error) {
+	if rate, ok := r[from+to]; ok {
+		return rate, nil
+	}
+	return decimal.Decimal{}, errors.New("no rate")
+}
+
+func testMoney(amount, currency string, rates RateProvider) *MoneyField {
+	return NewMoneyField(NewDefaultFieldKey("Price"), decimal.RequireFromString(amount), currency, rates)
+}
+
+func TestMoneyFieldSameCurrency(t *testing.T) {
+	a, b := testMoney("10", "usd", nil), testMoney("12.50", "USD", nil)
+	if a.ValueField.Currency != "USD" {
+		t.Errorf("currencies are upper cased: got %s", a.ValueField.Currency)
+	}
+	if !a.LessThan(b) || !b.GreaterThan(a) || a.Equal(b) || !a.Equal(testMoney("10.00", "USD", nil)) {
+		t.Error("amounts in the same currency compare by amount")
+	}
+}
+
+func TestMoneyFieldCurrencies(t *testing.T) {
+	usd := testMoney("10", "USD", nil)
+	eur := testMoney("10", "EUR", nil)
+	if usd.LessThan(eur) || usd.GreaterThan(eur) || usd.Equal(eur) {
+		t.Error("amounts in different currencies do not compare without rates")
+	}
+	rates := testRates{"EURUSD": decimal.RequireFromString("1.1")}
+	withRates := testMoney("11", "USD", rates)
+	if !withRates.Equal(eur) || !testMoney("10", "USD", rates).LessThan(eur) {
+		t.Error("the other amount is converted before comparing")
+	}
+	if testMoney("10", "USD", rates).Equal(testMoney("10", "GBP", nil)) {
+		t.Error("a currency without a rate does not compare")
+	}
+}
+
+func TestMoneyFieldString(t *testing.T) {
+	f := &MoneyField{}
+	if f.ToString() != "" || !f.IsEmpty() {
+		t.Error("an empty money field has no string form")
+	}
+	f.FromString("12.34 eur")
+	if f.ToString() != "12.34 EUR" {
+		t.Fatalf("FromString: got %q", f.ToString())
+	}
+	for _, bad := range []string{"12.34", "12.34 XXX", "abc EUR", "1 2 EUR"} {
+		f.FromString(bad)
+		if f.ToString() != "12.34 EUR" {
+			t.Errorf("FromString(%q) changed the value to %q", bad, f.ToString())
+		}
+	}
+	g := &MoneyField{}
+	g.SetValue(f)
+	if !g.Equal(f) {
+		t.Error("SetValue copies the amount and currency")
+	}
+	if !IsCurrencyCode("JPY") || IsCurrencyCode("jpy") {
+		t.Error("currency codes are upper case ISO-4217")
+	}
+}
+
+func TestMoneyFieldSetValue(t *testing.T) {
+	key := NewDefaultFieldKey("Price")
+	f := NewMoneyField(key, decimal.NewFromInt(12), "usd", nil)
+	f.SetValue(&MoneyField{KeyField: key})
+	if !f.IsEmpty() {
+		t.Errorf("an empty money clears the field, got %q", f.ToString())
+	}
+	if err := f.TrySetValue(testField("Price", "5 XXY")); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("an unknown currency is returned, got %v", err)
+	}
+	if err := f.TrySetValue(testField("Price", "five usd")); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("a bad amount is returned, got %v", err)
+	}
+	if err := f.TrySetValue(testField("Price", "5 eur")); err != nil || f.ToString() != "5 EUR" {
+		t.Errorf("got %q, %v", f.ToString(), err)
+	}
+	if err := f.TrySetValue(testField("Price", "")); err != nil || !f.IsEmpty() {
+		t.Errorf("an empty string clears the field, got %q, %v", f.ToString(), err)
+	}
+}
@@ -0,0 +1,387 @@
+package fielder
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// fieldMsgpackLen is the number of items in the msgpack form of a field, [type code, key, tag, value]
+// it is the compact counterpart of fieldJSON, the type is a one byte code and the value is native where msgpack has a type for it
+// numbers, bools, bytes and times are written as themselves, everything else as its string form, and an unset field as nil
+const fieldMsgpackLen = 4
+
+// the type codes used to tag encoded fields, the code is the index in this list plus one
+// codes are stored in caches, so new types are only ever appended
+var fieldTypeCodes = []string{
+	"string",
+	"time",
+	"decimal",
+	"integer",
+	"bool",
+	"float",
+	"duration",
+	"uuid",
+	"bytes",
+	"date",
+	"money",
+	"quantity",
+	"url",
+	"email",
+	"ip",
+	"cidr",
+	"geo",
+	"enum",
+	"counter",
+	"slice",
+	"map",
+	"struct",
+}
+
+func fieldTypeCode(f Field) uint8 {
+	name := FieldTypeName(f)
+	for i, v := range fieldTypeCodes {
+		if v == name {
+			return uint8(i + 1)
+		}
+	}
+	return 0
+}
+
+func fieldTypeNameFromCode(code uint8) (string, error) {
+	if code == 0 || int(code) > len(fieldTypeCodes) {
+		return "", fmt.Errorf("%w: code %d", ErrUnknownFieldType, code)
+	}
+	return fieldTypeCodes[code-1], nil
+}
+
+func fieldMsgpackValue(f Field) any {
+	if f.Value() == nil {
+		return nil
+	}
+	switch v := f.(type) {
+	case *BoolField:
+		if !v.Set {
+			return nil
+		}
+		return v.ValueField
+	case *GeoPointField:
+		if !v.Set {
+			return nil
+		}
+	case *IntegerField:
+		return int64(v.ValueField)
+	case *CounterField:
+		return v.Load()
+	case *FloatField:
+		return v.ValueField
+	case *BytesField:
+		return v.ValueField
+	case *TimeField:
+		return v.ValueField
+	}
+	return f.ToString()
+}
+
+func encodeFieldMsgpack(enc *msgpack.Encoder, f Field) error {
+	if IsSensitive(f) {
+		return sensitiveFieldError(f)
+	}
+	tag := f.Key().Tag
+	if tag == FieldKeyTag {
+		tag = ""
+	}
+	if err := enc.EncodeArrayLen(fieldMsgpackLen); err != nil {
+		return err
+	}
+	if err := enc.EncodeUint8(fieldTypeCode(f)); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(f.Key().Name.String()); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(tag); err != nil {
+		return err
+	}
+	return enc.Encode(fieldMsgpackValue(f))
+}
+
+// reads the header of an encoded field, leaving the decoder at the value
+func decodeFieldMsgpackHeader(dec *msgpack.Decoder) (uint8, FieldKey, error) {
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return 0, FieldKey{}, err
+	}
+	if n != fieldMsgpackLen {
+		return 0, FieldKey{}, fmt.Errorf("msgpack field has %d items, expected %d", n, fieldMsgpackLen)
+	}
+	code, err := dec.DecodeUint8()
+	if err != nil {
+		return 0, FieldKey{}, err
+	}
+	name, err := dec.DecodeString()
+	if err != nil {
+		return 0, FieldKey{}, err
+	}
+	tag, err := dec.DecodeString()
+	if err != nil {
+		return 0, FieldKey{}, err
+	}
+	return code, NewFieldKey(name, tag), nil
+}
+
+// sets f from the encoded value, nil resets f to its zero value
+func decodeFieldMsgpackValue(dec *msgpack.Decoder, f Field) error {
+	// DecodeInterfaceLoose would widen the numbers, but it also turns bytes into strings
+	v, err := dec.DecodeInterface()
+	if err != nil {
+		return err
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return scanField(f, rv.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ParseFieldString(f, strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32:
+		return scanField(f, rv.Float())
+	}
+	return scanField(f, v)
+}
+
+// sets the key and value of f from its msgpack form, f keeps its own type and options
+func decodeFieldMsgpack(dec *msgpack.Decoder, f Field) error {
+	_, key, err := decodeFieldMsgpackHeader(dec)
+	if err != nil {
+		return err
+	}
+	if keyField := reflect.ValueOf(f).Elem().FieldByName("KeyField"); keyField.IsValid() && keyField.CanSet() {
+		keyField.Set(reflect.ValueOf(key))
+	}
+	return decodeFieldMsgpackValue(dec, f)
+}
+
+func unmarshalFieldMsgpack(b []byte, f Field) error {
+	return decodeFieldMsgpack(msgpack.NewDecoder(bytes.NewReader(b)), f)
+}
+
+// MarshalFieldMsgpack encodes any field, including ones held behind an interface
+func MarshalFieldMsgpack(f Field) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeFieldMsgpack(msgpack.NewEncoder(&buf), f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalFieldMsgpack decodes a field of the expected value type, ex: reflect.TypeOf(decimal.Decimal{})
+// if expected is nil the type is taken from the encoded type code
+func UnmarshalFieldMsgpack(b []byte, expected reflect.Type) (Field, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(b))
+	code, key, err := decodeFieldMsgpackHeader(dec)
+	if err != nil {
+		return nil, err
+	}
+	var f Field
+	if expected != nil {
+		f = CreateFieldFromType(expected, nil, key)
+		if f == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, expected)
+		}
+	} else {
+		name, err := fieldTypeNameFromCode(code)
+		if err != nil {
+			return nil, err
+		}
+		if f, err = NewFieldFromTypeName(name, key); err != nil {
+			return nil, err
+		}
+	}
+	return f, decodeFieldMsgpackValue(dec, f)
+}
+
+func (s *StringField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *StringField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *TimeField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *TimeField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *DecimalField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *DecimalField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *IntegerField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *IntegerField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *BoolField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *BoolField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *FloatField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *FloatField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *DurationField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *DurationField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *UUIDField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *UUIDField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *BytesField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *BytesField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *DateField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *DateField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *MoneyField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *MoneyField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *QuantityField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *QuantityField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *URLField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *URLField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *EmailField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *EmailField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *IPField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *IPField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *CIDRField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *CIDRField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *GeoPointField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *GeoPointField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *EnumField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *EnumField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *CounterField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *CounterField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *SliceField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *SliceField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *MapField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *MapField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *StructField) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *StructField) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
+
+func (s *TypedField[T]) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return encodeFieldMsgpack(enc, s)
+}
+
+func (s *TypedField[T]) DecodeMsgpack(dec *msgpack.Decoder) error {
+	return decodeFieldMsgpack(dec, s)
+}
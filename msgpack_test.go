@@ -0,0 +1,83 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	key := NewDefaultFieldKey("Value")
+	for _, f := range []Field{
+		&StringField{ValueField: "hi", KeyField: key},
+		&IntegerField{ValueField: -300, KeyField: key},
+		&IntegerField{ValueField: 200, KeyField: key},
+		NewBool(key, true),
+		&FloatField{ValueField: 2.5, KeyField: key},
+		NewTimeField(key, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+		&DurationField{ValueField: time.Minute, KeyField: key},
+		&BytesField{ValueField: []byte{0, 1, 2}, KeyField: key},
+		NewDecimalField(key, testDecimal(t, "12.345").ValueField),
+		NewCounterField(key, 9),
+	} {
+		b, err := MarshalFieldMsgpack(f)
+		if err != nil {
+			t.Fatalf("%T: %v", f, err)
+		}
+		got, err := UnmarshalFieldMsgpack(b, nil)
+		if err != nil {
+			t.Fatalf("%T: %v", f, err)
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(f) || !got.Equal(f) || got.Key() != key {
+			t.Errorf("%T: got %T %q", f, got, got.ToString())
+		}
+	}
+}
+
+func TestMsgpackMembers(t *testing.T) {
+	type item struct {
+		Name   *StringField `msgpack:"name"`
+		Active *BoolField   `msgpack:"active"`
+	}
+	in := item{Name: &StringField{ValueField: "x", KeyField: NewFieldKey("name", "json")}, Active: &BoolField{}}
+	b, err := msgpack.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := item{Name: &StringField{}, Active: NewBool(FieldKeyNil, true).(*BoolField)}
+	if err := msgpack.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name.ValueField != "x" || out.Name.Key() != in.Name.KeyField || out.Active.Set {
+		t.Errorf("got %#v %#v", out.Name, out.Active)
+	}
+}
+
+func TestUnmarshalFieldMsgpackErrors(t *testing.T) {
+	b, _ := msgpack.Marshal([]any{uint8(200), "X", "", "v"})
+	if _, err := UnmarshalFieldMsgpack(b, nil); !errors.Is(err, ErrUnknownFieldType) {
+		t.Errorf("unknown codes: got %v", err)
+	}
+	b, _ = msgpack.Marshal([]any{uint8(1), "X"})
+	if _, err := UnmarshalFieldMsgpack(b, nil); err == nil {
+		t.Error("short arrays are an error")
+	}
+	b, _ = MarshalFieldMsgpack(&StringField{ValueField: "7"})
+	if f, err := UnmarshalFieldMsgpack(b, reflect.TypeOf(0)); err != nil || f.Value() != 7 {
+		t.Errorf("the expected type wins: got %v %v", f, err)
+	}
+}
+
+func TestMsgpackSensitive(t *testing.T) {
+	secret := NewRedactedField(testField("Token", "abc"))
+	if _, err := MarshalFieldMsgpack(secret); !errors.Is(err, ErrSensitiveField) {
+		t.Errorf("got %v", err)
+	}
+	// gob encodes the fields inside the wrappers as msgpack
+	if _, err := NewNullableField(secret).(*FieldNullable).GobEncode(); !errors.Is(err, ErrSensitiveField) {
+		t.Errorf("got %v", err)
+	}
+}
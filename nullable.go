@@ -0,0 +1,124 @@
+package fielder
+
+// NullableField separates "never set" from "set to the zero value"
+// the inner field types treat their zero value as empty (an IntegerField holding 0 is empty), so wrapping them
+// lets a parent tell the difference between a 0 that was written and a value that was never provided
+type NullableField interface {
+	Field
+	Nullable
+}
+
+type Nullable interface {
+	IsNull() bool
+	// marks the field as set without changing the current inner value, so the zero value can be set explicitly
+	Set()
+	// marks the field as unset and resets the inner value to its zero value
+	Clear()
+}
+
+type FieldNullable struct {
+	Field
+	Null bool `dynamodbav:"null" json:"null"`
+}
+
+// wraps a field that already holds a value
+func NewNullableField(f Field) NullableField {
+	return &FieldNullable{
+		Field: f,
+		Null:  false,
+	}
+}
+
+// wraps a field that has not been set yet
+func NewNullField(f Field) NullableField {
+	return &FieldNullable{
+		Field: f,
+		Null:  true,
+	}
+}
+
+func (s *FieldNullable) IsNull() bool {
+	return s.Null
+}
+
+func (s *FieldNullable) Set() {
+	s.Null = false
+}
+
+// the inner field keeps its options, ex: the layouts of a TimeField
+func (s *FieldNullable) Clear() {
+	if s.Field != nil {
+		resetField(s.Field)
+	}
+	s.Null = true
+}
+
+func (s *FieldNullable) Value() FieldValue {
+	if s.Null {
+		return nil
+	}
+	return s.Field.Value()
+}
+
+// a null field is less than every set field, and equal only to another null field
+func (s *FieldNullable) LessThan(in2 any) bool {
+	other, otherNull := unwrapNullable(in2)
+	if s.Null || otherNull {
+		return s.Null && !otherNull
+	}
+	return s.Field.LessThan(other)
+}
+
+func (s *FieldNullable) GreaterThan(in2 any) bool {
+	other, otherNull := unwrapNullable(in2)
+	if s.Null || otherNull {
+		return !s.Null && otherNull
+	}
+	return s.Field.GreaterThan(other)
+}
+
+func (s *FieldNullable) Equal(in2 any) bool {
+	other, otherNull := unwrapNullable(in2)
+	if s.Null || otherNull {
+		return s.Null && otherNull
+	}
+	return s.Field.Equal(other)
+}
+
+func (s *FieldNullable) ToString() string {
+	if s.Null {
+		return ""
+	}
+	return s.Field.ToString()
+}
+
+func (s *FieldNullable) FromString(st string) {
+	s.Field.FromString(st)
+	s.Null = false
+}
+
+func (s *FieldNullable) SetValue(in2 FieldValue) {
+	other, otherNull := unwrapNullable(in2)
+	if otherNull {
+		s.Clear()
+		return
+	}
+	s.Field.SetValue(other)
+	s.Null = false
+}
+
+func (s *FieldNullable) IsEmpty() bool {
+	return s.Null
+}
+
+// returns the inner field of a nullable field so it can be compared with a plain field
+// a nil input is treated as null
+func unwrapNullable(in any) (any, bool) {
+	if in == nil {
+		return nil, true
+	}
+	if n, ok := in.(*FieldNullable); ok {
+		return n.Field, n.Null
+	}
+	return in, false
+}
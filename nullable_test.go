@@ -0,0 +1,126 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestNullableFieldSeparatesUnsetFromZero(t *testing.T) {
+	key := NewDefaultFieldKey("Count")
+	unset := NewNullField(&IntegerField{KeyField: key})
+	zero := NewNullableField(&IntegerField{KeyField: key})
+	if !unset.IsNull() || unset.Value() != nil || !unset.IsEmpty() {
+		t.Error("a null field has no value")
+	}
+	if zero.IsNull() || zero.Value() != 0 || zero.IsEmpty() {
+		t.Error("a zero that was written is not null")
+	}
+	unset.Set()
+	if unset.IsNull() || unset.Value() != 0 {
+		t.Error("Set marks the current value as written")
+	}
+}
+
+func TestNullableFieldCompare(t *testing.T) {
+	key := NewDefaultFieldKey("Count")
+	null := NewNullField(&IntegerField{KeyField: key})
+	one := NewNullableField(&IntegerField{KeyField: key, ValueField: 1})
+	if !null.LessThan(one) || one.LessThan(null) || !one.GreaterThan(null) {
+		t.Error("null is less than every set field")
+	}
+	if !null.Equal(NewNullField(&IntegerField{})) || null.Equal(one) || !null.Equal(nil) {
+		t.Error("null is equal only to null")
+	}
+	if !one.Equal(&IntegerField{ValueField: 1}) || !one.LessThan(&IntegerField{ValueField: 2}) {
+		t.Error("a set field compares with plain fields by its inner field")
+	}
+}
+
+func TestNullableFieldSetValue(t *testing.T) {
+	f := NewNullField(&IntegerField{})
+	f.SetValue(&IntegerField{ValueField: 4})
+	if f.IsNull() || f.Value() != 4 {
+		t.Fatalf("SetValue: got %v null %v", f.Value(), f.IsNull())
+	}
+	f.SetValue(NewNullField(&IntegerField{}))
+	if !f.IsNull() {
+		t.Error("setting a null field clears it")
+	}
+	f.FromString("7")
+	if f.IsNull() || f.ToString() != "7" {
+		t.Errorf("FromString: got %q null %v", f.ToString(), f.IsNull())
+	}
+}
+
+func TestNullableFieldClearResetsInner(t *testing.T) {
+	enum := NewEnumField(NewDefaultFieldKey("Status"), "draft", "done")
+	enum.FromString("done")
+	f := NewNullableField(enum)
+	f.Clear()
+	if !f.IsNull() || enum.ValueField != "" {
+		t.Errorf("Clear left the enum at %q", enum.ValueField)
+	}
+	f.Set()
+	if f.Value() != EnumValue("") {
+		t.Errorf("a cleared then set enum holds %v", f.Value())
+	}
+	tf := NewTimeField(NewDefaultFieldKey("At"), time.Now(), WithLayouts(time.DateOnly))
+	n := NewNullableField(tf)
+	n.Clear()
+	if !tf.ValueField.IsZero() || len(tf.Layouts) != 1 {
+		t.Error("Clear resets the inner value and keeps its options")
+	}
+}
+
+// the validating fields refuse FromString(""), every NULL must still leave them empty
+func TestNullClearsValidatingFields(t *testing.T) {
+	fields := map[string]func() Field{
+		"url":      func() Field { return &URLField{ValueField: "https://x.com"} },
+		"email":    func() Field { return &EmailField{ValueField: "a@b.com"} },
+		"money":    func() Field { return NewMoneyField(NewDefaultFieldKey("Price"), decimal.NewFromInt(12), "usd", nil) },
+		"quantity": func() Field { return NewQuantityField(NewDefaultFieldKey("Weight"), decimal.NewFromInt(5), "kg", nil) },
+		"cidr": func() Field {
+			f := &CIDRField{}
+			f.FromString("10.0.0.0/8")
+			return f
+		},
+		"ip": func() Field {
+			f := &IPField{}
+			f.FromString("1.2.3.4")
+			return f
+		},
+	}
+	nulls := map[string]func(Field) error{
+		"sql":        func(f Field) error { return scanField(f, nil) },
+		"dynamo":     func(f Field) error { return attributeValueToField(&types.AttributeValueMemberNULL{Value: true}, f) },
+		"proto":      func(f Field) error { return FromProtoMessage(nil, f) },
+		"protovalue": func(f Field) error { return FromProtoValue(structpb.NewNullValue(), f) },
+		"clear": func(f Field) error {
+			n := NewNullableField(f)
+			n.Clear()
+			if !n.IsNull() {
+				return errors.New("not null after Clear")
+			}
+			return nil
+		},
+	}
+	for name, field := range fields {
+		for via, null := range nulls {
+			f := field()
+			if f.IsEmpty() {
+				t.Fatalf("%s: the test value did not parse", name)
+			}
+			if err := null(f); err != nil {
+				t.Errorf("%s via %s: %v", name, via, err)
+			}
+			if !f.IsEmpty() {
+				t.Errorf("%s via %s kept %q", name, via, f.ToString())
+			}
+		}
+	}
+}
@@ -0,0 +1,484 @@
+package fielder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// parents are written to parquet with one optional column per tagged member, and one row group per DefaultParquetRowGroupSize rows
+// the column types follow the avro mapping: numbers and bools are native, times are INT64 TIMESTAMP_MILLIS,
+// dates are INT32 DATE, decimals are DECIMAL(AvroDecimalPrecision, AvroDecimalScale) and every other field type is its UTF8 string form
+// pages are PLAIN encoded and uncompressed, which every parquet reader supports
+
+const DefaultParquetRowGroupSize = 10000
+
+var ErrParquetClosed = errors.New("parquet writer is closed")
+
+var parquetMagic = []byte("PAR1")
+
+// parquet physical types
+const (
+	parquetBoolean           int32 = 0
+	parquetInt32             int32 = 1
+	parquetInt64             int32 = 2
+	parquetDouble            int32 = 5
+	parquetByteArray         int32 = 6
+	parquetFixedLenByteArray int32 = 7
+)
+
+// parquet converted types, -1 is none
+const (
+	parquetNoConversion    int32 = -1
+	parquetUTF8            int32 = 0
+	parquetDecimal         int32 = 5
+	parquetDate            int32 = 6
+	parquetTimestampMillis int32 = 9
+)
+
+// decimals with precision 38 fit in 16 bytes
+const parquetDecimalLength = 16
+
+type parquetColumn struct {
+	key       FieldKey
+	name      string
+	physical  int32
+	converted int32
+	values    bytes.Buffer
+	// one definition level per row, 0 is null and 1 is set
+	levels []byte
+	// booleans are bit packed, so they are buffered until the page is written
+	bools []bool
+}
+
+// the column mapping for each field type name, names that are not here are UTF8 strings
+var parquetColumnTypes = map[string][2]int32{
+	"integer": {parquetInt64, parquetNoConversion},
+	"counter": {parquetInt64, parquetNoConversion},
+	"float":   {parquetDouble, parquetNoConversion},
+	"bool":    {parquetBoolean, parquetNoConversion},
+	"bytes":   {parquetByteArray, parquetNoConversion},
+	"time":    {parquetInt64, parquetTimestampMillis},
+	"date":    {parquetInt32, parquetDate},
+	"decimal": {parquetFixedLenByteArray, parquetDecimal},
+}
+
+// ParquetWriter streams parents into a parquet file, rows are buffered and written a row group at a time
+type ParquetWriter[P any] struct {
+	w            io.Writer
+	offset       int64
+	columns      []*parquetColumn
+	rows         int
+	totalRows    int64
+	rowGroups    []parquetRowGroup
+	RowGroupSize int
+	closed       bool
+}
+
+// the rows and column chunks of a written row group, kept for the footer
+type parquetRowGroup struct {
+	rows   int64
+	chunks []parquetChunk
+}
+
+// the location of a written column chunk, kept for the footer
+type parquetChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+func NewParquetWriter[P any](w io.Writer) (*ParquetWriter[P], error) {
+	ty := reflect.TypeOf(*new(P))
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	pw := &ParquetWriter[P]{w: w, RowGroupSize: DefaultParquetRowGroupSize}
+	for _, key := range FullKeySet[P](FieldKeyTag) {
+		if key.Name == "" {
+			continue
+		}
+		mapping := [2]int32{parquetByteArray, parquetUTF8}
+		// members that are fields can hold any type, so they are written as strings
+		if ty := GetFieldTypeFromKey[P](key); ty != nil && !ty.Implements(fieldInterfaceType) {
+			if ty.Kind() == reflect.Pointer {
+				ty = ty.Elem()
+			}
+			if m, ok := parquetColumnTypes[fieldTypeNames[ty]]; ok {
+				mapping = m
+			}
+		}
+		pw.columns = append(pw.columns, &parquetColumn{key: key, name: key.Name.String(), physical: mapping[0], converted: mapping[1]})
+	}
+	if err := pw.write(parquetMagic); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (pw *ParquetWriter[P]) write(b []byte) error {
+	n, err := pw.w.Write(b)
+	pw.offset += int64(n)
+	return err
+}
+
+// Write buffers one row, a full row group is written out before Write returns
+func (pw *ParquetWriter[P]) Write(p P) error {
+	if pw.closed {
+		return ErrParquetClosed
+	}
+	fields := ToFieldMap(p)
+	// checked before any column is written to, so a refused row leaves no partial values behind
+	for _, c := range pw.columns {
+		if f, ok := fields[c.key]; ok && !isNilField(f) && IsSensitive(f) {
+			return sensitiveFieldError(f)
+		}
+	}
+	for _, c := range pw.columns {
+		f, ok := fields[c.key]
+		if !ok || isNilField(f) || f.Value() == nil {
+			c.levels = append(c.levels, 0)
+			continue
+		}
+		c.levels = append(c.levels, 1)
+		c.appendValue(f)
+	}
+	pw.rows++
+	if pw.RowGroupSize > 0 && pw.rows >= pw.RowGroupSize {
+		return pw.Flush()
+	}
+	return nil
+}
+
+func (c *parquetColumn) appendValue(f Field) {
+	var scratch [8]byte
+	switch c.physical {
+	case parquetBoolean:
+		b, _ := f.Value().(bool)
+		c.bools = append(c.bools, b)
+	case parquetInt32:
+		// dates are days since the unix epoch
+		d, _ := f.Value().(Date)
+		days := d.Time().Unix() / int64(24*time.Hour/time.Second)
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(int32(days)))
+		c.values.Write(scratch[:4])
+	case parquetInt64:
+		var v int64
+		switch t := f.Value().(type) {
+		case time.Time:
+			v = t.UnixMilli()
+		case int:
+			v = int64(t)
+		case int64:
+			v = t
+		}
+		binary.LittleEndian.PutUint64(scratch[:], uint64(v))
+		c.values.Write(scratch[:])
+	case parquetDouble:
+		fl, _ := f.Value().(float64)
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(fl))
+		c.values.Write(scratch[:])
+	case parquetFixedLenByteArray:
+		d, _ := f.Value().(decimal.Decimal)
+		c.values.Write(parquetDecimalBytes(d))
+	default:
+		var b []byte
+		if c.converted == parquetUTF8 {
+			b = []byte(f.ToString())
+		} else {
+			b, _ = f.Value().([]byte)
+		}
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(len(b)))
+		c.values.Write(scratch[:4])
+		c.values.Write(b)
+	}
+}
+
+// the unscaled value as a big endian two's complement number, the layout parquet uses for decimals
+func parquetDecimalBytes(d decimal.Decimal) []byte {
+	unscaled := d.Round(AvroDecimalScale).Shift(AvroDecimalScale).BigInt()
+	out := make([]byte, parquetDecimalLength)
+	if unscaled.Sign() < 0 {
+		// two's complement of a negative number is 2^n + value
+		unscaled = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), parquetDecimalLength*8), unscaled)
+	}
+	unscaled.FillBytes(out)
+	return out
+}
+
+// Flush writes the buffered rows as a row group
+func (pw *ParquetWriter[P]) Flush() error {
+	if pw.closed {
+		return ErrParquetClosed
+	}
+	if pw.rows == 0 {
+		return nil
+	}
+	chunks := make([]parquetChunk, 0, len(pw.columns))
+	for _, c := range pw.columns {
+		chunk, err := pw.writeColumnChunk(c)
+		if err != nil {
+			return err
+		}
+		chunks = append(chunks, chunk)
+		c.values.Reset()
+		c.levels = c.levels[:0]
+		c.bools = c.bools[:0]
+	}
+	pw.rowGroups = append(pw.rowGroups, parquetRowGroup{rows: int64(pw.rows), chunks: chunks})
+	pw.totalRows += int64(pw.rows)
+	pw.rows = 0
+	return nil
+}
+
+// a column chunk is a single PLAIN data page, its definition levels are run length encoded
+func (pw *ParquetWriter[P]) writeColumnChunk(c *parquetColumn) (parquetChunk, error) {
+	levels := parquetRLE(c.levels)
+	var page bytes.Buffer
+	var scratch [4]byte
+	binary.LittleEndian.PutUint32(scratch[:], uint32(len(levels)))
+	page.Write(scratch[:])
+	page.Write(levels)
+	if c.physical == parquetBoolean {
+		page.Write(parquetBitPack(c.bools))
+	} else {
+		page.Write(c.values.Bytes())
+	}
+	var header thriftCompact
+	header.i32(1, 0) // DATA_PAGE
+	header.i32(2, int32(page.Len()))
+	header.i32(3, int32(page.Len()))
+	header.beginStruct(5)
+	header.i32(1, int32(len(c.levels)))
+	header.i32(2, 0) // PLAIN
+	header.i32(3, 3) // RLE
+	header.i32(4, 3) // RLE
+	header.endStruct()
+	header.stop()
+	chunk := parquetChunk{offset: pw.offset, size: int64(header.buf.Len() + page.Len()), values: int64(len(c.levels))}
+	if err := pw.write(header.buf.Bytes()); err != nil {
+		return chunk, err
+	}
+	return chunk, pw.write(page.Bytes())
+}
+
+// Close flushes the buffered rows and writes the footer, it does not close the underlying writer
+func (pw *ParquetWriter[P]) Close() error {
+	if pw.closed {
+		return nil
+	}
+	if err := pw.Flush(); err != nil {
+		return err
+	}
+	pw.closed = true
+	var meta thriftCompact
+	meta.i32(1, 1)
+	meta.beginList(2, thriftStruct, len(pw.columns)+1)
+	meta.beginListStruct()
+	meta.binary(4, []byte(reflect.TypeOf(*new(P)).Name()))
+	meta.i32(5, int32(len(pw.columns)))
+	meta.endStruct()
+	for _, c := range pw.columns {
+		meta.beginListStruct()
+		meta.i32(1, c.physical)
+		if c.physical == parquetFixedLenByteArray {
+			meta.i32(2, parquetDecimalLength)
+		}
+		meta.i32(3, 1) // OPTIONAL
+		meta.binary(4, []byte(c.name))
+		if c.converted != parquetNoConversion {
+			meta.i32(6, c.converted)
+		}
+		if c.converted == parquetDecimal {
+			meta.i32(7, AvroDecimalScale)
+			meta.i32(8, AvroDecimalPrecision)
+		}
+		meta.endStruct()
+	}
+	meta.i64(3, pw.totalRows)
+	meta.beginList(4, thriftStruct, len(pw.rowGroups))
+	for _, group := range pw.rowGroups {
+		meta.beginListStruct()
+		meta.beginList(1, thriftStruct, len(group.chunks))
+		var total int64
+		for i, chunk := range group.chunks {
+			c := pw.columns[i]
+			total += chunk.size
+			meta.beginListStruct()
+			meta.i64(2, chunk.offset)
+			meta.beginStruct(3)
+			meta.i32(1, c.physical)
+			meta.beginList(2, thriftI32, 2)
+			meta.listI32(0) // PLAIN
+			meta.listI32(3) // RLE
+			meta.beginList(3, thriftBinary, 1)
+			meta.listBinary([]byte(c.name))
+			meta.i32(4, 0) // UNCOMPRESSED
+			meta.i64(5, chunk.values)
+			meta.i64(6, chunk.size)
+			meta.i64(7, chunk.size)
+			meta.i64(9, chunk.offset)
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.i64(2, total)
+		// counted with the row group, a parent without tagged members has no column chunks to count them from
+		meta.i64(3, group.rows)
+		meta.endStruct()
+	}
+	meta.binary(6, []byte("go-fielder"))
+	meta.stop()
+	if err := pw.write(meta.buf.Bytes()); err != nil {
+		return err
+	}
+	var scratch [4]byte
+	binary.LittleEndian.PutUint32(scratch[:], uint32(meta.buf.Len()))
+	if err := pw.write(scratch[:]); err != nil {
+		return err
+	}
+	return pw.write(parquetMagic)
+}
+
+// WriteParquet writes all the parents to w as a single parquet file
+func WriteParquet[P any](w io.Writer, parents []P) error {
+	pw, err := NewParquetWriter[P](w)
+	if err != nil {
+		return err
+	}
+	for _, p := range parents {
+		if err := pw.Write(p); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
+
+// encodes bit width 1 levels as runs of repeated values in the RLE / bit packing hybrid
+func parquetRLE(levels []byte) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		out = append(out, levels[i])
+		i = j
+	}
+	return out
+}
+
+// PLAIN booleans are one bit each, least significant bit first
+func parquetBitPack(values []bool) []byte {
+	out := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
+
+// thrift compact protocol types
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftStruct byte = 12
+	thriftList   byte = 9
+)
+
+// thriftCompact writes the thrift compact protocol, only the parts the parquet footer and page headers need
+type thriftCompact struct {
+	buf bytes.Buffer
+	// the last field id of each open struct, field ids are written as deltas
+	last []int16
+}
+
+func (t *thriftCompact) lastID() int16 {
+	if len(t.last) == 0 {
+		return 0
+	}
+	return t.last[len(t.last)-1]
+}
+
+func (t *thriftCompact) fieldHeader(id int16, ty byte) {
+	if len(t.last) == 0 {
+		t.last = append(t.last, 0)
+	}
+	delta := id - t.lastID()
+	if delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | ty)
+	} else {
+		t.buf.WriteByte(ty)
+		t.varint(int64(id))
+	}
+	t.last[len(t.last)-1] = id
+}
+
+func (t *thriftCompact) varint(v int64) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (t *thriftCompact) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftCompact) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftCompact) binary(id int16, b []byte) {
+	t.fieldHeader(id, thriftBinary)
+	t.listBinary(b)
+}
+
+func (t *thriftCompact) beginStruct(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.last = append(t.last, 0)
+}
+
+// a struct that is an element of a list has no field header
+func (t *thriftCompact) beginListStruct() {
+	if len(t.last) == 0 {
+		t.last = append(t.last, 0)
+	}
+	t.last = append(t.last, 0)
+}
+
+func (t *thriftCompact) endStruct() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thriftCompact) stop() {
+	t.buf.WriteByte(0)
+}
+
+func (t *thriftCompact) beginList(id int16, elem byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elem)
+		return
+	}
+	t.buf.WriteByte(0xf0 | elem)
+	t.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+}
+
+func (t *thriftCompact) listI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftCompact) listBinary(b []byte) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(b))))
+	t.buf.Write(b)
+}
@@ -0,0 +1,252 @@
+package fielder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// reads the thrift compact structs the writer produces, fields are keyed by id
+// only the types the writer uses are read: i32, i64, binary, list and struct
+type testThriftReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *testThriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *testThriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *testThriftReader) value(ty byte) any {
+	switch ty {
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		r.pos += n
+		return r.b[r.pos-n : r.pos]
+	case thriftList:
+		h := r.b[r.pos]
+		r.pos++
+		size := int(h >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		out := make([]any, size)
+		for i := range out {
+			out[i] = r.value(h & 0x0f)
+		}
+		return out
+	case thriftStruct:
+		return r.structure()
+	}
+	panic("unexpected thrift type")
+}
+
+func (r *testThriftReader) structure() map[int16]any {
+	out := map[int16]any{}
+	var id int16
+	for {
+		h := r.b[r.pos]
+		r.pos++
+		if h == 0 {
+			return out
+		}
+		if delta := int16(h >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.varint())
+		}
+		out[id] = r.value(h & 0x0f)
+	}
+}
+
+// reads a file written by ParquetWriter into one map of column name to value per row, null values are left out
+func testReadParquet(t *testing.T, b []byte) (int64, []map[string]any) {
+	t.Helper()
+	if !bytes.HasPrefix(b, parquetMagic) || !bytes.HasSuffix(b, parquetMagic) {
+		t.Fatal("missing magic")
+	}
+	n := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	meta := (&testThriftReader{b: b[len(b)-8-n : len(b)-8]}).structure()
+	schema := meta[2].([]any)[1:]
+	rows := []map[string]any{}
+	for _, g := range meta[4].([]any) {
+		group := g.(map[int16]any)
+		start := len(rows)
+		for i := int64(0); i < group[3].(int64); i++ {
+			rows = append(rows, map[string]any{})
+		}
+		for i, c := range group[1].([]any) {
+			column := schema[i].(map[int16]any)
+			name, physical := string(column[4].([]byte)), int32(column[1].(int64))
+			chunk := c.(map[int16]any)[3].(map[int16]any)
+			r := &testThriftReader{b: b, pos: int(chunk[9].(int64))}
+			r.structure()
+			levelsLen := int(binary.LittleEndian.Uint32(b[r.pos:]))
+			levelsReader := &testThriftReader{b: b[r.pos+4 : r.pos+4+levelsLen]}
+			values := b[r.pos+4+levelsLen:]
+			levels := []byte{}
+			for levelsReader.pos < len(levelsReader.b) {
+				run := levelsReader.uvarint() >> 1
+				level := levelsReader.b[levelsReader.pos]
+				levelsReader.pos++
+				levels = append(levels, bytes.Repeat([]byte{level}, int(run))...)
+			}
+			set := 0
+			for row, level := range levels {
+				if level == 0 {
+					continue
+				}
+				var v any
+				switch physical {
+				case parquetBoolean:
+					v = values[set/8]&(1<<(set%8)) != 0
+				case parquetInt32:
+					v = int32(binary.LittleEndian.Uint32(values))
+					values = values[4:]
+				case parquetInt64:
+					v = int64(binary.LittleEndian.Uint64(values))
+					values = values[8:]
+				case parquetDouble:
+					v = math.Float64frombits(binary.LittleEndian.Uint64(values))
+					values = values[8:]
+				case parquetFixedLenByteArray:
+					unscaled := new(big.Int).SetBytes(values[:parquetDecimalLength])
+					if values[0]&0x80 != 0 {
+						unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), parquetDecimalLength*8))
+					}
+					v = decimal.NewFromBigInt(unscaled, -AvroDecimalScale)
+					values = values[parquetDecimalLength:]
+				default:
+					l := int(binary.LittleEndian.Uint32(values))
+					v = string(values[4 : 4+l])
+					values = values[4+l:]
+				}
+				set++
+				rows[start+row][name] = v
+			}
+		}
+	}
+	return meta[3].(int64), rows
+}
+
+type testParquetRow struct {
+	Name     string          `field:"Name"`
+	Age      int             `field:"Age"`
+	Score    float64         `field:"Score"`
+	Active   bool            `field:"Active"`
+	Joined   time.Time       `field:"Joined"`
+	Birthday Date            `field:"Birthday"`
+	Balance  decimal.Decimal `field:"Balance"`
+	Nickname *string         `field:"Nickname"`
+	Bio      *StringField    `field:"Bio"`
+	Note     Field           `field:"Note"`
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	nick := "sammy"
+	joined := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	in := []testParquetRow{
+		{Name: "sam", Age: 30, Score: 1.5, Active: true, Joined: joined, Birthday: Date{Year: 1990, Month: time.May, Day: 4},
+			Balance: decimal.RequireFromString("-12.34"), Nickname: &nick, Bio: testField("Bio", "hi"), Note: testField("Note", "there")},
+		// a Field member holding a nil pointer is null, not a panic
+		{Name: "kim", Age: 40, Note: (*StringField)(nil)},
+		{Name: "lee", Active: true, Note: &FieldNullable{}},
+	}
+	var b bytes.Buffer
+	pw, err := NewParquetWriter[testParquetRow](&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw.RowGroupSize = 2
+	for _, row := range in {
+		if err := pw.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	total, rows := testReadParquet(t, b.Bytes())
+	if total != 3 || len(rows) != 3 {
+		t.Fatalf("got %d rows: %v", total, rows)
+	}
+	first := rows[0]
+	if first["Name"] != "sam" || first["Age"] != int64(30) || first["Score"] != 1.5 || first["Active"] != true {
+		t.Errorf("scalars: got %v", first)
+	}
+	if first["Joined"] != joined.UnixMilli() || first["Birthday"] != int32(in[0].Birthday.Time().Unix()/86400) {
+		t.Errorf("times: got %v", first)
+	}
+	if d, _ := first["Balance"].(decimal.Decimal); !d.Equal(in[0].Balance) {
+		t.Errorf("decimal: got %v", first["Balance"])
+	}
+	if first["Nickname"] != "sammy" || first["Bio"] != "hi" || first["Note"] != "there" {
+		t.Errorf("strings: got %v", first)
+	}
+	for _, row := range rows[1:] {
+		if _, ok := row["Nickname"]; ok {
+			t.Errorf("nil pointers are null: got %v", row)
+		}
+		if _, ok := row["Note"]; ok {
+			t.Errorf("nil fields are null: got %v", row)
+		}
+	}
+	if rows[1]["Name"] != "kim" || rows[2]["Active"] != true || rows[1]["Active"] != false {
+		t.Errorf("got %v", rows)
+	}
+	if err := pw.Write(in[0]); err != ErrParquetClosed {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestParquetNoColumns(t *testing.T) {
+	type untagged struct {
+		Name string
+	}
+	var b bytes.Buffer
+	if err := WriteParquet(&b, []untagged{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if total, rows := testReadParquet(t, b.Bytes()); total != 2 || len(rows) != 2 {
+		t.Errorf("got %d %v", total, rows)
+	}
+	if _, err := NewParquetWriter[int](&b); err != ErrNotStruct {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestParquetSensitive(t *testing.T) {
+	var b bytes.Buffer
+	pw, err := NewParquetWriter[testParquetRow](&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pw.Write(testParquetRow{Name: "sam", Note: NewRedactedField(testField("Note", "abc"))}); !errors.Is(err, ErrSensitiveField) {
+		t.Errorf("got %v", err)
+	}
+	// the refused row left nothing behind
+	if err := pw.Write(testParquetRow{Name: "kim"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if total, rows := testReadParquet(t, b.Bytes()); total != 1 || rows[0]["Name"] != "kim" {
+		t.Errorf("got %d %v", total, rows)
+	}
+}
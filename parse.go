@@ -0,0 +1,147 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidURL      = errors.New("invalid url")
+	ErrInvalidEmail    = errors.New("invalid email address")
+	ErrInvalidIP       = errors.New("invalid ip address")
+	ErrInvalidCIDR     = errors.New("invalid cidr")
+	ErrInvalidGeoPoint = errors.New("invalid geo point")
+	ErrInvalidTime     = errors.New("time does not match any layout")
+	ErrInvalidDate     = errors.New("date does not match any layout")
+)
+
+// StringParser is implemented by fields that validate their input
+// FromString on these fields silently keeps the old value on bad input, ParseString reports why instead
+type StringParser interface {
+	ParseString(st string) error
+}
+
+// ParseFieldString sets f from st, returning the validation error if f is a StringParser
+// fields that do not validate always succeed
+func ParseFieldString(f Field, st string) error {
+	if p, ok := f.(StringParser); ok {
+		return p.ParseString(st)
+	}
+	f.FromString(st)
+	return nil
+}
+
+// sets f back to its zero value, or to null if f is nullable
+func resetField(f Field) {
+	switch v := f.(type) {
+	case Nullable:
+		v.Clear()
+		return
+	case *BoolField:
+		// setting a zero bool marks it as set, it has to go back to unset instead
+		v.ValueField = false
+		v.Set = false
+		return
+	case *GeoPointField:
+		v.ValueField = GeoPoint{}
+		v.Set = false
+		return
+	case *CounterField:
+		v.value.Store(0)
+		return
+	}
+	// the value is zeroed in place rather than set from a zero field, the validating fields, ex: URLField, refuse to be set to ""
+	// and the options of the field, ex: the layouts of a TimeField, are kept
+	if rv := reflect.ValueOf(f); rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct {
+		if value := rv.Elem().FieldByName("ValueField"); value.IsValid() && value.CanSet() {
+			value.Set(reflect.Zero(value.Type()))
+			return
+		}
+	}
+	// wrappers, ex: a RedactedField, reset the field they hold
+	if inner := unwrapField(f); inner != nil {
+		resetField(inner)
+	}
+}
+
+// the core types keep their lenient FromString, these report the parse error instead so bound input can be validated
+
+func (s *IntegerField) ParseString(st string) error {
+	it, err := strconv.Atoi(st)
+	if err != nil {
+		return err
+	}
+	s.ValueField = it
+	return nil
+}
+
+func (s *FloatField) ParseString(st string) error {
+	fl, err := strconv.ParseFloat(st, 64)
+	if err != nil {
+		return err
+	}
+	s.ValueField = fl
+	return nil
+}
+
+func (s *DecimalField) ParseString(st string) error {
+	d, err := decimal.NewFromString(st)
+	if err != nil {
+		return err
+	}
+	s.ValueField = s.round(d)
+	return nil
+}
+
+func (s *BoolField) ParseString(st string) error {
+	b, err := strconv.ParseBool(st)
+	if err != nil {
+		return err
+	}
+	s.ValueField = b
+	s.Set = true
+	return nil
+}
+
+func (s *TimeField) ParseString(st string) error {
+	for _, layout := range s.layouts() {
+		if t, err := time.ParseInLocation(layout, st, s.location()); err == nil {
+			s.ValueField = s.normalize(t)
+			return nil
+		}
+	}
+	return ErrInvalidTime
+}
+
+func (s *DateField) ParseString(st string) error {
+	for _, layout := range DateLayouts {
+		if t, err := time.Parse(layout, st); err == nil {
+			s.ValueField = DateOf(t)
+			return nil
+		}
+	}
+	return ErrInvalidDate
+}
+
+func (s *DurationField) ParseString(st string) error {
+	d, err := time.ParseDuration(st)
+	if err != nil {
+		return err
+	}
+	s.ValueField = d
+	return nil
+}
+
+func (s *UUIDField) ParseString(st string) error {
+	u, err := uuid.Parse(st)
+	if err != nil {
+		return err
+	}
+	s.ValueField = u
+	return nil
+}
@@ -0,0 +1,70 @@
+package fielder
+
+import "reflect"
+
+var fieldInterfaceType = reflect.TypeOf((*Field)(nil)).Elem()
+
+// pointer members like *string or *time.Time are how most structs express optional values
+// they become a NullableField around the field for the pointed to type, a nil pointer is null
+func createPointerField(ty reflect.Type, va any, fk FieldKey) Field {
+	if ty.Implements(fieldInterfaceType) {
+		// members that are already fields are not wrapped again
+		return nil
+	}
+	if va == nil || reflect.ValueOf(va).IsNil() {
+		inner := CreateFieldFromType(ty.Elem(), nil, fk)
+		if inner == nil {
+			return nil
+		}
+		return NewNullField(inner)
+	}
+	inner := CreateFieldFromType(ty.Elem(), reflect.ValueOf(va).Elem().Interface(), fk)
+	if inner == nil {
+		return nil
+	}
+	return NewNullableField(inner)
+}
+
+// creates an empty field to decode a member of type ty into, ex: a csv cell or a query parameter
+// members that are fields themselves, ex: *StringField, are allocated since CreateFieldFromType does not make them,
+// wrappers like *FieldNullable can not be made without the field they wrap, so they are nil like types that have no field
+func newMemberField(ty reflect.Type, key FieldKey) Field {
+	if ty == nil {
+		return nil
+	}
+	if ty.Kind() != reflect.Pointer || !ty.Implements(fieldInterfaceType) || ty.Elem().Kind() != reflect.Struct {
+		return CreateFieldFromType(ty, nil, key)
+	}
+	if inner, ok := ty.Elem().FieldByName("Field"); ok && inner.Type == fieldInterfaceType {
+		return nil
+	}
+	v := reflect.New(ty.Elem())
+	if keyField := v.Elem().FieldByName("KeyField"); keyField.IsValid() && keyField.CanSet() && keyField.Type() == reflect.TypeOf(key) {
+		keyField.Set(reflect.ValueOf(key))
+	}
+	return v.Interface().(Field)
+}
+
+// true for nil pointers held as a Field, ex: a Field member holding (*StringField)(nil),
+// and for wrappers around one, calling Value on either would panic
+func isNilField(f Field) bool {
+	if f == nil {
+		return true
+	}
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Pointer {
+		return false
+	}
+	if v.IsNil() {
+		return true
+	}
+	if v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	sf, ok := v.Elem().Type().FieldByName("Field")
+	if !ok || sf.Type != fieldInterfaceType {
+		return false
+	}
+	inner, err := v.Elem().FieldByIndexErr(sf.Index)
+	return err != nil || inner.IsNil() || isNilField(inner.Interface().(Field))
+}
@@ -0,0 +1,105 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreatePointerField(t *testing.T) {
+	key := NewDefaultFieldKey("Nickname")
+	name := "sam"
+	f := CreateFieldFromType(reflect.TypeOf(&name), &name, key)
+	n, ok := f.(*FieldNullable)
+	if !ok {
+		t.Fatalf("got %T", f)
+	}
+	if n.Null || n.ToString() != "sam" || n.Key() != key {
+		t.Errorf("a set pointer is a set nullable field: got %q null=%v", n.ToString(), n.Null)
+	}
+	var at *time.Time
+	f = CreateFieldFromType(reflect.TypeOf(at), at, key)
+	if n, ok := f.(*FieldNullable); !ok || !n.Null {
+		t.Errorf("a nil pointer is a null field: got %#v", f)
+	}
+	if f := CreateFieldFromType(reflect.TypeOf(&StringField{}), nil, key); f != nil {
+		t.Errorf("field members are not wrapped again: got %T", f)
+	}
+}
+
+func TestPointerMember(t *testing.T) {
+	type item struct {
+		Nickname *string `field:"Nickname"`
+		Age      *int    `field:"Age"`
+	}
+	age := 30
+	p := &item{Age: &age}
+	if f := GetResultItemFieldFromKeyDefault(*p, NewDefaultFieldKey("Nickname")); f == nil || f == FieldNil || !f.IsEmpty() {
+		t.Errorf("a nil pointer member is an empty field, got %#v", f)
+	}
+	f := GetResultItemFieldFromKeyDefault(*p, NewDefaultFieldKey("Age"))
+	if f == nil || f.ToString() != "30" {
+		t.Errorf("got %#v", f)
+	}
+}
+
+func TestCompareWithWrappers(t *testing.T) {
+	key := NewDefaultFieldKey("Name")
+	plain := &StringField{ValueField: "b", KeyField: key}
+	wrappers := map[string]Field{
+		"nullable":  NewNullableField(&StringField{ValueField: "b", KeyField: key}),
+		"versioned": NewVersionedField(&StringField{ValueField: "b", KeyField: key}, 0),
+		"typed": NewTypedField(key, "b", TypedComparators[string]{
+			Less: func(a, b string) bool { return a < b },
+		}),
+	}
+	for name, w := range wrappers {
+		if !plain.Equal(w) || plain.LessThan(w) || plain.GreaterThan(w) {
+			t.Errorf("%s: a field equals a wrapper holding the same value", name)
+		}
+		f := &StringField{KeyField: key}
+		f.SetValue(w)
+		if f.ValueField != "b" {
+			t.Errorf("%s: SetValue from a wrapper: got %q", name, f.ValueField)
+		}
+	}
+	if plain.Equal(NewNullField(&StringField{KeyField: key})) {
+		t.Error("a field does not equal a null field")
+	}
+	// wrapped fields are compared as their own type, not as strings
+	nine := CreateFieldFromType(reflect.TypeOf(0), 9, key)
+	ten := NewNullableField(CreateFieldFromType(reflect.TypeOf(0), 10, key))
+	if !nine.LessThan(ten) || nine.GreaterThan(ten) {
+		t.Error("9 is less than a wrapped 10")
+	}
+}
+
+func TestSetValueFromWrapper(t *testing.T) {
+	key := NewDefaultFieldKey("Name")
+	c, err := NewAESGCMCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the value is copied, not the string form, which is "****" or the ciphertext
+	for name, w := range map[string]Field{
+		"redacted":  NewRedactedField(&StringField{ValueField: "secret", KeyField: key}),
+		"encrypted": NewEncryptedField(&StringField{ValueField: "secret", KeyField: key}, c),
+		"both":      NewRedactedField(NewEncryptedField(&StringField{ValueField: "secret", KeyField: key}, c)),
+	} {
+		f := &StringField{KeyField: key}
+		f.SetValue(w)
+		if f.ValueField != "secret" {
+			t.Errorf("%s: got %q", name, f.ValueField)
+		}
+	}
+	n := &IntegerField{}
+	n.SetValue(NewRedactedField(&IntegerField{ValueField: 7}))
+	if n.ValueField != 7 {
+		t.Errorf("got %d", n.ValueField)
+	}
+	u := &URLField{}
+	u.SetValue(NewEncryptedField(&URLField{ValueField: "https://x.com"}, c))
+	if u.ToString() != "https://x.com" {
+		t.Errorf("got %q", u.ToString())
+	}
+}
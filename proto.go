@@ -0,0 +1,167 @@
+package fielder
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// fields cross grpc boundaries as protobuf well known types
+// ToProtoMessage picks the closest typed message, ex: Timestamp for a TimeField, and StringValue for anything without one
+// ToProtoValue uses the dynamic google.protobuf.Value, which is what a parent becomes inside a google.protobuf.Struct
+// decimals are sent as strings in both, a double would lose precision
+
+func ToProtoMessage(f Field) (proto.Message, error) {
+	if f == nil || f.Value() == nil {
+		return nil, nil
+	}
+	if IsSensitive(f) {
+		return nil, sensitiveFieldError(f)
+	}
+	switch v := f.(type) {
+	case *TimeField:
+		return timestamppb.New(v.ValueField), nil
+	case *DurationField:
+		return durationpb.New(v.ValueField), nil
+	case *IntegerField:
+		return wrapperspb.Int64(int64(v.ValueField)), nil
+	case *CounterField:
+		return wrapperspb.Int64(v.Load()), nil
+	case *FloatField:
+		return wrapperspb.Double(v.ValueField), nil
+	case *BoolField:
+		if !v.Set {
+			return nil, nil
+		}
+		return wrapperspb.Bool(v.ValueField), nil
+	case *BytesField:
+		return wrapperspb.Bytes(v.ValueField), nil
+	default:
+		if nullable, ok := f.(Nullable); ok && nullable.IsNull() {
+			return nil, nil
+		}
+		return wrapperspb.String(f.ToString()), nil
+	}
+}
+
+// sets f from a message made by ToProtoMessage, a nil message resets f to its zero value
+func FromProtoMessage(m proto.Message, f Field) error {
+	switch v := m.(type) {
+	case nil:
+		resetField(f)
+		return nil
+	case *timestamppb.Timestamp:
+		return ParseFieldString(f, v.AsTime().Format(time.RFC3339Nano))
+	case *durationpb.Duration:
+		return ParseFieldString(f, v.AsDuration().String())
+	case *wrapperspb.Int64Value:
+		return ParseFieldString(f, strconv.FormatInt(v.Value, 10))
+	case *wrapperspb.DoubleValue:
+		return ParseFieldString(f, strconv.FormatFloat(v.Value, 'f', -1, 64))
+	case *wrapperspb.BoolValue:
+		return ParseFieldString(f, strconv.FormatBool(v.Value))
+	case *wrapperspb.BytesValue:
+		return scanField(f, v.Value)
+	case *wrapperspb.StringValue:
+		return ParseFieldString(f, v.Value)
+	default:
+		return fmt.Errorf("fielder: cannot set %T from %T", f, m)
+	}
+}
+
+func ToProtoValue(f Field) (*structpb.Value, error) {
+	if f == nil || f.Value() == nil {
+		return structpb.NewNullValue(), nil
+	}
+	if IsSensitive(f) {
+		return nil, sensitiveFieldError(f)
+	}
+	switch v := f.(type) {
+	case *IntegerField:
+		return structpb.NewNumberValue(float64(v.ValueField)), nil
+	case *CounterField:
+		return structpb.NewNumberValue(float64(v.Load())), nil
+	case *FloatField:
+		return structpb.NewNumberValue(v.ValueField), nil
+	case *BoolField:
+		if !v.Set {
+			return structpb.NewNullValue(), nil
+		}
+		return structpb.NewBoolValue(v.ValueField), nil
+	case *SliceField, *MapField, *StructField:
+		// the string form of these is already json, which is what a Value is
+		out := &structpb.Value{}
+		if err := out.UnmarshalJSON([]byte(f.ToString())); err != nil {
+			return nil, err
+		}
+		return out, nil
+	default:
+		if nullable, ok := f.(Nullable); ok && nullable.IsNull() {
+			return structpb.NewNullValue(), nil
+		}
+		return structpb.NewStringValue(f.ToString()), nil
+	}
+}
+
+// sets f from a Value made by ToProtoValue, a null value resets f to its zero value
+func FromProtoValue(v *structpb.Value, f Field) error {
+	switch k := v.GetKind().(type) {
+	case nil, *structpb.Value_NullValue:
+		resetField(f)
+		return nil
+	case *structpb.Value_StringValue:
+		if k.StringValue == "" {
+			// empty strings are the empty state of every string based field, and not valid input for some of them
+			resetField(f)
+			return nil
+		}
+		return ParseFieldString(f, k.StringValue)
+	case *structpb.Value_NumberValue:
+		return ParseFieldString(f, strconv.FormatFloat(k.NumberValue, 'f', -1, 64))
+	case *structpb.Value_BoolValue:
+		return ParseFieldString(f, strconv.FormatBool(k.BoolValue))
+	default:
+		b, err := v.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		return ParseFieldString(f, string(b))
+	}
+}
+
+// converts every tagged member of a default parent into a google.protobuf.Struct, keyed by field name
+func ToProtoStruct[P any](p P) (*structpb.Struct, error) {
+	out := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	for k, f := range ToFieldMap(p) {
+		v, err := ToProtoValue(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k.Name, err)
+		}
+		out.Fields[k.Name.String()] = v
+	}
+	return out, nil
+}
+
+// the reverse of ToProtoStruct, names in the struct that are not keys of the parent are an error
+func FromProtoStruct[P any](s *structpb.Struct) (P, error) {
+	fields := map[FieldKey]Field{}
+	for name, v := range s.GetFields() {
+		key := NewDefaultFieldKey(name)
+		f := newMemberField(GetFieldTypeFromKey[P](key), key)
+		if f == nil {
+			return *new(P), fmt.Errorf("%s is not a key of %s", name, reflect.TypeOf(*new(P)))
+		}
+		if err := FromProtoValue(v, f); err != nil {
+			return *new(P), fmt.Errorf("%s: %w", name, err)
+		}
+		fields[key] = f
+	}
+	return FromFieldMap[P](fields)
+}
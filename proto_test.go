@@ -0,0 +1,103 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProtoMessage(t *testing.T) {
+	at := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	for _, f := range []Field{
+		NewTimeField(FieldKeyNil, at),
+		&DurationField{ValueField: time.Second},
+		&IntegerField{ValueField: 3},
+		&FloatField{ValueField: 1.5},
+		NewBool(FieldKeyNil, true),
+		&BytesField{ValueField: []byte{1}},
+		testDecimal(t, "1.10"),
+	} {
+		m, err := ToProtoMessage(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := CreateFieldFromType(f.Type(), nil, FieldKeyNil)
+		if err := FromProtoMessage(m, got); err != nil || !got.Equal(f) {
+			t.Errorf("%T: got %q %v", m, got.ToString(), err)
+		}
+	}
+	if m, _ := ToProtoMessage(NewTimeField(FieldKeyNil, at)); !m.(*timestamppb.Timestamp).AsTime().Equal(at) {
+		t.Error("times are timestamps")
+	}
+	if m, _ := ToProtoMessage(&DurationField{ValueField: time.Second}); m.(*durationpb.Duration).AsDuration() != time.Second {
+		t.Error("durations are durations")
+	}
+	if m, _ := ToProtoMessage(testDecimal(t, "1.10")); m.(*wrapperspb.StringValue).Value != "1.1" {
+		t.Error("decimals are strings")
+	}
+	if m, _ := ToProtoMessage(&BoolField{}); m != nil {
+		t.Error("an unset bool has no message")
+	}
+	s := &StringField{ValueField: "x"}
+	if err := FromProtoMessage(nil, s); err != nil || s.ValueField != "" {
+		t.Error("a nil message resets the field")
+	}
+}
+
+func TestProtoStruct(t *testing.T) {
+	nick := "s"
+	in := testProfile{Name: "sam", Age: 30, Nickname: &nick, Bio: testField("Bio", "hi")}
+	s, err := ToProtoStruct(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Fields["Age"].GetNumberValue() != 30 || s.Fields["Bio"].GetStringValue() != "hi" {
+		t.Errorf("got %v", s)
+	}
+	out, err := FromProtoStruct[testProfile](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "sam" || out.Age != 30 || *out.Nickname != "s" || out.Bio == nil || out.Bio.ValueField != "hi" {
+		t.Errorf("got %#v", out)
+	}
+	s.Fields["Shoe"] = structpb.NewNumberValue(9)
+	if _, err := FromProtoStruct[testProfile](s); err == nil {
+		t.Error("names that are not keys are an error")
+	}
+}
+
+func TestProtoValueNested(t *testing.T) {
+	v, err := ToProtoValue(testStrings("Tags", "a", "b"))
+	if err != nil || len(v.GetListValue().GetValues()) != 2 {
+		t.Fatalf("slices are lists: got %v %v", v, err)
+	}
+	got := testStrings("Tags")
+	if err := FromProtoValue(v, got); err != nil || !got.Equal(testStrings("Tags", "a", "b")) {
+		t.Errorf("got %q %v", got.ToString(), err)
+	}
+	if v, _ := ToProtoValue(NewNullField(&StringField{})); v.GetNullValue() != structpb.NullValue_NULL_VALUE || v.GetKind() == nil {
+		t.Error("a null field is a null value")
+	}
+}
+
+func TestProtoSensitive(t *testing.T) {
+	secret := NewRedactedField(testField("Token", "abc"))
+	if m, err := ToProtoMessage(secret); !errors.Is(err, ErrSensitiveField) || m != nil {
+		t.Errorf("got %v %v", m, err)
+	}
+	if v, err := ToProtoValue(secret); !errors.Is(err, ErrSensitiveField) || v != nil {
+		t.Errorf("got %v %v", v, err)
+	}
+	type secrets struct {
+		Token Field `field:"Token"`
+	}
+	if _, err := ToProtoStruct(secrets{Token: secret}); !errors.Is(err, ErrSensitiveField) {
+		t.Errorf("got %v", err)
+	}
+}
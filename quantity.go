@@ -0,0 +1,221 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrUnknownUnit       = errors.New("unknown unit")
+	ErrIncompatibleUnits = errors.New("units measure different dimensions")
+	ErrInvalidQuantity   = errors.New("invalid quantity")
+)
+
+// Quantity is an amount of some unit, ex: 1000 g
+type Quantity struct {
+	Amount decimal.Decimal `dynamodbav:"amount" json:"amount"`
+	Unit   string          `dynamodbav:"unit" json:"unit"`
+}
+
+// ex: "1000 g"
+func (q Quantity) String() string {
+	return q.Amount.String() + " " + q.Unit
+}
+
+// Unit describes a unit symbol as a multiple of the base unit of its dimension
+// ex: kg is Unit{Dimension: "mass", Factor: 1000} when the base unit of mass is g
+type Unit struct {
+	Dimension string
+	Factor    decimal.Decimal
+}
+
+// UnitTable maps unit symbols to their definitions, units can only be converted within a dimension
+type UnitTable map[string]Unit
+
+func mustDecimal(st string) decimal.Decimal {
+	return decimal.RequireFromString(st)
+}
+
+// DefaultUnits covers common mass, length, time and data size units
+var DefaultUnits = UnitTable{
+	"mg": {Dimension: "mass", Factor: mustDecimal("0.001")},
+	"g":  {Dimension: "mass", Factor: mustDecimal("1")},
+	"kg": {Dimension: "mass", Factor: mustDecimal("1000")},
+	"t":  {Dimension: "mass", Factor: mustDecimal("1000000")},
+	"oz": {Dimension: "mass", Factor: mustDecimal("28.349523125")},
+	"lb": {Dimension: "mass", Factor: mustDecimal("453.59237")},
+
+	"mm": {Dimension: "length", Factor: mustDecimal("0.001")},
+	"cm": {Dimension: "length", Factor: mustDecimal("0.01")},
+	"m":  {Dimension: "length", Factor: mustDecimal("1")},
+	"km": {Dimension: "length", Factor: mustDecimal("1000")},
+
+	"ns":  {Dimension: "time", Factor: mustDecimal("0.000000001")},
+	"us":  {Dimension: "time", Factor: mustDecimal("0.000001")},
+	"ms":  {Dimension: "time", Factor: mustDecimal("0.001")},
+	"s":   {Dimension: "time", Factor: mustDecimal("1")},
+	"min": {Dimension: "time", Factor: mustDecimal("60")},
+	"h":   {Dimension: "time", Factor: mustDecimal("3600")},
+	"d":   {Dimension: "time", Factor: mustDecimal("86400")},
+
+	"B":   {Dimension: "data", Factor: mustDecimal("1")},
+	"KB":  {Dimension: "data", Factor: mustDecimal("1000")},
+	"MB":  {Dimension: "data", Factor: mustDecimal("1000000")},
+	"GB":  {Dimension: "data", Factor: mustDecimal("1000000000")},
+	"TB":  {Dimension: "data", Factor: mustDecimal("1000000000000")},
+	"KiB": {Dimension: "data", Factor: mustDecimal("1024")},
+	"MiB": {Dimension: "data", Factor: mustDecimal("1048576")},
+	"GiB": {Dimension: "data", Factor: mustDecimal("1073741824")},
+	"TiB": {Dimension: "data", Factor: mustDecimal("1099511627776")},
+}
+
+// converts q into the unit "to"
+func (t UnitTable) Convert(q Quantity, to string) (Quantity, error) {
+	from, ok := t[q.Unit]
+	if !ok {
+		return Quantity{}, ErrUnknownUnit
+	}
+	target, ok := t[to]
+	if !ok {
+		return Quantity{}, ErrUnknownUnit
+	}
+	if from.Dimension != target.Dimension {
+		return Quantity{}, ErrIncompatibleUnits
+	}
+	if q.Unit == to {
+		return q, nil
+	}
+	return Quantity{Amount: q.Amount.Mul(from.Factor).Div(target.Factor), Unit: to}, nil
+}
+
+// QuantityField compares quantities after converting the other side into this field's unit
+// quantities of different dimensions (kg and ms) are never less, greater or equal
+type QuantityField struct {
+	ValueField Quantity  `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey  `dynamodbav:"key" json:"key"`
+	Units      UnitTable `dynamodbav:"-" json:"-"`
+}
+
+func NewQuantityField(key FieldKey, amount decimal.Decimal, unit string, units UnitTable) *QuantityField {
+	return &QuantityField{
+		ValueField: Quantity{Amount: amount, Unit: unit},
+		KeyField:   key,
+		Units:      units,
+	}
+}
+
+func (s *QuantityField) units() UnitTable {
+	if s.Units == nil {
+		return DefaultUnits
+	}
+	return s.Units
+}
+
+// converts the other quantity into this field's unit
+func (s *QuantityField) convert(in2 *QuantityField) (decimal.Decimal, bool) {
+	q, err := s.units().Convert(in2.ValueField, s.ValueField.Unit)
+	if err != nil {
+		return decimal.Decimal{}, false
+	}
+	return q.Amount, true
+}
+
+// returns this quantity in another unit
+func (s *QuantityField) In(unit string) (Quantity, error) {
+	return s.units().Convert(s.ValueField, unit)
+}
+
+func (s *QuantityField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *QuantityField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *QuantityField) Type() reflect.Type {
+	return reflect.TypeOf(Quantity{})
+}
+
+func (s *QuantityField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	other, ok := s.convert(in2.(*QuantityField))
+	return ok && s.ValueField.Amount.LessThan(other)
+}
+
+func (s *QuantityField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	other, ok := s.convert(in2.(*QuantityField))
+	return ok && s.ValueField.Amount.GreaterThan(other)
+}
+
+func (s *QuantityField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	other, ok := s.convert(in2.(*QuantityField))
+	return ok && s.ValueField.Amount.Equal(other)
+}
+
+func (s *QuantityField) ToString() string {
+	if s.IsEmpty() {
+		return ""
+	}
+	return s.ValueField.String()
+}
+
+func (s *QuantityField) FromString(st string) {
+	_ = s.ParseString(st)
+}
+
+// accepts "1000 g" or "1000g", units missing from the table are rejected
+func (s *QuantityField) ParseString(st string) error {
+	st = strings.TrimSpace(st)
+	split := strings.LastIndexAny(st, "0123456789.") + 1
+	if split == 0 {
+		return fmt.Errorf("%w: %s: expected an amount and a unit", ErrInvalidQuantity, st)
+	}
+	unit := strings.TrimSpace(st[split:])
+	if _, ok := s.units()[unit]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownUnit, unit)
+	}
+	d, err := decimal.NewFromString(strings.TrimSpace(st[:split]))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidQuantity, st, err)
+	}
+	s.ValueField = Quantity{Amount: d, Unit: unit}
+	return nil
+}
+
+// SetValue drops the parse error, use TrySetValue to see it
+func (s *QuantityField) SetValue(in2 FieldValue) {
+	_ = s.TrySetValue(in2)
+}
+
+// TrySetValue copies the value of a QuantityField as is, so an empty one clears the field
+// any other field is parsed from its string form, where "" clears the field and anything invalid is returned as an error
+func (s *QuantityField) TrySetValue(in2 FieldValue) error {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		st := in2.(Field).ToString()
+		if st == "" {
+			resetField(s)
+			return nil
+		}
+		return s.ParseString(st)
+	}
+	s.ValueField = in2.(*QuantityField).ValueField
+	return nil
+}
+
+func (s *QuantityField) IsEmpty() bool {
+	return s.ValueField.Unit == "" && s.ValueField.Amount.IsZero()
+}
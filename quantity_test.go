@@ -0,0 +1,86 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func testQuantity(amount, unit string) *QuantityField {
+	return NewQuantityField(NewDefaultFieldKey("Weight"), decimal.RequireFromString(amount), unit, nil)
+}
+
+func TestUnitTableConvert(t *testing.T) {
+	q, err := DefaultUnits.Convert(Quantity{Amount: decimal.RequireFromString("1.5"), Unit: "kg"}, "g")
+	if err != nil || q.String() != "1500 g" {
+		t.Errorf("got %s %v", q, err)
+	}
+	if _, err := DefaultUnits.Convert(Quantity{Unit: "kg"}, "ms"); !errors.Is(err, ErrIncompatibleUnits) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := DefaultUnits.Convert(Quantity{Unit: "stone"}, "kg"); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("got %v", err)
+	}
+	q, err = testQuantity("2", "GiB").In("MiB")
+	if err != nil || q.String() != "2048 MiB" {
+		t.Errorf("In: got %s %v", q, err)
+	}
+}
+
+func TestQuantityFieldCompare(t *testing.T) {
+	kg := testQuantity("1", "kg")
+	if !kg.Equal(testQuantity("1000", "g")) || !kg.LessThan(testQuantity("2.5", "lb")) || !kg.GreaterThan(testQuantity("999", "g")) {
+		t.Error("quantities are compared after converting to the same unit")
+	}
+	ms := testQuantity("1", "ms")
+	if kg.Equal(ms) || kg.LessThan(ms) || kg.GreaterThan(ms) {
+		t.Error("quantities of different dimensions do not compare")
+	}
+	custom := NewQuantityField(FieldKeyNil, decimal.RequireFromString("1"), "dozen", UnitTable{
+		"each":  {Dimension: "count", Factor: decimal.RequireFromString("1")},
+		"dozen": {Dimension: "count", Factor: decimal.RequireFromString("12")},
+	})
+	if !custom.Equal(NewQuantityField(FieldKeyNil, decimal.RequireFromString("12"), "each", nil)) {
+		t.Error("the receiver's unit table is used")
+	}
+}
+
+func TestQuantityFieldString(t *testing.T) {
+	f := &QuantityField{}
+	if f.ToString() != "" || !f.IsEmpty() {
+		t.Error("an empty quantity has no string form")
+	}
+	f.FromString("250ms")
+	if f.ToString() != "250 ms" {
+		t.Errorf("got %q", f.ToString())
+	}
+	for _, bad := range []string{"250 parsecs", "ms", "1.2.3 ms"} {
+		f.FromString(bad)
+		if f.ToString() != "250 ms" {
+			t.Errorf("FromString(%q) changed the value to %q", bad, f.ToString())
+		}
+	}
+	f.SetValue(testQuantity("3", "h"))
+	if f.ToString() != "3 h" {
+		t.Errorf("SetValue keeps the other unit: got %q", f.ToString())
+	}
+}
+
+func TestQuantityFieldSetValue(t *testing.T) {
+	key := NewDefaultFieldKey("Weight")
+	f := NewQuantityField(key, decimal.NewFromInt(5), "kg", nil)
+	f.SetValue(&QuantityField{KeyField: key})
+	if !f.IsEmpty() {
+		t.Errorf("an empty quantity clears the field, got %q", f.ToString())
+	}
+	if err := f.TrySetValue(testField("Weight", "5 parsecs")); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("an unknown unit is returned, got %v", err)
+	}
+	if err := f.TrySetValue(testField("Weight", "kg")); !errors.Is(err, ErrInvalidQuantity) {
+		t.Errorf("a missing amount is returned, got %v", err)
+	}
+	if err := f.TrySetValue(testField("Weight", "2 g")); err != nil || f.ToString() != "2 g" {
+		t.Errorf("got %q, %v", f.ToString(), err)
+	}
+}
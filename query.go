@@ -0,0 +1,115 @@
+package fielder
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+)
+
+// BindQuery builds a parent from query parameters named after its field keys, ex: ?Status=open&MinAge=21
+// values are parsed with the field's own parsing, so invalid input is an error naming the parameter
+// slice members take the parameter repeated, ex: ?Tag=a&Tag=b, and parameters that are not keys are ignored
+// the error is a FieldErrors with one entry per invalid parameter
+func BindQuery[P any](values url.Values) (P, error) {
+	return bindValues[P](values, nil)
+}
+
+// shared by query and form binding, every key is parsed and checked so all the invalid ones are reported together
+func bindValues[P any](values url.Values, conditions map[FieldKey]Conditional) (P, error) {
+	fields := map[FieldKey]Field{}
+	errs := FieldErrors{}
+	for _, key := range FullKeySet[P](FieldKeyTag) {
+		raw, ok := values[key.Name.String()]
+		if key.Name == "" || !ok || len(raw) == 0 {
+			continue
+		}
+		f, err := fieldFromValues[P](key, raw)
+		if err != nil {
+			errs = append(errs, NewFieldError(key, err))
+			continue
+		}
+		if f == nil {
+			continue
+		}
+		if c, ok := conditions[key]; ok && c != nil && !c.Meets(f) {
+			errs = append(errs, NewFieldError(key, ErrConditionNotMet))
+			continue
+		}
+		fields[key] = f
+	}
+	if len(errs) > 0 {
+		return *new(P), errs
+	}
+	return FromFieldMap[P](fields)
+}
+
+// parses the values for one key into a field of the member's type, empty values are skipped
+func fieldFromValues[P any](key FieldKey, raw []string) (Field, error) {
+	ty := GetFieldTypeFromKey[P](key)
+	if ty == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, key.Name)
+	}
+	return memberFromValues(ty, key, raw)
+}
+
+// fieldFromValues for a member whose type is already known
+func memberFromValues(ty reflect.Type, key FieldKey, raw []string) (Field, error) {
+	if ty.Kind() == reflect.Slice && ty != reflect.TypeOf([]byte{}) {
+		out := reflect.MakeSlice(ty, 0, len(raw))
+		for _, st := range raw {
+			if st == "" {
+				continue
+			}
+			elem := newMemberField(ty.Elem(), key)
+			if elem == nil {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, ty.Elem())
+			}
+			if err := ParseFieldString(elem, st); err != nil {
+				return nil, err
+			}
+			v := reflect.New(ty.Elem()).Elem()
+			if err := assignField(v, elem); err != nil {
+				return nil, err
+			}
+			out = reflect.Append(out, v)
+		}
+		f := CreateFieldFromType(ty, out.Interface(), key)
+		if f == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, ty)
+		}
+		return f, nil
+	}
+	// a single valued member takes the last value, the same as a later parameter overriding an earlier one
+	st := raw[len(raw)-1]
+	if st == "" {
+		return nil, nil
+	}
+	f := newMemberField(ty, key)
+	if f == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFieldType, ty)
+	}
+	if err := ParseFieldString(f, st); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// ToQuery is the reverse of BindQuery, members that are empty, null or sensitive are left out
+func ToQuery[P any](p P) url.Values {
+	out := url.Values{}
+	fields := ToFieldMap(p)
+	for _, key := range FullKeySet[P](FieldKeyTag) {
+		f, ok := fields[key]
+		if !ok || f.Value() == nil || f.IsEmpty() || IsSensitive(f) {
+			continue
+		}
+		if s, isSlice := f.(*SliceField); isSlice {
+			for _, elem := range s.ValueField {
+				out.Add(key.Name.String(), elem.ToString())
+			}
+			continue
+		}
+		out.Set(key.Name.String(), f.ToString())
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package fielder
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testQuery struct {
+	Status  string         `field:"Status"`
+	MinAge  int            `field:"MinAge"`
+	Since   time.Time      `field:"Since"`
+	Nick    *string        `field:"Nick"`
+	Tag     []string       `field:"Tag"`
+	Bio     *StringField   `field:"Bio"`
+	Aliases []*StringField `field:"Aliases"`
+}
+
+func TestBindQuery(t *testing.T) {
+	values, _ := url.ParseQuery("Status=closed&Status=open&MinAge=21&Since=2025-01-02T00:00:00Z&Nick=sam&Tag=a&Tag=&Tag=b&Bio=hi&Ignored=x")
+	q, err := BindQuery[testQuery](values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Status != "open" || q.MinAge != 21 || !q.Since.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %+v", q)
+	}
+	if q.Nick == nil || *q.Nick != "sam" {
+		t.Errorf("pointer member: got %v", q.Nick)
+	}
+	if !reflect.DeepEqual(q.Tag, []string{"a", "b"}) {
+		t.Errorf("slice member: got %v", q.Tag)
+	}
+	if q.Bio == nil || q.Bio.ValueField != "hi" || q.Bio.Key().Name != "Bio" {
+		t.Errorf("field member: got %v", q.Bio)
+	}
+}
+
+func TestBindQueryFieldSlice(t *testing.T) {
+	// slices of field members have no slice field to hold them, they are reported instead of dropped
+	_, err := BindQuery[testQuery](url.Values{"Aliases": {"a", "b"}})
+	if !errors.Is(err, ErrUnknownFieldType) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestBindQueryErrors(t *testing.T) {
+	_, err := BindQuery[testQuery](url.Values{"MinAge": {"old"}, "Since": {"later"}, "Status": {"ok"}})
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("every invalid parameter is reported: got %v", err)
+	}
+	q, err := BindQuery[testQuery](url.Values{"MinAge": {""}})
+	if err != nil || q.MinAge != 0 {
+		t.Errorf("empty values are skipped: got %v %v", q, err)
+	}
+}
+
+func TestToQuery(t *testing.T) {
+	nick := "sam"
+	q := testQuery{Status: "open", Nick: &nick, Tag: []string{"a", "b"}, Bio: testField("Bio", "hi")}
+	got := ToQuery(q)
+	want := url.Values{"Status": {"open"}, "Nick": {"sam"}, "Tag": {"a", "b"}, "Bio": {"hi"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	back, err := BindQuery[testQuery](got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Status != q.Status || *back.Nick != nick || back.Bio.ValueField != "hi" || !reflect.DeepEqual(back.Tag, q.Tag) {
+		t.Errorf("round trip: got %+v", back)
+	}
+}
+
+func TestToQuerySensitive(t *testing.T) {
+	type login struct {
+		User     string `field:"User"`
+		Password Field  `field:"Password"`
+	}
+	got := ToQuery(login{User: "sam", Password: NewRedactedField(testField("Password", "hunter2"))})
+	if got.Has("Password") || got.Get("User") != "sam" {
+		t.Errorf("got %v", got)
+	}
+}
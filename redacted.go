@@ -0,0 +1,84 @@
+package fielder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const RedactedString = "****"
+
+// ErrSensitiveField is returned by the encoders that store a field, ex: WriteCSV, dynamo, sql, msgpack, gob, AppendField,
+// protobuf, avro and parquet, since the string form of a sensitive field is RedactedString and could not be read back
+// the outputs meant for display, ex: json, graphql and flag defaults, show RedactedString instead
+var ErrSensitiveField = errors.New("sensitive fields can not be encoded")
+
+// Sensitive is implemented by fields whose values must not be logged
+type Sensitive interface {
+	IsSensitive() bool
+}
+
+// RedactedField hides its value from ToString, fmt and json so it is safe to log or diff
+// Value still returns the real data, so comparisons and conditionals work as normal
+// there is no FromString for "****", so the encoders built on ToString refuse it with ErrSensitiveField and ToQuery leaves it out,
+// store the field unwrapped or wrap it in an EncryptedField instead
+type RedactedField struct {
+	Field
+}
+
+func NewRedactedField(f Field) *RedactedField {
+	return &RedactedField{Field: f}
+}
+
+func IsSensitive(f Field) bool {
+	s, ok := f.(Sensitive)
+	return ok && s.IsSensitive()
+}
+
+func (s *RedactedField) IsSensitive() bool {
+	return true
+}
+
+func sensitiveFieldError(f Field) error {
+	return fmt.Errorf("%s: %w", f.Key().Name, ErrSensitiveField)
+}
+
+func (s *RedactedField) LessThan(in2 any) bool {
+	return s.Field.LessThan(unwrapRedacted(in2))
+}
+
+func (s *RedactedField) GreaterThan(in2 any) bool {
+	return s.Field.GreaterThan(unwrapRedacted(in2))
+}
+
+func (s *RedactedField) Equal(in2 any) bool {
+	return s.Field.Equal(unwrapRedacted(in2))
+}
+
+func (s *RedactedField) ToString() string {
+	return RedactedString
+}
+
+func (s *RedactedField) SetValue(in2 FieldValue) {
+	s.Field.SetValue(unwrapRedacted(in2))
+}
+
+func (s *RedactedField) String() string {
+	return RedactedString
+}
+
+func (s *RedactedField) GoString() string {
+	return RedactedString
+}
+
+func (s *RedactedField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newFieldJSON(s.Field, RedactedString))
+}
+
+// set and compare against the real value of another redacted field, not its redacted string
+func unwrapRedacted(in any) any {
+	if r, ok := in.(*RedactedField); ok {
+		return r.Field
+	}
+	return in
+}
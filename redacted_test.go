@@ -0,0 +1,69 @@
+package fielder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+func TestRedactedFieldHidesValue(t *testing.T) {
+	f := NewRedactedField(&StringField{ValueField: "hunter2", KeyField: NewDefaultFieldKey("Password")})
+	for name, got := range map[string]string{
+		"ToString": f.ToString(),
+		"%v":       fmt.Sprintf("%v", f),
+		"%#v":      fmt.Sprintf("%#v", f),
+	} {
+		if got != RedactedString {
+			t.Errorf("%s: got %q", name, got)
+		}
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte("hunter2")) {
+		t.Errorf("json: %s", b)
+	}
+	if f.Value() != "hunter2" || !IsSensitive(f) || IsSensitive(&StringField{}) {
+		t.Error("Value is the real value and only redacted fields are sensitive")
+	}
+}
+
+func TestRedactedFieldCompare(t *testing.T) {
+	a := NewRedactedField(&StringField{ValueField: "a"})
+	b := NewRedactedField(&StringField{ValueField: "b"})
+	if !a.LessThan(b) || !b.GreaterThan(a) || a.Equal(b) || !a.Equal(&StringField{ValueField: "a"}) {
+		t.Error("redacted fields compare by their real value")
+	}
+	b.SetValue(a)
+	if b.Value() != "a" {
+		t.Errorf("SetValue copies the real value: got %v", b.Value())
+	}
+}
+
+type testAccount struct {
+	User     *StringField   `field:"User"`
+	Password *RedactedField `field:"Password"`
+}
+
+func TestRedactedFieldEncoders(t *testing.T) {
+	p := testAccount{
+		User:     &StringField{ValueField: "sam", KeyField: NewDefaultFieldKey("User")},
+		Password: NewRedactedField(&StringField{ValueField: "hunter2", KeyField: NewDefaultFieldKey("Password")}),
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []testAccount{p}); !errors.Is(err, ErrSensitiveField) {
+		t.Errorf("WriteCSV: got %v", err)
+	}
+	if _, err := attributevalue.Marshal(p.Password); !errors.Is(err, ErrSensitiveField) {
+		t.Errorf("dynamo: got %v", err)
+	}
+	q := ToQuery(p)
+	if q.Has("Password") || q.Get("User") != "sam" {
+		t.Errorf("ToQuery leaves sensitive fields out: got %v", q)
+	}
+}
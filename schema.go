@@ -0,0 +1,155 @@
+package fielder
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// the schema of fieldJSON, which is what members that are fields encode to
+var fieldJSONSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"type":  map[string]any{"type": "string"},
+		"key":   map[string]any{"type": "string"},
+		"tag":   map[string]any{"type": "string"},
+		"value": map[string]any{"type": "string"},
+	},
+	"required": []string{"type", "key", "value"},
+}
+
+// GenerateJSONSchema describes the json encoding of a default parent, one property per tagged member
+// a member is required unless it is a pointer, has a default, or is tagged omitempty
+func GenerateJSONSchema[P any]() ([]byte, error) {
+	ty := reflect.TypeOf(*new(P))
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	properties := map[string]any{}
+	required := []string{}
+	usesField := false
+	for i := 0; i < ty.NumField(); i++ {
+		sf := ty.Field(i)
+		if name, _ := parseFieldTag(sf.Tag.Get(FieldKeyTag)); name == "" || !sf.IsExported() {
+			continue
+		}
+		name, omitempty, skip := jsonMemberName(sf)
+		if skip {
+			continue
+		}
+		if sf.Type.Implements(fieldInterfaceType) {
+			usesField = true
+		}
+		properties[name] = jsonSchemaOf(sf.Type, map[reflect.Type]bool{ty: true})
+		if !omitempty && sf.Type.Kind() != reflect.Pointer && !jsonSchemaDefaulted(sf.Type) {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{
+		"$schema":    jsonSchemaDialect,
+		"title":      ty.Name(),
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if usesField {
+		schema["$defs"] = map[string]any{"field": fieldJSONSchema}
+	}
+	return json.Marshal(schema)
+}
+
+// returns the name encoding/json uses for the member, and whether it is omitempty or left out entirely
+func jsonMemberName(sf reflect.StructField) (string, bool, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = sf.Name
+	}
+	return name, SliceContains(parts[1:], "omitempty", func(s1, s2 string) bool { return s1 == s2 }), false
+}
+
+// members holding a field with a default are filled in when they are missing
+func jsonSchemaDefaulted(ty reflect.Type) bool {
+	return ty.Implements(reflect.TypeOf((*Default)(nil)).Elem())
+}
+
+// seen holds the struct types on the current path, so recursive types end in an empty schema instead of looping
+func jsonSchemaOf(ty reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	if ty.Implements(fieldInterfaceType) {
+		return map[string]any{"$ref": "#/$defs/field"}
+	}
+	switch ty {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]any{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(decimal.Decimal{}):
+		return map[string]any{"type": "string", "format": "decimal"}
+	case reflect.TypeOf(uuid.UUID{}):
+		return map[string]any{"type": "string", "format": "uuid"}
+	case reflect.TypeOf(time.Duration(0)):
+		// durations encode as nanoseconds
+		return map[string]any{"type": "integer"}
+	case reflect.TypeOf([]byte{}):
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case reflect.TypeOf(net.IP{}):
+		return map[string]any{"type": "string", "anyOf": []any{
+			map[string]any{"format": "ipv4"},
+			map[string]any{"format": "ipv6"},
+		}}
+	case reflect.TypeOf(URL("")):
+		return map[string]any{"type": "string", "format": "uri"}
+	case reflect.TypeOf(Email("")):
+		return map[string]any{"type": "string", "format": "email"}
+	}
+	switch ty.Kind() {
+	case reflect.Pointer:
+		inner := jsonSchemaOf(ty.Elem(), seen)
+		return map[string]any{"anyOf": []any{inner, map[string]any{"type": "null"}}}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": jsonSchemaOf(ty.Elem(), seen)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchemaOf(ty.Elem(), seen)}
+	case reflect.Struct:
+		if seen[ty] {
+			return map[string]any{}
+		}
+		seen[ty] = true
+		defer delete(seen, ty)
+		properties := map[string]any{}
+		for i := 0; i < ty.NumField(); i++ {
+			sf := ty.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name, _, skip := jsonMemberName(sf)
+			if skip {
+				continue
+			}
+			properties[name] = jsonSchemaOf(sf.Type, seen)
+		}
+		return map[string]any{"type": "object", "properties": properties}
+	}
+	// interfaces, funcs and channels can hold anything
+	return map[string]any{}
+}
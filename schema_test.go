@@ -0,0 +1,77 @@
+package fielder
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testSchemaNode struct {
+	Name     string            `json:"name"`
+	Children []*testSchemaNode `json:"children"`
+}
+
+type testSchemaItem struct {
+	ID       string          `json:"id" field:"ID"`
+	Count    int             `json:"count,omitempty" field:"Count"`
+	At       time.Time       `json:"at" field:"At"`
+	Nickname *string         `json:"nickname" field:"Nickname"`
+	Bio      *StringField    `json:"bio" field:"Bio"`
+	Region   FieldWDefault   `json:"region" field:"Region"`
+	Node     testSchemaNode  `json:"node" field:"Node"`
+	Hidden   string          `json:"-" field:"Hidden"`
+	Untagged string          `json:"untagged"`
+	Scores   map[string]bool `field:"Scores"`
+}
+
+func testSchema(t *testing.T) map[string]any {
+	t.Helper()
+	b, err := GenerateJSONSchema[testSchemaItem]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestGenerateJSONSchemaMembers(t *testing.T) {
+	s := testSchema(t)
+	props := s["properties"].(map[string]any)
+	keys := []string{}
+	for k := range props {
+		keys = append(keys, k)
+	}
+	if len(props) != 8 || props["Hidden"] != nil || props["untagged"] != nil {
+		t.Errorf("only tagged members json encodes are properties: got %v", keys)
+	}
+	want := []any{"id", "at", "node", "Scores"}
+	if !reflect.DeepEqual(s["required"], want) {
+		t.Errorf("pointers, defaults and omitempty are optional: got %v", s["required"])
+	}
+	if props["at"].(map[string]any)["format"] != "date-time" {
+		t.Errorf("got %v", props["at"])
+	}
+	if props["bio"].(map[string]any)["$ref"] != "#/$defs/field" || s["$defs"] == nil {
+		t.Errorf("members that are fields refer to the field schema: got %v", props["bio"])
+	}
+	nick := props["nickname"].(map[string]any)["anyOf"].([]any)
+	if len(nick) != 2 || nick[1].(map[string]any)["type"] != "null" {
+		t.Errorf("pointers are nullable: got %v", nick)
+	}
+}
+
+func TestGenerateJSONSchemaRecursive(t *testing.T) {
+	node := testSchema(t)["properties"].(map[string]any)["node"].(map[string]any)
+	children := node["properties"].(map[string]any)["children"].(map[string]any)
+	inner := children["items"].(map[string]any)["anyOf"].([]any)[0].(map[string]any)
+	if len(inner) != 0 {
+		t.Errorf("a recursive type ends in an empty schema: got %v", inner)
+	}
+	if _, err := GenerateJSONSchema[string](); err != ErrNotStruct {
+		t.Errorf("got %v", err)
+	}
+}
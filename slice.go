@@ -0,0 +1,222 @@
+package fielder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SliceField is an ordered list of fields that behaves as a single field
+// every element is expected to share the same type, ElemType
+type SliceField struct {
+	ValueField []Field      `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey     `dynamodbav:"key" json:"key"`
+	ElemType   reflect.Type `dynamodbav:"-" json:"-"`
+}
+
+func NewSliceField(key FieldKey, elemType reflect.Type, elems ...Field) *SliceField {
+	return &SliceField{
+		ValueField: elems,
+		KeyField:   key,
+		ElemType:   elemType,
+	}
+}
+
+// elements are keyed off the parent key with their index, ex: Tags[0], Tags[1]
+func sliceElemKey(key FieldKey, i int) FieldKey {
+	return NewFieldKey(fmt.Sprintf("%s[%d]", key.Name, i), key.Tag)
+}
+
+// creates a slice field from a slice of any type supported by CreateFieldFromType
+func createSliceField(ty reflect.Type, va any, fk FieldKey) Field {
+	if CreateFieldFromType(ty.Elem(), nil, fk) == nil {
+		// unsupported element type
+		return nil
+	}
+	s := NewSliceField(fk, ty.Elem())
+	if va == nil {
+		return s
+	}
+	rv := reflect.ValueOf(va)
+	for i := 0; i < rv.Len(); i++ {
+		s.ValueField = append(s.ValueField, CreateFieldFromType(ty.Elem(), rv.Index(i).Interface(), sliceElemKey(fk, i)))
+	}
+	return s
+}
+
+// the value is the raw slice, ex: []string for a slice of StringFields. use Fields to get the fields themselves
+// a nil element, or one whose value is nil or not an ElemType, is the zero value of ElemType
+func (s *SliceField) Value() FieldValue {
+	if s.ElemType == nil {
+		out := make([]FieldValue, 0, len(s.ValueField))
+		for _, v := range s.ValueField {
+			if v == nil {
+				out = append(out, nil)
+				continue
+			}
+			out = append(out, v.Value())
+		}
+		return out
+	}
+	out := reflect.MakeSlice(reflect.SliceOf(s.ElemType), 0, len(s.ValueField))
+	for _, v := range s.ValueField {
+		elem := reflect.Zero(s.ElemType)
+		if v != nil {
+			if rv := reflect.ValueOf(v.Value()); rv.IsValid() && rv.Type().AssignableTo(s.ElemType) {
+				elem = rv
+			}
+		}
+		out = reflect.Append(out, elem)
+	}
+	return out.Interface()
+}
+
+func (s *SliceField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *SliceField) Type() reflect.Type {
+	if s.ElemType == nil {
+		return reflect.TypeOf([]FieldValue{})
+	}
+	return reflect.SliceOf(s.ElemType)
+}
+
+// slices are ordered element by element, a shorter slice that is a prefix of a longer one is less
+func (s *SliceField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.compare(in2.(*SliceField)) < 0
+}
+
+func (s *SliceField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.compare(in2.(*SliceField)) > 0
+}
+
+func (s *SliceField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.compare(in2.(*SliceField)) == 0
+}
+
+func (s *SliceField) compare(s2 *SliceField) int {
+	for i := 0; i < len(s.ValueField) && i < len(s2.ValueField); i++ {
+		if c := compareElems(s.ValueField[i], s2.ValueField[i]); c != 0 {
+			return c
+		}
+	}
+	return len(s.ValueField) - len(s2.ValueField)
+}
+
+// -1, 0 or 1, a nil element is less than any other
+// types like bool have no ordering but still have to be equal, unequal ones are ordered by their string form so the order holds both ways
+func compareElems(a, b Field) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case a.LessThan(b):
+		return -1
+	case a.GreaterThan(b):
+		return 1
+	case a.Equal(b):
+		return 0
+	}
+	return strings.Compare(a.ToString(), b.ToString())
+}
+
+// the string form is a json array of the ToString of each element, ex: ["a","b"]
+func (s *SliceField) ToString() string {
+	out := make([]string, 0, len(s.ValueField))
+	for _, v := range s.ValueField {
+		if v == nil {
+			out = append(out, "")
+			continue
+		}
+		out = append(out, v.ToString())
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (s *SliceField) FromString(st string) {
+	var in []string
+	if err := json.Unmarshal([]byte(st), &in); err != nil {
+		return
+	}
+	elemType := s.ElemType
+	if elemType == nil {
+		elemType = reflect.TypeOf("")
+	}
+	elems := make([]Field, 0, len(in))
+	for i, v := range in {
+		f := CreateFieldFromType(elemType, nil, sliceElemKey(s.KeyField, i))
+		if f == nil {
+			return
+		}
+		f.FromString(v)
+		elems = append(elems, f)
+	}
+	s.ValueField = elems
+}
+
+func (s *SliceField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = append([]Field{}, in2.(*SliceField).ValueField...)
+	return
+}
+
+func (s *SliceField) IsEmpty() bool {
+	return len(s.ValueField) == 0
+}
+
+func (s *SliceField) Fields() []Field {
+	return s.ValueField
+}
+
+func (s *SliceField) Len() int {
+	return len(s.ValueField)
+}
+
+// returns FieldNil if the index is out of range
+func (s *SliceField) Get(i int) Field {
+	if i < 0 || i >= len(s.ValueField) {
+		return FieldNil
+	}
+	return s.ValueField[i]
+}
+
+func (s *SliceField) Append(in ...Field) {
+	s.ValueField = append(s.ValueField, in...)
+}
+
+func (s *SliceField) Contains(f Field) bool {
+	return s.IndexOf(f) >= 0
+}
+
+// returns the index of the first element equal to f, or -1 if there is none
+func (s *SliceField) IndexOf(f Field) int {
+	for i, v := range s.ValueField {
+		if v != nil && v.Equal(f) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,93 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testStrings(key string, values ...string) *SliceField {
+	return CreateFieldFromType(reflect.TypeOf([]string{}), values, NewDefaultFieldKey(key)).(*SliceField)
+}
+
+func TestSliceFieldFromType(t *testing.T) {
+	s := testStrings("Tags", "a", "b")
+	if s.Len() != 2 || s.Get(1).Key().Name != "Tags[1]" {
+		t.Fatalf("got %d elements, second keyed %s", s.Len(), s.Get(1).Key().Name)
+	}
+	if got := s.Value(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Value: got %#v", got)
+	}
+	if s.Get(5) != FieldNil {
+		t.Error("an index out of range is FieldNil")
+	}
+	if CreateFieldFromType(reflect.TypeOf([]chan int{}), nil, NewDefaultFieldKey("C")) != nil {
+		t.Error("an unsupported element type makes no field")
+	}
+}
+
+func TestSliceFieldValueNilElements(t *testing.T) {
+	s := NewSliceField(NewDefaultFieldKey("Tags"), reflect.TypeOf(""), &StringField{ValueField: "a"}, nil, FieldNil)
+	if got := s.Value(); !reflect.DeepEqual(got, []string{"a", "", ""}) {
+		t.Errorf("Value: got %#v", got)
+	}
+	untyped := NewSliceField(NewDefaultFieldKey("Any"), nil, &IntegerField{ValueField: 1}, nil)
+	if got := untyped.Value(); !reflect.DeepEqual(got, []FieldValue{1, nil}) {
+		t.Errorf("Value without an ElemType: got %#v", got)
+	}
+	if got := s.ToString(); got != `["a","",""]` {
+		t.Errorf("ToString: got %s", got)
+	}
+}
+
+func TestSliceFieldOrder(t *testing.T) {
+	ab, ac, abc := testStrings("T", "a", "b"), testStrings("T", "a", "c"), testStrings("T", "a", "b", "c")
+	if !ab.LessThan(ac) || !ac.GreaterThan(ab) {
+		t.Error("slices are ordered element by element")
+	}
+	if !ab.LessThan(abc) || !abc.GreaterThan(ab) {
+		t.Error("a prefix is less than the longer slice")
+	}
+	if !ab.Equal(testStrings("T", "a", "b")) || ab.Equal(ac) {
+		t.Error("expected equal elements to make equal slices")
+	}
+}
+
+func TestSliceFieldOrderIsAntisymmetric(t *testing.T) {
+	key := NewDefaultFieldKey("Flags")
+	yes := NewSliceField(key, reflect.TypeOf(true), NewBool(key, true))
+	no := NewSliceField(key, reflect.TypeOf(true), NewBool(key, false))
+	if yes.LessThan(no) == no.LessThan(yes) {
+		t.Error("of two unequal slices exactly one is less")
+	}
+	if yes.GreaterThan(no) == no.GreaterThan(yes) {
+		t.Error("of two unequal slices exactly one is greater")
+	}
+	withNil := NewSliceField(key, reflect.TypeOf(true), nil)
+	if !withNil.LessThan(no) || !no.GreaterThan(withNil) || withNil.GreaterThan(no) {
+		t.Error("a nil element is less than any other")
+	}
+}
+
+func TestSliceFieldString(t *testing.T) {
+	s := testStrings("Tags")
+	s.FromString(`["x","y"]`)
+	if !reflect.DeepEqual(s.Value(), []string{"x", "y"}) || s.Get(0).Key().Name != "Tags[0]" {
+		t.Fatalf("FromString: got %#v", s.Value())
+	}
+	s.FromString("not json")
+	if s.Len() != 2 {
+		t.Error("invalid json changed the value")
+	}
+	other := testStrings("Tags")
+	other.SetValue(s)
+	s.Append(&StringField{ValueField: "z"})
+	if other.Len() != 2 {
+		t.Error("SetValue shares the element slice")
+	}
+	if !other.Contains(&StringField{ValueField: "y"}) || other.IndexOf(&StringField{ValueField: "q"}) != -1 {
+		t.Error("Contains and IndexOf look elements up by Equal")
+	}
+	if other.IsEmpty() || !testStrings("Tags").IsEmpty() {
+		t.Error("only a slice without elements is empty")
+	}
+}
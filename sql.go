@@ -0,0 +1,220 @@
+package fielder
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// fields can be used directly as sql scan targets, ex: row.Scan(&nameField) or row.Scan(nameField)
+// they can not implement driver.Valuer themselves because Field already has a Value method,
+// so wrap them with SQLValue when passing them as query arguments, ex: db.Exec(q, SQLValue(nameField))
+// a NULL column resets the field to its zero value, or to null for a NullableField
+
+// SQLColumn adapts a field to driver.Valuer and sql.Scanner
+type SQLColumn struct {
+	Field
+}
+
+func SQLValue(f Field) SQLColumn {
+	return SQLColumn{Field: f}
+}
+
+// numbers, bools, times and bytes are passed as their native driver types, everything else as its string form
+func (c SQLColumn) Value() (driver.Value, error) {
+	return fieldToDriverValue(c.Field)
+}
+
+func (c SQLColumn) Scan(src any) error {
+	return scanField(c.Field, src)
+}
+
+func fieldToDriverValue(f Field) (driver.Value, error) {
+	if f == nil || f.Value() == nil {
+		return nil, nil
+	}
+	if IsSensitive(f) {
+		return nil, sensitiveFieldError(f)
+	}
+	switch v := f.(type) {
+	case *StringField:
+		return v.ValueField, nil
+	case *TimeField:
+		if v.ValueField.IsZero() {
+			return nil, nil
+		}
+		return v.ValueField, nil
+	case *DateField:
+		if v.ValueField.IsZero() {
+			return nil, nil
+		}
+		return v.ValueField.Time(), nil
+	case *IntegerField:
+		return int64(v.ValueField), nil
+	case *CounterField:
+		return v.Load(), nil
+	case *FloatField:
+		return v.ValueField, nil
+	case *BoolField:
+		if !v.Set {
+			return nil, nil
+		}
+		return v.ValueField, nil
+	case *BytesField:
+		return v.ValueField, nil
+	case *DecimalField:
+		return v.ToString(), nil
+	case *EmptyField:
+		return nil, nil
+	default:
+		if nullable, ok := f.(Nullable); ok && nullable.IsNull() {
+			return nil, nil
+		}
+		return f.ToString(), nil
+	}
+}
+
+func scanField(f Field, src any) error {
+	var st string
+	switch v := src.(type) {
+	case nil:
+		resetField(f)
+		return nil
+	case string:
+		st = v
+	case []byte:
+		if b, ok := f.(*BytesField); ok {
+			b.ValueField = append([]byte{}, v...)
+			return nil
+		}
+		st = string(v)
+	case int64:
+		st = strconv.FormatInt(v, 10)
+	case float64:
+		st = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		st = strconv.FormatBool(v)
+	case time.Time:
+		switch t := f.(type) {
+		case *TimeField:
+			t.ValueField = t.normalize(v)
+			return nil
+		case *DateField:
+			t.ValueField = DateOf(v)
+			return nil
+		}
+		st = v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Errorf("fielder: cannot scan %T into %T", src, f)
+	}
+	return ParseFieldString(f, st)
+}
+
+func (s *StringField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *TimeField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *DecimalField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *IntegerField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *BoolField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *FloatField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *DurationField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *UUIDField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *BytesField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *DateField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *MoneyField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *QuantityField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *URLField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *EmailField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *IPField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *CIDRField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *GeoPointField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *EnumField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *CounterField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *SliceField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *MapField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+func (s *StructField) Scan(src any) error {
+	return scanField(s, src)
+}
+
+// the wrappers scan straight into the field they hold, the same way FromString does
+// a conditional field scans through its conditional when it is checked on decode, see CheckOnDecode
+
+func (s *FieldWDefaultImpl) Scan(src any) error {
+	return scanField(s.Field, src)
+}
+
+func (s *conditionalFieldWDefault) Scan(src any) error {
+	return s.FieldConditional.Scan(src)
+}
+
+func (s *FieldNullable) Scan(src any) error {
+	if src == nil {
+		s.Clear()
+		return nil
+	}
+	s.Null = false
+	return scanField(s.Field, src)
+}
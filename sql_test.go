@@ -0,0 +1,95 @@
+package fielder
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLValue(t *testing.T) {
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for name, tc := range map[string]struct {
+		f    Field
+		want any
+	}{
+		"string":     {&StringField{ValueField: "x"}, "x"},
+		"integer":    {&IntegerField{ValueField: 7}, int64(7)},
+		"counter":    {NewCounterField(FieldKeyNil, 8), int64(8)},
+		"float":      {&FloatField{ValueField: 0.5}, 0.5},
+		"bool":       {NewBool(FieldKeyNil, false), false},
+		"unset bool": {&BoolField{}, nil},
+		"decimal":    {testDecimal(t, "1.25"), "1.25"},
+		"time":       {NewTimeField(FieldKeyNil, at), at},
+		"zero time":  {&TimeField{}, nil},
+		"duration":   {&DurationField{ValueField: time.Second}, "1s"},
+		"null":       {NewNullField(&StringField{}), nil},
+		"nil":        {nil, nil},
+	} {
+		got, err := SQLValue(tc.f).Value()
+		if err != nil || got != tc.want {
+			t.Errorf("%s: got %#v %v", name, got, err)
+		}
+	}
+}
+
+func TestSQLScan(t *testing.T) {
+	i := &IntegerField{}
+	if err := i.Scan(int64(12)); err != nil || i.ValueField != 12 {
+		t.Errorf("int64: got %d %v", i.ValueField, err)
+	}
+	d := testDecimal(t, "0")
+	if err := d.Scan([]byte("3.50")); err != nil || d.ToString() != "3.5" {
+		t.Errorf("[]byte: got %s %v", d.ToString(), err)
+	}
+	b := &BoolField{}
+	if err := b.Scan(true); err != nil || !b.ValueField || !b.Set {
+		t.Errorf("bool: got %#v %v", b, err)
+	}
+	if err := b.Scan(nil); err != nil || b.Set {
+		t.Errorf("NULL leaves a bool unset: got %#v %v", b, err)
+	}
+	raw := []byte{1, 2}
+	by := &BytesField{}
+	by.Scan(raw)
+	raw[0] = 9
+	if !bytes.Equal(by.ValueField, []byte{1, 2}) {
+		t.Errorf("scanned bytes are copied: got %v", by.ValueField)
+	}
+	ny, _ := time.LoadLocation("America/New_York")
+	tf := &TimeField{}
+	tf.Scan(time.Date(2025, 1, 1, 12, 0, 0, 0, ny))
+	if tf.ValueField.Location() != time.UTC || tf.ValueField.Hour() != 17 {
+		t.Errorf("times are normalized: got %s", tf.ValueField)
+	}
+	if err := i.Scan(struct{}{}); err == nil {
+		t.Error("unsupported sources are an error")
+	}
+	if err := i.Scan("twelve"); err == nil {
+		t.Error("a value that does not parse is an error")
+	}
+}
+
+func TestSQLScanWrappers(t *testing.T) {
+	n := NewNullableField(&StringField{ValueField: "x"}).(*FieldNullable)
+	if err := n.Scan(nil); err != nil || !n.IsNull() {
+		t.Errorf("NULL makes a nullable field null: got %#v", n)
+	}
+	if err := n.Scan("y"); err != nil || n.IsNull() || n.ToString() != "y" {
+		t.Errorf("got %q null=%v", n.ToString(), n.IsNull())
+	}
+	col := SQLValue(&IntegerField{})
+	if err := col.Scan(int64(3)); err != nil || col.Field.Value() != 3 {
+		t.Errorf("SQLColumn scans into its field: got %v %v", col.Field.Value(), err)
+	}
+	w := NewFieldWDefault(&StringField{}, NewDefault(false, &StringField{})).(*FieldWDefaultImpl)
+	if err := w.Scan("z"); err != nil || w.ToString() != "z" {
+		t.Errorf("got %q %v", w.ToString(), err)
+	}
+}
+
+func TestSQLValueSensitive(t *testing.T) {
+	if v, err := SQLValue(NewRedactedField(testField("Token", "abc"))).Value(); !errors.Is(err, ErrSensitiveField) || v != nil {
+		t.Errorf("got %v %v", v, err)
+	}
+}
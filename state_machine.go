@@ -2,8 +2,13 @@ package fielder
 
 import (
 	"container/ring"
+	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"sort"
 	"sync"
+	"time"
 )
 
 // will be an implementation of a state machine which does not use switches (switches at scale are hard to read)
@@ -25,6 +30,19 @@ type StateId string
 
 var SameStateNoUpdate = errors.New("state has no transition, is terminal, no update")
 
+// returned by the value based API when more than one state holds the value
+var ErrAmbiguousStateValue = errors.New("state value is held by more than one state")
+
+// the errors returned by processing, wrapped with the ids involved so callers can branch with errors.Is
+var (
+	ErrUnknownState  = errors.New("state does not exist in machine")
+	ErrNoTransition  = errors.New("no valid transitions available")
+	ErrNilStateValue = errors.New("state value is nil")
+	ErrEmptyNextId   = errors.New("next id is empty")
+	ErrStateType     = errors.New("ring holds a value that is not a state")
+	ErrNoBehavior    = errors.New("no mapped behavior available for state value")
+)
+
 // the state id represents how states refer to each other
 
 type StateMachine struct {
@@ -36,71 +54,219 @@ type StateMachine struct {
 	ValueCache map[StateId]StateValue // the StateValue is the value of each state. if a state machine has nodes 0, 1, 2, 3,  then each number is the value
 	// of a state in the machine. if the state machine has nodes "first", "next", "then", "last", then each string is the value of a state in the machine
 
+	startAddr RingAddress      // the address of the first state, the ring is always walked from here so it is never moved after it is populated
+	hooks     []TransitionHook // called after every transition, see OnTransition
+	recorder  *HistoryRecorder // records every call to ProcessInMachine, see RecordHistory
+	strict    bool             // report overlapping transitions, see SetStrict
+	subs      []*Subscription  // sent every transition after the hooks, see Subscribe
+
 	// we parse the initial ring and create the value cache at instantiation. Because i want to protect our state machines and keep them simple, we will not allow
 	// writing to the state machine once its created. if you need to change it, just create a new one with the states you want
 }
 
-func (sm *StateMachine) lookupValueCacheId(in StateValue, equals func(i, j StateValue) bool) StateId {
+// rLock and lock take the mutex and return the matching unlock, a machine created as a literal has no mutex to take
+func (sm *StateMachine) rLock() func() {
+	if sm.mu == nil {
+		return func() {}
+	}
+	sm.mu.RLock()
+	return sm.mu.RUnlock
+}
+
+func (sm *StateMachine) lock() func() {
+	if sm.mu == nil {
+		return func() {}
+	}
+	sm.mu.Lock()
+	return sm.mu.Unlock
+}
+
+// returns the states in the ring in the order the machine was created with
+func (sm *StateMachine) ringStates() []any {
+	if sm == nil {
+		return nil
+	}
+	defer sm.rLock()()
+	start := sm.startAddr
+	if start == nil {
+		start = sm.Ring
+	}
+	out := []any{}
+	(*ring.Ring)(start).Do(func(v any) {
+		out = append(out, v)
+	})
+	return out
+}
+
+// returns the state held in the ring for the id
+func (sm *StateMachine) lookupRingState(id StateId) (any, error) {
+	defer sm.rLock()()
+	addr, ok := sm.IdRingAddressCache[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownState, id)
+	}
+	if addr == nil {
+		return nil, fmt.Errorf("%w: %q has no address", ErrUnknownState, id)
+	}
+	return addr.Value, nil
+}
+
+func (sm *StateMachine) lookupValue(id StateId) (StateValue, bool) {
+	defer sm.rLock()()
+	v, ok := sm.ValueCache[id]
+	return v, ok
+}
+
+// finds the id of the state holding the value, the value based API can only be used when the value belongs to one state
+func (sm *StateMachine) lookupValueCacheId(in StateValue, equals func(i, j StateValue) bool) (StateId, error) {
+	defer sm.rLock()()
+	var found []StateId
 	for k, v := range sm.ValueCache {
 		if equals(v, in) {
-			return k
+			found = append(found, k)
 		}
 	}
-	return ""
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("%w: no state has the value %v", ErrUnknownState, in)
+	case 1:
+		return found[0], nil
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i] < found[j] })
+	return "", fmt.Errorf("%w: %v is held by %v, use ProcessFromId", ErrAmbiguousStateValue, in, found)
 }
 
 // "in" is the current state value, "testData" is the data that will be tested by the conditional questions to determine next state
 // "equals" is a function that allows us to compare values without knowing the exact type ahead of time
+// the state is found by its value, so this only works for values held by one state, ProcessFromId works for any machine
 func (sm *StateMachine) ProcessInMachine(in StateValue, testData any, equals func(i, j StateValue) bool) (StateValue, error) {
-	stateId := sm.lookupValueCacheId(in, equals)
-	// evaluate state with id stateId
-	currentAddr, ok := sm.IdRingAddressCache[stateId]
-	if !ok {
-		return nil, errors.New("id does not exist in machine")
+	return sm.ProcessInMachineCtx(context.Background(), in, testData, equals)
+}
+
+// ProcessInMachineCtx is ProcessInMachine with a context that is checked before every matcher, passed to context matchers,
+// and handed to the hooks in the TransitionEvent
+func (sm *StateMachine) ProcessInMachineCtx(ctx context.Context, in StateValue, testData any, equals func(i, j StateValue) bool) (StateValue, error) {
+	if err := ctx.Err(); err != nil {
+		sm.recordHistory(HistoryEntry{Input: in, Data: testData}, nil, err)
+		return nil, err
+	}
+	id, err := sm.lookupValueCacheId(in, equals)
+	if err != nil {
+		sm.recordHistory(HistoryEntry{Input: in, Data: testData}, nil, err)
+		return nil, err
+	}
+	_, value, err := sm.processFromId(ctx, id, in, testData, "")
+	return value, err
+}
+
+// ProcessFromId evaluates the state with the id against the data, and returns the id and value of the next state
+// states are found by id rather than by value, so states can share a value
+func (sm *StateMachine) ProcessFromId(current StateId, testData any) (StateId, StateValue, error) {
+	return sm.ProcessFromIdCtx(context.Background(), current, testData)
+}
+
+func (sm *StateMachine) ProcessFromIdCtx(ctx context.Context, current StateId, testData any) (StateId, StateValue, error) {
+	in, _ := sm.lookupValue(current)
+	return sm.processFromId(ctx, current, in, testData, "")
+}
+
+// evaluates the state with the id, fires the hooks and records the call, the next id is returned when it is known even if there is an error
+// an event of "" evaluates the matcher transitions, any other event evaluates the transitions for that event
+func (sm *StateMachine) processFromId(ctx context.Context, stateId StateId, in StateValue, testData any, event string) (StateId, StateValue, error) {
+	nextId, value, fire, err := sm.planFromId(ctx, stateId, in, testData, event)
+	fire()
+	return nextId, value, err
+}
+
+// processFromId without firing the hooks, they are run by calling fire, ex: once the caller has released its own lock
+func (sm *StateMachine) planFromId(ctx context.Context, stateId StateId, in StateValue, testData any, event string) (StateId, StateValue, func(), error) {
+	return sm.planMove(ctx, stateId, in, testData, event, func(current any) (StateId, error) {
+		s, _ := asMachineState(current)
+		if event != "" {
+			return s.EvaluateEventCtx(ctx, event, testData)
+		}
+		next, _, err := s.selectTransition(ctx, testData, sm.isStrict())
+		return next, err
+	})
+}
+
+// moves from the state with the id to the state chosen by evaluate, which is given the state held in the ring
+func (sm *StateMachine) moveFromId(ctx context.Context, stateId StateId, in StateValue, testData any, event string, evaluate func(current any) (StateId, error)) (StateId, StateValue, error) {
+	nextId, value, fire, err := sm.planMove(ctx, stateId, in, testData, event, evaluate)
+	fire()
+	return nextId, value, err
+}
+
+// works out the move and records it, fire runs the hooks and notifies the subscriptions of the move, it does nothing when there was no move
+func (sm *StateMachine) planMove(ctx context.Context, stateId StateId, in StateValue, testData any, event string, evaluate func(current any) (StateId, error)) (nextId StateId, out StateValue, fire func(), err error) {
+	fire = func() {}
+	entry := HistoryEntry{Input: in, Data: testData, From: stateId, Event: event}
+	defer func() {
+		entry.To = nextId
+		sm.recordHistory(entry, out, err)
+	}()
+	if err := ctx.Err(); err != nil {
+		return "", nil, fire, err
 	}
-	if currentAddr == nil {
-		return nil, errors.New("address does not exist in machine")
+	// evaluate state with id stateId
+	current, err := sm.lookupRingState(stateId)
+	if err != nil {
+		return "", nil, fire, err
 	}
-	currentState, ok := currentAddr.Value.(State)
+	s, ok := asMachineState(current)
 	if !ok {
-		return nil, errors.New("error retrieving state")
+		return "", nil, fire, fmt.Errorf("%w: %T", ErrStateType, current)
 	}
-	nextId, err := currentState.EvaluateTransition(testData)
+	fromValue := s.Value()
+	nextId, err = evaluate(current)
 	if err != nil {
-		return nil, err
+		return nextId, nil, fire, err
 	}
 	if nextId == "" {
-		return nil, errors.New("next id is empty")
+		return "", nil, fire, fmt.Errorf("state %s: %w", stateId, ErrEmptyNextId)
 	}
-	if nextId == stateId {
+	// conditional states always move to the state their outcome names, so only plain and terminal states report SameStateNoUpdate
+	if _, conditional := s.(*ConditionalState); nextId == stateId && (!conditional || s.IsTerminal()) {
 		// we havent switched states, return the same
-		return nil, SameStateNoUpdate
+		return nextId, nil, fire, SameStateNoUpdate
 	}
 
-	value, ok := sm.ValueCache[nextId]
+	value, ok := sm.lookupValue(nextId)
 	if !ok {
-		return nil, errors.New("next id does not exist in machine")
+		return nextId, nil, fire, fmt.Errorf("next %w: %q", ErrUnknownState, nextId)
 	}
 	if value == nil {
-		return nil, errors.New("value is nil for next id")
+		return nextId, nil, fire, fmt.Errorf("state %s: %w", nextId, ErrNilStateValue)
+	}
+	next, _ := sm.lookupRingState(nextId)
+	fire = func() {
+		sm.fireTransition(current, next, TransitionEvent{From: stateId, To: nextId, FromValue: fromValue, ToValue: value, Data: testData, Event: event, Context: ctx})
 	}
-	return value, nil
+	return nextId, value, fire, nil
 }
 
 func (sm *StateMachine) PopulateRing(in ...State) {
+	defer sm.lock()()
 	// we already created the ring and make it length "len(in)" so we know we can iterate safely through the items in the ring
 	// Initialize the ring with the states. create the id -> ring address cache and the id -> value cache
+	// a separate cursor walks the ring, so sm.Ring stays on the first state
+	addr := sm.Ring
+	sm.startAddr = addr
 	for _, v := range in {
-		sm.Ring.Value = v
-		sm.IdRingAddressCache[v.Id] = sm.Ring
+		addr.Value = v
+		sm.IdRingAddressCache[v.Id] = addr
 		sm.ValueCache[v.Id] = v.StateValue
-		sm.Ring = sm.Ring.Next()
+		addr = addr.Next()
 	}
 }
 
 func NewStateMachine(states ...State) *StateMachine {
 	if len(states) == 0 {
-		return &StateMachine{}
+		return &StateMachine{
+			mu:                 new(sync.RWMutex),
+			IdRingAddressCache: make(map[StateId]RingAddress),
+			ValueCache:         make(map[StateId]StateValue),
+		}
 	}
 	sm := &StateMachine{
 		Ring:               ring.New(len(states)),
@@ -116,35 +282,112 @@ func NewStateMachine(states ...State) *StateMachine {
 type StateValue FieldValue
 
 type State struct {
-	Id         StateId      // since rings dont have a beginning or end, we give each state an id so that we can find it later
-	Matches    []Transition // the different transitions from this current state
-	StateValue              // what is the value at this state?
-	Start      bool         // is this the start state for the machine?
-	Terminal   bool         // is this an end state for the machine? (no more transitions are needed)
+	Id         StateId        // since rings dont have a beginning or end, we give each state an id so that we can find it later
+	Matches    []Transition   // the different transitions from this current state
+	StateValue                // what is the value at this state?
+	Start      bool           // is this the start state for the machine?
+	Terminal   bool           // is this an end state for the machine? (no more transitions are needed)
+	OnEnter    TransitionHook // called after the machine moves into this state
+	OnExit     TransitionHook // called after the machine moves out of this state
+	Else       StateId        // the state to move to when no transition matches, optional
+	Retry      *RetryPolicy   // how a MachineInstance retries the state when no transition matches, optional
 }
 
 func (s *State) EvaluateTransition(dataToTest any) (StateId, error) {
+	return s.EvaluateTransitionCtx(context.Background(), dataToTest)
+}
+
+// the context is checked before each transition is tested, so a cancelled context stops a slow guard from being started
+// transitions are tested in priority order, and the Else state is taken when none of them match
+func (s *State) EvaluateTransitionCtx(ctx context.Context, dataToTest any) (StateId, error) {
+	return s.evaluateTransitions(ctx, dataToTest, false)
+}
+
+// in strict mode every transition is tested, and it is an error when more than one matches
+func (s *State) evaluateTransitions(ctx context.Context, dataToTest any, strict bool) (StateId, error) {
+	next, _, err := s.selectTransition(ctx, dataToTest, strict)
+	return next, err
+}
+
+// also returns the index of the transition that was taken, -1 when it was the Else state or the state is terminal
+func (s *State) selectTransition(ctx context.Context, dataToTest any, strict bool) (StateId, int, error) {
 	if s.Terminal {
-		return s.Id, nil
+		return s.Id, -1, nil
 	}
-	for _, v := range s.Matches {
-		if v.SimpleMatcher(dataToTest) {
-			return v.NextState, nil
+	var matched []int
+	for _, i := range transitionOrder(len(s.Matches), func(i int) int { return s.Matches[i].Priority }) {
+		v := s.Matches[i]
+		if v.Event != "" || v.After > 0 {
+			// event transitions are only taken by Fire, and timed transitions by Expire
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return "", -1, err
+		}
+		ok, err := v.matches(ctx, dataToTest)
+		if err != nil {
+			return "", -1, &GuardError{State: s.Id, NextState: v.NextState, Err: err}
+		}
+		if ok {
+			if !strict {
+				return v.NextState, i, nil
+			}
+			matched = append(matched, i)
 		}
 	}
-	return "", errors.New("no valid transitions available")
+	return chooseTransition(s.Id, s.Else, matched, func(i int) StateId { return s.Matches[i].NextState })
 }
 
-// note, each transition matcher must be mutually exclusive
-// only one transition of a state should evaluate to "true" at a time, there should be no overlap between transitions
+// note, each transition matcher should be mutually exclusive
+// when they overlap the transition with the highest priority wins, then the first one in the slice, see SetStrict to report the overlap instead
 type Transition struct {
 	NextState     StateId
 	SimpleMatcher // matcher is a much simpler function type to support whether we are eligible to move to this next state
+	// used instead of the SimpleMatcher when it is set, for guards that call out to other services
+	ContextMatcher ContextMatcher
+	// used instead of both matchers when it is set, for guards that can fail, see GuardError
+	Guard GuardMatcher
+	// the name the matcher is registered under, needed to save the machine, see RegisterMatcher
+	MatcherName string
+	// a transition with an event is only taken when the event is fired, the matcher is then an optional guard on the payload
+	Event string
+	// a timed transition is taken once the machine has been in the state for the duration, see MachineInstance.Expire
+	After time.Duration
+	// transitions with a higher priority are tested first, transitions with the same priority are tested in slice order
+	Priority int
+	// the relative chance of the transition being taken by a weighted step, see MachineInstance.StepWeighted
+	Weight float64
 }
 
 type SimpleMatcher func(inputToMatch any) bool
 
+// ContextMatcher is a SimpleMatcher that is given the context of the ProcessInMachineCtx call
+type ContextMatcher func(ctx context.Context, inputToMatch any) bool
+
+func (t Transition) matches(ctx context.Context, dataToTest any) (bool, error) {
+	switch {
+	case t.Guard != nil:
+		return t.Guard(ctx, dataToTest)
+	case t.ContextMatcher != nil:
+		return t.ContextMatcher(ctx, dataToTest), nil
+	}
+	return t.SimpleMatcher(dataToTest), nil
+}
+
+// an event transition with no matcher is taken whenever its event is fired
+func (t Transition) guarded() bool {
+	return t.Guard != nil || t.ContextMatcher != nil || t.SimpleMatcher != nil
+}
+
+// BasicEquals compares with ==, values of a type that can not be compared with == are compared with reflect.DeepEqual instead of panicking
 func BasicEquals(s1, s2 StateValue) bool {
+	t1, t2 := reflect.TypeOf(s1), reflect.TypeOf(s2)
+	if t1 != t2 {
+		return false
+	}
+	if t1 != nil && !t1.Comparable() {
+		return reflect.DeepEqual(s1, s2)
+	}
 	return s1 == s2
 }
 
@@ -155,7 +398,7 @@ func NextBehavior[inputType any, behaviorType any](sm *StateMachine, currentStat
 	}
 	behavior, ok := mapper[nextValue]
 	if !ok {
-		return "", *new(behaviorType), errors.New("no mapped behavior available for state value")
+		return "", *new(behaviorType), fmt.Errorf("%w: %v", ErrNoBehavior, nextValue)
 	}
 	return nextValue, behavior, nil
 }
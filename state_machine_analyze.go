@@ -0,0 +1,174 @@
+package fielder
+
+import "sort"
+
+// MachineAnalysis is the result of Analyze, the states are in the order the machine was created with
+type MachineAnalysis struct {
+	// states that can not be reached from the start state
+	Unreachable []StateId
+	// states that have no path to a terminal state, every state is here when the machine has no terminal states
+	NoTerminalPath []StateId
+	// groups of states that can move between each other but never leave the group, each one traps an entity that enters it
+	Traps [][]StateId
+}
+
+// Clean reports whether the analysis found nothing
+func (a MachineAnalysis) Clean() bool {
+	return len(a.Unreachable) == 0 && len(a.NoTerminalPath) == 0 && len(a.Traps) == 0
+}
+
+// Analyze follows the transitions of the machine, every kind of transition counts as a way out of a state whether or not it is ever taken
+// transitions to states that are not in the machine are ignored, Validate reports those
+func (sm *StateMachine) Analyze() MachineAnalysis {
+	var order []StateId
+	next := map[StateId][]StateId{}
+	prev := map[StateId][]StateId{}
+	terminal := map[StateId]bool{}
+	start := sm.Start
+	states := sm.ringStates()
+	for _, v := range states {
+		id, _, isStart := ringStateInfo(v)
+		if _, ok := next[id]; ok || id == "" {
+			continue
+		}
+		order = append(order, id)
+		next[id] = nil
+		if _, t := ringStateEdges(v); t {
+			terminal[id] = true
+		}
+		if start == "" && isStart {
+			start = id
+		}
+	}
+	for _, v := range states {
+		id, out, _ := ringStateInfo(v)
+		for _, n := range out {
+			if _, ok := next[n]; ok {
+				next[id] = append(next[id], n)
+				prev[n] = append(prev[n], id)
+			}
+		}
+	}
+	if start == "" && len(order) > 0 {
+		start = order[0]
+	}
+
+	out := MachineAnalysis{}
+	reachable := walkStates([]StateId{start}, next)
+	var terminals []StateId
+	for _, id := range order {
+		if terminal[id] {
+			terminals = append(terminals, id)
+		}
+	}
+	live := walkStates(terminals, prev)
+	for _, id := range order {
+		if !reachable[id] {
+			out.Unreachable = append(out.Unreachable, id)
+		}
+		if !live[id] {
+			out.NoTerminalPath = append(out.NoTerminalPath, id)
+		}
+	}
+	for _, group := range stronglyConnected(order, next) {
+		if stateGroupTraps(group, next, terminal) {
+			out.Traps = append(out.Traps, group)
+		}
+	}
+	return out
+}
+
+// returns the states that can be reached from the roots by following the edges
+func walkStates(roots []StateId, edges map[StateId][]StateId) map[StateId]bool {
+	seen := map[StateId]bool{}
+	queue := append([]StateId{}, roots...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		queue = append(queue, edges[id]...)
+	}
+	return seen
+}
+
+// a group traps when it is a cycle with no terminal state and no transition out of it
+func stateGroupTraps(group []StateId, next map[StateId][]StateId, terminal map[StateId]bool) bool {
+	in := map[StateId]bool{}
+	for _, id := range group {
+		if terminal[id] {
+			return false
+		}
+		in[id] = true
+	}
+	cycle := len(group) > 1
+	for _, id := range group {
+		for _, n := range next[id] {
+			if !in[n] {
+				return false
+			}
+			if n == id {
+				cycle = true
+			}
+		}
+	}
+	return cycle
+}
+
+// tarjan's algorithm, the groups and the states in each are in the order the states are given
+func stronglyConnected(order []StateId, next map[StateId][]StateId) [][]StateId {
+	index := map[StateId]int{}
+	low := map[StateId]int{}
+	onStack := map[StateId]bool{}
+	var stack []StateId
+	var groups [][]StateId
+	var visit func(id StateId)
+	visit = func(id StateId) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, n := range next[id] {
+			if _, ok := index[n]; !ok {
+				visit(n)
+				low[id] = min(low[id], low[n])
+			} else if onStack[n] {
+				low[id] = min(low[id], index[n])
+			}
+		}
+		if low[id] != index[id] {
+			return
+		}
+		member := map[StateId]bool{}
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+			member[n] = true
+			if n == id {
+				break
+			}
+		}
+		var group []StateId
+		for _, s := range order {
+			if member[s] {
+				group = append(group, s)
+			}
+		}
+		groups = append(groups, group)
+	}
+	for _, id := range order {
+		if _, ok := index[id]; !ok {
+			visit(id)
+		}
+	}
+	// groups are found deepest first, put them in the order of their first state
+	position := map[StateId]int{}
+	for i, id := range order {
+		position[id] = i
+	}
+	sort.Slice(groups, func(i, j int) bool { return position[groups[i][0]] < position[groups[j][0]] })
+	return groups
+}
@@ -0,0 +1,56 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	to := func(ids ...StateId) []Transition {
+		var out []Transition
+		for _, id := range ids {
+			out = append(out, Transition{NextState: id, SimpleMatcher: testIsGo})
+		}
+		return out
+	}
+	sm := NewStateMachine(
+		State{Id: "start", Start: true, Matches: to("work", "spin")},
+		State{Id: "work", Matches: to("done")},
+		State{Id: "done", Terminal: true},
+		// spin and wait move between each other and can never finish
+		State{Id: "spin", Matches: to("wait")},
+		State{Id: "wait", Matches: to("spin")},
+		// stuck loops on itself
+		State{Id: "orphan", Matches: to("stuck")},
+		State{Id: "stuck", Matches: to("stuck", "missing")},
+	)
+	got := sm.Analyze()
+	want := MachineAnalysis{
+		Unreachable:    []StateId{"orphan", "stuck"},
+		NoTerminalPath: []StateId{"spin", "wait", "orphan", "stuck"},
+		Traps:          [][]StateId{{"spin", "wait"}, {"stuck"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v", got)
+	}
+	if got.Clean() {
+		t.Error("the analysis found problems")
+	}
+}
+
+func TestAnalyzeClean(t *testing.T) {
+	// a cycle with a way out is not a trap
+	sm := NewStateMachine(
+		State{Id: "a", Start: true, Matches: []Transition{{NextState: "b", SimpleMatcher: testIsGo}}},
+		State{Id: "b", Matches: []Transition{{NextState: "a", SimpleMatcher: testIsGo}}, Else: "end"},
+		State{Id: "end", Terminal: true},
+	)
+	if got := sm.Analyze(); !got.Clean() {
+		t.Errorf("got %+v", got)
+	}
+	// without a terminal state no state can finish
+	light := NewStateMachine(testLightStates()...).Analyze()
+	if len(light.NoTerminalPath) != 3 || len(light.Traps) != 1 || len(light.Unreachable) != 0 {
+		t.Errorf("got %+v", light)
+	}
+}
@@ -0,0 +1,119 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrBuilderNoState   = errors.New("transition added before any state")
+	ErrBuilderNoMatcher = errors.New("GoTo called without a matcher from On")
+	ErrBuilderNoGoTo    = errors.New("On called without a GoTo for the matcher")
+)
+
+// MachineBuilder assembles the states of a machine one call at a time, ex:
+// NewMachineBuilder().State("draft", "DRAFT").On(submitted).GoTo("review").State("review", "REVIEW").On(approved).GoTo("done").Terminal("done").Build()
+// transitions are added to the state most recently added with State, and the first state is the start state
+// mistakes in the call order are collected and returned from Build along with the problems found by Validate
+type MachineBuilder struct {
+	states  []State
+	current int
+	pending SimpleMatcher
+	// the registered name of the pending matcher, empty when it was given to On directly
+	pendingName string
+	// the event of the pending transition, see OnEvent
+	pendingEvent string
+	// the duration of the pending timed transition, see After
+	pendingAfter time.Duration
+	errs         []error
+}
+
+func NewMachineBuilder() *MachineBuilder {
+	return &MachineBuilder{current: -1}
+}
+
+// State adds a state and makes it the state that On and GoTo add transitions to
+func (b *MachineBuilder) State(id StateId, value StateValue) *MachineBuilder {
+	b.closePending()
+	b.states = append(b.states, State{Id: id, StateValue: value, Start: len(b.states) == 0})
+	b.current = len(b.states) - 1
+	return b
+}
+
+// On starts a transition from the current state, it is finished by GoTo
+func (b *MachineBuilder) On(matcher SimpleMatcher) *MachineBuilder {
+	b.closePending()
+	if b.current < 0 {
+		b.errs = append(b.errs, ErrBuilderNoState)
+		return b
+	}
+	b.pending = matcher
+	b.pendingName = ""
+	b.pendingEvent = ""
+	b.pendingAfter = 0
+	return b
+}
+
+// OnNamed is On with the matcher registered under the name in the DefaultRegistry, a machine built this way can be saved
+func (b *MachineBuilder) OnNamed(name string) *MachineBuilder {
+	m, ok := LookupMatcher(name)
+	if !ok {
+		b.closePending()
+		b.errs = append(b.errs, fmt.Errorf("%w: %s", ErrUnknownMatcher, name))
+		return b
+	}
+	if b.On(m).pending != nil {
+		b.pendingName = name
+	}
+	return b
+}
+
+func (b *MachineBuilder) GoTo(next StateId) *MachineBuilder {
+	if b.pending == nil && b.pendingEvent == "" && b.pendingAfter == 0 {
+		b.errs = append(b.errs, fmt.Errorf("%w: %s", ErrBuilderNoMatcher, next))
+		return b
+	}
+	b.states[b.current].Matches = append(b.states[b.current].Matches, Transition{NextState: next, SimpleMatcher: b.pending, MatcherName: b.pendingName, Event: b.pendingEvent, After: b.pendingAfter})
+	b.pending = nil
+	b.pendingName = ""
+	b.pendingEvent = ""
+	b.pendingAfter = 0
+	return b
+}
+
+// Terminal marks the state with the id as terminal, the state is added with its id as the value if it has not been added yet
+func (b *MachineBuilder) Terminal(id StateId) *MachineBuilder {
+	b.closePending()
+	for i := range b.states {
+		if b.states[i].Id == id {
+			b.states[i].Terminal = true
+			b.current = i
+			return b
+		}
+	}
+	b.State(id, string(id))
+	b.states[b.current].Terminal = true
+	return b
+}
+
+// an On that was never finished is reported rather than dropped
+func (b *MachineBuilder) closePending() {
+	if b.pending != nil || b.pendingEvent != "" || b.pendingAfter != 0 {
+		b.errs = append(b.errs, fmt.Errorf("state %s: %w", b.states[b.current].Id, ErrBuilderNoGoTo))
+		b.pending = nil
+		b.pendingName = ""
+		b.pendingEvent = ""
+		b.pendingAfter = 0
+	}
+}
+
+// Build returns the machine, or every problem with it joined into one error
+func (b *MachineBuilder) Build() (*StateMachine, error) {
+	b.closePending()
+	sm := NewStateMachine(b.states...)
+	if err := errors.Join(append(b.errs, sm.Validate())...); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
@@ -0,0 +1,64 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+func testIsGo(in any) bool {
+	return in == "go"
+}
+
+func TestMachineBuilder(t *testing.T) {
+	sm, err := NewMachineBuilder().
+		State("draft", "DRAFT").On(testIsGo).GoTo("review").
+		State("review", "REVIEW").On(testIsGo).GoTo("done").On(func(in any) bool { return in == "reject" }).GoTo("draft").
+		Terminal("done").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.Start != "draft" {
+		t.Errorf("the first state is the start: got %s", sm.Start)
+	}
+	next, value, err := sm.ProcessFromId("draft", "go")
+	if err != nil || next != "review" || value != "REVIEW" {
+		t.Errorf("got %s %v %v", next, value, err)
+	}
+	if next, _, err := sm.ProcessFromId("review", "reject"); err != nil || next != "draft" {
+		t.Errorf("got %s %v", next, err)
+	}
+	if _, value, err := sm.ProcessFromId("review", "go"); err != nil || value != "done" {
+		t.Errorf("a terminal state added by Terminal has its id as the value: got %v %v", value, err)
+	}
+	if _, _, err := sm.ProcessFromId("done", "go"); err != SameStateNoUpdate {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestMachineBuilderTerminalExisting(t *testing.T) {
+	sm, err := NewMachineBuilder().State("a", 1).On(testIsGo).GoTo("b").State("b", 2).Terminal("b").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := sm.lookupValue("b"); v != 2 {
+		t.Errorf("marking a state terminal keeps its value: got %v", v)
+	}
+}
+
+func TestMachineBuilderErrors(t *testing.T) {
+	_, err := NewMachineBuilder().
+		On(testIsGo).
+		State("a", 1).GoTo("b").
+		On(testIsGo).
+		State("b", 2).On(testIsGo).GoTo("missing").
+		Build()
+	for _, want := range []error{ErrBuilderNoState, ErrBuilderNoMatcher, ErrBuilderNoGoTo, ErrUnknownNextState} {
+		if !errors.Is(err, want) {
+			t.Errorf("missing %v in %v", want, err)
+		}
+	}
+	if _, err := NewMachineBuilder().State("a", 1).On(testIsGo).Build(); !errors.Is(err, ErrBuilderNoGoTo) {
+		t.Errorf("an unfinished On at Build is reported: got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package fielder
+
+import (
+	"container/ring"
+	"sync"
+)
+
+// machines are composed from their states, the machine hooks, history recorder and strict mode of the parts are not carried over
+
+// MachinePart is a machine merged into another, its state ids are prefixed so parts can reuse ids, see PrefixStateId
+type MachinePart struct {
+	Prefix  string
+	Machine *StateMachine
+}
+
+// PrefixStateId returns the id a state of a MachinePart has in the merged machine, ex: PrefixStateId("payment", "due") is "payment.due"
+func PrefixStateId(prefix string, id StateId) StateId {
+	if prefix == "" {
+		return id
+	}
+	return StateId(prefix+".") + id
+}
+
+// Compose chains b after a, every terminal state of a gets a transition to the start of b with the bridge as its matcher and stops being terminal
+// a nil bridge always matches, the ids of the two machines must not overlap, use Merge first when they do
+// the composed machine starts where a starts and is validated
+func Compose(a, b *StateMachine, bridge SimpleMatcher) (*StateMachine, error) {
+	if bridge == nil {
+		bridge = EnforceableTrue
+	}
+	// a conditional state is bridged with a conditional that asks the bridge
+	bridgeConditional := Conditions(Prerequisite{IsCandidate: EnforceableTrue, Gauntlet: []Question{func() Enforceable { return Enforceable(bridge) }}})
+	states := []any{}
+	for _, v := range a.ringStates() {
+		switch s := v.(type) {
+		case State:
+			if s.Terminal {
+				s.Terminal = false
+				s.Matches = append(append([]Transition{}, s.Matches...), Transition{NextState: b.Start, SimpleMatcher: bridge})
+			}
+			v = s
+		case ConditionalState:
+			if s.Terminal {
+				s.Terminal = false
+				s.Outcomes = append(append([]ConditionalTransition{}, s.Outcomes...), ConditionalTransition{NextState: b.Start, Conditional: bridgeConditional})
+			}
+			v = s
+		}
+		states = append(states, v)
+	}
+	for _, v := range b.ringStates() {
+		states = append(states, withoutStart(v))
+	}
+	sm := newMachineFromRingStates(a.Start, states)
+	if err := sm.Validate(); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// Merge unions the states of the parts into one machine, with every state id prefixed by its part's prefix
+// the parts are not linked to each other, add transitions between them with Compose or by building on the merged states
+// the merged machine starts where the first part starts and is validated
+func Merge(parts ...MachinePart) (*StateMachine, error) {
+	states := []any{}
+	var start StateId
+	for i, p := range parts {
+		rename := func(id StateId) StateId { return PrefixStateId(p.Prefix, id) }
+		for _, v := range p.Machine.ringStates() {
+			v = renameRingState(v, rename)
+			if i > 0 {
+				v = withoutStart(v)
+			}
+			states = append(states, v)
+		}
+		if i == 0 {
+			start = rename(p.Machine.Start)
+		}
+	}
+	sm := newMachineFromRingStates(start, states)
+	if err := sm.Validate(); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// copies the state held in the ring with its id and the ids it moves to renamed
+func renameRingState(v any, rename func(StateId) StateId) any {
+	switch s := v.(type) {
+	case State:
+		s.Id = rename(s.Id)
+		s.Matches = append([]Transition{}, s.Matches...)
+		for i := range s.Matches {
+			s.Matches[i].NextState = rename(s.Matches[i].NextState)
+		}
+		if s.Else != "" {
+			s.Else = rename(s.Else)
+		}
+		s.Retry = renameRetry(s.Retry, rename)
+		return s
+	case ConditionalState:
+		s.Id = rename(s.Id)
+		s.Outcomes = append([]ConditionalTransition{}, s.Outcomes...)
+		for i := range s.Outcomes {
+			s.Outcomes[i].NextState = rename(s.Outcomes[i].NextState)
+		}
+		if s.Else != "" {
+			s.Else = rename(s.Else)
+		}
+		s.Retry = renameRetry(s.Retry, rename)
+		return s
+	}
+	return v
+}
+
+func renameRetry(p *RetryPolicy, rename func(StateId) StateId) *RetryPolicy {
+	if p == nil || p.Fallback == "" {
+		return p
+	}
+	renamed := *p
+	renamed.Fallback = rename(p.Fallback)
+	return &renamed
+}
+
+func withoutStart(v any) any {
+	switch s := v.(type) {
+	case State:
+		s.Start = false
+		return s
+	case ConditionalState:
+		s.Start = false
+		return s
+	}
+	return v
+}
+
+// creates a machine holding states of either kind, in the order given
+func newMachineFromRingStates(start StateId, states []any) *StateMachine {
+	if len(states) == 0 {
+		return NewStateMachine()
+	}
+	sm := &StateMachine{
+		Ring:               ring.New(len(states)),
+		mu:                 new(sync.RWMutex),
+		Start:              start,
+		IdRingAddressCache: make(map[StateId]RingAddress),
+		ValueCache:         make(map[StateId]StateValue),
+	}
+	addr := sm.Ring
+	sm.startAddr = addr
+	for _, v := range states {
+		addr.Value = v
+		if s, ok := asMachineState(v); ok {
+			sm.IdRingAddressCache[s.StateId()] = addr
+			sm.ValueCache[s.StateId()] = s.Value()
+		}
+		addr = addr.Next()
+	}
+	return sm
+}
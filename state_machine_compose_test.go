@@ -0,0 +1,70 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+func testShipMachine() *StateMachine {
+	return NewStateMachine(
+		State{Id: "packing", StateValue: "Packing", Start: true, Matches: []Transition{{NextState: "shipped", SimpleMatcher: testIsGo}}},
+		State{Id: "shipped", StateValue: "Shipped", Terminal: true},
+	)
+}
+
+func TestCompose(t *testing.T) {
+	sm, err := Compose(testDraftMachine(), testShipMachine(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.Start != "draft" {
+		t.Errorf("got %s", sm.Start)
+	}
+	sim := Simulate(sm, "go", "go", "anything", "go", "go")
+	sim.AssertPath(t, "draft", "review", "done", "packing", "shipped", "shipped")
+	if !errors.Is(sim.Steps[4].Err, SameStateNoUpdate) {
+		t.Errorf("the terminal state of b is still terminal: got %v", sim.Steps[4].Err)
+	}
+	// the parts are not changed
+	if s, _ := testDraftMachine().lookupRingState("done"); !s.(State).Terminal {
+		t.Error("got a changed part")
+	}
+}
+
+func TestComposeBridge(t *testing.T) {
+	sm, err := Compose(testDraftMachine(), testShipMachine(), testIsGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Simulate(sm, "go", "go", "stop", "go").AssertPath(t, "draft", "review", "done", "done", "packing")
+	// a conditional terminal state is bridged with a conditional
+	csm := NewConditionalStateMachine(ConditionalState{Id: "checked", StateValue: "Checked", Start: true, Terminal: true})
+	sm, err = Compose(csm.StateMachine, testShipMachine(), testIsGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, _, err := sm.ProcessFromId("checked", "go"); err != nil || next != "packing" {
+		t.Errorf("got %s %v", next, err)
+	}
+	if _, err := Compose(testDraftMachine(), testDraftMachine(), nil); !errors.Is(err, ErrDuplicateStateId) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	sm, err := Merge(MachinePart{Prefix: "first", Machine: testDraftMachine()}, MachinePart{Prefix: "second", Machine: testDraftMachine()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.Start != "first.draft" {
+		t.Errorf("got %s", sm.Start)
+	}
+	Simulate(sm, "go", "stop").AssertPath(t, "first.draft", "first.review", "first.draft")
+	SimulateFrom(sm, "second.draft", "go", "go").AssertPath(t, "second.draft", "second.review", "second.done")
+	if PrefixStateId("", "a") != "a" || PrefixStateId("payment", "due") != "payment.due" {
+		t.Error("got a wrong prefix")
+	}
+	if _, err := Merge(MachinePart{Machine: testDraftMachine()}, MachinePart{Machine: testDraftMachine()}); !errors.Is(err, ErrDuplicateStateId) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrEventNotPermitted = errors.New("event is not permitted in the current state")
+
+// EvaluateEventCtx returns the next state for the event, the first transition for the event whose guard passes is taken, in priority order
+// a transition with no matcher has no guard
+func (s *State) EvaluateEventCtx(ctx context.Context, event string, payload any) (StateId, error) {
+	found := false
+	for _, i := range transitionOrder(len(s.Matches), func(i int) int { return s.Matches[i].Priority }) {
+		v := s.Matches[i]
+		if v.Event != event {
+			continue
+		}
+		found = true
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if !v.guarded() {
+			return v.NextState, nil
+		}
+		ok, err := v.matches(ctx, payload)
+		if err != nil {
+			return "", &GuardError{State: s.Id, NextState: v.NextState, Event: event, Err: err}
+		}
+		if ok {
+			return v.NextState, nil
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrEventNotPermitted, event)
+	}
+	return "", fmt.Errorf("state %s: %w: %s", s.Id, ErrNoTransition, event)
+}
+
+func (s *ConditionalState) EvaluateEventCtx(ctx context.Context, event string, payload any) (StateId, error) {
+	found := false
+	for _, i := range transitionOrder(len(s.Outcomes), func(i int) int { return s.Outcomes[i].Priority }) {
+		v := s.Outcomes[i]
+		if v.Event != event {
+			continue
+		}
+		found = true
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if v.Conditional == nil {
+			return v.NextState, nil
+		}
+		ok, err := conditionalMeets(ctx, v.Conditional, payload)
+		if err != nil {
+			return "", &GuardError{State: s.Id, NextState: v.NextState, Event: event, Err: err}
+		}
+		if ok {
+			return v.NextState, nil
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrEventNotPermitted, event)
+	}
+	return "", fmt.Errorf("state %s: %w: %s", s.Id, ErrNoTransition, event)
+}
+
+// returns the events of the state's transitions, in the order they were declared
+func ringStateEvents(v any) []string {
+	out := []string{}
+	add := func(e string) {
+		if e != "" && !SliceContains(out, e, func(s1, s2 string) bool { return s1 == s2 }) {
+			out = append(out, e)
+		}
+	}
+	switch s := v.(type) {
+	case State:
+		for _, t := range s.Matches {
+			add(t.Event)
+		}
+	case ConditionalState:
+		for _, t := range s.Outcomes {
+			add(t.Event)
+		}
+	}
+	return out
+}
+
+// Fire moves the instance along the transition for the event, the payload is tested by the transition's guard
+// it returns ErrEventNotPermitted when the current state has no transition for the event
+func (mi *MachineInstance) Fire(event string, payload any) (StateValue, error) {
+	return mi.FireCtx(context.Background(), event, payload)
+}
+
+func (mi *MachineInstance) FireCtx(ctx context.Context, event string, payload any) (StateValue, error) {
+	if event == "" {
+		return nil, fmt.Errorf("%w: empty event", ErrEventNotPermitted)
+	}
+	mi.mu.Lock()
+	in, _ := mi.Machine.lookupValue(mi.current)
+	nextId, value, fire, err := mi.Machine.planFromId(ctx, mi.current, in, payload, event)
+	if err != nil {
+		mi.mu.Unlock()
+		return nil, err
+	}
+	mi.moveTo(nextId, mi.Now())
+	mi.mu.Unlock()
+	fire()
+	return value, nil
+}
+
+// PermittedEvents returns the events the current state has transitions for, whether their guards pass is only known when they are fired
+func (mi *MachineInstance) PermittedEvents() []string {
+	s, err := mi.Machine.lookupRingState(mi.Current())
+	if err != nil {
+		return []string{}
+	}
+	return ringStateEvents(s)
+}
+
+// OnEvent starts a transition from the current state of the builder that is taken when the event is fired, it is finished by GoTo
+func (b *MachineBuilder) OnEvent(event string) *MachineBuilder {
+	b.closePending()
+	if b.current < 0 {
+		b.errs = append(b.errs, ErrBuilderNoState)
+		return b
+	}
+	b.pendingEvent = event
+	return b
+}
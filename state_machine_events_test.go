@@ -0,0 +1,91 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testOrderMachine(t *testing.T) *StateMachine {
+	t.Helper()
+	sm, err := NewStateMachineChecked(
+		State{Id: "pending", StateValue: "PENDING", Start: true, Matches: []Transition{
+			{NextState: "paid", Event: "pay"},
+			{NextState: "cancelled", Event: "cancel"},
+		}},
+		State{Id: "paid", StateValue: "PAID", Matches: []Transition{
+			{NextState: "shipped", Event: "ship", SimpleMatcher: func(in any) bool { return in == "has address" }},
+		}},
+		State{Id: "shipped", StateValue: "shipped", Terminal: true},
+		State{Id: "cancelled", StateValue: "cancelled", Terminal: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sm
+}
+
+func TestMachineBuilderOnEvent(t *testing.T) {
+	sm, err := NewMachineBuilder().State("pending", "PENDING").OnEvent("pay").GoTo("paid").Terminal("paid").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := NewMachineInstance(sm).Fire("pay", nil); err != nil || v != "paid" {
+		t.Errorf("got %v %v", v, err)
+	}
+	if _, err := NewMachineBuilder().OnEvent("pay").Build(); !errors.Is(err, ErrBuilderNoState) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestFire(t *testing.T) {
+	mi := NewMachineInstance(testOrderMachine(t))
+	if got := mi.PermittedEvents(); !reflect.DeepEqual(got, []string{"pay", "cancel"}) {
+		t.Errorf("got %v", got)
+	}
+	var fired TransitionEvent
+	mi.Machine.OnTransition(func(e TransitionEvent) { fired = e })
+	if v, err := mi.Fire("pay", nil); err != nil || v != "PAID" {
+		t.Fatalf("got %v %v", v, err)
+	}
+	if fired.Event != "pay" || fired.From != "pending" || fired.To != "paid" {
+		t.Errorf("got %+v", fired)
+	}
+	// the guard is tested against the payload
+	if _, err := mi.Fire("ship", "no address"); !errors.Is(err, ErrNoTransition) || mi.Current() != "paid" {
+		t.Errorf("got %v at %s", err, mi.Current())
+	}
+	if v, err := mi.Fire("ship", "has address"); err != nil || v != "shipped" {
+		t.Errorf("got %v %v", v, err)
+	}
+}
+
+func TestFireNotPermitted(t *testing.T) {
+	mi := NewMachineInstance(testOrderMachine(t))
+	if _, err := mi.Fire("ship", "has address"); !errors.Is(err, ErrEventNotPermitted) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := mi.Fire("", nil); !errors.Is(err, ErrEventNotPermitted) {
+		t.Errorf("got %v", err)
+	}
+	// event transitions are not taken by Step
+	if _, err := mi.Step(nil); !errors.Is(err, ErrNoTransition) || mi.Current() != "pending" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestFireConditional(t *testing.T) {
+	csm := NewConditionalStateMachine(
+		ConditionalState{Id: "a", StateValue: "A", Start: true, Outcomes: []ConditionalTransition{
+			{NextState: "b", Event: "go", Conditional: testNotBad()},
+		}},
+		ConditionalState{Id: "b", StateValue: "B", Terminal: true},
+	)
+	mi := NewMachineInstance(csm.StateMachine)
+	if _, err := mi.Fire("go", testField("Name", "bad")); !errors.Is(err, ErrNoTransition) {
+		t.Errorf("got %v", err)
+	}
+	if v, err := mi.Fire("go", testField("Name", "good")); err != nil || v != "B" {
+		t.Errorf("got %v %v", v, err)
+	}
+}
@@ -0,0 +1,132 @@
+package fielder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// a transition as drawn in a diagram, the label is the event and the registered name of the matcher or conditional, ex: submit [complete]
+type machineEdge struct {
+	next  StateId
+	label string
+}
+
+func ringStateEdges(v any) ([]machineEdge, bool) {
+	out := []machineEdge{}
+	switch s := v.(type) {
+	case State:
+		for _, t := range s.Matches {
+			out = append(out, machineEdge{next: t.NextState, label: edgeLabel(edgeTrigger(t.Event, t.After), t.MatcherName)})
+		}
+		if s.Else != "" {
+			out = append(out, machineEdge{next: s.Else, label: "else"})
+		}
+		if s.Retry != nil && s.Retry.Fallback != "" {
+			out = append(out, machineEdge{next: s.Retry.Fallback, label: "fallback"})
+		}
+		return out, s.Terminal
+	case ConditionalState:
+		for _, t := range s.Outcomes {
+			out = append(out, machineEdge{next: t.NextState, label: edgeLabel(edgeTrigger(t.Event, t.After), t.ConditionalName)})
+		}
+		if s.Else != "" {
+			out = append(out, machineEdge{next: s.Else, label: "else"})
+		}
+		if s.Retry != nil && s.Retry.Fallback != "" {
+			out = append(out, machineEdge{next: s.Retry.Fallback, label: "fallback"})
+		}
+		return out, s.Terminal
+	}
+	return out, false
+}
+
+// a timed transition is labelled with its duration, ex: after 24h0m0s
+func edgeTrigger(event string, after time.Duration) string {
+	if after > 0 {
+		return "after " + after.String()
+	}
+	return event
+}
+
+func edgeLabel(event, guard string) string {
+	switch {
+	case event == "":
+		return guard
+	case guard == "":
+		return event
+	}
+	return event + " [" + guard + "]"
+}
+
+// ExportDOT renders the machine as a graphviz digraph, terminal states are double circles and the start state has an arrow from a point
+// transitions are labelled with their registered matcher or conditional names
+func (sm *StateMachine) ExportDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph machine {\n\trankdir=LR;\n")
+	if sm.Start != "" {
+		fmt.Fprintf(&b, "\t__start [shape=point];\n\t__start -> %s;\n", strconv.Quote(string(sm.Start)))
+	}
+	var edges strings.Builder
+	for _, v := range sm.ringStates() {
+		id, _, _ := ringStateInfo(v)
+		out, terminal := ringStateEdges(v)
+		shape := "circle"
+		if terminal {
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(&b, "\t%s [shape=%s];\n", strconv.Quote(string(id)), shape)
+		for _, e := range out {
+			fmt.Fprintf(&edges, "\t%s -> %s", strconv.Quote(string(id)), strconv.Quote(string(e.next)))
+			if e.label != "" {
+				fmt.Fprintf(&edges, " [label=%s]", strconv.Quote(e.label))
+			}
+			edges.WriteString(";\n")
+		}
+	}
+	b.WriteString(edges.String())
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ExportMermaid renders the machine as a mermaid state diagram, the start state comes from [*] and terminal states go to [*]
+func (sm *StateMachine) ExportMermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	if sm.Start != "" {
+		fmt.Fprintf(&b, "    [*] --> %s\n", mermaidId(sm.Start))
+	}
+	// states are declared before they are used, so the ones with a label show it everywhere
+	for _, v := range sm.ringStates() {
+		id, _, _ := ringStateInfo(v)
+		if mermaidId(id) != string(id) {
+			fmt.Fprintf(&b, "    state %s as %s\n", strconv.Quote(string(id)), mermaidId(id))
+		}
+	}
+	for _, v := range sm.ringStates() {
+		id, _, _ := ringStateInfo(v)
+		out, terminal := ringStateEdges(v)
+		for _, e := range out {
+			fmt.Fprintf(&b, "    %s --> %s", mermaidId(id), mermaidId(e.next))
+			if e.label != "" {
+				fmt.Fprintf(&b, ": %s", e.label)
+			}
+			b.WriteString("\n")
+		}
+		if terminal {
+			fmt.Fprintf(&b, "    %s --> [*]\n", mermaidId(id))
+		}
+	}
+	return b.String()
+}
+
+// mermaid ids can only hold letters, digits and underscores, other characters are replaced and the state is given its real id as a label
+func mermaidId(id StateId) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, string(id))
+}
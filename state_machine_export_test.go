@@ -0,0 +1,64 @@
+package fielder
+
+import (
+	"testing"
+	"time"
+)
+
+func testExportMachine(t *testing.T) *StateMachine {
+	t.Helper()
+	sm, err := NewStateMachineChecked(
+		State{Id: "draft", StateValue: 1, Start: true, Else: "in review", Matches: []Transition{
+			{NextState: "done", SimpleMatcher: testIsGo, MatcherName: "complete", Event: "submit"},
+			{NextState: "done", After: 24 * time.Hour},
+		}},
+		State{Id: "in review", StateValue: 2, Retry: &RetryPolicy{MaxAttempts: 2, Fallback: "draft"}},
+		State{Id: "done", StateValue: 3, Terminal: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sm
+}
+
+func TestExportDOT(t *testing.T) {
+	want := `digraph machine {
+	rankdir=LR;
+	__start [shape=point];
+	__start -> "draft";
+	"draft" [shape=circle];
+	"in review" [shape=circle];
+	"done" [shape=doublecircle];
+	"draft" -> "done" [label="submit [complete]"];
+	"draft" -> "done" [label="after 24h0m0s"];
+	"draft" -> "in review" [label="else"];
+	"in review" -> "draft" [label="fallback"];
+}
+`
+	if got := testExportMachine(t).ExportDOT(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExportMermaid(t *testing.T) {
+	want := `stateDiagram-v2
+    [*] --> draft
+    state "in review" as in_review
+    draft --> done: submit [complete]
+    draft --> done: after 24h0m0s
+    draft --> in_review: else
+    in_review --> draft: fallback
+    done --> [*]
+`
+	if got := testExportMachine(t).ExportMermaid(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEdgeLabel(t *testing.T) {
+	for _, c := range [][3]string{{"", "", ""}, {"submit", "", "submit"}, {"", "complete", "complete"}, {"submit", "complete", "submit [complete]"}} {
+		if got := edgeLabel(c[0], c[1]); got != c[2] {
+			t.Errorf("%q %q: got %q", c[0], c[1], got)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package fielder
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// field driven transitions compare a field of the data given to the machine with a field value, ex:
+// Transition{NextState: "review", SimpleMatcher: WhenField("Amount", GT, NewDecimalField(NewDefaultFieldKey("Amount"), limit))}
+// the data can be a Parent, a parent struct or a pointer to one, a *FieldSet, a map[FieldKey]Field or a single Field with the name
+
+// CompareFields reports whether f compares to value with the op, a missing or empty f matches nothing
+func CompareFields(f Field, op safeOp, value Field) bool {
+	if f == nil || f == FieldNil || value == nil {
+		return false
+	}
+	switch op {
+	case EQ:
+		return f.Equal(value)
+	case NE:
+		return !f.Equal(value)
+	case LT:
+		return f.LessThan(value)
+	case LE:
+		return f.LessThan(value) || f.Equal(value)
+	case GT:
+		return f.GreaterThan(value)
+	case GE:
+		return f.GreaterThan(value) || f.Equal(value)
+	}
+	return false
+}
+
+// returns the field with the name from the data a matcher is given, FieldNil when it has none
+func fieldFromData(data any, name string) Field {
+	key := NewDefaultFieldKey(name)
+	switch d := data.(type) {
+	case nil:
+		return FieldNil
+	case *FieldSet:
+		if f := d.Get(key); f != nil {
+			return f
+		}
+		return FieldNil
+	case map[FieldKey]Field:
+		if f, ok := d[key]; ok {
+			return f
+		}
+		return FieldNil
+	case Field:
+		if d.Key().Name == key.Name {
+			return d
+		}
+		return FieldNil
+	case Parent:
+		return d.GetResultItemFieldFromKey(key)
+	}
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Struct {
+		return FieldNil
+	}
+	return GetResultItemFieldFromKeyDefault(v.Interface(), key)
+}
+
+// WhenField returns a matcher that is true when the named field of the data compares to the value with the op
+func WhenField(name string, op safeOp, value Field) SimpleMatcher {
+	return func(inputToMatch any) bool {
+		return CompareFields(fieldFromData(inputToMatch, name), op, value)
+	}
+}
+
+// FieldTransition is a transition to next that is taken when the named field compares to the value with the op
+// it is named after the comparison, ex: Amount GT 100, a saved machine is restored once the matcher is registered under that name
+func FieldTransition(next StateId, name string, op safeOp, value Field) Transition {
+	return Transition{NextState: next, SimpleMatcher: WhenField(name, op, value), MatcherName: fieldComparisonName(name, op, value)}
+}
+
+// WhenFieldConditional is a Conditional that is met when the named field compares to the value with the op
+func WhenFieldConditional(name string, op safeOp, value Field) Conditional {
+	return Conditions(Prerequisite{
+		IsCandidate: EnforceableTrue,
+		Gauntlet: []Question{func() Enforceable {
+			return Enforceable(WhenField(name, op, value))
+		}},
+	})
+}
+
+func FieldConditionalTransition(next StateId, name string, op safeOp, value Field) ConditionalTransition {
+	return ConditionalTransition{NextState: next, Conditional: WhenFieldConditional(name, op, value), ConditionalName: fieldComparisonName(name, op, value)}
+}
+
+func fieldComparisonName(name string, op safeOp, value Field) string {
+	return fmt.Sprintf("%s %s %s", name, op, value.ToString())
+}
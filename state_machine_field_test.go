@@ -0,0 +1,78 @@
+package fielder
+
+import "testing"
+
+type testInvoice struct {
+	Amount *IntegerField `field:"Amount"`
+	Owner  string        `field:"Owner"`
+}
+
+func testAmount(v int) *IntegerField {
+	return &IntegerField{ValueField: v, KeyField: NewDefaultFieldKey("Amount")}
+}
+
+func TestFieldFromData(t *testing.T) {
+	amount := testAmount(150)
+	invoice := &testInvoice{Amount: amount, Owner: "ann"}
+	// field members are returned as they are, not copied
+	for _, data := range []any{invoice, *invoice, DefaultParent(invoice), NewFieldSet(amount), map[FieldKey]Field{amount.Key(): amount}, amount} {
+		if got := fieldFromData(data, "Amount"); got != Field(amount) {
+			t.Errorf("%T: got %v", data, got)
+		}
+	}
+	if got := fieldFromData(invoice, "Owner"); got == nil || got.ToString() != "ann" {
+		t.Errorf("got %v", got)
+	}
+	for _, data := range []any{nil, "Amount", 150, testField("Name", "x"), NewFieldSet(), map[FieldKey]Field{}} {
+		if got := fieldFromData(data, "Amount"); got != FieldNil {
+			t.Errorf("%T: got %v", data, got)
+		}
+	}
+}
+
+func TestCompareFields(t *testing.T) {
+	limit := testAmount(100)
+	for _, c := range []struct {
+		v    int
+		op   safeOp
+		want bool
+	}{
+		{100, EQ, true}, {99, NE, true}, {99, LT, true}, {100, LE, true}, {101, GT, true}, {100, GE, true},
+		{100, LT, false}, {101, LE, false}, {100, GT, false}, {99, GE, false}, {100, "?", false},
+	} {
+		if got := CompareFields(testAmount(c.v), c.op, limit); got != c.want {
+			t.Errorf("%d %s 100: got %v", c.v, c.op, got)
+		}
+	}
+	if CompareFields(nil, EQ, limit) || CompareFields(FieldNil, NE, limit) || CompareFields(limit, EQ, nil) {
+		t.Error("a missing field matches nothing")
+	}
+}
+
+func TestFieldTransition(t *testing.T) {
+	sm := NewStateMachine(
+		State{Id: "new", StateValue: "New", Matches: []Transition{FieldTransition("review", "Amount", GT, testAmount(100))}, Else: "approved"},
+		State{Id: "review", StateValue: "Review"},
+		State{Id: "approved", StateValue: "Approved"},
+	)
+	if tr := FieldTransition("review", "Amount", GT, testAmount(100)); tr.MatcherName != "Amount GT 100" {
+		t.Errorf("got %q", tr.MatcherName)
+	}
+	if next, _, err := sm.ProcessFromId("new", &testInvoice{Amount: testAmount(150)}); err != nil || next != "review" {
+		t.Errorf("got %s %v", next, err)
+	}
+	if next, _, err := sm.ProcessFromId("new", &testInvoice{Amount: testAmount(50)}); err != nil || next != "approved" {
+		t.Errorf("got %s %v", next, err)
+	}
+	// an unset member matches nothing
+	if next, _, err := sm.ProcessFromId("new", &testInvoice{}); err != nil || next != "approved" {
+		t.Errorf("got %s %v", next, err)
+	}
+	csm := NewConditionalStateMachine(
+		ConditionalState{Id: "new", StateValue: "New", Outcomes: []ConditionalTransition{FieldConditionalTransition("review", "Amount", GE, testAmount(100))}},
+		ConditionalState{Id: "review", StateValue: "Review"},
+	)
+	if next, _, err := csm.ProcessFromId("new", &testInvoice{Amount: testAmount(100)}); err != nil || next != "review" {
+		t.Errorf("got %s %v", next, err)
+	}
+}
@@ -0,0 +1,46 @@
+package fielder
+
+import (
+	"context"
+	"fmt"
+)
+
+// GuardMatcher is a matcher that can fail, ex: a guard that looks something up in another service
+// an error stops the evaluation of the state and is returned as a GuardError, instead of reading as a transition that did not match
+type GuardMatcher func(ctx context.Context, inputToMatch any) (bool, error)
+
+// FallibleConditional is a Conditional whose checks can fail, conditional transitions use MeetsErr when the conditional has it
+type FallibleConditional interface {
+	Conditional
+	MeetsErr(any) (bool, error)
+}
+
+func conditionalMeets(ctx context.Context, c Conditional, dataToTest any) (bool, error) {
+	if cc, ok := c.(ContextConditional); ok {
+		return cc.MeetsCtx(ctx, dataToTest)
+	}
+	if fc, ok := c.(FallibleConditional); ok {
+		return fc.MeetsErr(dataToTest)
+	}
+	return c.Meets(dataToTest), nil
+}
+
+// GuardError is returned when a guard fails while a state is evaluated, it is never SameStateNoUpdate or a missing transition
+type GuardError struct {
+	State     StateId
+	NextState StateId
+	// the event being fired, empty for matcher transitions
+	Event string
+	Err   error
+}
+
+func (e *GuardError) Error() string {
+	if e.Event != "" {
+		return fmt.Sprintf("guard of %s -> %s on %s failed: %v", e.State, e.NextState, e.Event, e.Err)
+	}
+	return fmt.Sprintf("guard of %s -> %s failed: %v", e.State, e.NextState, e.Err)
+}
+
+func (e *GuardError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,69 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var errTestLookup = errors.New("lookup failed")
+
+type testFallible struct {
+	Conditional
+	err error
+}
+
+func (c testFallible) MeetsErr(v any) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	return c.Meets(v), nil
+}
+
+func TestGuardError(t *testing.T) {
+	s := State{Id: "a", Matches: []Transition{
+		{NextState: "b", Guard: func(context.Context, any) (bool, error) { return false, errTestLookup }},
+		{NextState: "c", SimpleMatcher: func(any) bool { return true }},
+	}}
+	_, err := s.EvaluateTransition(nil)
+	var guard *GuardError
+	if !errors.As(err, &guard) || guard.State != "a" || guard.NextState != "b" || !errors.Is(err, errTestLookup) {
+		t.Fatalf("a failing guard stops the evaluation: got %v", err)
+	}
+	if err.Error() != "guard of a -> b failed: lookup failed" {
+		t.Errorf("got %q", err.Error())
+	}
+	guard.Event = "pay"
+	if guard.Error() != "guard of a -> b on pay failed: lookup failed" {
+		t.Errorf("got %q", guard.Error())
+	}
+	s.Matches[0].Guard = func(context.Context, any) (bool, error) { return false, nil }
+	if next, err := s.EvaluateTransition(nil); err != nil || next != "c" {
+		t.Errorf("a guard that does not pass is a transition that did not match: got %s %v", next, err)
+	}
+}
+
+func TestGuardErrorInstance(t *testing.T) {
+	sm := NewStateMachine(
+		State{Id: "a", StateValue: "A", Matches: []Transition{{NextState: "b", Guard: func(context.Context, any) (bool, error) { return false, errTestLookup }}}},
+		State{Id: "b", StateValue: "B"},
+	)
+	mi := NewMachineInstance(sm)
+	var guard *GuardError
+	if _, err := mi.Step(nil); !errors.As(err, &guard) || mi.Current() != "a" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestConditionalMeets(t *testing.T) {
+	bad := testField("Name", "bad")
+	if ok, err := conditionalMeets(context.Background(), testNotBad(), bad); ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+	if _, err := conditionalMeets(context.Background(), testFallible{testNotBad(), errTestLookup}, bad); err != errTestLookup {
+		t.Errorf("got %v", err)
+	}
+	if ok, err := conditionalMeets(context.Background(), testFallible{Conditional: testNotBad()}, testField("Name", "good")); !ok || err != nil {
+		t.Errorf("got %v %v", ok, err)
+	}
+}
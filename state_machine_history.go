@@ -0,0 +1,94 @@
+package fielder
+
+import (
+	"container/ring"
+	"sync"
+	"time"
+)
+
+// HistoryEntry is one ProcessInMachine call, From and To are empty when the call failed before they were known
+type HistoryEntry struct {
+	Time   time.Time
+	Input  StateValue
+	Data   any
+	Event  string
+	From   StateId
+	To     StateId
+	Result StateValue
+	Err    error
+}
+
+// HistoryRecorder keeps the last entries of a machine in a ring, older entries are overwritten once it is full
+type HistoryRecorder struct {
+	mu    sync.Mutex
+	ring  *ring.Ring
+	count int
+	size  int
+	// the clock used to stamp entries, time.Now unless it is replaced
+	Now func() time.Time
+}
+
+func NewHistoryRecorder(size int) *HistoryRecorder {
+	if size < 1 {
+		size = 1
+	}
+	return &HistoryRecorder{ring: ring.New(size), size: size, Now: time.Now}
+}
+
+func (r *HistoryRecorder) Record(e HistoryEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e.Time.IsZero() {
+		e.Time = r.Now()
+	}
+	r.ring.Value = e
+	r.ring = r.ring.Next()
+	if r.count < r.size {
+		r.count++
+	}
+}
+
+// History returns a copy of the entries, oldest first
+func (r *HistoryRecorder) History() []HistoryEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]HistoryEntry, 0, r.count)
+	// the ring sits on the slot to write next, which is the oldest entry once the ring is full
+	start := r.ring.Move(-r.count)
+	for i := 0; i < r.count; i++ {
+		out = append(out, start.Value.(HistoryEntry))
+		start = start.Next()
+	}
+	return out
+}
+
+func (r *HistoryRecorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+func (r *HistoryRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ring = ring.New(r.size)
+	r.count = 0
+}
+
+// RecordHistory makes the machine record every ProcessInMachine call in r, nil stops recording
+func (sm *StateMachine) RecordHistory(r *HistoryRecorder) {
+	defer sm.lock()()
+	sm.recorder = r
+}
+
+func (sm *StateMachine) recordHistory(e HistoryEntry, result StateValue, err error) {
+	unlock := sm.rLock()
+	r := sm.recorder
+	unlock()
+	if r == nil {
+		return
+	}
+	e.Result = result
+	e.Err = err
+	r.Record(e)
+}
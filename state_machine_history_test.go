@@ -0,0 +1,60 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistoryRecorder(t *testing.T) {
+	r := NewHistoryRecorder(2)
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.Now = func() time.Time { return at }
+	r.Record(HistoryEntry{From: "a"})
+	if h := r.History(); len(h) != 1 || h[0].From != "a" || !h[0].Time.Equal(at) {
+		t.Errorf("got %+v", h)
+	}
+	r.Record(HistoryEntry{From: "b"})
+	r.Record(HistoryEntry{From: "c", Time: at.Add(time.Hour)})
+	h := r.History()
+	if r.Len() != 2 || len(h) != 2 || h[0].From != "b" || h[1].From != "c" {
+		t.Errorf("the oldest entry is overwritten once full: got %+v", h)
+	}
+	if !h[1].Time.Equal(at.Add(time.Hour)) {
+		t.Error("entries that have a time keep it")
+	}
+	r.Reset()
+	if r.Len() != 0 || len(r.History()) != 0 {
+		t.Error("reset empties the recorder")
+	}
+	if NewHistoryRecorder(0).size != 1 {
+		t.Error("a recorder holds at least one entry")
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	r := NewHistoryRecorder(10)
+	sm.RecordHistory(r)
+	sm.ProcessInMachine("Red", "go", BasicEquals)
+	sm.ProcessInMachine("Green", "stop", BasicEquals)
+	sm.ProcessInMachine("Blue", "go", BasicEquals)
+	h := r.History()
+	if len(h) != 3 {
+		t.Fatalf("got %+v", h)
+	}
+	if h[0].From != "red" || h[0].To != "green" || h[0].Result != "Green" || h[0].Input != "Red" || h[0].Data != "go" || h[0].Err != nil {
+		t.Errorf("transition: got %+v", h[0])
+	}
+	if h[1].From != "green" || !errors.Is(h[1].Err, ErrNoTransition) || h[1].Result != nil {
+		t.Errorf("no transition: got %+v", h[1])
+	}
+	if h[2].From != "" || !errors.Is(h[2].Err, ErrUnknownState) {
+		t.Errorf("unknown state: got %+v", h[2])
+	}
+	sm.RecordHistory(nil)
+	sm.ProcessInMachine("Red", "go", BasicEquals)
+	if r.Len() != 3 {
+		t.Error("a nil recorder stops recording")
+	}
+}
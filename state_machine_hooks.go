@@ -0,0 +1,74 @@
+package fielder
+
+import "context"
+
+// TransitionEvent describes one move between states, it is passed to the hooks of the states and the machine
+type TransitionEvent struct {
+	From      StateId
+	To        StateId
+	FromValue StateValue
+	ToValue   StateValue
+	// the data the transition was tested against
+	Data any
+	// the event that was fired, empty for transitions taken by a matcher
+	Event string
+	// the context of the ProcessInMachineCtx call, so hooks can read request scoped values
+	Context context.Context
+}
+
+type TransitionHook func(TransitionEvent)
+
+// OnTransition registers hooks that are called after every transition of the machine
+// hooks run in the order: OnExit of the old state, the machine's hooks in registration order, then OnEnter of the new state
+func (sm *StateMachine) OnTransition(hooks ...TransitionHook) {
+	defer sm.lock()()
+	sm.hooks = append(sm.hooks, hooks...)
+}
+
+func ringStateHooks(v any) (enter TransitionHook, exit TransitionHook) {
+	if s, ok := asMachineState(v); ok {
+		return s.Hooks()
+	}
+	return nil, nil
+}
+
+// runs the hooks for a transition between the two states held in the ring
+func (sm *StateMachine) fireTransition(from, to any, event TransitionEvent) {
+	_, exit := ringStateHooks(from)
+	enter, _ := ringStateHooks(to)
+	if exit != nil {
+		exit(event)
+	}
+	unlock := sm.rLock()
+	hooks := sm.hooks
+	subs := sm.subs
+	unlock()
+	for _, h := range hooks {
+		h(event)
+	}
+	if enter != nil {
+		enter(event)
+	}
+	for _, s := range subs {
+		s.publish(event)
+	}
+}
+
+// OnEnter and OnExit add hooks to the current state of the builder
+func (b *MachineBuilder) OnEnter(hook TransitionHook) *MachineBuilder {
+	if b.current < 0 {
+		b.errs = append(b.errs, ErrBuilderNoState)
+		return b
+	}
+	b.states[b.current].OnEnter = hook
+	return b
+}
+
+func (b *MachineBuilder) OnExit(hook TransitionHook) *MachineBuilder {
+	if b.current < 0 {
+		b.errs = append(b.errs, ErrBuilderNoState)
+		return b
+	}
+	b.states[b.current].OnExit = hook
+	return b
+}
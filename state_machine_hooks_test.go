@@ -0,0 +1,72 @@
+package fielder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type testHookKey struct{}
+
+func TestTransitionHooks(t *testing.T) {
+	var calls []string
+	var got TransitionEvent
+	record := func(name string) TransitionHook {
+		return func(e TransitionEvent) {
+			calls = append(calls, name)
+			got = e
+		}
+	}
+	sm, err := NewMachineBuilder().
+		State("red", "Red").OnExit(record("exit red")).OnEnter(record("enter red")).On(testIsGo).GoTo("green").
+		State("green", "Green").OnEnter(record("enter green")).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm.OnTransition(record("machine 1"), record("machine 2"))
+	ctx := context.WithValue(context.Background(), testHookKey{}, "request")
+	if _, err := sm.ProcessInMachineCtx(ctx, "Red", "go", BasicEquals); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"exit red", "machine 1", "machine 2", "enter green"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got %v want %v", calls, want)
+	}
+	if got.From != "red" || got.To != "green" || got.FromValue != "Red" || got.ToValue != "Green" || got.Data != "go" || got.Event != "" {
+		t.Errorf("got %+v", got)
+	}
+	if got.Context.Value(testHookKey{}) != "request" {
+		t.Error("hooks get the context of the call")
+	}
+}
+
+func TestTransitionHooksNotCalled(t *testing.T) {
+	called := false
+	sm := NewStateMachine(testLightStates()...)
+	sm.OnTransition(func(TransitionEvent) { called = true })
+	if _, err := sm.ProcessInMachine("Red", "stop", BasicEquals); err == nil {
+		t.Error("expected no transition")
+	}
+	if called {
+		t.Error("hooks are only called after a transition")
+	}
+}
+
+func TestTransitionHooksReenter(t *testing.T) {
+	// hooks run without the machine's lock held, so they can use the machine
+	sm := NewStateMachine(testLightStates()...)
+	var validated error
+	sm.OnTransition(func(TransitionEvent) {
+		sm.OnTransition()
+		validated = sm.Validate()
+	})
+	if _, _, err := sm.ProcessFromId("red", "go"); err != nil || validated != nil {
+		t.Errorf("got %v %v", err, validated)
+	}
+}
+
+func TestMachineBuilderHooksWithoutState(t *testing.T) {
+	if _, err := NewMachineBuilder().OnEnter(func(TransitionEvent) {}).OnExit(func(TransitionEvent) {}).State("a", 1).Build(); err == nil {
+		t.Error("hooks added before any state are an error")
+	}
+}
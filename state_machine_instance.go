@@ -0,0 +1,107 @@
+package fielder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MachineInstance is the progress of one entity through a shared machine, it holds the id of the current state
+// so the machine never has to find the state from its value
+type MachineInstance struct {
+	Machine *StateMachine
+	// the clock used to time the current state for timed transitions, time.Now unless it is replaced
+	Now func() time.Time
+	// waits between the attempts of a RetryPolicy, a wait that respects the context unless it is replaced
+	Sleep   func(ctx context.Context, d time.Duration) error
+	mu      sync.Mutex
+	current StateId
+	entered time.Time     // when the instance moved into the current state
+	wake    chan struct{} // signalled when the instance moves, so RunTimers waits on the new state
+}
+
+// NewMachineInstance returns an instance at the start state of the machine
+// a ConditionalStateMachine is passed as its StateMachine, ex: NewMachineInstance(csm.StateMachine)
+func NewMachineInstance(sm *StateMachine) *MachineInstance {
+	return newMachineInstance(sm, sm.Start, time.Now())
+}
+
+func newMachineInstance(sm *StateMachine, current StateId, entered time.Time) *MachineInstance {
+	return &MachineInstance{Machine: sm, Now: time.Now, Sleep: sleepCtx, current: current, entered: entered, wake: make(chan struct{}, 1)}
+}
+
+// NewMachineInstanceAt returns an instance at the state with the id, for an entity that has already made progress
+func NewMachineInstanceAt(sm *StateMachine, current StateId) (*MachineInstance, error) {
+	if _, ok := sm.lookupValue(current); !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownState, current)
+	}
+	return newMachineInstance(sm, current, time.Now()), nil
+}
+
+// NewMachineInstanceSince is NewMachineInstanceAt for an entity that moved into the state at the time, so its timed transitions are due on time
+func NewMachineInstanceSince(sm *StateMachine, current StateId, entered time.Time) (*MachineInstance, error) {
+	mi, err := NewMachineInstanceAt(sm, current)
+	if err != nil {
+		return nil, err
+	}
+	mi.entered = entered
+	return mi, nil
+}
+
+func (mi *MachineInstance) Current() StateId {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+	return mi.current
+}
+
+// Value returns the value of the current state
+func (mi *MachineInstance) Value() StateValue {
+	v, _ := mi.Machine.lookupValue(mi.Current())
+	return v
+}
+
+// Terminal reports whether the current state is terminal
+func (mi *MachineInstance) Terminal() bool {
+	s, err := mi.Machine.lookupRingState(mi.Current())
+	if err != nil {
+		return false
+	}
+	_, terminal := ringStateEdges(s)
+	return terminal
+}
+
+// Step tests the data against the transitions of the current state and moves to the next state
+// the instance stays where it is when there is an error, including SameStateNoUpdate
+func (mi *MachineInstance) Step(testData any) (StateValue, error) {
+	return mi.StepCtx(context.Background(), testData)
+}
+
+// the hooks and subscriptions run after the instance has moved and without its lock, so they can use the instance
+func (mi *MachineInstance) StepCtx(ctx context.Context, testData any) (StateValue, error) {
+	// steps of one instance are serialized, so two steps can not both move from the same state
+	mi.mu.Lock()
+	in, _ := mi.Machine.lookupValue(mi.current)
+	nextId, value, fire, err := mi.Machine.planFromId(ctx, mi.current, in, testData, "")
+	if err != nil {
+		// a state with a RetryPolicy is tested again before the step fails
+		if nextId, value, fire, err = mi.retryStep(ctx, in, testData, err); err != nil {
+			mi.mu.Unlock()
+			return nil, err
+		}
+	}
+	mi.moveTo(nextId, mi.Now())
+	mi.mu.Unlock()
+	fire()
+	return value, nil
+}
+
+// moves the instance to the state, mi.mu must be held
+func (mi *MachineInstance) moveTo(id StateId, entered time.Time) {
+	mi.current = id
+	mi.entered = entered
+	select {
+	case mi.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,100 @@
+package fielder
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMachineInstance(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	a, b := NewMachineInstance(sm), NewMachineInstance(sm)
+	if a.Current() != "red" || a.Value() != "Red" {
+		t.Fatalf("an instance starts at the start state: got %s", a.Current())
+	}
+	if v, err := a.Step("go"); err != nil || v != "Green" || a.Current() != "green" {
+		t.Errorf("got %v %v", v, err)
+	}
+	if b.Current() != "red" {
+		t.Error("instances of one machine move separately")
+	}
+	if _, err := a.Step("stop"); !errors.Is(err, ErrNoTransition) || a.Current() != "green" {
+		t.Errorf("an instance stays where it is on an error: got %v at %s", err, a.Current())
+	}
+	if a.Terminal() {
+		t.Error("green is not terminal")
+	}
+}
+
+func TestMachineInstanceAt(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	mi, err := NewMachineInstanceAt(sm, "yellow")
+	if err != nil || mi.Current() != "yellow" {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := NewMachineInstanceAt(sm, "blue"); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+	entered := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mi, err = NewMachineInstanceSince(sm, "green", entered)
+	if err != nil || !mi.EnteredAt().Equal(entered) {
+		t.Errorf("got %v %v", mi.EnteredAt(), err)
+	}
+}
+
+func TestMachineInstanceHooksUseInstance(t *testing.T) {
+	// hooks and subscriptions run without the instance's lock, so reading or stepping the instance from them does not deadlock
+	sm := NewStateMachine(testLightStates()...)
+	mi := NewMachineInstance(sm)
+	var seen []StateId
+	sm.OnTransition(func(e TransitionEvent) {
+		seen = append(seen, mi.Current())
+		if e.To == "green" {
+			mi.Fire("unknown", nil)
+			mi.StepWeighted(rand.New(rand.NewSource(1)), "stop")
+			mi.Expire()
+		}
+	})
+	ch := make(chan TransitionEvent, 1)
+	sub := sm.Subscribe(ch, Block)
+	defer sub.Unsubscribe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mi.Step("go")
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the hook deadlocked the instance")
+	}
+	if len(seen) != 1 || seen[0] != "green" {
+		t.Errorf("hooks see the instance after it moved: got %v", seen)
+	}
+	if e := <-ch; e.To != "green" {
+		t.Errorf("got %+v", e)
+	}
+}
+
+func TestMachineInstanceConcurrent(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	mi := NewMachineInstance(sm)
+	var wg sync.WaitGroup
+	moved := make(chan StateValue, 30)
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := mi.Step("go"); err == nil {
+				moved <- v
+			}
+		}()
+	}
+	wg.Wait()
+	close(moved)
+	if len(moved) != 30 || mi.Current() != "red" {
+		t.Errorf("steps are serialized: %d moves, at %s", len(moved), mi.Current())
+	}
+}
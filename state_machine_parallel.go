@@ -0,0 +1,157 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var ErrDuplicateRegion = errors.New("region name is used by more than one region")
+
+// Region is one independent part of a composite state, ex: "docs" and "payment" of an order that needs both before it can ship
+// each region moves through its own machine, the regions do not wait on each other
+type Region struct {
+	Name    string
+	Machine *StateMachine
+}
+
+// ParallelInstance is the progress of one entity through a composite state, it holds an instance for every region
+// the composite state is terminal only when every region is terminal
+type ParallelInstance struct {
+	names   []string
+	regions map[string]*MachineInstance
+}
+
+// NewParallelInstance returns an instance with every region at the start state of its machine
+func NewParallelInstance(regions ...Region) (*ParallelInstance, error) {
+	p := &ParallelInstance{regions: make(map[string]*MachineInstance, len(regions))}
+	for _, r := range regions {
+		if _, ok := p.regions[r.Name]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateRegion, r.Name)
+		}
+		p.names = append(p.names, r.Name)
+		p.regions[r.Name] = NewMachineInstance(r.Machine)
+	}
+	return p, nil
+}
+
+// Region returns the instance of the region with the name, or nil
+func (p *ParallelInstance) Region(name string) *MachineInstance {
+	return p.regions[name]
+}
+
+// Regions returns the region names in the order they were given
+func (p *ParallelInstance) Regions() []string {
+	return append([]string{}, p.names...)
+}
+
+// Current returns the id of the current state of every region
+func (p *ParallelInstance) Current() map[string]StateId {
+	out := make(map[string]StateId, len(p.names))
+	for _, name := range p.names {
+		out[name] = p.regions[name].Current()
+	}
+	return out
+}
+
+// Terminal reports whether every region is in a terminal state
+func (p *ParallelInstance) Terminal() bool {
+	for _, name := range p.names {
+		if !p.regions[name].Terminal() {
+			return false
+		}
+	}
+	return true
+}
+
+// Step tests the data against every region that is not terminal, the regions are stepped concurrently
+// it returns the new values of the regions that moved, a region that has no transition for the data stays where it is
+// and is not an error, any other errors are returned joined, and the regions they did not come from still move
+func (p *ParallelInstance) Step(testData any) (map[string]StateValue, error) {
+	return p.StepCtx(context.Background(), testData)
+}
+
+func (p *ParallelInstance) StepCtx(ctx context.Context, testData any) (map[string]StateValue, error) {
+	return p.each(func(mi *MachineInstance) (StateValue, error) {
+		return mi.StepCtx(ctx, testData)
+	})
+}
+
+// Fire fires the event in every region that permits it, it is ErrEventNotPermitted when no region does
+func (p *ParallelInstance) Fire(event string, payload any) (map[string]StateValue, error) {
+	return p.FireCtx(context.Background(), event, payload)
+}
+
+func (p *ParallelInstance) FireCtx(ctx context.Context, event string, payload any) (map[string]StateValue, error) {
+	permitted := false
+	for _, name := range p.names {
+		if SliceContains(p.regions[name].PermittedEvents(), event, func(s1, s2 string) bool { return s1 == s2 }) {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotPermitted, event)
+	}
+	return p.each(func(mi *MachineInstance) (StateValue, error) {
+		if !SliceContains(mi.PermittedEvents(), event, func(s1, s2 string) bool { return s1 == s2 }) {
+			return nil, SameStateNoUpdate
+		}
+		return mi.FireCtx(ctx, event, payload)
+	})
+}
+
+// PermittedEvents returns the events that can be fired in at least one region, sorted
+func (p *ParallelInstance) PermittedEvents() []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, name := range p.names {
+		for _, e := range p.regions[name].PermittedEvents() {
+			if !seen[e] {
+				seen[e] = true
+				out = append(out, e)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (p *ParallelInstance) each(step func(mi *MachineInstance) (StateValue, error)) (map[string]StateValue, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	moved := map[string]StateValue{}
+	errs := make([]error, len(p.names))
+	for i, name := range p.names {
+		mi := p.regions[name]
+		if mi.Terminal() {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, name string, mi *MachineInstance) {
+			defer wg.Done()
+			value, err := step(mi)
+			switch {
+			case errors.Is(err, SameStateNoUpdate), errors.Is(err, ErrNoTransition):
+				return
+			case err != nil:
+				errs[i] = fmt.Errorf("region %s: %w", name, err)
+				return
+			}
+			mu.Lock()
+			moved[name] = value
+			mu.Unlock()
+		}(i, name, mi)
+	}
+	wg.Wait()
+	return moved, errors.Join(errs...)
+}
+
+// RegionsTerminal is a matcher for the transition out of a composite state, it matches a *ParallelInstance whose regions are all terminal
+// ex: State{Id: "fulfilling", Matches: []Transition{{NextState: "shipped", SimpleMatcher: RegionsTerminal}}}
+func RegionsTerminal(inputToMatch any) bool {
+	p, ok := inputToMatch.(*ParallelInstance)
+	return ok && p.Terminal()
+}
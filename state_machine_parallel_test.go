@@ -0,0 +1,89 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testRegion(t *testing.T, name string, matcher SimpleMatcher) Region {
+	t.Helper()
+	sm, err := NewMachineBuilder().State("waiting", name+" waiting").On(matcher).GoTo("done").Terminal("done").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Region{Name: name, Machine: sm}
+}
+
+func TestParallelInstance(t *testing.T) {
+	p, err := NewParallelInstance(
+		testRegion(t, "docs", func(in any) bool { return in == "signed" || in == "all" }),
+		testRegion(t, "payment", func(in any) bool { return in == "paid" || in == "all" }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.Regions(), []string{"docs", "payment"}) {
+		t.Errorf("got %v", p.Regions())
+	}
+	moved, err := p.Step("signed")
+	if err != nil || !reflect.DeepEqual(moved, map[string]StateValue{"docs": "done"}) {
+		t.Errorf("a region without a transition stays and is not an error: got %v %v", moved, err)
+	}
+	if p.Terminal() || RegionsTerminal(p) {
+		t.Error("payment is still waiting")
+	}
+	moved, err = p.Step("all")
+	if err != nil || !reflect.DeepEqual(moved, map[string]StateValue{"payment": "done"}) {
+		t.Errorf("terminal regions are not stepped: got %v %v", moved, err)
+	}
+	if !p.Terminal() || !RegionsTerminal(p) || RegionsTerminal("not an instance") {
+		t.Error("every region is terminal")
+	}
+	if !reflect.DeepEqual(p.Current(), map[string]StateId{"docs": "done", "payment": "done"}) {
+		t.Errorf("got %v", p.Current())
+	}
+	if p.Region("docs") == nil || p.Region("missing") != nil {
+		t.Error("regions are found by name")
+	}
+}
+
+func TestParallelInstanceFire(t *testing.T) {
+	events, err := NewStateMachineChecked(
+		State{Id: "a", StateValue: "A", Matches: []Transition{{NextState: "b", Event: "go"}, {NextState: "b", Event: "also"}}},
+		State{Id: "b", StateValue: "B", Terminal: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewParallelInstance(Region{Name: "events", Machine: events}, testRegion(t, "plain", testIsGo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.PermittedEvents(); !reflect.DeepEqual(got, []string{"also", "go"}) {
+		t.Errorf("got %v", got)
+	}
+	if _, err := p.Fire("stop", nil); !errors.Is(err, ErrEventNotPermitted) {
+		t.Errorf("got %v", err)
+	}
+	moved, err := p.Fire("go", nil)
+	if err != nil || !reflect.DeepEqual(moved, map[string]StateValue{"events": "B"}) {
+		t.Errorf("got %v %v", moved, err)
+	}
+}
+
+func TestParallelInstanceErrors(t *testing.T) {
+	if _, err := NewParallelInstance(testRegion(t, "a", testIsGo), testRegion(t, "a", testIsGo)); !errors.Is(err, ErrDuplicateRegion) {
+		t.Errorf("got %v", err)
+	}
+	failing := NewStateMachine(
+		State{Id: "a", StateValue: "A", Matches: []Transition{{NextState: "b", Guard: func(context.Context, any) (bool, error) { return false, errTestLookup }}}},
+		State{Id: "b", StateValue: "B"},
+	)
+	p, _ := NewParallelInstance(Region{Name: "failing", Machine: failing}, testRegion(t, "plain", testIsGo))
+	moved, err := p.Step("go")
+	if !errors.Is(err, errTestLookup) || moved["plain"] != "done" {
+		t.Errorf("the other regions still move: got %v %v", moved, err)
+	}
+}
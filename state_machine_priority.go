@@ -0,0 +1,80 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrAmbiguousTransition = errors.New("more than one transition matches")
+	ErrBuilderNoTransition = errors.New("Priority called before any GoTo")
+)
+
+// SetStrict makes the machine test every transition of a state, and return ErrAmbiguousTransition when more than one matches
+// instead of taking the one with the highest priority
+func (sm *StateMachine) SetStrict(strict bool) {
+	defer sm.lock()()
+	sm.strict = strict
+}
+
+func (sm *StateMachine) isStrict() bool {
+	defer sm.rLock()()
+	return sm.strict
+}
+
+// returns the indexes of n transitions in the order they are tested, highest priority first and slice order within a priority
+func transitionOrder(n int, priority func(i int) int) []int {
+	out := make([]int, n)
+	sorted := true
+	for i := range out {
+		out[i] = i
+		if i > 0 && priority(i) > priority(i-1) {
+			sorted = false
+		}
+	}
+	if !sorted {
+		sort.SliceStable(out, func(i, j int) bool { return priority(out[i]) > priority(out[j]) })
+	}
+	return out
+}
+
+// picks the next state from the indexes of the transitions that matched, falling back to the else state when none did
+func chooseTransition(id, elseId StateId, matched []int, next func(i int) StateId) (StateId, int, error) {
+	switch {
+	case len(matched) == 1:
+		return next(matched[0]), matched[0], nil
+	case len(matched) > 1:
+		ids := make([]StateId, 0, len(matched))
+		for _, i := range matched {
+			ids = append(ids, next(i))
+		}
+		return "", -1, fmt.Errorf("state %s: %w: %v", id, ErrAmbiguousTransition, ids)
+	case elseId != "":
+		return elseId, -1, nil
+	}
+	return "", -1, fmt.Errorf("state %s: %w", id, ErrNoTransition)
+}
+
+// Else sets the state the current state of the builder moves to when none of its transitions match
+func (b *MachineBuilder) Else(next StateId) *MachineBuilder {
+	b.closePending()
+	if b.current < 0 {
+		b.errs = append(b.errs, ErrBuilderNoState)
+		return b
+	}
+	b.states[b.current].Else = next
+	return b
+}
+
+// Priority sets the priority of the transition most recently finished by GoTo
+func (b *MachineBuilder) Priority(priority int) *MachineBuilder {
+	b.closePending()
+	if b.current < 0 || len(b.states[b.current].Matches) == 0 {
+		b.errs = append(b.errs, ErrBuilderNoTransition)
+		return b
+	}
+	matches := b.states[b.current].Matches
+	matches[len(matches)-1].Priority = priority
+	return b
+}
@@ -0,0 +1,79 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTransitionPriority(t *testing.T) {
+	always := func(any) bool { return true }
+	s := State{Id: "a", Matches: []Transition{
+		{NextState: "low", SimpleMatcher: always},
+		{NextState: "high", SimpleMatcher: always, Priority: 10},
+		{NextState: "also high", SimpleMatcher: always, Priority: 10},
+	}}
+	if next, err := s.EvaluateTransition(nil); err != nil || next != "high" {
+		t.Errorf("the highest priority wins, then slice order: got %s %v", next, err)
+	}
+	if got := transitionOrder(3, func(i int) int { return s.Matches[i].Priority }); !reflect.DeepEqual(got, []int{1, 2, 0}) {
+		t.Errorf("got %v", got)
+	}
+	if next, _, err := s.selectTransition(context.Background(), nil, true); !errors.Is(err, ErrAmbiguousTransition) || next != "" {
+		t.Errorf("strict evaluation reports the overlap: got %s %v", next, err)
+	}
+}
+
+func TestElse(t *testing.T) {
+	sm, err := NewMachineBuilder().
+		State("a", "A").On(testIsGo).GoTo("b").Else("c").
+		State("b", "B").
+		State("c", "C").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, _, err := sm.ProcessFromId("a", "stop"); err != nil || next != "c" {
+		t.Errorf("got %s %v", next, err)
+	}
+	if next, _, err := sm.ProcessFromId("a", "go"); err != nil || next != "b" {
+		t.Errorf("got %s %v", next, err)
+	}
+}
+
+func TestSetStrict(t *testing.T) {
+	always := func(any) bool { return true }
+	sm := NewStateMachine(
+		State{Id: "a", StateValue: "A", Matches: []Transition{{NextState: "b", SimpleMatcher: always}, {NextState: "c", SimpleMatcher: always}}},
+		State{Id: "b", StateValue: "B"},
+		State{Id: "c", StateValue: "C"},
+	)
+	if next, _, err := sm.ProcessFromId("a", nil); err != nil || next != "b" {
+		t.Errorf("got %s %v", next, err)
+	}
+	sm.SetStrict(true)
+	if _, _, err := sm.ProcessFromId("a", nil); !errors.Is(err, ErrAmbiguousTransition) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestMachineBuilderPriority(t *testing.T) {
+	always := func(any) bool { return true }
+	sm, err := NewMachineBuilder().
+		State("a", "A").On(always).GoTo("b").On(always).GoTo("c").Priority(1).
+		State("b", "B").State("c", "C").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, _, err := sm.ProcessFromId("a", nil); err != nil || next != "c" {
+		t.Errorf("got %s %v", next, err)
+	}
+	if _, err := NewMachineBuilder().State("a", "A").Priority(1).Build(); !errors.Is(err, ErrBuilderNoTransition) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := NewMachineBuilder().Else("a").Build(); !errors.Is(err, ErrBuilderNoState) {
+		t.Errorf("got %v", err)
+	}
+}
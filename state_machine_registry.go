@@ -0,0 +1,89 @@
+package fielder
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registry holds matchers and conditionals under names, so machine definitions can refer to them
+type Registry struct {
+	mu           sync.RWMutex
+	matchers     map[string]SimpleMatcher
+	conditionals map[string]Conditional
+}
+
+func NewRegistry() *Registry {
+	return &Registry{matchers: map[string]SimpleMatcher{}, conditionals: map[string]Conditional{}}
+}
+
+// DefaultRegistry is the registry used by the package level functions
+var DefaultRegistry = NewRegistry()
+
+// RegisterMatcher registers a matcher under a name, registering a name again replaces the matcher
+func (r *Registry) RegisterMatcher(name string, m SimpleMatcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.matchers[name] = m
+}
+
+func (r *Registry) RegisterConditional(name string, c Conditional) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conditionals[name] = c
+}
+
+func (r *Registry) Matcher(name string) (SimpleMatcher, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.matchers[name]
+	return m, ok
+}
+
+func (r *Registry) Conditional(name string) (Conditional, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.conditionals[name]
+	return c, ok
+}
+
+func RegisterMatcher(name string, m SimpleMatcher) {
+	DefaultRegistry.RegisterMatcher(name, m)
+}
+
+func RegisterConditional(name string, c Conditional) {
+	DefaultRegistry.RegisterConditional(name, c)
+}
+
+func LookupMatcher(name string) (SimpleMatcher, bool) {
+	return DefaultRegistry.Matcher(name)
+}
+
+func LookupConditional(name string) (Conditional, bool) {
+	return DefaultRegistry.Conditional(name)
+}
+
+// NamedTransition returns a transition using the matcher registered under the name
+func NamedTransition(next StateId, matcher string) (Transition, error) {
+	return DefaultRegistry.NamedTransition(next, matcher)
+}
+
+func (r *Registry) NamedTransition(next StateId, matcher string) (Transition, error) {
+	m, ok := r.Matcher(matcher)
+	if !ok {
+		return Transition{}, fmt.Errorf("%w: %s", ErrUnknownMatcher, matcher)
+	}
+	return Transition{NextState: next, SimpleMatcher: m, MatcherName: matcher}, nil
+}
+
+// NamedConditionalTransition returns a transition using the conditional registered under the name
+func NamedConditionalTransition(next StateId, conditional string) (ConditionalTransition, error) {
+	return DefaultRegistry.NamedConditionalTransition(next, conditional)
+}
+
+func (r *Registry) NamedConditionalTransition(next StateId, conditional string) (ConditionalTransition, error) {
+	c, ok := r.Conditional(conditional)
+	if !ok {
+		return ConditionalTransition{}, fmt.Errorf("%w: %s", ErrUnknownMatcher, conditional)
+	}
+	return ConditionalTransition{NextState: next, Conditional: c, ConditionalName: conditional}, nil
+}
@@ -0,0 +1,71 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterMatcher("go", testIsGo)
+	r.RegisterConditional("not bad", testNotBad())
+	if _, ok := r.Matcher("stop"); ok {
+		t.Error("unregistered names are not found")
+	}
+	tr, err := r.NamedTransition("b", "go")
+	if err != nil || tr.NextState != "b" || tr.MatcherName != "go" || !tr.SimpleMatcher("go") {
+		t.Errorf("got %+v %v", tr, err)
+	}
+	if _, err := r.NamedTransition("b", "stop"); !errors.Is(err, ErrUnknownMatcher) {
+		t.Errorf("got %v", err)
+	}
+	ct, err := r.NamedConditionalTransition("b", "not bad")
+	if err != nil || ct.ConditionalName != "not bad" || ct.Conditional == nil {
+		t.Errorf("got %+v %v", ct, err)
+	}
+	if _, err := r.NamedConditionalTransition("b", "missing"); !errors.Is(err, ErrUnknownMatcher) {
+		t.Errorf("got %v", err)
+	}
+	// registering a name again replaces it
+	r.RegisterMatcher("go", func(any) bool { return false })
+	if m, _ := r.Matcher("go"); m("go") {
+		t.Error("the matcher was not replaced")
+	}
+	if _, ok := LookupMatcher("go"); ok {
+		t.Error("a new registry is separate from the default")
+	}
+}
+
+func TestRegistryMachines(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterMatcher("go", testIsGo)
+	def := MachineDefinition{States: []StateDefinition{
+		{Id: "a", Value: "A", Transitions: []TransitionDefinition{{NextState: "b", Matcher: "go"}}},
+		{Id: "b", Value: "B"},
+	}}
+	sm, err := r.StateMachine(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, _, err := sm.ProcessFromId("a", "go"); err != nil || next != "b" {
+		t.Errorf("got %s %v", next, err)
+	}
+	if _, err := NewStateMachineFromDefinition(def); !errors.Is(err, ErrUnknownMatcher) {
+		t.Errorf("the default registry does not have the matcher: got %v", err)
+	}
+	// a conditional machine needs conditionals, not matchers
+	if _, err := r.ConditionalStateMachine(def); !errors.Is(err, ErrMachineKind) {
+		t.Errorf("got %v", err)
+	}
+	r.RegisterConditional("not bad", testNotBad())
+	def.States[0].Transitions = []TransitionDefinition{{NextState: "b", Conditional: "not bad"}}
+	if _, err := r.ConditionalStateMachine(def); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMachineBuilderOnNamed(t *testing.T) {
+	if _, err := NewMachineBuilder().State("a", 1).OnNamed("test.unregistered").GoTo("a").Build(); !errors.Is(err, ErrUnknownMatcher) {
+		t.Errorf("got %v", err)
+	}
+}
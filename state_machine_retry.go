@@ -0,0 +1,102 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInstanceMoved = errors.New("instance moved while the step waited to retry")
+
+// RetryPolicy is how a MachineInstance handles a state with no transition for the data, for matchers that ask other services
+// the state is tested again with the same data up to MaxAttempts times in all, waiting Backoff before the second attempt
+// and twice as long before each one after, up to MaxBackoff when it is set
+// when every attempt fails the instance moves to the Fallback state, or returns the last error when there is none
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Fallback    StateId
+}
+
+// Delay returns the wait before the attempt, the first attempt has none
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	if attempt < 2 || p.Backoff <= 0 {
+		return 0
+	}
+	d := p.Backoff
+	for i := 2; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// waits for the duration or until the context is done
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retries the current state after a step failed with err, mi.mu must be held
+// only ErrNoTransition is retried, every other error is returned as it is
+// the lock is released while waiting between attempts, so the instance can be read and moved in the meantime,
+// the step fails with ErrInstanceMoved when it was, and mi.mu is held again when this returns
+// the hooks of the move are not fired, they are returned for the caller to fire
+func (mi *MachineInstance) retryStep(ctx context.Context, in StateValue, testData any, err error) (StateId, StateValue, func(), error) {
+	from := mi.current
+	current, lookupErr := mi.Machine.lookupRingState(from)
+	if lookupErr != nil || !errors.Is(err, ErrNoTransition) {
+		return "", nil, nil, err
+	}
+	s, _ := asMachineState(current)
+	policy := s.Retries()
+	if policy == nil {
+		return "", nil, nil, err
+	}
+	for attempt := 2; attempt <= policy.MaxAttempts; attempt++ {
+		mi.mu.Unlock()
+		serr := mi.Sleep(ctx, policy.Delay(attempt))
+		mi.mu.Lock()
+		if serr != nil {
+			return "", nil, nil, serr
+		}
+		if mi.current != from {
+			return "", nil, nil, fmt.Errorf("state %s: %w to %s", from, ErrInstanceMoved, mi.current)
+		}
+		nextId, value, fire, err2 := mi.Machine.planFromId(ctx, from, in, testData, "")
+		if err2 == nil || !errors.Is(err2, ErrNoTransition) {
+			return nextId, value, fire, err2
+		}
+		err = err2
+	}
+	if policy.Fallback == "" {
+		return "", nil, nil, fmt.Errorf("after %d attempts: %w", max(policy.MaxAttempts, 1), err)
+	}
+	return mi.Machine.planMove(ctx, from, in, testData, "", func(any) (StateId, error) {
+		return policy.Fallback, nil
+	})
+}
+
+// Retry sets the retry policy of the current state of the builder
+func (b *MachineBuilder) Retry(policy RetryPolicy) *MachineBuilder {
+	b.closePending()
+	if b.current < 0 {
+		b.errs = append(b.errs, ErrBuilderNoState)
+		return b
+	}
+	b.states[b.current].Retry = &policy
+	return b
+}
@@ -0,0 +1,96 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	var got []time.Duration
+	for attempt := 1; attempt <= 6; attempt++ {
+		got = append(got, p.Delay(attempt))
+	}
+	want := []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v", got)
+	}
+	if (RetryPolicy{}).Delay(3) != 0 || (RetryPolicy{Backoff: time.Second}).Delay(5) != 8*time.Second {
+		t.Error("got a wrong delay")
+	}
+}
+
+// a machine whose check state matches on the attempt given by ready, and falls back to failed when fallback is set
+func testRetryMachine(t *testing.T, ready int, fallback StateId) (*MachineInstance, *[]time.Duration) {
+	t.Helper()
+	attempts := 0
+	sm, err := NewMachineBuilder().
+		State("check", "Check").On(func(any) bool { attempts++; return attempts >= ready }).GoTo("ok").
+		Retry(RetryPolicy{MaxAttempts: 3, Backoff: time.Second, Fallback: fallback}).
+		OnEvent("cancel").GoTo("cancelled").
+		State("ok", "Ok").
+		State("failed", "Failed").
+		State("cancelled", "Cancelled").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mi := NewMachineInstance(sm)
+	var waits []time.Duration
+	mi.Sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return mi, &waits
+}
+
+func TestRetryStep(t *testing.T) {
+	mi, waits := testRetryMachine(t, 3, "")
+	if v, err := mi.Step(nil); err != nil || v != "Ok" {
+		t.Errorf("got %v %v", v, err)
+	}
+	if !reflect.DeepEqual(*waits, []time.Duration{time.Second, 2 * time.Second}) {
+		t.Errorf("got %v", *waits)
+	}
+	mi, _ = testRetryMachine(t, 4, "failed")
+	if v, err := mi.Step(nil); err != nil || v != "Failed" {
+		t.Errorf("got %v %v", v, err)
+	}
+	mi, _ = testRetryMachine(t, 4, "")
+	if _, err := mi.Step(nil); !errors.Is(err, ErrNoTransition) || mi.Current() != "check" {
+		t.Errorf("got %v", err)
+	}
+	mi, _ = testRetryMachine(t, 4, "failed")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mi.StepCtx(ctx, nil); !errors.Is(err, context.Canceled) || mi.Current() != "check" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestRetryStepUnlocked(t *testing.T) {
+	// the instance can be read and moved while a step waits to retry, the step then fails
+	mi, _ := testRetryMachine(t, 3, "failed")
+	mi.Sleep = func(ctx context.Context, d time.Duration) error {
+		if mi.Current() != "check" {
+			t.Error("got a moved instance")
+		}
+		_, err := mi.Fire("cancel", nil)
+		return err
+	}
+	if _, err := mi.Step(nil); !errors.Is(err, ErrInstanceMoved) {
+		t.Errorf("got %v", err)
+	}
+	if mi.Current() != "cancelled" {
+		t.Errorf("got %s", mi.Current())
+	}
+}
+
+func TestMachineBuilderRetry(t *testing.T) {
+	if _, err := NewMachineBuilder().Retry(RetryPolicy{}).Build(); !errors.Is(err, ErrBuilderNoState) {
+		t.Errorf("got %v", err)
+	}
+}
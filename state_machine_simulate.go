@@ -0,0 +1,154 @@
+package fielder
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// TB is the part of testing.TB the simulation asserts with, so *testing.T can be passed without this package importing testing
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// SimulationStep is one input given to the machine during a simulation
+type SimulationStep struct {
+	Input any
+	From  StateId
+	To    StateId // the same as From when the machine did not move
+	Value StateValue
+	// the registered name of the matcher or conditional that was taken, "#n" for the nth transition of the state when it has no name,
+	// "else" for the Else state and "" when the machine did not move
+	Matcher string
+	Err     error
+}
+
+// Simulation is the run of a sequence of inputs through a machine, ex:
+// sim := Simulate(sm, order1, order2)
+// sim.AssertPath(t, "draft", "review", "done")
+type Simulation struct {
+	Machine *StateMachine
+	Start   StateId
+	Steps   []SimulationStep
+}
+
+// Simulate steps the machine from its start state once for each input, a step that errors leaves the machine where it is and the next input is tried from there
+func Simulate(sm *StateMachine, inputs ...any) *Simulation {
+	return SimulateFrom(sm, sm.Start, inputs...)
+}
+
+func SimulateFrom(sm *StateMachine, start StateId, inputs ...any) *Simulation {
+	return simulate(sm, start, inputs, func(s MachineState, in any) (StateId, int, error) {
+		return s.selectTransition(context.Background(), in, sm.isStrict())
+	})
+}
+
+// steps the machine once for each input, choosing the transition with choose
+func simulate(sm *StateMachine, start StateId, inputs []any, choose func(s MachineState, in any) (StateId, int, error)) *Simulation {
+	sim := &Simulation{Machine: sm, Start: start}
+	current := start
+	for _, in := range inputs {
+		step := SimulationStep{Input: in, From: current, To: current}
+		taken := -1
+		from, _ := sm.lookupValue(current)
+		next, value, err := sm.moveFromId(context.Background(), current, from, in, "", func(v any) (StateId, error) {
+			s, _ := asMachineState(v)
+			next, i, err := choose(s, in)
+			taken = i
+			return next, err
+		})
+		step.Err = err
+		if err == nil {
+			step.To = next
+			step.Value = value
+			current = next
+			step.Matcher = simulationMatcher(sm, step.From, taken)
+		}
+		sim.Steps = append(sim.Steps, step)
+	}
+	return sim
+}
+
+func simulationMatcher(sm *StateMachine, id StateId, taken int) string {
+	s, err := sm.lookupRingState(id)
+	if err != nil {
+		return ""
+	}
+	name := ""
+	switch s := s.(type) {
+	case State:
+		if taken < 0 {
+			return "else"
+		}
+		name = s.Matches[taken].MatcherName
+	case ConditionalState:
+		if taken < 0 {
+			return "else"
+		}
+		name = s.Outcomes[taken].ConditionalName
+	}
+	if name == "" {
+		return fmt.Sprintf("#%d", taken)
+	}
+	return name
+}
+
+// Path returns the start state followed by the state after every step
+func (s *Simulation) Path() []StateId {
+	out := []StateId{s.Start}
+	for _, step := range s.Steps {
+		out = append(out, step.To)
+	}
+	return out
+}
+
+// Matchers returns the matcher taken at every step
+func (s *Simulation) Matchers() []string {
+	out := make([]string, 0, len(s.Steps))
+	for _, step := range s.Steps {
+		out = append(out, step.Matcher)
+	}
+	return out
+}
+
+// Current returns the state the machine was left in
+func (s *Simulation) Current() StateId {
+	if len(s.Steps) == 0 {
+		return s.Start
+	}
+	return s.Steps[len(s.Steps)-1].To
+}
+
+// AssertPath reports an error to t when the path is not the one wanted, the start state included
+func (s *Simulation) AssertPath(t TB, want ...StateId) bool {
+	t.Helper()
+	if got := s.Path(); !reflect.DeepEqual(got, want) {
+		t.Errorf("state path = %v, want %v", got, want)
+		return false
+	}
+	return true
+}
+
+// AssertMatchers reports an error to t when the matchers taken are not the ones wanted
+func (s *Simulation) AssertMatchers(t TB, want ...string) bool {
+	t.Helper()
+	if got := s.Matchers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("matchers = %q, want %q", got, want)
+		return false
+	}
+	return true
+}
+
+// AssertNoErrors reports every step that errored to t
+func (s *Simulation) AssertNoErrors(t TB) bool {
+	t.Helper()
+	ok := true
+	for i, step := range s.Steps {
+		if step.Err != nil {
+			t.Errorf("step %d from %s with %v: %v", i, step.From, step.Input, step.Err)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,64 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// a TB that keeps what it was told
+type testRecorder struct {
+	errs []string
+}
+
+func (r *testRecorder) Helper() {}
+
+func (r *testRecorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func testDraftMachine() *StateMachine {
+	return NewStateMachine(
+		State{Id: "draft", StateValue: "Draft", Start: true, Matches: []Transition{{NextState: "review", SimpleMatcher: testIsGo, MatcherName: "submit"}}},
+		State{Id: "review", StateValue: "Review", Matches: []Transition{{NextState: "done", SimpleMatcher: testIsGo}}, Else: "draft"},
+		State{Id: "done", StateValue: "Done", Terminal: true},
+	)
+}
+
+func TestSimulate(t *testing.T) {
+	sim := Simulate(testDraftMachine(), "go", "stop", "go", "go")
+	sim.AssertPath(t, "draft", "review", "draft", "review", "done")
+	sim.AssertMatchers(t, "submit", "else", "submit", "#0")
+	sim.AssertNoErrors(t)
+	if sim.Current() != "done" || sim.Steps[3].Value != "Done" {
+		t.Errorf("got %s %v", sim.Current(), sim.Steps[3].Value)
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	// a step that errors leaves the machine where it was
+	sim := SimulateFrom(testDraftMachine(), "done", "go")
+	sim.AssertPath(t, "done", "done")
+	if step := sim.Steps[0]; !errors.Is(step.Err, SameStateNoUpdate) || step.Matcher != "" {
+		t.Errorf("got %+v", step)
+	}
+	sim = Simulate(testDraftMachine(), "stop", "go")
+	sim.AssertPath(t, "draft", "draft", "review")
+	if !errors.Is(sim.Steps[0].Err, ErrNoTransition) {
+		t.Errorf("got %v", sim.Steps[0].Err)
+	}
+	if empty := Simulate(testDraftMachine()); empty.Current() != "draft" {
+		t.Errorf("got %s", empty.Current())
+	}
+}
+
+func TestSimulateAssertions(t *testing.T) {
+	r := &testRecorder{}
+	sim := Simulate(testDraftMachine(), "stop")
+	if sim.AssertPath(r, "draft", "review") || sim.AssertMatchers(r, "submit") || sim.AssertNoErrors(r) {
+		t.Error("the assertions fail")
+	}
+	if len(r.errs) != 3 {
+		t.Errorf("got %q", r.errs)
+	}
+}
@@ -0,0 +1,305 @@
+package fielder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// functions can not be saved, so a machine is saved with the names its matchers and conditionals are registered under
+// and they are looked up by name again when it is restored
+// state values are saved as themselves, so they come back as their json types, ex: a number comes back as a float64
+
+var (
+	ErrUnnamedMatcher = errors.New("transition matcher has no registered name")
+	ErrUnknownMatcher = errors.New("no matcher is registered under the name")
+	ErrMachineKind    = errors.New("definition can not be used for this kind of machine")
+)
+
+// a transition refers to a matcher for a StateMachine, or to a conditional for a ConditionalStateMachine
+type TransitionDefinition struct {
+	NextState   StateId `dynamodbav:"next" json:"next" yaml:"next"`
+	Matcher     string  `dynamodbav:"matcher,omitempty" json:"matcher,omitempty" yaml:"matcher,omitempty"`
+	Conditional string  `dynamodbav:"conditional,omitempty" json:"conditional,omitempty" yaml:"conditional,omitempty"`
+	Event       string  `dynamodbav:"event,omitempty" json:"event,omitempty" yaml:"event,omitempty"`
+	// the duration of a timed transition, in the form of time.ParseDuration, ex: 24h
+	After    string  `dynamodbav:"after,omitempty" json:"after,omitempty" yaml:"after,omitempty"`
+	Priority int     `dynamodbav:"priority,omitempty" json:"priority,omitempty" yaml:"priority,omitempty"`
+	Weight   float64 `dynamodbav:"weight,omitempty" json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+type StateDefinition struct {
+	Id          StateId                `dynamodbav:"id" json:"id" yaml:"id"`
+	Value       StateValue             `dynamodbav:"value" json:"value" yaml:"value"`
+	Start       bool                   `dynamodbav:"start,omitempty" json:"start,omitempty" yaml:"start,omitempty"`
+	Terminal    bool                   `dynamodbav:"terminal,omitempty" json:"terminal,omitempty" yaml:"terminal,omitempty"`
+	Transitions []TransitionDefinition `dynamodbav:"transitions,omitempty" json:"transitions,omitempty" yaml:"transitions,omitempty"`
+	Else        StateId                `dynamodbav:"else,omitempty" json:"else,omitempty" yaml:"else,omitempty"`
+	Retry       *RetryDefinition       `dynamodbav:"retry,omitempty" json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// the saved form of a RetryPolicy, the durations are in the form of time.ParseDuration
+type RetryDefinition struct {
+	MaxAttempts int     `dynamodbav:"max_attempts" json:"max_attempts" yaml:"max_attempts"`
+	Backoff     string  `dynamodbav:"backoff,omitempty" json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	MaxBackoff  string  `dynamodbav:"max_backoff,omitempty" json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+	Fallback    StateId `dynamodbav:"fallback,omitempty" json:"fallback,omitempty" yaml:"fallback,omitempty"`
+}
+
+func retryDefinition(p *RetryPolicy) *RetryDefinition {
+	if p == nil {
+		return nil
+	}
+	return &RetryDefinition{MaxAttempts: p.MaxAttempts, Backoff: durationDefinition(p.Backoff), MaxBackoff: durationDefinition(p.MaxBackoff), Fallback: p.Fallback}
+}
+
+func (d *RetryDefinition) policy() (*RetryPolicy, error) {
+	if d == nil {
+		return nil, nil
+	}
+	backoff, err := parseDurationDefinition(d.Backoff)
+	if err != nil {
+		return nil, err
+	}
+	maxBackoff, err := parseDurationDefinition(d.MaxBackoff)
+	if err != nil {
+		return nil, err
+	}
+	return &RetryPolicy{MaxAttempts: d.MaxAttempts, Backoff: backoff, MaxBackoff: maxBackoff, Fallback: d.Fallback}, nil
+}
+
+// MachineDefinition is the form a machine is saved in, the states are in the order the machine was created with
+// the yaml tags let a config loader decode definitions directly, ex: yaml.Unmarshal(config, &def)
+type MachineDefinition struct {
+	States []StateDefinition `dynamodbav:"states" json:"states" yaml:"states"`
+}
+
+// ParseMachineDefinition reads a definition from json config
+func ParseMachineDefinition(b []byte) (MachineDefinition, error) {
+	var def MachineDefinition
+	err := json.Unmarshal(b, &def)
+	return def, err
+}
+
+// MachineSnapshot is a machine along with the state one entity is in
+type MachineSnapshot struct {
+	Machine MachineDefinition `dynamodbav:"machine" json:"machine"`
+	Current StateId           `dynamodbav:"current" json:"current"`
+}
+
+// Definition returns the saveable form of the machine, every matcher and conditional needs a registered name
+func (sm *StateMachine) Definition() (MachineDefinition, error) {
+	def := MachineDefinition{States: []StateDefinition{}}
+	var errs []error
+	for _, v := range sm.ringStates() {
+		switch s := v.(type) {
+		case State:
+			sd := StateDefinition{Id: s.Id, Value: s.StateValue, Start: s.Start, Terminal: s.Terminal, Else: s.Else, Retry: retryDefinition(s.Retry)}
+			for _, t := range s.Matches {
+				// an event or timed transition with no guard has no matcher to name
+				if t.MatcherName == "" && ((t.Event == "" && t.After == 0) || t.guarded()) {
+					errs = append(errs, fmt.Errorf("state %s: %w: %s", s.Id, ErrUnnamedMatcher, t.NextState))
+					continue
+				}
+				sd.Transitions = append(sd.Transitions, TransitionDefinition{NextState: t.NextState, Matcher: t.MatcherName, Event: t.Event, After: durationDefinition(t.After), Priority: t.Priority, Weight: t.Weight})
+			}
+			def.States = append(def.States, sd)
+		case ConditionalState:
+			sd := StateDefinition{Id: s.Id, Value: s.StateValue, Start: s.Start, Terminal: s.Terminal, Else: s.Else, Retry: retryDefinition(s.Retry)}
+			for _, t := range s.Outcomes {
+				if t.ConditionalName == "" && ((t.Event == "" && t.After == 0) || t.Conditional != nil) {
+					errs = append(errs, fmt.Errorf("state %s: %w: %s", s.Id, ErrUnnamedMatcher, t.NextState))
+					continue
+				}
+				sd.Transitions = append(sd.Transitions, TransitionDefinition{NextState: t.NextState, Conditional: t.ConditionalName, Event: t.Event, After: durationDefinition(t.After), Priority: t.Priority, Weight: t.Weight})
+			}
+			def.States = append(def.States, sd)
+		default:
+			errs = append(errs, fmt.Errorf("%T can not be saved", v))
+		}
+	}
+	return def, errors.Join(errs...)
+}
+
+// NewStateMachineFromDefinition creates the machine again, looking up the matchers by name in the DefaultRegistry, the machine is validated
+func NewStateMachineFromDefinition(def MachineDefinition) (*StateMachine, error) {
+	return DefaultRegistry.StateMachine(def)
+}
+
+func (r *Registry) StateMachine(def MachineDefinition) (*StateMachine, error) {
+	states := make([]State, 0, len(def.States))
+	var errs []error
+	for _, sd := range def.States {
+		s := State{Id: sd.Id, StateValue: sd.Value, Start: sd.Start, Terminal: sd.Terminal, Else: sd.Else}
+		retry, err := sd.Retry.policy()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("state %s: %w", sd.Id, err))
+		}
+		s.Retry = retry
+		for _, td := range sd.Transitions {
+			after, err := parseDurationDefinition(td.After)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("state %s: %w", sd.Id, err))
+				continue
+			}
+			if td.Matcher == "" {
+				if td.Event == "" && after == 0 {
+					errs = append(errs, fmt.Errorf("state %s: %w: transition to %s has no matcher", sd.Id, ErrMachineKind, td.NextState))
+				} else {
+					s.Matches = append(s.Matches, Transition{NextState: td.NextState, Event: td.Event, After: after, Priority: td.Priority, Weight: td.Weight})
+				}
+				continue
+			}
+			t, err := r.NamedTransition(td.NextState, td.Matcher)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("state %s: %w", sd.Id, err))
+				continue
+			}
+			t.Event = td.Event
+			t.After = after
+			t.Priority = td.Priority
+			t.Weight = td.Weight
+			s.Matches = append(s.Matches, t)
+		}
+		states = append(states, s)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return NewStateMachineChecked(states...)
+}
+
+// NewConditionalStateMachineFromDefinition creates the machine again, looking up the conditionals by name in the DefaultRegistry
+func NewConditionalStateMachineFromDefinition(def MachineDefinition) (*ConditionalStateMachine, error) {
+	return DefaultRegistry.ConditionalStateMachine(def)
+}
+
+func (r *Registry) ConditionalStateMachine(def MachineDefinition) (*ConditionalStateMachine, error) {
+	states := make([]ConditionalState, 0, len(def.States))
+	var errs []error
+	for _, sd := range def.States {
+		s := ConditionalState{Id: sd.Id, StateValue: sd.Value, Start: sd.Start, Terminal: sd.Terminal, Else: sd.Else}
+		retry, err := sd.Retry.policy()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("state %s: %w", sd.Id, err))
+		}
+		s.Retry = retry
+		for _, td := range sd.Transitions {
+			after, err := parseDurationDefinition(td.After)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("state %s: %w", sd.Id, err))
+				continue
+			}
+			if td.Conditional == "" {
+				if td.Event == "" && after == 0 {
+					errs = append(errs, fmt.Errorf("state %s: %w: transition to %s has no conditional", sd.Id, ErrMachineKind, td.NextState))
+				} else {
+					s.Outcomes = append(s.Outcomes, ConditionalTransition{NextState: td.NextState, Event: td.Event, After: after, Priority: td.Priority, Weight: td.Weight})
+				}
+				continue
+			}
+			t, err := r.NamedConditionalTransition(td.NextState, td.Conditional)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("state %s: %w", sd.Id, err))
+				continue
+			}
+			t.Event = td.Event
+			t.After = after
+			t.Priority = td.Priority
+			t.Weight = td.Weight
+			s.Outcomes = append(s.Outcomes, t)
+		}
+		states = append(states, s)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return NewConditionalStateMachineChecked(states...)
+}
+
+func durationDefinition(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func parseDurationDefinition(st string) (time.Duration, error) {
+	if st == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(st)
+}
+
+func (sm *StateMachine) MarshalJSON() ([]byte, error) {
+	def, err := sm.Definition()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(def)
+}
+
+func (sm *StateMachine) UnmarshalJSON(b []byte) error {
+	var def MachineDefinition
+	if err := json.Unmarshal(b, &def); err != nil {
+		return err
+	}
+	restored, err := NewStateMachineFromDefinition(def)
+	if err != nil {
+		return err
+	}
+	*sm = *restored
+	return nil
+}
+
+func (sm *ConditionalStateMachine) UnmarshalJSON(b []byte) error {
+	var def MachineDefinition
+	if err := json.Unmarshal(b, &def); err != nil {
+		return err
+	}
+	restored, err := NewConditionalStateMachineFromDefinition(def)
+	if err != nil {
+		return err
+	}
+	*sm = *restored
+	return nil
+}
+
+// Snapshot returns the machine with the id of the state holding the current value
+func (sm *StateMachine) Snapshot(current StateValue, equals func(i, j StateValue) bool) (MachineSnapshot, error) {
+	def, err := sm.Definition()
+	if err != nil {
+		return MachineSnapshot{}, err
+	}
+	id, err := sm.lookupValueCacheId(current, equals)
+	if err != nil {
+		return MachineSnapshot{}, err
+	}
+	return MachineSnapshot{Machine: def, Current: id}, nil
+}
+
+// SnapshotFromId returns the machine with the id of the current state, for machines where states share a value
+func (sm *StateMachine) SnapshotFromId(current StateId) (MachineSnapshot, error) {
+	def, err := sm.Definition()
+	if err != nil {
+		return MachineSnapshot{}, err
+	}
+	if _, ok := sm.lookupValue(current); !ok {
+		return MachineSnapshot{}, fmt.Errorf("%w: %q", ErrUnknownState, current)
+	}
+	return MachineSnapshot{Machine: def, Current: current}, nil
+}
+
+// RestoreSnapshot creates the machine again and returns it with the value of the current state
+func RestoreSnapshot(s MachineSnapshot) (*StateMachine, StateValue, error) {
+	sm, err := NewStateMachineFromDefinition(s.Machine)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, ok := sm.lookupValue(s.Current)
+	if !ok {
+		return nil, nil, fmt.Errorf("current %w: %q", ErrUnknownState, s.Current)
+	}
+	return sm, value, nil
+}
@@ -0,0 +1,131 @@
+package fielder
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// the light machine with its matchers named in the DefaultRegistry, so it can be saved
+func testNamedLight(t *testing.T) *StateMachine {
+	t.Helper()
+	RegisterMatcher("test.go", testIsGo)
+	sm, err := NewMachineBuilder().
+		State("red", "Red").OnNamed("test.go").GoTo("green").
+		State("green", "Green").OnNamed("test.go").GoTo("yellow").
+		State("yellow", "Yellow").OnNamed("test.go").GoTo("red").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sm
+}
+
+func TestMachineDefinition(t *testing.T) {
+	sm := testNamedLight(t)
+	b, err := json.Marshal(sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := ParseMachineDefinition(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(def.States) != 3 || def.States[0].Id != "red" || !def.States[0].Start || def.States[0].Transitions[0].Matcher != "test.go" {
+		t.Errorf("got %+v", def)
+	}
+	var restored StateMachine
+	if err := json.Unmarshal(b, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if next, value, err := restored.ProcessFromId("green", "go"); err != nil || next != "yellow" || value != "Yellow" {
+		t.Errorf("got %s %v %v", next, value, err)
+	}
+}
+
+func TestMachineDefinitionTransitions(t *testing.T) {
+	RegisterMatcher("test.go", testIsGo)
+	def := MachineDefinition{States: []StateDefinition{
+		{Id: "a", Value: "A", Else: "c", Retry: &RetryDefinition{MaxAttempts: 3, Backoff: "1s", Fallback: "c"}, Transitions: []TransitionDefinition{
+			{NextState: "b", Matcher: "test.go", Priority: 2, Weight: 0.5},
+			{NextState: "b", Event: "push"},
+			{NextState: "c", After: "24h"},
+		}},
+		{Id: "b", Value: "B"},
+		{Id: "c", Value: "C", Terminal: true},
+	}}
+	sm, err := NewStateMachineFromDefinition(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := sm.Definition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := back.States[0]
+	if a.Else != "c" || a.Retry == nil || a.Retry.MaxAttempts != 3 || a.Retry.Backoff != "1s" || len(a.Transitions) != 3 {
+		t.Fatalf("got %+v", a)
+	}
+	if a.Transitions[0] != def.States[0].Transitions[0] || a.Transitions[1].Event != "push" || a.Transitions[2].After != "24h0m0s" {
+		t.Errorf("got %+v", a.Transitions)
+	}
+	if !back.States[2].Terminal {
+		t.Error("terminal states stay terminal")
+	}
+}
+
+func TestMachineDefinitionErrors(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	if _, err := sm.Definition(); !errors.Is(err, ErrUnnamedMatcher) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := json.Marshal(sm); !errors.Is(err, ErrUnnamedMatcher) {
+		t.Errorf("got %v", err)
+	}
+	bad := MachineDefinition{States: []StateDefinition{{Id: "a", Transitions: []TransitionDefinition{
+		{NextState: "a", Matcher: "test.missing"},
+		{NextState: "a"},
+		{NextState: "a", After: "soon"},
+	}}}}
+	_, err := NewStateMachineFromDefinition(bad)
+	if !errors.Is(err, ErrUnknownMatcher) || !errors.Is(err, ErrMachineKind) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestMachineSnapshot(t *testing.T) {
+	sm := testNamedLight(t)
+	snap, err := sm.Snapshot("Green", BasicEquals)
+	if err != nil || snap.Current != "green" {
+		t.Fatalf("got %+v %v", snap, err)
+	}
+	b, _ := json.Marshal(snap)
+	var back MachineSnapshot
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	restored, value, err := RestoreSnapshot(back)
+	if err != nil || value != "Green" {
+		t.Fatalf("got %v %v", value, err)
+	}
+	if v, err := restored.ProcessInMachine(value, "go", BasicEquals); err != nil || v != "Yellow" {
+		t.Errorf("got %v %v", v, err)
+	}
+	if _, err := sm.SnapshotFromId("blue"); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+	back.Current = "blue"
+	if _, _, err := RestoreSnapshot(back); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestDurationDefinition(t *testing.T) {
+	if durationDefinition(0) != "" || durationDefinition(time.Hour) != "1h0m0s" {
+		t.Error("durations are saved in their string form, zero is empty")
+	}
+	if d, err := parseDurationDefinition(""); err != nil || d != 0 {
+		t.Errorf("got %s %v", d, err)
+	}
+}
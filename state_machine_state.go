@@ -0,0 +1,84 @@
+package fielder
+
+import (
+	"context"
+	"math/rand"
+)
+
+// MachineState is what State and ConditionalState share, a machine holds either kind in its ring and processes them the same way
+// the only difference left is that a conditional state always moves to the state its outcome names, even when that is itself,
+// while a plain state that stays where it is returns SameStateNoUpdate, a terminal state of either kind returns SameStateNoUpdate
+type MachineState interface {
+	StateId() StateId
+	Value() StateValue
+	IsStart() bool
+	IsTerminal() bool
+	// the states the transitions, the Else state and the retry fallback go to, in the order they are declared
+	NextStates() []StateId
+	Hooks() (enter TransitionHook, exit TransitionHook)
+	Retries() *RetryPolicy
+	EvaluateTransitionCtx(ctx context.Context, dataToTest any) (StateId, error)
+	EvaluateEventCtx(ctx context.Context, event string, payload any) (StateId, error)
+	selectTransition(ctx context.Context, dataToTest any, strict bool) (StateId, int, error)
+	selectWeighted(ctx context.Context, dataToTest any, rng *rand.Rand) (StateId, int, error)
+}
+
+var (
+	_ MachineState = (*State)(nil)
+	_ MachineState = (*ConditionalState)(nil)
+)
+
+// returns the state held in the ring as a MachineState
+func asMachineState(v any) (MachineState, bool) {
+	switch s := v.(type) {
+	case State:
+		return &s, true
+	case ConditionalState:
+		return &s, true
+	}
+	return nil, false
+}
+
+func (s State) StateId() StateId                        { return s.Id }
+func (s State) Value() StateValue                       { return s.StateValue }
+func (s State) IsStart() bool                           { return s.Start }
+func (s State) IsTerminal() bool                        { return s.Terminal }
+func (s State) Hooks() (TransitionHook, TransitionHook) { return s.OnEnter, s.OnExit }
+func (s State) Retries() *RetryPolicy                   { return s.Retry }
+
+func (s State) NextStates() []StateId {
+	next := make([]StateId, 0, len(s.Matches)+1)
+	for _, t := range s.Matches {
+		next = append(next, t.NextState)
+	}
+	if s.Else != "" {
+		next = append(next, s.Else)
+	}
+	if s.Retry != nil && s.Retry.Fallback != "" {
+		next = append(next, s.Retry.Fallback)
+	}
+	return next
+}
+
+func (s ConditionalState) StateId() StateId  { return s.Id }
+func (s ConditionalState) Value() StateValue { return s.StateValue }
+func (s ConditionalState) IsStart() bool     { return s.Start }
+func (s ConditionalState) IsTerminal() bool  { return s.Terminal }
+func (s ConditionalState) Hooks() (TransitionHook, TransitionHook) {
+	return s.OnEnter, s.OnExit
+}
+func (s ConditionalState) Retries() *RetryPolicy { return s.Retry }
+
+func (s ConditionalState) NextStates() []StateId {
+	next := make([]StateId, 0, len(s.Outcomes)+1)
+	for _, t := range s.Outcomes {
+		next = append(next, t.NextState)
+	}
+	if s.Else != "" {
+		next = append(next, s.Else)
+	}
+	if s.Retry != nil && s.Retry.Fallback != "" {
+		next = append(next, s.Retry.Fallback)
+	}
+	return next
+}
@@ -0,0 +1,307 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var ErrStateNotFound = errors.New("no state is saved for the entity")
+
+// SavedState is the state an entity is in, as a StateStore saves it
+type SavedState struct {
+	Current StateId
+	// when the entity moved into the current state, its timed transitions are timed from it, zero when it is not known
+	Entered time.Time
+}
+
+// StateStore saves the state each entity is in, so the progress of a MachineInstance survives restarts
+// Save is given the moves made since the last save, a store appends them to the history it keeps
+type StateStore interface {
+	Load(ctx context.Context, entityId string) (SavedState, error)
+	Save(ctx context.Context, entityId string, state SavedState, history []HistoryEntry) error
+}
+
+// StoredInstance is a MachineInstance that saves its state to the store after every move
+// when the save fails the instance has still moved, and the error from the store is returned with the new value
+type StoredInstance struct {
+	*MachineInstance
+	Store    StateStore
+	EntityId string
+}
+
+// LoadInstance returns the instance for the entity at its saved state, an entity with no saved state starts at the start state
+// its timed transitions are timed from when it entered the state, or from when it is loaded when the store does not know that
+func LoadInstance(ctx context.Context, store StateStore, sm *StateMachine, entityId string) (*StoredInstance, error) {
+	saved, err := store.Load(ctx, entityId)
+	switch {
+	case errors.Is(err, ErrStateNotFound):
+		return &StoredInstance{MachineInstance: NewMachineInstance(sm), Store: store, EntityId: entityId}, nil
+	case err != nil:
+		return nil, err
+	}
+	entered := saved.Entered
+	if entered.IsZero() {
+		entered = time.Now()
+	}
+	mi, err := NewMachineInstanceSince(sm, saved.Current, entered)
+	if err != nil {
+		return nil, fmt.Errorf("entity %s: %w", entityId, err)
+	}
+	return &StoredInstance{MachineInstance: mi, Store: store, EntityId: entityId}, nil
+}
+
+func (s *StoredInstance) Step(testData any) (StateValue, error) {
+	return s.StepCtx(context.Background(), testData)
+}
+
+func (s *StoredInstance) StepCtx(ctx context.Context, testData any) (StateValue, error) {
+	from := s.Current()
+	value, err := s.MachineInstance.StepCtx(ctx, testData)
+	return s.saveMove(ctx, HistoryEntry{Data: testData, From: from}, value, err)
+}
+
+func (s *StoredInstance) Fire(event string, payload any) (StateValue, error) {
+	return s.FireCtx(context.Background(), event, payload)
+}
+
+func (s *StoredInstance) FireCtx(ctx context.Context, event string, payload any) (StateValue, error) {
+	from := s.Current()
+	value, err := s.MachineInstance.FireCtx(ctx, event, payload)
+	return s.saveMove(ctx, HistoryEntry{Data: payload, Event: event, From: from}, value, err)
+}
+
+func (s *StoredInstance) Expire() (StateValue, error) {
+	return s.ExpireCtx(context.Background())
+}
+
+func (s *StoredInstance) ExpireCtx(ctx context.Context) (StateValue, error) {
+	from := s.Current()
+	value, err := s.MachineInstance.ExpireCtx(ctx)
+	return s.saveMove(ctx, HistoryEntry{From: from}, value, err)
+}
+
+// Save saves the current state with no history, ex: for an entity that was just created
+func (s *StoredInstance) Save(ctx context.Context) error {
+	return s.Store.Save(ctx, s.EntityId, s.saved(), nil)
+}
+
+// the state of the instance as it is saved, read under one lock so the two agree
+func (s *StoredInstance) saved() SavedState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SavedState{Current: s.current, Entered: s.entered}
+}
+
+func (s *StoredInstance) saveMove(ctx context.Context, e HistoryEntry, value StateValue, err error) (StateValue, error) {
+	if err != nil {
+		return nil, err
+	}
+	saved := s.saved()
+	e.Time = saved.Entered
+	e.Input, _ = s.Machine.lookupValue(e.From)
+	e.To = saved.Current
+	e.Result = value
+	if err := s.Store.Save(ctx, s.EntityId, saved, []HistoryEntry{e}); err != nil {
+		return value, fmt.Errorf("entity %s: %w", s.EntityId, err)
+	}
+	return value, nil
+}
+
+// MemoryStateStore is a StateStore held in memory, for tests and single process tools
+type MemoryStateStore struct {
+	mu      sync.Mutex
+	current map[string]SavedState
+	history map[string][]HistoryEntry
+}
+
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{current: map[string]SavedState{}, history: map[string][]HistoryEntry{}}
+}
+
+func (m *MemoryStateStore) Load(ctx context.Context, entityId string) (SavedState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	saved, ok := m.current[entityId]
+	if !ok {
+		return SavedState{}, fmt.Errorf("%w: %s", ErrStateNotFound, entityId)
+	}
+	return saved, nil
+}
+
+func (m *MemoryStateStore) Save(ctx context.Context, entityId string, state SavedState, history []HistoryEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current[entityId] = state
+	m.history[entityId] = append(m.history[entityId], history...)
+	return nil
+}
+
+// History returns every move saved for the entity, oldest first
+func (m *MemoryStateStore) History(entityId string) []HistoryEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]HistoryEntry{}, m.history[entityId]...)
+}
+
+// DynamoStateClient is the part of the dynamodb client the store uses, *dynamodb.Client satisfies it
+type DynamoStateClient interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+}
+
+// the attributes of the item a DynamoStateStore keeps for each entity, next to its key
+const (
+	DynamoStateAttribute   = "State"
+	DynamoEnteredAttribute = "Entered"
+	DynamoHistoryAttribute = "History"
+)
+
+// the moves a DynamoStateStore made by NewDynamoStateStore keeps for each entity, an item can not grow past 400KB
+const DefaultHistoryLimit = 100
+
+// DynamoStateStore keeps one item per entity, the key attribute holds the entity id, the state attribute the state id,
+// the entered attribute when the entity entered the state, and the history attribute a list of the latest moves
+// the data and values of a move are not saved, they can be anything, only its time, event, states and error are
+type DynamoStateStore struct {
+	Client       DynamoStateClient
+	Table        string
+	KeyAttribute string
+	// the most moves the history keeps, the oldest are removed once it holds more, 0 keeps every move
+	HistoryLimit int
+}
+
+// NewDynamoStateStore returns a store for the table, with the entity id in the attribute named "EntityId"
+// and the history limited to DefaultHistoryLimit moves
+func NewDynamoStateStore(client DynamoStateClient, table string) *DynamoStateStore {
+	return &DynamoStateStore{Client: client, Table: table, KeyAttribute: "EntityId", HistoryLimit: DefaultHistoryLimit}
+}
+
+// the saved form of a HistoryEntry
+type dynamoHistoryEntry struct {
+	Time  time.Time `dynamodbav:"time"`
+	Event string    `dynamodbav:"event,omitempty"`
+	From  StateId   `dynamodbav:"from"`
+	To    StateId   `dynamodbav:"to"`
+	Err   string    `dynamodbav:"err,omitempty"`
+}
+
+func (d *DynamoStateStore) key(entityId string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{d.KeyAttribute: &types.AttributeValueMemberS{Value: entityId}}
+}
+
+func (d *DynamoStateStore) Load(ctx context.Context, entityId string) (SavedState, error) {
+	out, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:            aws.String(d.Table),
+		Key:                  d.key(entityId),
+		ConsistentRead:       aws.Bool(true),
+		ProjectionExpression: aws.String("#state, #entered"),
+		ExpressionAttributeNames: map[string]string{
+			"#state":   DynamoStateAttribute,
+			"#entered": DynamoEnteredAttribute,
+		},
+	})
+	if err != nil {
+		return SavedState{}, err
+	}
+	av, ok := out.Item[DynamoStateAttribute]
+	if !ok {
+		return SavedState{}, fmt.Errorf("%w: %s", ErrStateNotFound, entityId)
+	}
+	var saved SavedState
+	if err := attributevalue.Unmarshal(av, &saved.Current); err != nil {
+		return SavedState{}, err
+	}
+	// items saved before the entered time was kept do not have it
+	if av, ok := out.Item[DynamoEnteredAttribute]; ok {
+		if err := attributevalue.Unmarshal(av, &saved.Entered); err != nil {
+			return SavedState{}, err
+		}
+	}
+	return saved, nil
+}
+
+func (d *DynamoStateStore) Save(ctx context.Context, entityId string, state SavedState, history []HistoryEntry) error {
+	entries := make([]dynamoHistoryEntry, 0, len(history))
+	for _, e := range history {
+		saved := dynamoHistoryEntry{Time: e.Time, Event: e.Event, From: e.From, To: e.To}
+		if e.Err != nil {
+			saved.Err = e.Err.Error()
+		}
+		entries = append(entries, saved)
+	}
+	current, err := attributevalue.Marshal(state.Current)
+	if err != nil {
+		return err
+	}
+	entered, err := attributevalue.Marshal(state.Entered)
+	if err != nil {
+		return err
+	}
+	moves, err := attributevalue.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	out, err := d.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(d.Table),
+		Key:              d.key(entityId),
+		UpdateExpression: aws.String("SET #state = :state, #entered = :entered, #history = list_append(if_not_exists(#history, :empty), :moves)"),
+		ExpressionAttributeNames: map[string]string{
+			"#state":   DynamoStateAttribute,
+			"#entered": DynamoEnteredAttribute,
+			"#history": DynamoHistoryAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":state":   current,
+			":entered": entered,
+			":moves":   moves,
+			":empty":   &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return err
+	}
+	if l, ok := out.Attributes[DynamoHistoryAttribute].(*types.AttributeValueMemberL); ok {
+		return d.trimHistory(ctx, entityId, len(l.Value))
+	}
+	return nil
+}
+
+// removes the oldest moves once the history holds more than the limit
+// the removal only applies while the history still has the length it was read with, a save made in between trims it again itself
+func (d *DynamoStateStore) trimHistory(ctx context.Context, entityId string, length int) error {
+	if d.HistoryLimit <= 0 || length <= d.HistoryLimit {
+		return nil
+	}
+	oldest := make([]string, 0, length-d.HistoryLimit)
+	for i := 0; i < length-d.HistoryLimit; i++ {
+		oldest = append(oldest, "#history["+strconv.Itoa(i)+"]")
+	}
+	_, err := d.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(d.Table),
+		Key:                 d.key(entityId),
+		UpdateExpression:    aws.String("REMOVE " + strings.Join(oldest, ", ")),
+		ConditionExpression: aws.String("size(#history) = :length"),
+		ExpressionAttributeNames: map[string]string{
+			"#history": DynamoHistoryAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":length": &types.AttributeValueMemberN{Value: strconv.Itoa(length)},
+		},
+	})
+	var changed *types.ConditionalCheckFailedException
+	if errors.As(err, &changed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// a DynamoStateClient holding one table in memory, it understands the expressions the DynamoStateStore sends
+type testStateTable struct {
+	items   map[string]map[string]types.AttributeValue
+	updates []*dynamodb.UpdateItemInput
+	// the length the history has before a trim is applied, ex: to act as a save made in between
+	raced int
+}
+
+func (c *testStateTable) GetItem(ctx context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{Item: c.items[in.Key["EntityId"].(*types.AttributeValueMemberS).Value]}, nil
+}
+
+func (c *testStateTable) UpdateItem(ctx context.Context, in *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	c.updates = append(c.updates, in)
+	id := in.Key["EntityId"].(*types.AttributeValueMemberS).Value
+	item := c.items[id]
+	if item == nil {
+		item = map[string]types.AttributeValue{}
+		c.items[id] = item
+	}
+	history, _ := item[DynamoHistoryAttribute].(*types.AttributeValueMemberL)
+	if history == nil {
+		history = &types.AttributeValueMemberL{}
+	}
+	expr := aws.ToString(in.UpdateExpression)
+	if remove, ok := strings.CutPrefix(expr, "REMOVE "); ok {
+		if c.raced > 0 || in.ExpressionAttributeValues[":length"].(*types.AttributeValueMemberN).Value != strconv.Itoa(len(history.Value)) {
+			return nil, &types.ConditionalCheckFailedException{}
+		}
+		n := len(strings.Split(remove, ", "))
+		item[DynamoHistoryAttribute] = &types.AttributeValueMemberL{Value: history.Value[n:]}
+		return &dynamodb.UpdateItemOutput{}, nil
+	}
+	item[DynamoStateAttribute] = in.ExpressionAttributeValues[":state"]
+	item[DynamoEnteredAttribute] = in.ExpressionAttributeValues[":entered"]
+	moves := in.ExpressionAttributeValues[":moves"].(*types.AttributeValueMemberL)
+	history = &types.AttributeValueMemberL{Value: append(append([]types.AttributeValue{}, history.Value...), moves.Value...)}
+	item[DynamoHistoryAttribute] = history
+	return &dynamodb.UpdateItemOutput{Attributes: item}, nil
+}
+
+func TestStoredInstance(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStateStore()
+	sm := NewStateMachine(testLightStates()...)
+	si, err := LoadInstance(ctx, store, sm, "light")
+	if err != nil || si.Current() != "red" {
+		t.Fatalf("an entity with no saved state starts at the start state: got %v", err)
+	}
+	si.Now = clock.Now
+	if v, err := si.Step("go"); err != nil || v != "Green" {
+		t.Fatalf("got %v %v", v, err)
+	}
+	// a step that does not move is not saved
+	if _, err := si.Step("stop"); err == nil {
+		t.Error("expected an error")
+	}
+	saved, err := store.Load(ctx, "light")
+	if err != nil || saved.Current != "green" || !saved.Entered.Equal(clock.now) {
+		t.Errorf("got %+v %v", saved, err)
+	}
+	history := store.History("light")
+	if len(history) != 1 || history[0].From != "red" || history[0].To != "green" || history[0].Input != "Red" || history[0].Result != "Green" {
+		t.Errorf("got %+v", history)
+	}
+	loaded, err := LoadInstance(ctx, store, sm, "light")
+	if err != nil || loaded.Current() != "green" || !loaded.EnteredAt().Equal(clock.now) {
+		t.Errorf("got %v", err)
+	}
+	store.Save(ctx, "lost", SavedState{Current: "blue"}, nil)
+	if _, err := LoadInstance(ctx, store, sm, "lost"); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestLoadInstanceEntered(t *testing.T) {
+	// the timed transitions of a loaded entity are due from when it entered the state, not from when it was loaded
+	ctx := context.Background()
+	entered := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStateStore()
+	store.Save(ctx, "order", SavedState{Current: "pending", Entered: entered}, nil)
+	si, err := LoadInstance(ctx, store, testTimedMachine(t), "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if due, ok := si.Deadline(); !ok || !due.Equal(entered.Add(time.Hour)) {
+		t.Errorf("got %s", due)
+	}
+	si.Now = func() time.Time { return entered.Add(2 * time.Hour) }
+	if v, err := si.Expire(); err != nil || v != "REMINDED" {
+		t.Fatalf("got %v %v", v, err)
+	}
+	if saved, _ := store.Load(ctx, "order"); saved.Current != "reminded" || !saved.Entered.Equal(entered.Add(time.Hour)) {
+		t.Errorf("got %+v", saved)
+	}
+	// a store that does not know when the state was entered times it from the load
+	store.Save(ctx, "new", SavedState{Current: "pending"}, nil)
+	before := time.Now()
+	if si, err := LoadInstance(ctx, store, testTimedMachine(t), "new"); err != nil || si.EnteredAt().Before(before) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestDynamoStateStore(t *testing.T) {
+	ctx := context.Background()
+	table := &testStateTable{items: map[string]map[string]types.AttributeValue{}}
+	store := NewDynamoStateStore(table, "states")
+	if store.HistoryLimit != DefaultHistoryLimit {
+		t.Errorf("got %d", store.HistoryLimit)
+	}
+	if _, err := store.Load(ctx, "order"); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("got %v", err)
+	}
+	entered := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	move := HistoryEntry{Time: entered, From: "pending", To: "paid", Event: "pay", Err: errors.New("late")}
+	if err := store.Save(ctx, "order", SavedState{Current: "paid", Entered: entered}, []HistoryEntry{move}); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := store.Load(ctx, "order")
+	if err != nil || saved.Current != "paid" || !saved.Entered.Equal(entered) {
+		t.Errorf("got %+v %v", saved, err)
+	}
+	// items saved without the entered time load with a zero one
+	delete(table.items["order"], DynamoEnteredAttribute)
+	if saved, err := store.Load(ctx, "order"); err != nil || !saved.Entered.IsZero() {
+		t.Errorf("got %+v %v", saved, err)
+	}
+}
+
+func TestDynamoStateStoreHistoryLimit(t *testing.T) {
+	ctx := context.Background()
+	table := &testStateTable{items: map[string]map[string]types.AttributeValue{}}
+	store := NewDynamoStateStore(table, "states")
+	store.HistoryLimit = 2
+	for _, to := range []StateId{"a", "b", "c", "d"} {
+		if err := store.Save(ctx, "e", SavedState{Current: to}, []HistoryEntry{{To: to}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history := table.items["e"][DynamoHistoryAttribute].(*types.AttributeValueMemberL).Value
+	if len(history) != 2 || history[0].(*types.AttributeValueMemberM).Value["to"].(*types.AttributeValueMemberS).Value != "c" {
+		t.Errorf("the oldest moves are removed: got %v", history)
+	}
+	// a trim that lost to another save is left to the next one
+	table.raced = 1
+	if err := store.Save(ctx, "e", SavedState{Current: "e"}, []HistoryEntry{{To: "e"}}); err != nil {
+		t.Errorf("got %v", err)
+	}
+	if got := len(table.items["e"][DynamoHistoryAttribute].(*types.AttributeValueMemberL).Value); got != 3 {
+		t.Errorf("got %d", got)
+	}
+	// a limit of 0 keeps every move
+	store.HistoryLimit = 0
+	updates := len(table.updates)
+	store.Save(ctx, "e", SavedState{Current: "f"}, []HistoryEntry{{To: "f"}})
+	if len(table.updates) != updates+1 {
+		t.Error("nothing is trimmed without a limit")
+	}
+}
@@ -0,0 +1,115 @@
+package fielder
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Backpressure is what a subscription does with a transition when its channel is full
+type Backpressure int
+
+const (
+	// the new transition is dropped and counted, the machine never waits
+	DropNewest Backpressure = iota
+	// the oldest transition in the channel is dropped and counted to make room, the machine never waits
+	DropOldest
+	// the machine waits for room in the channel, until the context of the transition is done or the subscription is cancelled
+	Block
+)
+
+// Subscription is a channel that is sent every transition of a machine, see Subscribe
+type Subscription struct {
+	sm      *StateMachine
+	ch      chan TransitionEvent
+	policy  Backpressure
+	dropped atomic.Int64
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Subscribe sends every transition of the machine to the channel, after the hooks have run
+// the channel is never closed by the machine, stop the subscription with Unsubscribe
+// a transition is sent from the goroutine that made it, the policy decides what happens when the channel is full
+func (sm *StateMachine) Subscribe(ch chan TransitionEvent, policy Backpressure) *Subscription {
+	s := &Subscription{sm: sm, ch: ch, policy: policy, done: make(chan struct{})}
+	defer sm.lock()()
+	sm.subs = append(sm.subs, s)
+	return s
+}
+
+// SubscribeFunc calls fn with every transition of the machine from a goroutine of its own, in the order they were made
+// up to buffer transitions wait for fn, the policy decides what happens to the ones past that
+func (sm *StateMachine) SubscribeFunc(fn func(TransitionEvent), buffer int, policy Backpressure) *Subscription {
+	s := sm.Subscribe(make(chan TransitionEvent, buffer), policy)
+	go func() {
+		for {
+			select {
+			case <-s.done:
+				return
+			case e := <-s.ch:
+				fn(e)
+			}
+		}
+	}()
+	return s
+}
+
+// Unsubscribe stops sending transitions to the subscription, a transition waiting on a full channel is dropped
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.done)
+		defer s.sm.lock()()
+		for i, sub := range s.sm.subs {
+			if sub == s {
+				// the slice is copied, so a transition that is being published keeps the slice it read
+				s.sm.subs = append(append([]*Subscription{}, s.sm.subs[:i]...), s.sm.subs[i+1:]...)
+				break
+			}
+		}
+	})
+}
+
+// Dropped returns the number of transitions the subscription has dropped because its channel was full
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+func (s *Subscription) publish(e TransitionEvent) {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	select {
+	case s.ch <- e:
+		return
+	default:
+	}
+	switch s.policy {
+	case DropOldest:
+		select {
+		case <-s.ch:
+			s.dropped.Add(1)
+		default:
+		}
+		select {
+		case s.ch <- e:
+		default:
+			s.dropped.Add(1)
+		}
+	case Block:
+		var ctxDone <-chan struct{}
+		if e.Context != nil {
+			ctxDone = e.Context.Done()
+		}
+		select {
+		case s.ch <- e:
+		case <-s.done:
+			s.dropped.Add(1)
+		case <-ctxDone:
+			s.dropped.Add(1)
+		}
+	default:
+		s.dropped.Add(1)
+	}
+}
@@ -0,0 +1,99 @@
+package fielder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// moves the light machine red -> green -> yellow -> red
+func testCycleLight(sm *StateMachine) {
+	for _, id := range []StateId{"red", "green", "yellow"} {
+		sm.ProcessFromId(id, "go")
+	}
+}
+
+func TestSubscribeDrop(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	newest := sm.Subscribe(make(chan TransitionEvent, 1), DropNewest)
+	oldest := sm.Subscribe(make(chan TransitionEvent, 1), DropOldest)
+	testCycleLight(sm)
+	if e := <-newest.ch; e.From != "red" || e.To != "green" || newest.Dropped() != 2 {
+		t.Errorf("DropNewest keeps the first transition: got %s -> %s, dropped %d", e.From, e.To, newest.Dropped())
+	}
+	if e := <-oldest.ch; e.From != "yellow" || e.To != "red" || oldest.Dropped() != 2 {
+		t.Errorf("DropOldest keeps the last transition: got %s -> %s, dropped %d", e.From, e.To, oldest.Dropped())
+	}
+	// moves that fail are not sent
+	sm.ProcessFromId("red", "stop")
+	if len(newest.ch) != 0 {
+		t.Error("got a transition for a failed move")
+	}
+}
+
+func TestSubscribeBlock(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	ch := make(chan TransitionEvent)
+	sub := sm.Subscribe(ch, Block)
+	go sm.ProcessFromId("red", "go")
+	select {
+	case e := <-ch:
+		if e.To != "green" {
+			t.Errorf("got %s", e.To)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the transition was not sent")
+	}
+	// the hooks run just before the transition is sent
+	moving := make(chan struct{}, 1)
+	sm.OnTransition(func(TransitionEvent) { moving <- struct{}{} })
+	// a blocked transition is dropped when its context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sm.ProcessFromIdCtx(ctx, "green", "go")
+		close(done)
+	}()
+	<-moving
+	cancel()
+	<-done
+	if sub.Dropped() != 1 {
+		t.Errorf("got %d", sub.Dropped())
+	}
+	// or when the subscription is cancelled
+	done = make(chan struct{})
+	go func() {
+		sm.ProcessFromId("yellow", "go")
+		close(done)
+	}()
+	<-moving
+	sub.Unsubscribe()
+	<-done
+	if sub.Dropped() != 2 {
+		t.Errorf("got %d", sub.Dropped())
+	}
+	sub.Unsubscribe()
+	sm.ProcessFromId("red", "go")
+	<-moving
+	if len(sm.subs) != 0 {
+		t.Errorf("got %d subscriptions", len(sm.subs))
+	}
+}
+
+func TestSubscribeFunc(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	got := make(chan StateId, 3)
+	sub := sm.SubscribeFunc(func(e TransitionEvent) { got <- e.To }, 3, Block)
+	defer sub.Unsubscribe()
+	testCycleLight(sm)
+	for _, want := range []StateId{"green", "yellow", "red"} {
+		select {
+		case to := <-got:
+			if to != want {
+				t.Errorf("got %s, want %s", to, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s was not sent", want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestProcessInMachine(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	value, err := sm.ProcessInMachine("Red", "go", BasicEquals)
+	if err != nil || value != "Green" {
+		t.Errorf("got %v %v", value, err)
+	}
+	if _, err := sm.ProcessInMachine("Red", "stop", BasicEquals); !errors.Is(err, ErrNoTransition) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := sm.ProcessInMachine("Blue", "go", BasicEquals); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestProcessInMachineCtx(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := sm.ProcessInMachineCtx(ctx, "Red", "go", BasicEquals); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v", err)
+	}
+	if _, _, err := sm.ProcessFromIdCtx(ctx, "red", "go"); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestContextMatcher(t *testing.T) {
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "allowed"))
+	s := State{Id: "a", Matches: []Transition{
+		{NextState: "b", ContextMatcher: func(ctx context.Context, _ any) bool {
+			cancel()
+			return ctx.Value(key{}) == "denied"
+		}},
+		{NextState: "c", SimpleMatcher: func(any) bool { return true }},
+	}}
+	// the matcher sees the context of the call, and a context cancelled by it stops the next transition from being tested
+	if _, err := s.EvaluateTransitionCtx(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v", err)
+	}
+	if next, err := s.EvaluateTransition(nil); err != nil || next != "c" {
+		t.Errorf("got %s %v", next, err)
+	}
+}
+
+func TestConditionalProcessInMachineCtx(t *testing.T) {
+	sm := NewConditionalStateMachine(ConditionalState{Id: "a", StateValue: "A"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := sm.ProcessInMachineCtx(ctx, "A", nil, BasicEquals); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestBasicEquals(t *testing.T) {
+	if !BasicEquals("a", "a") || BasicEquals("a", 1) || BasicEquals(1, int64(1)) {
+		t.Error("values compare with ==")
+	}
+	if !BasicEquals([]int{1}, []int{1}) || BasicEquals([]int{1}, []int{2}) {
+		t.Error("values that can not be compared with == are compared deeply")
+	}
+}
+
+func TestProcessInMachineConcurrent(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	sm.RecordHistory(NewHistoryRecorder(100))
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// the writers register hooks while the readers move through the machine
+			if i%4 == 0 {
+				sm.OnTransition(func(TransitionEvent) {})
+				return
+			}
+			for _, v := range []StateValue{"Red", "Green", "Yellow"} {
+				if _, err := sm.ProcessInMachine(v, "go", BasicEquals); err != nil {
+					t.Error(err)
+				}
+			}
+			sm.ExportDOT()
+		}(i)
+	}
+	wg.Wait()
+	if sm.recorder.Len() != 45 {
+		t.Errorf("got %d entries", sm.recorder.Len())
+	}
+}
+
+func TestStateMachineLiteral(t *testing.T) {
+	// a machine made as a literal has no mutex, it is still usable from one goroutine
+	sm := &StateMachine{IdRingAddressCache: map[StateId]RingAddress{}, ValueCache: map[StateId]StateValue{}}
+	sm.OnTransition(func(TransitionEvent) {})
+	if _, _, err := sm.ProcessFromId("a", nil); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestStateMachineErrors(t *testing.T) {
+	always := func(any) bool { return true }
+	sm := NewStateMachine(
+		State{Id: "to missing", StateValue: 1, Matches: []Transition{{NextState: "missing", SimpleMatcher: always}}},
+		State{Id: "to nil", StateValue: 2, Matches: []Transition{{NextState: "nil", SimpleMatcher: always}}},
+		State{Id: "to empty", StateValue: 3, Matches: []Transition{{NextState: "", SimpleMatcher: always}}},
+		State{Id: "to self", StateValue: 4, Matches: []Transition{{NextState: "to self", SimpleMatcher: always}}},
+		State{Id: "nil"},
+	)
+	for id, want := range map[StateId]error{
+		"to missing": ErrUnknownState,
+		"to nil":     ErrNilStateValue,
+		"to empty":   ErrEmptyNextId,
+		"to self":    SameStateNoUpdate,
+		"unknown":    ErrUnknownState,
+	} {
+		if _, _, err := sm.ProcessFromId(id, nil); !errors.Is(err, want) {
+			t.Errorf("%s: got %v want %v", id, err, want)
+		}
+	}
+	sm.IdRingAddressCache["not a state"] = sm.IdRingAddressCache["nil"]
+	sm.IdRingAddressCache["not a state"].Value = "text"
+	if _, _, err := sm.ProcessFromId("not a state", nil); !errors.Is(err, ErrStateType) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestNextBehavior(t *testing.T) {
+	sm := NewStateMachine(testLightStates()...)
+	behaviors := map[StateValue]string{"Green": "drive"}
+	value, behavior, err := NextBehavior(sm, "Red", "go", behaviors)
+	if err != nil || value != "Green" || behavior != "drive" {
+		t.Errorf("got %v %s %v", value, behavior, err)
+	}
+	if _, _, err := NextBehavior(sm, "Green", "go", behaviors); !errors.Is(err, ErrNoBehavior) {
+		t.Errorf("got %v", err)
+	}
+	if _, _, err := NextBehavior(sm, "Green", "stop", behaviors); !errors.Is(err, ErrNoTransition) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestAmbiguousStateValue(t *testing.T) {
+	sm := NewStateMachine(State{Id: "a", StateValue: "same"}, State{Id: "b", StateValue: "same"})
+	if _, err := sm.ProcessInMachine("same", nil, BasicEquals); !errors.Is(err, ErrAmbiguousStateValue) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := sm.Snapshot("same", BasicEquals); !errors.Is(err, ErrAmbiguousStateValue) {
+		t.Errorf("got %v", err)
+	}
+	if _, err := sm.ProcessInMachine("other", nil, BasicEquals); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+	if snap, err := sm.SnapshotFromId("b"); err != nil || snap.Current != "b" {
+		t.Errorf("got %+v %v", snap, err)
+	}
+}
+
+func TestProcessFromId(t *testing.T) {
+	// off and standby share a value, they are told apart by id
+	sm := NewStateMachine(
+		State{Id: "off", StateValue: "Off", Matches: []Transition{{NextState: "on", SimpleMatcher: testIsGo}}},
+		State{Id: "on", StateValue: "On", Matches: []Transition{{NextState: "standby", SimpleMatcher: testIsGo}}},
+		State{Id: "standby", StateValue: "Off", Matches: []Transition{{NextState: "off", SimpleMatcher: testIsGo}}},
+	)
+	want := []struct {
+		id    StateId
+		value StateValue
+	}{{"on", "On"}, {"standby", "Off"}, {"off", "Off"}, {"on", "On"}}
+	current := StateId("off")
+	for _, w := range want {
+		next, value, err := sm.ProcessFromId(current, "go")
+		if err != nil || next != w.id || value != w.value {
+			t.Fatalf("from %s: got %s %v %v", current, next, value, err)
+		}
+		current = next
+	}
+	if _, _, err := sm.ProcessFromId("missing", "go"); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+}
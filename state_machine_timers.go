@@ -0,0 +1,142 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// timed transitions move an instance on its own once it has been in a state long enough, ex: cancel an order that has been pending payment for a day
+// State{Id: "pending_payment", Matches: []Transition{{NextState: "cancelled", After: 24 * time.Hour}}}
+// the matcher of a timed transition is not used, the time in the state is the only condition
+
+var ErrNotExpired = errors.New("no timed transition is due")
+
+type timedTransition struct {
+	next  StateId
+	after time.Duration
+}
+
+// returns the timed transition of the state that is due first, false when it has none
+func ringStateTimeout(v any) (timedTransition, bool) {
+	var out timedTransition
+	found := false
+	consider := func(next StateId, after time.Duration) {
+		if after > 0 && (!found || after < out.after) {
+			out = timedTransition{next: next, after: after}
+			found = true
+		}
+	}
+	switch s := v.(type) {
+	case State:
+		if s.Terminal {
+			return out, false
+		}
+		for _, t := range s.Matches {
+			consider(t.NextState, t.After)
+		}
+	case ConditionalState:
+		if s.Terminal {
+			return out, false
+		}
+		for _, t := range s.Outcomes {
+			consider(t.NextState, t.After)
+		}
+	}
+	return out, found
+}
+
+// EnteredAt returns when the instance moved into its current state
+func (mi *MachineInstance) EnteredAt() time.Time {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+	return mi.entered
+}
+
+// Deadline returns when the first timed transition of the current state is due, false when the state has none
+func (mi *MachineInstance) Deadline() (time.Time, bool) {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+	return mi.deadline()
+}
+
+func (mi *MachineInstance) deadline() (time.Time, bool) {
+	s, err := mi.Machine.lookupRingState(mi.current)
+	if err != nil {
+		return time.Time{}, false
+	}
+	t, ok := ringStateTimeout(s)
+	if !ok {
+		return time.Time{}, false
+	}
+	return mi.entered.Add(t.after), true
+}
+
+// Expire takes the timed transition of the current state if it is due, it returns ErrNotExpired when none is
+// the instance is timed as entering the next state when the transition was due, so a chain of timeouts that is behind catches up on time
+func (mi *MachineInstance) Expire() (StateValue, error) {
+	return mi.ExpireCtx(context.Background())
+}
+
+func (mi *MachineInstance) ExpireCtx(ctx context.Context) (StateValue, error) {
+	mi.mu.Lock()
+	due, ok := mi.deadline()
+	if !ok || mi.Now().Before(due) {
+		defer mi.mu.Unlock()
+		return nil, fmt.Errorf("state %s: %w", mi.current, ErrNotExpired)
+	}
+	in, _ := mi.Machine.lookupValue(mi.current)
+	nextId, value, fire, err := mi.Machine.planMove(ctx, mi.current, in, nil, "", func(current any) (StateId, error) {
+		t, _ := ringStateTimeout(current)
+		return t.next, nil
+	})
+	if err != nil {
+		mi.mu.Unlock()
+		return nil, err
+	}
+	mi.moveTo(nextId, due)
+	mi.mu.Unlock()
+	fire()
+	return value, nil
+}
+
+// RunTimers takes the timed transitions of the instance as they come due, until the context is done
+// it follows the instance as Step and Fire move it, the wait is measured with Now, so a replaced clock is only read and never waited on
+func (mi *MachineInstance) RunTimers(ctx context.Context) error {
+	for {
+		var timeout <-chan time.Time
+		var timer *time.Timer
+		if due, ok := mi.Deadline(); ok {
+			timer = time.NewTimer(due.Sub(mi.Now()))
+			timeout = timer.C
+		}
+		var err error
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-mi.wake:
+		case <-timeout:
+			if _, err = mi.ExpireCtx(ctx); errors.Is(err, ErrNotExpired) {
+				err = nil
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// After starts a timed transition from the current state of the builder, it is finished by GoTo
+func (b *MachineBuilder) After(d time.Duration) *MachineBuilder {
+	b.closePending()
+	if b.current < 0 {
+		b.errs = append(b.errs, ErrBuilderNoState)
+		return b
+	}
+	b.pendingAfter = d
+	return b
+}
@@ -0,0 +1,101 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// a clock that only moves when it is told to
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func testTimedMachine(t *testing.T) *StateMachine {
+	t.Helper()
+	sm, err := NewMachineBuilder().
+		State("pending", "PENDING").After(24*time.Hour).GoTo("cancelled").After(time.Hour).GoTo("reminded").
+		State("reminded", "REMINDED").After(23 * time.Hour).GoTo("cancelled").
+		Terminal("cancelled").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sm
+}
+
+func TestExpire(t *testing.T) {
+	clock := &testClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mi, err := NewMachineInstanceSince(testTimedMachine(t), "pending", clock.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mi.Now = clock.Now
+	due, ok := mi.Deadline()
+	if !ok || !due.Equal(clock.now.Add(time.Hour)) {
+		t.Errorf("the first timed transition to come due is the deadline: got %v %v", due, ok)
+	}
+	if _, err := mi.Expire(); !errors.Is(err, ErrNotExpired) {
+		t.Errorf("got %v", err)
+	}
+	// a day late, both timeouts are due and the instance catches up on time
+	clock.now = clock.now.Add(25 * time.Hour)
+	if v, err := mi.Expire(); err != nil || v != "REMINDED" {
+		t.Fatalf("got %v %v", v, err)
+	}
+	if !mi.EnteredAt().Equal(due) {
+		t.Errorf("the next state is entered when the transition was due: got %v", mi.EnteredAt())
+	}
+	if v, err := mi.Expire(); err != nil || v != "cancelled" {
+		t.Errorf("got %v %v", v, err)
+	}
+	if _, ok := mi.Deadline(); ok {
+		t.Error("terminal states have no deadline")
+	}
+	// timed transitions are not taken by Step
+	if _, err := NewMachineInstance(mi.Machine).Step(nil); !errors.Is(err, ErrNoTransition) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestRunTimers(t *testing.T) {
+	sm, err := NewMachineBuilder().
+		State("a", "A").After(time.Millisecond).GoTo("b").
+		State("b", "B").OnEvent("back").GoTo("a").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mi := NewMachineInstance(sm)
+	moved := make(chan StateId, 10)
+	sm.OnTransition(func(e TransitionEvent) { moved <- e.To })
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- mi.RunTimers(ctx) }()
+	if got := <-moved; got != "b" {
+		t.Errorf("got %s", got)
+	}
+	// the timers follow the instance when something else moves it
+	if _, err := mi.Fire("back", nil); err != nil {
+		t.Fatal(err)
+	}
+	<-moved
+	if got := <-moved; got != "b" {
+		t.Errorf("got %s", got)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestMachineBuilderAfter(t *testing.T) {
+	if _, err := NewMachineBuilder().After(time.Hour).Build(); !errors.Is(err, ErrBuilderNoState) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package fielder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrStateValueType = errors.New("state value is not of the machine's value type")
+
+// TypedMachine is a machine whose state values are all of type V, its methods take and return V
+// and compare values with ==, so callers need no type assertions or equals function, ex:
+// tm, err := NewTypedMachine[OrderStatus](states...)
+// next, err := tm.ProcessInMachine(StatusDraft, order)
+type TypedMachine[V comparable] struct {
+	*StateMachine
+}
+
+// NewTypedMachine is NewStateMachine for states whose values are all V
+func NewTypedMachine[V comparable](states ...State) (*TypedMachine[V], error) {
+	return Typed[V](NewStateMachine(states...))
+}
+
+// Typed returns the machine as a TypedMachine, it is ErrStateValueType when a state value is not a V
+func Typed[V comparable](sm *StateMachine) (*TypedMachine[V], error) {
+	var errs []error
+	for _, v := range sm.ringStates() {
+		s, ok := asMachineState(v)
+		if !ok {
+			continue
+		}
+		if _, ok := s.Value().(V); !ok {
+			errs = append(errs, fmt.Errorf("state %s: %w: %T", s.StateId(), ErrStateValueType, s.Value()))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &TypedMachine[V]{StateMachine: sm}, nil
+}
+
+// TypedEquals compares two state values as V with ==, values that are not a V are never equal
+func TypedEquals[V comparable](s1, s2 StateValue) bool {
+	v1, ok1 := s1.(V)
+	v2, ok2 := s2.(V)
+	return ok1 && ok2 && v1 == v2
+}
+
+func (tm *TypedMachine[V]) ProcessInMachine(in V, testData any) (V, error) {
+	return tm.ProcessInMachineCtx(context.Background(), in, testData)
+}
+
+func (tm *TypedMachine[V]) ProcessInMachineCtx(ctx context.Context, in V, testData any) (V, error) {
+	out, err := tm.StateMachine.ProcessInMachineCtx(ctx, in, testData, TypedEquals[V])
+	if err != nil {
+		return *new(V), err
+	}
+	return out.(V), nil
+}
+
+func (tm *TypedMachine[V]) ProcessFromId(current StateId, testData any) (StateId, V, error) {
+	return tm.ProcessFromIdCtx(context.Background(), current, testData)
+}
+
+func (tm *TypedMachine[V]) ProcessFromIdCtx(ctx context.Context, current StateId, testData any) (StateId, V, error) {
+	next, out, err := tm.StateMachine.ProcessFromIdCtx(ctx, current, testData)
+	if err != nil {
+		return next, *new(V), err
+	}
+	return next, out.(V), nil
+}
+
+// Value returns the value of the state with the id
+func (tm *TypedMachine[V]) Value(id StateId) (V, bool) {
+	v, ok := tm.lookupValue(id)
+	if !ok {
+		return *new(V), false
+	}
+	return v.(V), true
+}
+
+// Id returns the id of the state holding the value, it is ErrAmbiguousStateValue when several states hold it
+func (tm *TypedMachine[V]) Id(value V) (StateId, error) {
+	return tm.lookupValueCacheId(value, TypedEquals[V])
+}
+
+// TypedInstance is a MachineInstance of a TypedMachine
+type TypedInstance[V comparable] struct {
+	*MachineInstance
+}
+
+// NewInstance returns an instance at the start state of the machine
+func (tm *TypedMachine[V]) NewInstance() *TypedInstance[V] {
+	return &TypedInstance[V]{MachineInstance: NewMachineInstance(tm.StateMachine)}
+}
+
+// Value returns the value of the current state
+func (ti *TypedInstance[V]) Value() V {
+	v, _ := ti.MachineInstance.Value().(V)
+	return v
+}
+
+func (ti *TypedInstance[V]) Step(testData any) (V, error) {
+	return ti.StepCtx(context.Background(), testData)
+}
+
+func (ti *TypedInstance[V]) StepCtx(ctx context.Context, testData any) (V, error) {
+	out, err := ti.MachineInstance.StepCtx(ctx, testData)
+	if err != nil {
+		return *new(V), err
+	}
+	return out.(V), nil
+}
+
+func (ti *TypedInstance[V]) Fire(event string, payload any) (V, error) {
+	return ti.FireCtx(context.Background(), event, payload)
+}
+
+func (ti *TypedInstance[V]) FireCtx(ctx context.Context, event string, payload any) (V, error) {
+	out, err := ti.MachineInstance.FireCtx(ctx, event, payload)
+	if err != nil {
+		return *new(V), err
+	}
+	return out.(V), nil
+}
@@ -0,0 +1,83 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+type testLight string
+
+const (
+	testRed    testLight = "Red"
+	testGreen  testLight = "Green"
+	testYellow testLight = "Yellow"
+)
+
+func testTypedLight(t *testing.T) *TypedMachine[testLight] {
+	t.Helper()
+	states := testLightStates()
+	for i, v := range []testLight{testRed, testGreen, testYellow} {
+		states[i].StateValue = v
+	}
+	tm, err := NewTypedMachine[testLight](states...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestTypedMachine(t *testing.T) {
+	tm := testTypedLight(t)
+	if v, err := tm.ProcessInMachine(testRed, "go"); err != nil || v != testGreen {
+		t.Errorf("got %v %v", v, err)
+	}
+	if next, v, err := tm.ProcessFromId("green", "go"); err != nil || next != "yellow" || v != testYellow {
+		t.Errorf("got %s %v %v", next, v, err)
+	}
+	if v, err := tm.ProcessInMachine(testRed, "stop"); !errors.Is(err, ErrNoTransition) || v != "" {
+		t.Errorf("got %v %v", v, err)
+	}
+	if _, _, err := tm.ProcessFromId("blue", "go"); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+	if v, ok := tm.Value("yellow"); !ok || v != testYellow {
+		t.Errorf("got %v %v", v, ok)
+	}
+	if _, ok := tm.Value("blue"); ok {
+		t.Error("blue is not a state")
+	}
+	if id, err := tm.Id(testGreen); err != nil || id != "green" {
+		t.Errorf("got %s %v", id, err)
+	}
+	if _, err := tm.Id("Blue"); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestTyped(t *testing.T) {
+	if _, err := Typed[testLight](NewStateMachine(testLightStates()...)); !errors.Is(err, ErrStateValueType) {
+		t.Errorf("string values are not testLight values: got %v", err)
+	}
+	if _, err := Typed[string](NewStateMachine(testLightStates()...)); err != nil {
+		t.Errorf("got %v", err)
+	}
+	if !TypedEquals[testLight](testRed, testRed) || TypedEquals[testLight](testRed, "Red") || TypedEquals[testLight](nil, nil) {
+		t.Error("values compare as V")
+	}
+}
+
+func TestTypedInstance(t *testing.T) {
+	ti := testTypedLight(t).NewInstance()
+	if ti.Value() != testRed {
+		t.Errorf("got %v", ti.Value())
+	}
+	if v, err := ti.Step("go"); err != nil || v != testGreen || ti.Value() != testGreen {
+		t.Errorf("got %v %v", v, err)
+	}
+	if v, err := ti.Step("stop"); err == nil || v != "" {
+		t.Errorf("got %v %v", v, err)
+	}
+	if _, err := ti.Fire("go", nil); !errors.Is(err, ErrEventNotPermitted) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package fielder
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDuplicateStateId = errors.New("state id is used by more than one state")
+	ErrUnknownNextState = errors.New("transition goes to a state that is not in the machine")
+	ErrMultipleStart    = errors.New("more than one state is marked as start")
+	ErrEmptyStateId     = errors.New("state has no id")
+)
+
+// returns the id, the next states of every transition, and the start flag of a state held in the ring
+func ringStateInfo(v any) (StateId, []StateId, bool) {
+	if s, ok := asMachineState(v); ok {
+		return s.StateId(), s.NextStates(), s.IsStart()
+	}
+	return "", nil, false
+}
+
+// Validate checks the structure of the machine, the error holds every problem found, joined with errors.Join
+// a machine is valid when every state has a unique id, every transition goes to a state in the machine, and at most one state is marked as start
+func (sm *StateMachine) Validate() error {
+	var errs []error
+	seen := map[StateId]bool{}
+	starts := []StateId{}
+	// the ring is walked rather than the caches, since a duplicate id replaces the earlier state in the caches
+	states := sm.ringStates()
+	for _, v := range states {
+		id, _, start := ringStateInfo(v)
+		if id == "" {
+			errs = append(errs, ErrEmptyStateId)
+			continue
+		}
+		if seen[id] {
+			errs = append(errs, fmt.Errorf("state %s: %w", id, ErrDuplicateStateId))
+		}
+		seen[id] = true
+		if start {
+			starts = append(starts, id)
+		}
+	}
+	for _, v := range states {
+		id, next, _ := ringStateInfo(v)
+		for _, n := range next {
+			if !seen[n] {
+				errs = append(errs, fmt.Errorf("state %s: %w: %s", id, ErrUnknownNextState, n))
+			}
+		}
+	}
+	if len(starts) > 1 {
+		errs = append(errs, fmt.Errorf("%w: %v", ErrMultipleStart, starts))
+	}
+	return errors.Join(errs...)
+}
+
+// NewStateMachineChecked is NewStateMachine, returning the problems from Validate instead of a machine that is malformed
+func NewStateMachineChecked(states ...State) (*StateMachine, error) {
+	sm := NewStateMachine(states...)
+	if err := sm.Validate(); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+func NewConditionalStateMachineChecked(states ...ConditionalState) (*ConditionalStateMachine, error) {
+	sm := NewConditionalStateMachine(states...)
+	if err := sm.Validate(); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
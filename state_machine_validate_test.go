@@ -0,0 +1,51 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+// a light that moves red -> green -> yellow -> red when the data is "go"
+func testLightStates() []State {
+	next := func(to StateId) []Transition {
+		return []Transition{{NextState: to, SimpleMatcher: func(in any) bool { return in == "go" }}}
+	}
+	return []State{
+		{Id: "red", StateValue: "Red", Start: true, Matches: next("green")},
+		{Id: "green", StateValue: "Green", Matches: next("yellow")},
+		{Id: "yellow", StateValue: "Yellow", Matches: next("red")},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	sm, err := NewStateMachineChecked(testLightStates()...)
+	if err != nil || sm == nil {
+		t.Fatalf("got %v", err)
+	}
+	if err := NewStateMachine().Validate(); err != nil {
+		t.Errorf("an empty machine is valid: got %v", err)
+	}
+}
+
+func TestValidateMalformed(t *testing.T) {
+	states := append(testLightStates(),
+		State{Id: "red", StateValue: "Crimson", Start: true},
+		State{StateValue: "nothing"},
+		State{Id: "blue", Matches: []Transition{{NextState: "purple"}}},
+	)
+	sm, err := NewStateMachineChecked(states...)
+	if sm != nil {
+		t.Error("a malformed machine is not returned")
+	}
+	for _, want := range []error{ErrDuplicateStateId, ErrEmptyStateId, ErrUnknownNextState, ErrMultipleStart} {
+		if !errors.Is(err, want) {
+			t.Errorf("missing %v in %v", want, err)
+		}
+	}
+}
+
+func TestValidateConditional(t *testing.T) {
+	if _, err := NewConditionalStateMachineChecked(ConditionalState{Id: ""}); !errors.Is(err, ErrEmptyStateId) {
+		t.Errorf("got %v", err)
+	}
+}
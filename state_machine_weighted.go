@@ -0,0 +1,119 @@
+package fielder
+
+import (
+	"context"
+	"math/rand"
+)
+
+// a weighted step tests every transition of the state and picks one of those that match at random, in proportion to their weights
+// it is meant for simulating load through a machine, ex: 90% of orders are paid and 10% time out
+// a weight of 0 counts as 1, so a state with no weights picks evenly, and the Else state is taken when nothing matches
+
+func transitionWeight(w float64) float64 {
+	if w <= 0 {
+		return 1
+	}
+	return w
+}
+
+// picks one of the eligible indexes in proportion to its weight
+func pickWeighted(rng *rand.Rand, eligible []int, weight func(i int) float64) int {
+	total := 0.0
+	for _, i := range eligible {
+		total += transitionWeight(weight(i))
+	}
+	pick := rng.Float64() * total
+	for _, i := range eligible {
+		pick -= transitionWeight(weight(i))
+		if pick < 0 {
+			return i
+		}
+	}
+	return eligible[len(eligible)-1]
+}
+
+func (s *State) selectWeighted(ctx context.Context, dataToTest any, rng *rand.Rand) (StateId, int, error) {
+	if s.Terminal {
+		return s.Id, -1, nil
+	}
+	var eligible []int
+	for i, v := range s.Matches {
+		if v.Event != "" || v.After > 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return "", -1, err
+		}
+		ok, err := v.matches(ctx, dataToTest)
+		if err != nil {
+			return "", -1, &GuardError{State: s.Id, NextState: v.NextState, Err: err}
+		}
+		if ok {
+			eligible = append(eligible, i)
+		}
+	}
+	if len(eligible) == 0 {
+		return chooseTransition(s.Id, s.Else, nil, nil)
+	}
+	i := pickWeighted(rng, eligible, func(i int) float64 { return s.Matches[i].Weight })
+	return s.Matches[i].NextState, i, nil
+}
+
+func (s *ConditionalState) selectWeighted(ctx context.Context, dataToTest any, rng *rand.Rand) (StateId, int, error) {
+	if s.Terminal {
+		return s.Id, -1, nil
+	}
+	var eligible []int
+	for i, v := range s.Outcomes {
+		if v.Event != "" || v.After > 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return "", -1, err
+		}
+		ok, err := conditionalMeets(ctx, v.Conditional, dataToTest)
+		if err != nil {
+			return "", -1, &GuardError{State: s.Id, NextState: v.NextState, Err: err}
+		}
+		if ok {
+			eligible = append(eligible, i)
+		}
+	}
+	if len(eligible) == 0 {
+		return chooseTransition(s.Id, s.Else, nil, nil)
+	}
+	i := pickWeighted(rng, eligible, func(i int) float64 { return s.Outcomes[i].Weight })
+	return s.Outcomes[i].NextState, i, nil
+}
+
+// StepWeighted is Step with the next state picked by weight from every transition that matches, the rng makes a run repeatable
+// ex: mi.StepWeighted(rand.New(rand.NewSource(1)), order)
+func (mi *MachineInstance) StepWeighted(rng *rand.Rand, testData any) (StateValue, error) {
+	return mi.StepWeightedCtx(context.Background(), rng, testData)
+}
+
+func (mi *MachineInstance) StepWeightedCtx(ctx context.Context, rng *rand.Rand, testData any) (StateValue, error) {
+	mi.mu.Lock()
+	in, _ := mi.Machine.lookupValue(mi.current)
+	nextId, value, fire, err := mi.Machine.planMove(ctx, mi.current, in, testData, "", func(v any) (StateId, error) {
+		s, _ := asMachineState(v)
+		next, _, err := s.selectWeighted(ctx, testData, rng)
+		return next, err
+	})
+	if err != nil {
+		mi.mu.Unlock()
+		return nil, err
+	}
+	mi.moveTo(nextId, mi.Now())
+	mi.mu.Unlock()
+	fire()
+	return value, nil
+}
+
+// SimulateWeighted is Simulate with weighted steps, the same seed gives the same path
+// ex: SimulateWeighted(sm, rand.New(rand.NewSource(seed)), make([]any, 20)...) steps the machine 20 times with nil data
+func SimulateWeighted(sm *StateMachine, rng *rand.Rand, inputs ...any) *Simulation {
+	return simulate(sm, sm.Start, inputs, func(s MachineState, in any) (StateId, int, error) {
+		return s.selectWeighted(context.Background(), in, rng)
+	})
+}
@@ -0,0 +1,96 @@
+package fielder
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func testWeightedMachine() *StateMachine {
+	always := func(any) bool { return true }
+	return NewStateMachine(
+		State{Id: "order", StateValue: "Order", Start: true, Matches: []Transition{
+			{NextState: "paid", SimpleMatcher: always, Weight: 9},
+			{NextState: "expired", SimpleMatcher: always, Weight: 1},
+			// never eligible, so its weight does not count
+			{NextState: "refunded", SimpleMatcher: func(any) bool { return false }, Weight: 1000},
+		}, Else: "expired"},
+		State{Id: "paid", StateValue: "Paid", Matches: []Transition{{NextState: "order", SimpleMatcher: always}}},
+		State{Id: "expired", StateValue: "Expired", Matches: []Transition{{NextState: "order", SimpleMatcher: always}}},
+		State{Id: "refunded", StateValue: "Refunded"},
+	)
+}
+
+func TestSimulateWeighted(t *testing.T) {
+	sim := SimulateWeighted(testWeightedMachine(), rand.New(rand.NewSource(1)), make([]any, 2000)...)
+	sim.AssertNoErrors(t)
+	counts := map[StateId]int{}
+	for _, step := range sim.Steps {
+		if step.From == "order" {
+			counts[step.To]++
+		}
+	}
+	if counts["refunded"] != 0 || counts["paid"] < 850 || counts["paid"] > 950 || counts["paid"]+counts["expired"] != 1000 {
+		t.Errorf("got %v", counts)
+	}
+	again := SimulateWeighted(testWeightedMachine(), rand.New(rand.NewSource(1)), make([]any, 2000)...)
+	if !reflect.DeepEqual(sim.Path(), again.Path()) {
+		t.Error("the same seed gives the same path")
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	// weights of 0 count as 1, so the picks are even
+	rng := rand.New(rand.NewSource(2))
+	counts := map[int]int{}
+	for i := 0; i < 1000; i++ {
+		counts[pickWeighted(rng, []int{0, 1}, func(int) float64 { return 0 })]++
+	}
+	if counts[0] < 400 || counts[1] < 400 {
+		t.Errorf("got %v", counts)
+	}
+	if transitionWeight(-1) != 1 || transitionWeight(2.5) != 2.5 {
+		t.Error("got a wrong weight")
+	}
+}
+
+func TestStepWeighted(t *testing.T) {
+	sm := testWeightedMachine()
+	mi := NewMachineInstance(sm)
+	rng := rand.New(rand.NewSource(3))
+	v, err := mi.StepWeighted(rng, nil)
+	if err != nil || (v != "Paid" && v != "Expired") {
+		t.Fatalf("got %v %v", v, err)
+	}
+	// the Else state is taken when nothing matches
+	state, _ := sm.lookupRingState("order")
+	s := state.(State)
+	s.Matches = s.Matches[2:]
+	if next, i, err := s.selectWeighted(context.Background(), nil, rng); err != nil || next != "expired" || i != -1 {
+		t.Errorf("got %s %d %v", next, i, err)
+	}
+	csm := NewConditionalStateMachine(
+		ConditionalState{Id: "a", StateValue: "A", Outcomes: []ConditionalTransition{
+			{NextState: "b", Conditional: testNotBad(), Weight: 1},
+			{NextState: "c", Conditional: testNotBad(), Weight: 1},
+		}},
+		ConditionalState{Id: "b", StateValue: "B", Terminal: true},
+		ConditionalState{Id: "c", StateValue: "C", Terminal: true},
+	)
+	seen := map[StateId]bool{}
+	for i := 0; i < 50; i++ {
+		mi := NewMachineInstance(csm.StateMachine)
+		if _, err := mi.StepWeighted(rng, testField("Name", "good")); err != nil {
+			t.Fatal(err)
+		}
+		seen[mi.Current()] = true
+	}
+	if !seen["b"] || !seen["c"] {
+		t.Errorf("got %v", seen)
+	}
+	mi = NewMachineInstance(csm.StateMachine)
+	if _, err := mi.StepWeighted(rng, testField("Name", "bad")); err == nil || mi.Current() != "a" {
+		t.Errorf("got %v", err)
+	}
+}
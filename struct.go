@@ -0,0 +1,187 @@
+package fielder
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// separates the segments of a key that points into a nested struct, ex: Address.City
+const FieldPathSeparator = "."
+
+// StructField wraps a nested parent struct so it can be used as a field of the outer parent
+// the inner fields are reachable with Get, using a path relative to this field, ex: "City" or "Geo.Lat"
+// and are keyed with the full path from the outer parent, ex: "Address.City"
+type StructField struct {
+	ValueField any          `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey     `dynamodbav:"key" json:"key"`
+	StructType reflect.Type `dynamodbav:"-" json:"-"`
+}
+
+func NewStructField(key FieldKey, value any) *StructField {
+	return &StructField{
+		ValueField: value,
+		KeyField:   key,
+		StructType: reflect.TypeOf(value),
+	}
+}
+
+func createStructField(ty reflect.Type, va any, fk FieldKey) Field {
+	if va == nil {
+		va = reflect.New(ty).Elem().Interface()
+	}
+	return &StructField{
+		ValueField: va,
+		KeyField:   fk,
+		StructType: ty,
+	}
+}
+
+// walks a dotted path through nested structs, returns the zero reflect.Value if any segment is missing
+func reflectValueByPath(v reflect.Value, path FieldName) reflect.Value {
+	for _, name := range strings.Split(path.String(), FieldPathSeparator) {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return reflect.Value{}
+		}
+	}
+	return v
+}
+
+// the member as a field, members that are fields are returned as they are and a nil one is FieldNil
+// other members are made into a field with CreateFieldFromType, which is nil for types it does not support
+func memberAsField(v reflect.Value, key FieldKey) Field {
+	if v.Type().Implements(fieldInterfaceType) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return FieldNil
+		}
+		if v.CanInterface() {
+			return v.Interface().(Field)
+		}
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+	return CreateFieldFromType(v.Type(), v.Interface(), key)
+}
+
+// walks a dotted path through nested struct types, returns nil if any segment is missing
+func reflectTypeByPath(t reflect.Type, path FieldName) reflect.Type {
+	for _, name := range strings.Split(path.String(), FieldPathSeparator) {
+		if t == nil || t.Kind() != reflect.Struct {
+			return nil
+		}
+		sf, ok := t.FieldByName(name)
+		if !ok {
+			return nil
+		}
+		t = sf.Type
+	}
+	return t
+}
+
+func (s *StructField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *StructField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *StructField) Type() reflect.Type {
+	return s.StructType
+}
+
+// less than and greater than are not relevant for structs
+func (s *StructField) LessThan(in2 any) bool {
+	return false
+}
+
+func (s *StructField) GreaterThan(in2 any) bool {
+	return false
+}
+
+// structs are equal when every inner field is equal
+func (s *StructField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	s2 := in2.(*StructField)
+	for _, v := range s.Fields() {
+		if !v.Equal(s2.Get(strings.TrimPrefix(v.Key().Name.String(), s.KeyField.Name.String()+FieldPathSeparator))) {
+			return false
+		}
+	}
+	return true
+}
+
+// the string form is the json encoding of the inner struct
+func (s *StructField) ToString() string {
+	b, err := json.Marshal(s.ValueField)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (s *StructField) FromString(st string) {
+	if s.StructType == nil {
+		return
+	}
+	v := reflect.New(s.StructType)
+	if err := json.Unmarshal([]byte(st), v.Interface()); err != nil {
+		return
+	}
+	s.ValueField = v.Elem().Interface()
+}
+
+func (s *StructField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = in2.(*StructField).ValueField
+	return
+}
+
+func (s *StructField) IsEmpty() bool {
+	return s.ValueField == nil || reflect.ValueOf(s.ValueField).IsZero()
+}
+
+// returns the inner field at the path, relative to this field. returns FieldNil if the path does not exist
+func (s *StructField) Get(path string) Field {
+	v := reflectValueByPath(reflect.ValueOf(s.ValueField), FieldName(path))
+	if !v.IsValid() {
+		return FieldNil
+	}
+	f := memberAsField(v, nestedFieldKey(s.KeyField, path))
+	if f == nil {
+		return FieldNil
+	}
+	return f
+}
+
+// returns a field for every tagged member of the inner struct, in declaration order, nil field members are left out
+// nested structs come back as StructFields, so callers can keep descending with Get or Fields
+func (s *StructField) Fields() []Field {
+	out := []Field{}
+	if s.StructType == nil || s.StructType.Kind() != reflect.Struct {
+		return out
+	}
+	v := reflect.ValueOf(s.ValueField)
+	for i := 0; i < s.StructType.NumField(); i++ {
+		sf := s.StructType.Field(i)
+		if !sf.IsExported() || sf.Tag.Get(s.KeyField.Tag) == "" {
+			continue
+		}
+		if f := memberAsField(v.Field(i), nestedFieldKey(s.KeyField, sf.Name)); f != nil && f != FieldNil {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testGeo struct {
+	Lat float64 `field:"Lat"`
+}
+
+type testAddress struct {
+	City string       `field:"City"`
+	Geo  testGeo      `field:"Geo"`
+	Zip  *StringField `field:"Zip"`
+}
+
+func TestStructFieldGet(t *testing.T) {
+	s := NewStructField(NewDefaultFieldKey("Address"), testAddress{City: "Oslo", Geo: testGeo{Lat: 59.9}, Zip: NewStringField(NewDefaultFieldKey("Zip"), "0150")})
+	if f := s.Get("City"); f.Value() != "Oslo" || f.Key().Name != "Address.City" {
+		t.Errorf("Get City: got %v keyed %s", f.Value(), f.Key().Name)
+	}
+	if f := s.Get("Geo.Lat"); f.Value() != 59.9 || f.Key().Name != "Address.Geo.Lat" {
+		t.Errorf("Get Geo.Lat: got %v keyed %s", f.Value(), f.Key().Name)
+	}
+	if f := s.Get("Zip"); f.Value() != "0150" {
+		t.Errorf("a member that is a field is returned as it is: got %v", f.Value())
+	}
+	if s.Get("Nope") != FieldNil || s.Get("City.Nope") != FieldNil {
+		t.Error("a missing path is FieldNil")
+	}
+}
+
+func TestStructFieldFields(t *testing.T) {
+	s := NewStructField(NewDefaultFieldKey("Address"), testAddress{City: "Oslo"})
+	var names []FieldName
+	for _, f := range s.Fields() {
+		names = append(names, f.Key().Name)
+	}
+	// Zip is a nil field and is left out
+	if !reflect.DeepEqual(names, []FieldName{"Address.City", "Address.Geo"}) {
+		t.Errorf("Fields: got %v", names)
+	}
+	if _, ok := s.Fields()[1].(*StructField); !ok {
+		t.Error("nested structs come back as StructFields")
+	}
+}
+
+func TestStructFieldEqualAndString(t *testing.T) {
+	key := NewDefaultFieldKey("Address")
+	a := NewStructField(key, testAddress{City: "Oslo"})
+	b := NewStructField(key, testAddress{City: "Oslo"})
+	if !a.Equal(b) {
+		t.Error("structs with equal members are equal")
+	}
+	b.FromString(`{"City":"Bergen"}`)
+	if a.Equal(b) || b.Get("City").Value() != "Bergen" {
+		t.Errorf("FromString: got %v", b.Value())
+	}
+	a.SetValue(b)
+	if !a.Equal(b) {
+		t.Error("SetValue copies the struct")
+	}
+	if a.IsEmpty() || !NewStructField(key, testAddress{}).IsEmpty() {
+		t.Error("only the zero struct is empty")
+	}
+	created := CreateFieldFromType(reflect.TypeOf(testAddress{}), nil, key)
+	if sf, ok := created.(*StructField); !ok || !sf.IsEmpty() {
+		t.Errorf("CreateFieldFromType: got %#v", created)
+	}
+}
+
+func TestReflectByPath(t *testing.T) {
+	v := reflect.ValueOf(testAddress{Geo: testGeo{Lat: 1}})
+	if got := reflectValueByPath(v, "Geo.Lat"); !got.IsValid() || got.Float() != 1 {
+		t.Errorf("reflectValueByPath: got %v", got)
+	}
+	if reflectValueByPath(v, "City.Len").IsValid() {
+		t.Error("a path through a non struct is invalid")
+	}
+	if got := reflectTypeByPath(reflect.TypeOf(testAddress{}), "Geo.Lat"); got != reflect.TypeOf(0.0) {
+		t.Errorf("reflectTypeByPath: got %v", got)
+	}
+	if reflectTypeByPath(reflect.TypeOf(testAddress{}), "Missing") != nil {
+		t.Error("a missing member has no type")
+	}
+}
@@ -1,9 +1,15 @@
 package fielder
 
 import (
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"net"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -25,16 +31,31 @@ type Parent interface {
 //		DefaultStringItem `field:"DefaultStringItem"`
 //	}
 func GetResultItemFieldFromKeyDefault[parentValueType any](in parentValueType, f FieldKey) Field {
-	if fieldValue := GetReflectValueOfKeyDefault(in, f); fieldValue.IsZero() || (fieldValue.Interface().(Field)).Key() == FieldKeyNil {
+	fieldValue := GetReflectValueOfKeyDefault(in, f)
+	if !fieldValue.IsValid() {
 		return FieldNil
-	} else {
+	}
+	if fieldValue.Type().Implements(fieldInterfaceType) {
+		// members that are fields are returned as they are, a nil one is FieldNil
+		if inner := memberAsField(fieldValue, f); inner != nil && inner.Key() != FieldKeyNil {
+			return inner
+		}
+		return FieldNil
+	}
+	if fieldValue.Kind() == reflect.Pointer {
+		// pointer members are optional values, a nil pointer is an empty field rather than a missing one
 		return CreateFieldFromType(fieldValue.Type(), fieldValue.Interface(), f)
 	}
+	if fieldValue.IsZero() {
+		return FieldNil
+	}
+	return CreateFieldFromType(fieldValue.Type(), fieldValue.Interface(), f)
 }
 
 func GetReflectValueOfKeyDefault[parentValueType any](in parentValueType, f FieldKey) reflect.Value {
 	itemStructValue := reflect.ValueOf(in)
-	return itemStructValue.FieldByName(string(f.Name))
+	// keys can point into nested structs, ex: Address.City
+	return reflectValueByPath(itemStructValue, f.Name)
 }
 
 func CheckKeyExistsDefault[parentValueType any](f FieldKey) bool {
@@ -46,11 +67,7 @@ func CheckKeyExistsDefault[parentValueType any](f FieldKey) bool {
 }
 
 func GetFieldTypeFromKey[parentValueType any](f FieldKey) reflect.Type {
-	if fieldType, ok := reflect.TypeOf(*new(parentValueType)).FieldByName(f.Name.String()); ok {
-		return fieldType.Type
-	} else {
-		return nil
-	}
+	return reflectTypeByPath(reflect.TypeOf(*new(parentValueType)), f.Name)
 }
 
 type FieldKey struct {
@@ -96,11 +113,18 @@ func FullKeySet[inType any](tag string) []FieldKey {
 	keySet := []FieldKey{}
 	reflectType := reflect.TypeOf(*new(inType))
 	for i := 0; i < reflectType.NumField(); i++ {
-		keySet = append(keySet, NewFieldKey(reflectType.Field(i).Tag.Get(tag), tag))
+		name, _ := parseFieldTag(reflectType.Field(i).Tag.Get(tag))
+		keySet = append(keySet, NewFieldKey(name, tag))
 	}
 	return keySet
 }
 
+// a tag value is the field name followed by comma separated options, ex: field:"UserId,pk"
+func parseFieldTag(tagValue string) (string, []string) {
+	parts := strings.Split(tagValue, ",")
+	return parts[0], parts[1:]
+}
+
 var FieldNil = CreateFieldFromType((&EmptyField{}).Type(), nil, FieldKeyNil)
 
 // field interface
@@ -120,6 +144,49 @@ type Field interface {
 type StringField struct {
 	ValueField string   `dynamodbav:"value" json:"value"`
 	KeyField   FieldKey `dynamodbav:"key" json:"key"`
+	// compare with unicode case folding, so "Apple" == "apple"
+	FoldCase bool `dynamodbav:"-" json:"-"`
+	// compare with a language collator for user facing ordering, takes precedence over FoldCase
+	// collators are not safe for concurrent use, so neither is comparing a field that has one
+	Collator *collate.Collator `dynamodbav:"-" json:"-"`
+}
+
+type StringFieldOption func(*StringField)
+
+func WithFoldCase() StringFieldOption {
+	return func(s *StringField) {
+		s.FoldCase = true
+	}
+}
+
+// ex: WithCollation(language.German, collate.IgnoreCase)
+func WithCollation(tag language.Tag, opts ...collate.Option) StringFieldOption {
+	return func(s *StringField) {
+		s.Collator = collate.New(tag, opts...)
+	}
+}
+
+func NewStringField(key FieldKey, st string, opts ...StringFieldOption) *StringField {
+	s := &StringField{
+		ValueField: st,
+		KeyField:   key,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *StringField) compare(st string) int {
+	switch {
+	case s.Collator != nil:
+		return s.Collator.CompareString(s.ValueField, st)
+	case s.FoldCase:
+		fold := cases.Fold()
+		return strings.Compare(fold.String(s.ValueField), fold.String(st))
+	default:
+		return strings.Compare(s.ValueField, st)
+	}
 }
 
 func (s *StringField) Value() FieldValue {
@@ -138,21 +205,21 @@ func (s *StringField) LessThan(in2 any) bool {
 	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
 		return *out
 	}
-	return s.ValueField < in2.(*StringField).ValueField
+	return s.compare(in2.(*StringField).ValueField) < 0
 }
 
 func (s *StringField) GreaterThan(in2 any) bool {
 	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
 		return *out
 	}
-	return s.ValueField > in2.(*StringField).ValueField
+	return s.compare(in2.(*StringField).ValueField) > 0
 }
 
 func (s *StringField) Equal(in2 any) bool {
 	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
 		return *out
 	}
-	return s.ValueField == in2.(*StringField).ValueField
+	return s.compare(in2.(*StringField).ValueField) == 0
 }
 
 func (s *StringField) ToString() string {
@@ -164,6 +231,7 @@ func (s *StringField) FromString(st string) {
 }
 
 func (s *StringField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
 	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
 		f := in2.(Field)
 		s.ValueField = f.ToString()
@@ -180,6 +248,54 @@ func (s *StringField) IsEmpty() bool {
 type TimeField struct {
 	ValueField time.Time `dynamodbav:"value" json:"value"`
 	KeyField   FieldKey  `dynamodbav:"key" json:"key"`
+	// layouts FromString will try in order, the first is also used by ToString. defaults to RFC3339
+	Layouts []string `dynamodbav:"-" json:"-"`
+	// the zone values are normalized to, and the zone used for layouts without one. defaults to UTC
+	Location *time.Location `dynamodbav:"-" json:"-"`
+}
+
+type TimeFieldOption func(*TimeField)
+
+func WithLayouts(layouts ...string) TimeFieldOption {
+	return func(s *TimeField) {
+		s.Layouts = layouts
+	}
+}
+
+func WithLocation(loc *time.Location) TimeFieldOption {
+	return func(s *TimeField) {
+		s.Location = loc
+	}
+}
+
+func NewTimeField(key FieldKey, t time.Time, opts ...TimeFieldOption) *TimeField {
+	s := &TimeField{KeyField: key}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.ValueField = s.normalize(t)
+	return s
+}
+
+func (s *TimeField) layouts() []string {
+	if len(s.Layouts) == 0 {
+		return []string{time.RFC3339}
+	}
+	return s.Layouts
+}
+
+func (s *TimeField) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+func (s *TimeField) normalize(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(s.location())
 }
 
 func (s *TimeField) Value() FieldValue {
@@ -216,24 +332,27 @@ func (s *TimeField) Equal(in2 any) bool {
 }
 
 func (s *TimeField) ToString() string {
-	return s.ValueField.Format(time.RFC3339)
+	return s.ValueField.Format(s.layouts()[0])
 }
 
+// tries each layout in order, input matching none of them is ignored
 func (s *TimeField) FromString(st string) {
-	t, err := time.Parse(time.RFC3339, st)
-	if err != nil {
-		return
+	for _, layout := range s.layouts() {
+		if t, err := time.ParseInLocation(layout, st, s.location()); err == nil {
+			s.ValueField = s.normalize(t)
+			return
+		}
 	}
-	s.ValueField = t
 }
 
 func (s *TimeField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
 	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
 		f := in2.(Field)
 		s.FromString(f.ToString())
 		return
 	}
-	s.ValueField = in2.(*TimeField).ValueField
+	s.ValueField = s.normalize(in2.(*TimeField).ValueField)
 	return
 }
 
@@ -244,6 +363,91 @@ func (s *TimeField) IsEmpty() bool {
 type DecimalField struct {
 	ValueField decimal.Decimal `dynamodbav:"value" json:"value"`
 	KeyField   FieldKey        `dynamodbav:"key" json:"key"`
+	// number of decimal places values are rounded to on set, only applied when Rounding is not RoundNone
+	// a Scale of 0 only rounds to whole numbers when it was given with WithScale, so a mode without a scale does not round
+	Scale    int32        `dynamodbav:"-" json:"-"`
+	Rounding RoundingMode `dynamodbav:"-" json:"-"`
+	// values within Tolerance of each other compare as equal
+	Tolerance decimal.Decimal `dynamodbav:"-" json:"-"`
+	scaled    bool
+}
+
+type RoundingMode int
+
+const (
+	RoundNone     RoundingMode = iota
+	RoundHalfUp                // half away from zero, like decimal.Round
+	RoundHalfEven              // bankers rounding, like decimal.RoundBank
+	RoundCeil
+	RoundFloor
+	RoundUp   // away from zero
+	RoundDown // toward zero, truncates
+)
+
+type DecimalFieldOption func(*DecimalField)
+
+// rounds to places decimal places on set, using RoundHalfUp unless WithRounding says otherwise
+func WithScale(places int32) DecimalFieldOption {
+	return func(s *DecimalField) {
+		s.Scale = places
+		s.scaled = true
+		if s.Rounding == RoundNone {
+			s.Rounding = RoundHalfUp
+		}
+	}
+}
+
+// the mode WithScale rounds with, on its own it does not round
+func WithRounding(mode RoundingMode) DecimalFieldOption {
+	return func(s *DecimalField) {
+		s.Rounding = mode
+	}
+}
+
+func WithTolerance(tolerance decimal.Decimal) DecimalFieldOption {
+	return func(s *DecimalField) {
+		s.Tolerance = tolerance.Abs()
+	}
+}
+
+func NewDecimalField(key FieldKey, d decimal.Decimal, opts ...DecimalFieldOption) *DecimalField {
+	s := &DecimalField{KeyField: key}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.ValueField = s.round(d)
+	return s
+}
+
+func (s *DecimalField) round(d decimal.Decimal) decimal.Decimal {
+	if s.Scale == 0 && !s.scaled {
+		return d
+	}
+	switch s.Rounding {
+	case RoundHalfUp:
+		return d.Round(s.Scale)
+	case RoundHalfEven:
+		return d.RoundBank(s.Scale)
+	case RoundCeil:
+		return d.RoundCeil(s.Scale)
+	case RoundFloor:
+		return d.RoundFloor(s.Scale)
+	case RoundUp:
+		return d.RoundUp(s.Scale)
+	case RoundDown:
+		return d.RoundDown(s.Scale)
+	default:
+		return d
+	}
+}
+
+// -1, 0 or 1, treating values within the tolerance as equal
+func (s *DecimalField) compare(d decimal.Decimal) int {
+	diff := s.ValueField.Sub(d)
+	if diff.Abs().LessThanOrEqual(s.Tolerance) {
+		return 0
+	}
+	return diff.Sign()
 }
 
 func (s *DecimalField) Value() FieldValue {
@@ -262,21 +466,21 @@ func (s *DecimalField) LessThan(in2 any) bool {
 	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
 		return *out
 	}
-	return s.ValueField.LessThan(in2.(*DecimalField).ValueField)
+	return s.compare(in2.(*DecimalField).ValueField) < 0
 }
 
 func (s *DecimalField) GreaterThan(in2 any) bool {
 	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
 		return *out
 	}
-	return s.ValueField.GreaterThan(in2.(*DecimalField).ValueField)
+	return s.compare(in2.(*DecimalField).ValueField) > 0
 }
 
 func (s *DecimalField) Equal(in2 any) bool {
 	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
 		return *out
 	}
-	return s.ValueField.Equal(in2.(*DecimalField).ValueField)
+	return s.compare(in2.(*DecimalField).ValueField) == 0
 }
 
 func (s *DecimalField) ToString() string {
@@ -288,16 +492,17 @@ func (s *DecimalField) FromString(st string) {
 	if err != nil {
 		return
 	}
-	s.ValueField = d
+	s.ValueField = s.round(d)
 }
 
 func (s *DecimalField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
 	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
 		f := in2.(Field)
 		s.FromString(f.ToString())
 		return
 	}
-	s.ValueField = in2.(*DecimalField).ValueField
+	s.ValueField = s.round(in2.(*DecimalField).ValueField)
 	return
 }
 
@@ -356,6 +561,7 @@ func (s *IntegerField) FromString(st string) {
 }
 
 func (s *IntegerField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
 	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
 		f := in2.(Field)
 		s.FromString(f.ToString())
@@ -441,6 +647,7 @@ func (s *BoolField) FromString(st string) {
 }
 
 func (s *BoolField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
 	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
 		f := in2.(Field)
 		s.FromString(f.ToString())
@@ -451,8 +658,9 @@ func (s *BoolField) SetValue(in2 FieldValue) {
 	return
 }
 
+// a bool is empty until it is set, false is a value like true
 func (s *BoolField) IsEmpty() bool {
-	return s.Set
+	return !s.Set
 }
 
 type EmptyField struct {
@@ -515,6 +723,18 @@ func CreateFieldFromType(ty reflect.Type, va any, fk FieldKey) Field {
 	d := decimal.Decimal{}
 	i := int(0)
 	b := true
+	dur := time.Duration(0)
+	u := uuid.UUID{}
+	by := []byte{}
+	da := Date{}
+	mo := Money{}
+	ur := URL("")
+	em := Email("")
+	ip := net.IP{}
+	ipn := net.IPNet{}
+	geo := GeoPoint{}
+	fl := float64(0)
+	qu := Quantity{}
 	if ty == nil {
 		return nil
 	}
@@ -568,10 +788,144 @@ func CreateFieldFromType(ty reflect.Type, va any, fk FieldKey) Field {
 		return &BoolField{
 			ValueField: va.(bool),
 			KeyField:   fk,
+			Set:        true,
+		}
+	case reflect.TypeOf(dur):
+		if va == nil {
+			return &DurationField{
+				KeyField: fk,
+			}
+		}
+		return &DurationField{
+			ValueField: va.(time.Duration),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(u):
+		if va == nil {
+			return &UUIDField{
+				KeyField: fk,
+			}
+		}
+		return &UUIDField{
+			ValueField: va.(uuid.UUID),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(by):
+		if va == nil {
+			return &BytesField{
+				KeyField: fk,
+			}
+		}
+		return &BytesField{
+			ValueField: va.([]byte),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(da):
+		if va == nil {
+			return &DateField{
+				KeyField: fk,
+			}
+		}
+		return &DateField{
+			ValueField: va.(Date),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(mo):
+		if va == nil {
+			return &MoneyField{
+				KeyField: fk,
+			}
+		}
+		return &MoneyField{
+			ValueField: va.(Money),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(ur):
+		if va == nil {
+			return &URLField{
+				KeyField: fk,
+			}
+		}
+		return &URLField{
+			ValueField: va.(URL),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(em):
+		if va == nil {
+			return &EmailField{
+				KeyField: fk,
+			}
+		}
+		return &EmailField{
+			ValueField: va.(Email),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(ip):
+		if va == nil {
+			return &IPField{
+				KeyField: fk,
+			}
+		}
+		return &IPField{
+			ValueField: va.(net.IP),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(ipn):
+		if va == nil {
+			return &CIDRField{
+				KeyField: fk,
+			}
+		}
+		return &CIDRField{
+			ValueField: va.(net.IPNet),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(geo):
+		if va == nil {
+			return &GeoPointField{
+				KeyField: fk,
+			}
+		}
+		return &GeoPointField{
+			ValueField: va.(GeoPoint),
+			KeyField:   fk,
+			Set:        true,
+		}
+	case reflect.TypeOf(fl):
+		if va == nil {
+			return &FloatField{
+				KeyField: fk,
+			}
+		}
+		return &FloatField{
+			ValueField: va.(float64),
+			KeyField:   fk,
+		}
+	case reflect.TypeOf(qu):
+		if va == nil {
+			return &QuantityField{
+				KeyField: fk,
+			}
+		}
+		return &QuantityField{
+			ValueField: va.(Quantity),
+			KeyField:   fk,
 		}
 	case reflect.TypeOf(&EmptyField{}):
 		return &EmptyField{KeyField: fk}
 	default:
+		if ty.Kind() == reflect.Pointer {
+			return createPointerField(ty, va, fk)
+		}
+		if ty.Kind() == reflect.Slice {
+			return createSliceField(ty, va, fk)
+		}
+		if ty.Kind() == reflect.Map {
+			return createMapField(ty, va, fk)
+		}
+		if ty.Kind() == reflect.Struct {
+			return createStructField(ty, va, fk)
+		}
 		// THIS SHOULD NEVER HAPPEN
 		return nil
 	}
@@ -598,8 +952,61 @@ func checkAndDoSafeCompare(f1 Field, f2 any, o safeOp) *bool {
 	if noSafeCheck := sameCompareTypes(f1, f2f); !noSafeCheck {
 		return pointerTo(safeCompare(f1.ToString(), f2f.ToString(), o))
 	}
-	// return nil if we need to do a same type comparison
-	return nil
+	if reflect.TypeOf(f1) == reflect.TypeOf(f2f) {
+		// return nil if we need to do a same type comparison
+		return nil
+	}
+	// the same type from another go type, ex: a FieldNullable or FieldVersioned around the same field,
+	// is compared through the field it wraps, the caller can not assert it to its own type
+	inner := f2f
+	for reflect.TypeOf(inner) != reflect.TypeOf(f1) {
+		if inner = unwrapField(inner); inner == nil {
+			return pointerTo(safeCompare(f1.ToString(), f2f.ToString(), o))
+		}
+	}
+	switch o {
+	case LT:
+		return pointerTo(f1.LessThan(inner))
+	case GT:
+		return pointerTo(f1.GreaterThan(inner))
+	case EQ:
+		return pointerTo(f1.Equal(inner))
+	}
+	return pointerTo(safeCompare(f1.ToString(), f2f.ToString(), o))
+}
+
+// the value SetValue copies from, a wrapper around a field of the receiver's type, ex: a RedactedField or EncryptedField,
+// is unwrapped so its value is copied rather than its string form, which would be "****" or the ciphertext
+func setSource(s Field, in2 FieldValue) FieldValue {
+	if f, ok := in2.(Field); ok && f != nil {
+		return unwrapFieldTo(f, s)
+	}
+	return in2
+}
+
+// returns the field a wrapper holds, or nil when f does not wrap a field or a null one
+func unwrapField(f Field) Field {
+	switch v := f.(type) {
+	case *ComputedField:
+		return v.current()
+	case *FieldNullable:
+		if v.Null {
+			return nil
+		}
+	}
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	sf, ok := v.Elem().Type().FieldByName("Field")
+	if !ok || sf.Type != fieldInterfaceType {
+		return nil
+	}
+	inner, err := v.Elem().FieldByIndexErr(sf.Index)
+	if err != nil || inner.IsNil() {
+		return nil
+	}
+	return inner.Interface().(Field)
 }
 
 // for safe operations between different types
@@ -616,8 +1023,11 @@ type Op func(s1, s2 string) bool
 
 var (
 	EQ safeOp = "EQ"
+	NE safeOp = "NE"
 	LT safeOp = "LT"
+	LE safeOp = "LE"
 	GT safeOp = "GT"
+	GE safeOp = "GE"
 
 	safeOps = map[safeOp]Op{
 		EQ: func(s1, s2 string) bool {
@@ -629,5 +1039,14 @@ var (
 		GT: func(s1, s2 string) bool {
 			return s1 > s2
 		},
+		NE: func(s1, s2 string) bool {
+			return s1 != s2
+		},
+		LE: func(s1, s2 string) bool {
+			return s1 <= s2
+		},
+		GE: func(s1, s2 string) bool {
+			return s1 >= s2
+		},
 	}
 )
@@ -0,0 +1,154 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+func TestTimeFieldLayouts(t *testing.T) {
+	f := NewTimeField(NewDefaultFieldKey("At"), time.Time{}, WithLayouts("2006-01-02", "01/02/2006"))
+	f.FromString("03/04/2025")
+	if want := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC); !f.ValueField.Equal(want) {
+		t.Fatalf("the second layout is tried: got %s", f.ValueField)
+	}
+	if f.ToString() != "2025-03-04" {
+		t.Errorf("the first layout is used by ToString: got %q", f.ToString())
+	}
+	f.FromString("March 4")
+	if f.ToString() != "2025-03-04" {
+		t.Errorf("input matching no layout is ignored: got %q", f.ToString())
+	}
+	if err := f.ParseString("March 4"); !errors.Is(err, ErrInvalidTime) {
+		t.Errorf("ParseString: got %v", err)
+	}
+}
+
+func TestTimeFieldLocation(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	f := NewTimeField(NewDefaultFieldKey("At"), time.Date(2025, 1, 1, 17, 0, 0, 0, time.UTC), WithLocation(ny))
+	if f.ValueField.Location() != ny || f.ValueField.Hour() != 12 {
+		t.Errorf("values are normalized to the location: got %s", f.ValueField)
+	}
+	g := NewTimeField(NewDefaultFieldKey("At"), time.Time{}, WithLocation(ny), WithLayouts("2006-01-02 15:04"))
+	g.FromString("2025-01-01 12:00")
+	if !g.Equal(f) {
+		t.Errorf("layouts without a zone are read in the location: got %s", g.ValueField)
+	}
+	utc := &TimeField{}
+	utc.SetValue(f)
+	if utc.ValueField.Location() != time.UTC || !utc.ValueField.Equal(f.ValueField) {
+		t.Errorf("SetValue normalizes to the field's own location: got %s", utc.ValueField)
+	}
+}
+
+func TestTimeFieldDefaults(t *testing.T) {
+	f := &TimeField{}
+	f.FromString("2025-01-01T12:00:00+02:00")
+	if f.ToString() != "2025-01-01T10:00:00Z" {
+		t.Errorf("RFC3339 in UTC by default: got %q", f.ToString())
+	}
+}
+
+func testDecimal(t *testing.T, st string, opts ...DecimalFieldOption) *DecimalField {
+	t.Helper()
+	return NewDecimalField(NewDefaultFieldKey("Amount"), decimal.RequireFromString(st), opts...)
+}
+
+func TestDecimalFieldRounding(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		opts []DecimalFieldOption
+		want string
+	}{
+		{"2.345", []DecimalFieldOption{WithScale(2)}, "2.35"},
+		{"2.345", []DecimalFieldOption{WithScale(2), WithRounding(RoundHalfEven)}, "2.34"},
+		{"2.345", []DecimalFieldOption{WithRounding(RoundDown), WithScale(1)}, "2.3"},
+		{"-2.31", []DecimalFieldOption{WithScale(1), WithRounding(RoundFloor)}, "-2.4"},
+		{"2.31", []DecimalFieldOption{WithScale(1), WithRounding(RoundCeil)}, "2.4"},
+		{"2.5", []DecimalFieldOption{WithScale(0)}, "3"},
+		// a mode without a scale does not round
+		{"2.345", []DecimalFieldOption{WithRounding(RoundHalfUp)}, "2.345"},
+		{"2.345", nil, "2.345"},
+	} {
+		if got := testDecimal(t, tc.in, tc.opts...).ToString(); got != tc.want {
+			t.Errorf("%s: got %s want %s", tc.in, got, tc.want)
+		}
+	}
+	f := testDecimal(t, "0", WithScale(2))
+	f.FromString("1.005")
+	if f.ToString() != "1.01" {
+		t.Errorf("FromString rounds: got %s", f.ToString())
+	}
+	f.SetValue(testDecimal(t, "3.333"))
+	if f.ToString() != "3.33" {
+		t.Errorf("SetValue rounds: got %s", f.ToString())
+	}
+}
+
+func TestDecimalFieldTolerance(t *testing.T) {
+	f := testDecimal(t, "1.00", WithTolerance(decimal.RequireFromString("-0.01")))
+	if !f.Equal(testDecimal(t, "1.01")) || !f.Equal(testDecimal(t, "0.99")) {
+		t.Error("values within the tolerance are equal")
+	}
+	if !f.LessThan(testDecimal(t, "1.02")) || !f.GreaterThan(testDecimal(t, "0.98")) {
+		t.Error("values outside the tolerance are ordered")
+	}
+}
+
+func TestStringFieldFoldCase(t *testing.T) {
+	key := NewDefaultFieldKey("Name")
+	f := NewStringField(key, "Straße", WithFoldCase())
+	if !f.Equal(NewStringField(key, "STRASSE")) || f.LessThan(NewStringField(key, "strasse")) {
+		t.Error("folded strings compare equal")
+	}
+	if NewStringField(key, "Apple").Equal(NewStringField(key, "apple")) {
+		t.Error("strings compare by bytes without FoldCase")
+	}
+	if f.ToString() != "Straße" {
+		t.Errorf("the value is kept as written: got %q", f.ToString())
+	}
+}
+
+func TestStringFieldCollation(t *testing.T) {
+	key := NewDefaultFieldKey("Name")
+	// by bytes "Z" < "a" and "ä" sorts after "z"
+	if !NewStringField(key, "Z").LessThan(NewStringField(key, "a")) {
+		t.Fatal("byte order")
+	}
+	f := NewStringField(key, "a", WithCollation(language.German))
+	if !f.LessThan(NewStringField(key, "Z")) {
+		t.Error("the collator orders letters regardless of case")
+	}
+	umlaut := NewStringField(key, "ä", WithCollation(language.German))
+	if !umlaut.LessThan(NewStringField(key, "b")) {
+		t.Error("ä sorts with a in german")
+	}
+	ignore := NewStringField(key, "Apple", WithFoldCase(), WithCollation(language.English, collate.IgnoreCase))
+	if !ignore.Equal(NewStringField(key, "APPLE")) {
+		t.Error("collator options apply")
+	}
+}
+
+func TestBoolFieldIsEmpty(t *testing.T) {
+	key := NewDefaultFieldKey("Active")
+	if !NewBoolEmpty(key).IsEmpty() || !CreateFieldFromType(reflect.TypeOf(true), nil, key).IsEmpty() {
+		t.Error("a bool is empty until it is set")
+	}
+	if NewBool(key, false).IsEmpty() || CreateFieldFromType(reflect.TypeOf(true), false, key).IsEmpty() {
+		t.Error("false is a value")
+	}
+	b := NewBoolEmpty(key)
+	b.FromString("false")
+	if b.IsEmpty() {
+		t.Error("a bool read from a string is set")
+	}
+}
@@ -0,0 +1,123 @@
+package fielder
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypedField lets callers wrap their own domain types as fields without adding a new struct to this package
+// the behavior of the field is driven entirely by the Comparators it is constructed with
+// ex:
+//
+//	type Money struct {
+//		Cents    int64
+//		Currency string
+//	}
+//
+//	f := NewTypedField(NewDefaultFieldKey("Price"), Money{Cents: 100, Currency: "USD"}, TypedComparators[Money]{
+//		Less: func(a, b Money) bool { return a.Cents < b.Cents },
+//	})
+type TypedField[T comparable] struct {
+	ValueField  T                   `dynamodbav:"value" json:"value"`
+	KeyField    FieldKey            `dynamodbav:"key" json:"key"`
+	Comparators TypedComparators[T] `dynamodbav:"-" json:"-"`
+}
+
+// TypedComparators is the set of functions a TypedField uses in place of hard coded comparisons
+// any function left nil falls back to a sensible default:
+// Less reports false, Equal uses ==, ToString uses fmt and FromString rejects the input
+type TypedComparators[T comparable] struct {
+	Less       func(a, b T) bool
+	Equal      func(a, b T) bool
+	ToString   func(a T) string
+	FromString func(st string) (T, error)
+}
+
+func NewTypedField[T comparable](key FieldKey, value T, comparators TypedComparators[T]) *TypedField[T] {
+	return &TypedField[T]{
+		ValueField:  value,
+		KeyField:    key,
+		Comparators: comparators,
+	}
+}
+
+func (s *TypedField[T]) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *TypedField[T]) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *TypedField[T]) Type() reflect.Type {
+	// go through the pointer so interface type parameters still produce a type
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (s *TypedField[T]) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.less(s.ValueField, in2.(*TypedField[T]).ValueField)
+}
+
+func (s *TypedField[T]) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.less(in2.(*TypedField[T]).ValueField, s.ValueField)
+}
+
+func (s *TypedField[T]) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.equal(s.ValueField, in2.(*TypedField[T]).ValueField)
+}
+
+func (s *TypedField[T]) ToString() string {
+	if s.Comparators.ToString == nil {
+		return fmt.Sprint(s.ValueField)
+	}
+	return s.Comparators.ToString(s.ValueField)
+}
+
+func (s *TypedField[T]) FromString(st string) {
+	if s.Comparators.FromString == nil {
+		return
+	}
+	v, err := s.Comparators.FromString(st)
+	if err != nil {
+		return
+	}
+	s.ValueField = v
+}
+
+func (s *TypedField[T]) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = in2.(*TypedField[T]).ValueField
+	return
+}
+
+func (s *TypedField[T]) IsEmpty() bool {
+	return s.equal(s.ValueField, *new(T))
+}
+
+func (s *TypedField[T]) less(a, b T) bool {
+	if s.Comparators.Less == nil {
+		return false
+	}
+	return s.Comparators.Less(a, b)
+}
+
+func (s *TypedField[T]) equal(a, b T) bool {
+	if s.Comparators.Equal == nil {
+		return a == b
+	}
+	return s.Comparators.Equal(a, b)
+}
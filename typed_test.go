@@ -0,0 +1,81 @@
+package fielder
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type testCents struct {
+	Cents    int64
+	Currency string
+}
+
+var testCentsComparators = TypedComparators[testCents]{
+	Less: func(a, b testCents) bool { return a.Cents < b.Cents },
+	ToString: func(a testCents) string {
+		return strconv.FormatInt(a.Cents, 10) + " " + a.Currency
+	},
+	FromString: func(st string) (testCents, error) {
+		n, cur, ok := strings.Cut(st, " ")
+		if !ok {
+			return testCents{}, errors.New("want cents and currency")
+		}
+		c, err := strconv.ParseInt(n, 10, 64)
+		return testCents{Cents: c, Currency: cur}, err
+	},
+}
+
+func TestTypedFieldComparators(t *testing.T) {
+	key := NewDefaultFieldKey("Price")
+	a := NewTypedField(key, testCents{100, "USD"}, testCentsComparators)
+	b := NewTypedField(key, testCents{250, "USD"}, testCentsComparators)
+	if !a.LessThan(b) || a.GreaterThan(b) || !b.GreaterThan(a) {
+		t.Error("expected the Less comparator to order the fields")
+	}
+	if !a.Equal(NewTypedField(key, testCents{100, "USD"}, testCentsComparators)) || a.Equal(b) {
+		t.Error("expected == to be used without an Equal comparator")
+	}
+	if a.Type() != reflect.TypeOf(testCents{}) {
+		t.Errorf("Type: got %s", a.Type())
+	}
+}
+
+func TestTypedFieldString(t *testing.T) {
+	f := NewTypedField(NewDefaultFieldKey("Price"), testCents{}, testCentsComparators)
+	if !f.IsEmpty() {
+		t.Error("the zero value is empty")
+	}
+	f.FromString("5 EUR")
+	if f.ValueField != (testCents{5, "EUR"}) || f.ToString() != "5 EUR" {
+		t.Fatalf("got %+v", f.ValueField)
+	}
+	f.FromString("garbage")
+	if f.ValueField != (testCents{5, "EUR"}) {
+		t.Error("input the FromString comparator rejects changed the value")
+	}
+	f.SetValue(NewTypedField(NewDefaultFieldKey("Price"), testCents{7, "GBP"}, testCentsComparators))
+	if f.ValueField != (testCents{7, "GBP"}) {
+		t.Errorf("SetValue: got %+v", f.ValueField)
+	}
+}
+
+func TestTypedFieldDefaults(t *testing.T) {
+	f := NewTypedField(NewDefaultFieldKey("N"), 3, TypedComparators[int]{})
+	if f.LessThan(NewTypedField(NewDefaultFieldKey("N"), 4, TypedComparators[int]{})) {
+		t.Error("without Less nothing is ordered")
+	}
+	if f.ToString() != "3" {
+		t.Errorf("ToString falls back to fmt: got %q", f.ToString())
+	}
+	f.FromString("4")
+	if f.ValueField != 3 {
+		t.Error("without FromString input is rejected")
+	}
+	folded := NewTypedField(NewDefaultFieldKey("S"), "A", TypedComparators[string]{Equal: strings.EqualFold})
+	if !folded.Equal(NewTypedField(NewDefaultFieldKey("S"), "a", TypedComparators[string]{})) {
+		t.Error("expected the Equal comparator to be used")
+	}
+}
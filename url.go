@@ -0,0 +1,183 @@
+package fielder
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// URL is the value type of a URLField, an absolute url with a scheme and host
+type URL string
+
+type URLField struct {
+	ValueField URL      `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey `dynamodbav:"key" json:"key"`
+}
+
+func (s *URLField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *URLField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *URLField) Type() reflect.Type {
+	return reflect.TypeOf(URL(""))
+}
+
+func (s *URLField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.ValueField < in2.(*URLField).ValueField
+}
+
+func (s *URLField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.ValueField > in2.(*URLField).ValueField
+}
+
+func (s *URLField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField == in2.(*URLField).ValueField
+}
+
+func (s *URLField) ToString() string {
+	return string(s.ValueField)
+}
+
+func (s *URLField) FromString(st string) {
+	_ = s.ParseString(st)
+}
+
+func (s *URLField) ParseString(st string) error {
+	u, err := url.Parse(st)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidURL, st, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%w: %s: url must be absolute", ErrInvalidURL, st)
+	}
+	s.ValueField = URL(u.String())
+	return nil
+}
+
+// SetValue drops the parse error, use TrySetValue to see it
+func (s *URLField) SetValue(in2 FieldValue) {
+	_ = s.TrySetValue(in2)
+}
+
+// TrySetValue copies the value of a URLField as is, so an empty one clears the field
+// any other field is parsed from its string form, where "" clears the field and anything invalid is returned as an error
+func (s *URLField) TrySetValue(in2 FieldValue) error {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		st := in2.(Field).ToString()
+		if st == "" {
+			resetField(s)
+			return nil
+		}
+		return s.ParseString(st)
+	}
+	s.ValueField = in2.(*URLField).ValueField
+	return nil
+}
+
+func (s *URLField) IsEmpty() bool {
+	return s.ValueField == ""
+}
+
+// Email is the value type of an EmailField, a bare address with no display name
+type Email string
+
+type EmailField struct {
+	ValueField Email    `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey `dynamodbav:"key" json:"key"`
+}
+
+func (s *EmailField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *EmailField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *EmailField) Type() reflect.Type {
+	return reflect.TypeOf(Email(""))
+}
+
+func (s *EmailField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return s.ValueField < in2.(*EmailField).ValueField
+}
+
+func (s *EmailField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return s.ValueField > in2.(*EmailField).ValueField
+}
+
+func (s *EmailField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField == in2.(*EmailField).ValueField
+}
+
+func (s *EmailField) ToString() string {
+	return string(s.ValueField)
+}
+
+func (s *EmailField) FromString(st string) {
+	_ = s.ParseString(st)
+}
+
+// the domain is lower cased, the local part is kept as is
+func (s *EmailField) ParseString(st string) error {
+	addr, err := mail.ParseAddress(st)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidEmail, st, err)
+	}
+	if addr.Name != "" || addr.Address != strings.TrimSpace(st) {
+		return fmt.Errorf("%w: %s: expected a bare address", ErrInvalidEmail, st)
+	}
+	at := strings.LastIndex(addr.Address, "@")
+	s.ValueField = Email(addr.Address[:at] + strings.ToLower(addr.Address[at:]))
+	return nil
+}
+
+// SetValue drops the parse error, use TrySetValue to see it
+func (s *EmailField) SetValue(in2 FieldValue) {
+	_ = s.TrySetValue(in2)
+}
+
+// TrySetValue copies the value of a EmailField as is, so an empty one clears the field
+// any other field is parsed from its string form, where "" clears the field and anything invalid is returned as an error
+func (s *EmailField) TrySetValue(in2 FieldValue) error {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		st := in2.(Field).ToString()
+		if st == "" {
+			resetField(s)
+			return nil
+		}
+		return s.ParseString(st)
+	}
+	s.ValueField = in2.(*EmailField).ValueField
+	return nil
+}
+
+func (s *EmailField) IsEmpty() bool {
+	return s.ValueField == ""
+}
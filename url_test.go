@@ -0,0 +1,90 @@
+package fielder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestURLFieldParse(t *testing.T) {
+	f := &URLField{KeyField: NewDefaultFieldKey("Homepage")}
+	if err := f.ParseString("https://example.com/a?b=c"); err != nil {
+		t.Fatal(err)
+	}
+	if f.ToString() != "https://example.com/a?b=c" {
+		t.Errorf("got %q", f.ToString())
+	}
+	for _, bad := range []string{"/relative/path", "example.com", "://nope", ""} {
+		if err := f.ParseString(bad); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("ParseString(%q): got %v", bad, err)
+		}
+	}
+	f.FromString("not a url")
+	if f.ToString() != "https://example.com/a?b=c" {
+		t.Errorf("FromString of bad input changed the value to %q", f.ToString())
+	}
+}
+
+func TestURLFieldCompare(t *testing.T) {
+	a := &URLField{ValueField: "https://a.example.com"}
+	b := &URLField{ValueField: "https://b.example.com"}
+	if !a.LessThan(b) || !b.GreaterThan(a) || a.Equal(b) || !a.Equal(&URLField{ValueField: a.ValueField}) {
+		t.Error("urls compare as strings")
+	}
+	c := &URLField{}
+	if !c.IsEmpty() {
+		t.Error("a url field without a value is empty")
+	}
+	c.SetValue(&StringField{ValueField: "http://c.example.com"})
+	if c.ValueField != "http://c.example.com" {
+		t.Errorf("SetValue from string: got %q", c.ValueField)
+	}
+}
+
+func TestEmailFieldParse(t *testing.T) {
+	f := &EmailField{KeyField: NewDefaultFieldKey("Email")}
+	if err := f.ParseString("Jane.Doe@Example.COM"); err != nil {
+		t.Fatal(err)
+	}
+	if f.ValueField != "Jane.Doe@example.com" {
+		t.Errorf("only the domain is lower cased: got %q", f.ValueField)
+	}
+	for _, bad := range []string{"Jane <jane@example.com>", "jane", "jane@", ""} {
+		if err := f.ParseString(bad); !errors.Is(err, ErrInvalidEmail) {
+			t.Errorf("ParseString(%q): got %v", bad, err)
+		}
+	}
+	if f.ValueField != "Jane.Doe@example.com" {
+		t.Errorf("a bad address changed the value to %q", f.ValueField)
+	}
+}
+
+func TestEmailFieldCompare(t *testing.T) {
+	a := &EmailField{ValueField: "a@example.com"}
+	b := &EmailField{}
+	b.SetValue(&EmailField{ValueField: "b@example.com"})
+	if !a.LessThan(b) || !b.GreaterThan(a) || a.Equal(b) {
+		t.Error("addresses compare as strings")
+	}
+	if (&EmailField{}).IsEmpty() != true || a.IsEmpty() {
+		t.Error("only an address without a value is empty")
+	}
+}
+
+func TestURLAndEmailFieldSetValue(t *testing.T) {
+	u := &URLField{ValueField: "https://x.com"}
+	u.SetValue(&URLField{})
+	if !u.IsEmpty() {
+		t.Errorf("an empty url clears the field, got %q", u.ToString())
+	}
+	if err := u.TrySetValue(testField("Site", "x.com")); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("a relative url is returned, got %v", err)
+	}
+	e := &EmailField{ValueField: "a@b.com"}
+	e.SetValue(&EmailField{})
+	if !e.IsEmpty() {
+		t.Errorf("an empty email clears the field, got %q", e.ToString())
+	}
+	if err := e.TrySetValue(testField("Email", "not an address")); !errors.Is(err, ErrInvalidEmail) {
+		t.Errorf("a bad address is returned, got %v", err)
+	}
+}
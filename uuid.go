@@ -0,0 +1,75 @@
+package fielder
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+type UUIDField struct {
+	ValueField uuid.UUID `dynamodbav:"value" json:"value"`
+	KeyField   FieldKey  `dynamodbav:"key" json:"key"`
+}
+
+func (s *UUIDField) Value() FieldValue {
+	return s.ValueField
+}
+
+func (s *UUIDField) Key() FieldKey {
+	return s.KeyField
+}
+
+func (s *UUIDField) Type() reflect.Type {
+	return reflect.TypeOf(uuid.UUID{})
+}
+
+// uuids are ordered by their raw bytes, not their string form
+func (s *UUIDField) LessThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, LT); out != nil {
+		return *out
+	}
+	return bytes.Compare(s.ValueField[:], in2.(*UUIDField).ValueField[:]) < 0
+}
+
+func (s *UUIDField) GreaterThan(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, GT); out != nil {
+		return *out
+	}
+	return bytes.Compare(s.ValueField[:], in2.(*UUIDField).ValueField[:]) > 0
+}
+
+func (s *UUIDField) Equal(in2 any) bool {
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		return *out
+	}
+	return s.ValueField == in2.(*UUIDField).ValueField
+}
+
+func (s *UUIDField) ToString() string {
+	return s.ValueField.String()
+}
+
+// invalid uuids are rejected and leave the current value untouched
+func (s *UUIDField) FromString(st string) {
+	u, err := uuid.Parse(st)
+	if err != nil {
+		return
+	}
+	s.ValueField = u
+}
+
+func (s *UUIDField) SetValue(in2 FieldValue) {
+	in2 = setSource(s, in2)
+	if out := checkAndDoSafeCompare(s, in2, EQ); out != nil {
+		f := in2.(Field)
+		s.FromString(f.ToString())
+		return
+	}
+	s.ValueField = in2.(*UUIDField).ValueField
+	return
+}
+
+func (s *UUIDField) IsEmpty() bool {
+	return s.ValueField == uuid.Nil
+}
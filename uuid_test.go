@@ -0,0 +1,49 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUUIDFieldCompare(t *testing.T) {
+	low := &UUIDField{ValueField: uuid.MustParse("00000000-0000-0000-0000-000000000001")}
+	high := &UUIDField{ValueField: uuid.MustParse("ffffffff-0000-0000-0000-000000000000")}
+	if !low.LessThan(high) || low.GreaterThan(high) || !high.GreaterThan(low) {
+		t.Error("uuids are ordered by their bytes")
+	}
+	if !low.Equal(&UUIDField{ValueField: low.ValueField}) || low.Equal(high) {
+		t.Error("expected equal uuids to compare equal")
+	}
+}
+
+func TestUUIDFieldString(t *testing.T) {
+	id := uuid.New()
+	f := &UUIDField{}
+	f.FromString(id.String())
+	if f.ValueField != id || f.ToString() != id.String() {
+		t.Fatalf("got %s", f.ToString())
+	}
+	f.FromString("not a uuid")
+	if f.ValueField != id {
+		t.Error("an invalid uuid changed the value")
+	}
+	other := &UUIDField{}
+	other.SetValue(f)
+	if other.ValueField != id {
+		t.Error("SetValue did not copy the uuid")
+	}
+	other.SetValue(&StringField{ValueField: uuid.Nil.String()})
+	if !other.IsEmpty() {
+		t.Error("the nil uuid is empty")
+	}
+}
+
+func TestCreateUUIDField(t *testing.T) {
+	id := uuid.New()
+	f := CreateFieldFromType(reflect.TypeOf(uuid.UUID{}), id, NewDefaultFieldKey("Id"))
+	if u, ok := f.(*UUIDField); !ok || u.ValueField != id {
+		t.Fatalf("got %#v", f)
+	}
+}
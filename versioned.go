@@ -0,0 +1,85 @@
+package fielder
+
+import "reflect"
+
+// VersionedField remembers the values it held before each successful SetValue so they can be rolled back
+type VersionedField interface {
+	Field
+	Versioned
+}
+
+type Versioned interface {
+	// prior values, oldest first. the current value is not included
+	History() []Field
+	// restores the most recent prior value, returns false if there is nothing to roll back to
+	Rollback() bool
+}
+
+type FieldVersioned struct {
+	Field
+	// how many prior values to keep, zero or less keeps every value
+	Limit   int
+	history []Field
+}
+
+func NewVersionedField(f Field, limit int) VersionedField {
+	return &FieldVersioned{
+		Field: f,
+		Limit: limit,
+	}
+}
+
+// a write only counts as successful if it changed the value, so writes rejected by a
+// wrapped Conditional, or that set the same value again, are not recorded
+func (s *FieldVersioned) SetValue(in2 FieldValue) {
+	previous := cloneField(s.Field)
+	s.Field.SetValue(in2)
+	if previous == nil || s.Field.Equal(previous) {
+		return
+	}
+	s.history = append(s.history, previous)
+	if s.Limit > 0 && len(s.history) > s.Limit {
+		s.history = s.history[len(s.history)-s.Limit:]
+	}
+}
+
+// the prior values are copies, changing them does not change what Rollback restores
+func (s *FieldVersioned) History() []Field {
+	history := make([]Field, len(s.history))
+	for i, v := range s.history {
+		if history[i] = cloneField(v); history[i] == nil {
+			history[i] = v
+		}
+	}
+	return history
+}
+
+// the prior value is written back through the inner field's SetValue, so any wrapper it has still applies
+func (s *FieldVersioned) Rollback() bool {
+	if len(s.history) == 0 {
+		return false
+	}
+	previous := s.history[len(s.history)-1]
+	s.history = s.history[:len(s.history)-1]
+	s.Field.SetValue(previous)
+	return true
+}
+
+// returns a copy of the field holding its current value
+// wrappers like FieldConditional are cloned as a copy of the field they wrap, since it is the value we want to keep
+// fields that CreateFieldFromType cannot rebuild (like enums) fall back to a shallow copy of the struct
+func cloneField(f Field) Field {
+	rv := reflect.ValueOf(f)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	if c := CreateFieldFromType(f.Type(), f.Value(), f.Key()); c != nil && reflect.TypeOf(c) == rv.Type() {
+		return c
+	}
+	if inner := rv.Elem().FieldByName("Field"); inner.IsValid() && inner.Type() == fieldInterfaceType && !inner.IsNil() {
+		return cloneField(inner.Interface().(Field))
+	}
+	c := reflect.New(rv.Elem().Type())
+	c.Elem().Set(rv.Elem())
+	return c.Interface().(Field)
+}
@@ -0,0 +1,63 @@
+package fielder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionedFieldHistory(t *testing.T) {
+	f := NewVersionedField(&StringField{KeyField: NewDefaultFieldKey("Status")}, 0)
+	for _, v := range []string{"draft", "review", "review", "live"} {
+		f.SetValue(&StringField{ValueField: v})
+	}
+	var got []string
+	for _, h := range f.History() {
+		got = append(got, h.ToString())
+	}
+	if want := []string{"", "draft", "review"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("writes of the same value are not recorded: got %q want %q", got, want)
+	}
+	if !f.Rollback() || f.ToString() != "review" {
+		t.Errorf("Rollback restores the last value: got %q", f.ToString())
+	}
+	if len(f.History()) != 2 {
+		t.Errorf("Rollback drops the value it restored: got %d", len(f.History()))
+	}
+}
+
+func TestVersionedFieldLimit(t *testing.T) {
+	f := NewVersionedField(&StringField{}, 2)
+	for _, v := range []string{"a", "b", "c", "d"} {
+		f.SetValue(&StringField{ValueField: v})
+	}
+	h := f.History()
+	if len(h) != 2 || h[0].ToString() != "b" || h[1].ToString() != "c" {
+		t.Errorf("only the newest values are kept: got %v", h)
+	}
+	f.Rollback()
+	f.Rollback()
+	if f.Rollback() || f.ToString() != "b" {
+		t.Errorf("nothing to roll back to: got %q", f.ToString())
+	}
+}
+
+func TestVersionedFieldHistoryIsACopy(t *testing.T) {
+	f := NewVersionedField(&StringField{ValueField: "a"}, 0)
+	f.SetValue(&StringField{ValueField: "b"})
+	f.History()[0].SetValue(&StringField{ValueField: "z"})
+	if f.Rollback(); f.ToString() != "a" {
+		t.Errorf("the history keeps copies of the values: got %q", f.ToString())
+	}
+}
+
+func TestCloneField(t *testing.T) {
+	src := &StringField{ValueField: "a", KeyField: NewDefaultFieldKey("Name")}
+	c := cloneField(src)
+	src.ValueField = "b"
+	if c.ToString() != "a" || c.Key() != src.KeyField {
+		t.Errorf("got %q %v", c.ToString(), c.Key())
+	}
+	if cloneField(nil) != nil {
+		t.Error("a nil field has no clone")
+	}
+}